@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/ankitiscracked/fastest/cli/internal/agent"
 	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/conflicts"
 )
 
 // mockAgent returns a fake agent for testing.
@@ -116,6 +118,59 @@ func TestSnapshotWithAgentMessage(t *testing.T) {
 	}
 }
 
+func TestSnapshotMessageFromDiffYes(t *testing.T) {
+	root := setupWorkspace(t, "ws-agent-snap-yes", map[string]string{
+		"main.go": "package main\nfunc main() {}\n",
+	})
+	setenv(t, "XDG_CACHE_HOME", filepath.Join(root, "cache"))
+	setenv(t, "XDG_CONFIG_HOME", filepath.Join(root, "config"))
+
+	createBaseSnapshot(t, root)
+	writeFile(t, filepath.Join(root, "main.go"), "package main\nfunc main() { println(\"hi\") }\n")
+
+	SetDeps(Deps{
+		AgentGetPreferred: func() (*agent.Agent, error) {
+			return mockAgent(), nil
+		},
+		AgentInvoke: func(a *agent.Agent, prompt string) (string, error) {
+			return "  Print a greeting from main\n", nil
+		},
+	})
+	defer ResetDeps()
+
+	restoreCwd := chdir(t, root)
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "--message-from-diff", "--yes"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("snapshot --message-from-diff --yes failed: %v", err)
+	}
+	restoreCwd()
+
+	cfg, err := config.LoadAt(root)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	meta := readSnapshotMeta(t, root, cfg.CurrentSnapshotID)
+	if meta.Message != "Print a greeting from main" {
+		t.Fatalf("expected agent message, got %q", meta.Message)
+	}
+}
+
+func TestSnapshotYesRequiresAgentMessage(t *testing.T) {
+	root := setupWorkspace(t, "ws-snap-yes", map[string]string{
+		"a.txt": "hello",
+	})
+
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "test", "--yes"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected snapshot --yes without --agent-message to fail")
+	}
+}
+
 func TestDriftWithAgentSummary(t *testing.T) {
 	_, targetRoot, _ := setupForkedWorkspaces(t,
 		map[string]string{"a.txt": "one"},
@@ -153,6 +208,63 @@ func TestDriftWithAgentSummary(t *testing.T) {
 	}
 }
 
+func TestDriftSummarizeCachesByFingerprint(t *testing.T) {
+	_, targetRoot, _ := setupForkedWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	calls := 0
+	SetDeps(Deps{
+		AgentGetPreferred: func() (*agent.Agent, error) {
+			return mockAgent(), nil
+		},
+		AgentInvoke: func(a *agent.Agent, prompt string) (string, error) {
+			calls++
+			return "Added a text file\nextra detail", nil
+		},
+	})
+	defer ResetDeps()
+
+	run := func() string {
+		var output string
+		err := captureStdout(func() error {
+			cmd := NewRootCmd()
+			cmd.SetArgs([]string{"drift", "ws-source", "--no-dirty", "--summarize"})
+			return cmd.Execute()
+		}, &output)
+		if err != nil {
+			if code := ExitCode(err); code != 1 {
+				t.Fatalf("drift failed unexpectedly: %v", err)
+			}
+		}
+		return output
+	}
+
+	output := run()
+	if !strings.Contains(output, "Added a text file") {
+		t.Fatalf("expected summary in output, got:\n%s", output)
+	}
+	if strings.Contains(output, "extra detail") {
+		t.Fatalf("expected summary to be a single line, got:\n%s", output)
+	}
+	firstCalls := calls
+	if firstCalls == 0 {
+		t.Fatalf("expected agent to be invoked")
+	}
+
+	output = run()
+	if !strings.Contains(output, "Added a text file") {
+		t.Fatalf("expected cached summary in output, got:\n%s", output)
+	}
+	if calls != firstCalls {
+		t.Fatalf("expected cached summary to skip agent, got %d calls (was %d)", calls, firstCalls)
+	}
+}
+
 func TestMergeDryRunShowsConflicts(t *testing.T) {
 	// Verify that merge --dry-run with --agent-summary shows conflict info.
 	// Note: The agent summary in printConflictDetails requires conflicts.Detect
@@ -236,6 +348,167 @@ func TestAgentInvokeMergeIntegration(t *testing.T) {
 	}
 }
 
+func TestAgentInvokeMergeFilesIntegration(t *testing.T) {
+	mockInvoke := func(a *agent.Agent, prompt string) (string, error) {
+		if !strings.Contains(prompt, "FILE: a.go") || !strings.Contains(prompt, "FILE: b.go") {
+			return "", fmt.Errorf("prompt is missing a file: %s", prompt)
+		}
+		return "• Renamed the helper in both files\n\n---MERGED FILE: a.go---\nmerged a\n---MERGED FILE: b.go---\nmerged b", nil
+	}
+
+	files := []agent.FileConflict{
+		{Path: "a.go", Base: "base a", Current: "current a", Source: "source a"},
+		{Path: "b.go", Base: "base b", Current: "current b", Source: "source b"},
+	}
+	results, err := agent.InvokeMergeFiles(mockAgent(), files, mockInvoke)
+	if err != nil {
+		t.Fatalf("InvokeMergeFiles failed: %v", err)
+	}
+	if results["a.go"].MergedCode != "merged a" || results["b.go"].MergedCode != "merged b" {
+		t.Fatalf("unexpected merged code: %q, %q", results["a.go"].MergedCode, results["b.go"].MergedCode)
+	}
+	if len(results["b.go"].Strategy) != 1 || results["b.go"].Strategy[0] != "Renamed the helper in both files" {
+		t.Fatalf("unexpected strategy: %v", results["b.go"].Strategy)
+	}
+
+	partial := func(a *agent.Agent, prompt string) (string, error) {
+		return "---MERGED FILE: a.go---\nmerged a", nil
+	}
+	if _, err := agent.InvokeMergeFiles(mockAgent(), files, partial); err == nil {
+		t.Fatal("expected an error when a file is missing from the response")
+	}
+}
+
+func TestAgentMergeContextLines(t *testing.T) {
+	lines := func(edit func([]string)) []byte {
+		l := make([]string, 50)
+		for i := range l {
+			l[i] = fmt.Sprintf("line %d", i+1)
+		}
+		edit(l)
+		return []byte(strings.Join(l, "\n") + "\n")
+	}
+	base := lines(func([]string) {})
+	current := lines(func(l []string) { l[9] = "current 10"; l[39] = "current 40" })
+	source := lines(func(l []string) { l[9] = "source 10"; l[44] = "source 45" })
+
+	var prompt string
+	mockInvoke := func(a *agent.Agent, p string) (string, error) {
+		prompt = p
+		return "• Combined line 10\n\n---RESOLVED HUNK 1---\nresolved 10", nil
+	}
+
+	result, err := agentMerge(mockAgent(), "big.txt", base, current, source, 2, nil, mockInvoke)
+	if err != nil {
+		t.Fatalf("agentMerge failed: %v", err)
+	}
+	if !strings.Contains(prompt, "line 8") || !strings.Contains(prompt, "line 12") {
+		t.Fatalf("prompt should include 2 lines of context:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "line 7\n") || strings.Contains(prompt, "line 25") {
+		t.Fatalf("prompt should not include lines outside the hunk context:\n%s", prompt)
+	}
+	want := string(lines(func(l []string) { l[9] = "resolved 10"; l[39] = "current 40"; l[44] = "source 45" }))
+	if result.MergedCode != want {
+		t.Fatalf("unexpected merged code:\n%s", result.MergedCode)
+	}
+	if len(result.Strategy) != 1 || result.Strategy[0] != "Combined line 10" {
+		t.Fatalf("unexpected strategy: %v", result.Strategy)
+	}
+
+	// Without a resolution for every hunk the merge fails.
+	empty := func(a *agent.Agent, p string) (string, error) { return "• nothing", nil }
+	if _, err := agentMerge(mockAgent(), "big.txt", base, current, source, 2, nil, empty); err == nil {
+		t.Fatal("expected an error when a hunk is missing from the response")
+	}
+}
+
+func TestAgentMergeReusesAnalysisCache(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".fst"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	base := []byte("a\nb\nc\nd\ne\n")
+	current := []byte("a\nB1\nc\nd\ne\n")
+	source := []byte("a\nB2\nc\nd\nE\n")
+	mockInvoke := func(a *agent.Agent, p string) (string, error) {
+		return "• Kept both\n\n---RESOLVED HUNK 1---\nB1\nB2", nil
+	}
+
+	// A dry run analyses the file and saves the result.
+	dryRun := conflicts.LoadAnalysisCache(root, "manifest-base", "manifest-cur", "manifest-src")
+	first, err := agentMerge(mockAgent(), "f.txt", base, current, source, 1, dryRun, mockInvoke)
+	if err != nil {
+		t.Fatalf("agentMerge failed: %v", err)
+	}
+	if dryRun.Hits() != 0 {
+		t.Fatalf("expected a cold cache, got %d hits", dryRun.Hits())
+	}
+	if err := dryRun.Save(root); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// The real merge of the same manifests reuses it.
+	merge := conflicts.LoadAnalysisCache(root, "manifest-base", "manifest-cur", "manifest-src")
+	second, err := agentMerge(mockAgent(), "f.txt", base, current, source, 1, merge, mockInvoke)
+	if err != nil {
+		t.Fatalf("agentMerge failed: %v", err)
+	}
+	if merge.Hits() != 1 {
+		t.Fatalf("expected the cached analysis to be reused, got %d hits", merge.Hits())
+	}
+	if second.MergedCode != first.MergedCode {
+		t.Fatalf("cached analysis changed the result:\n%s\nvs\n%s", second.MergedCode, first.MergedCode)
+	}
+
+	// Different content or different manifests are analysed afresh.
+	if _, err := agentMerge(mockAgent(), "f.txt", base, []byte("a\nB3\nc\nd\ne\n"), source, 1, merge, mockInvoke); err != nil {
+		t.Fatalf("agentMerge failed: %v", err)
+	}
+	if merge.Hits() != 1 {
+		t.Fatalf("changed content must not hit the cache")
+	}
+	other := conflicts.LoadAnalysisCache(root, "manifest-base", "manifest-other", "manifest-src")
+	if _, err := agentMerge(mockAgent(), "f.txt", base, current, source, 1, other, mockInvoke); err != nil {
+		t.Fatalf("agentMerge failed: %v", err)
+	}
+	if other.Hits() != 0 {
+		t.Fatalf("different manifests must not hit the cache")
+	}
+}
+
+func TestMergeDryRunCachesAnalysisOnlyForContextLines(t *testing.T) {
+	_, targetRoot, _ := setupForkedWorkspaces(t,
+		map[string]string{"base.txt": "target\n"},
+		map[string]string{"base.txt": "source\n"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	cachePath := conflicts.AnalysisCachePath(targetRoot)
+	for _, tc := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"merge", "ws-source", "--dry-run"}, false},
+		{[]string{"merge", "ws-source", "--dry-run", "--manual", "--context-lines", "2"}, false},
+		{[]string{"merge", "ws-source", "--dry-run", "--context-lines", "2"}, true},
+	} {
+		if err := captureStdout(func() error {
+			cmd := NewRootCmd()
+			cmd.SetArgs(tc.args)
+			return cmd.Execute()
+		}, new(string)); err != nil {
+			t.Fatalf("%v: %v", tc.args, err)
+		}
+		_, err := os.Stat(cachePath)
+		if got := err == nil; got != tc.want {
+			t.Fatalf("%v: expected cache written = %v, got %v", tc.args, tc.want, got)
+		}
+	}
+}
+
 func TestAgentInvokeConflictSummaryIntegration(t *testing.T) {
 	mockInvoke := func(a *agent.Agent, prompt string) (string, error) {
 		return "Two files have overlapping edits in the auth module.", nil
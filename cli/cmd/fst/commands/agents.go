@@ -8,6 +8,7 @@ import (
 	"golang.org/x/term"
 
 	"github.com/ankitiscracked/fastest/cli/internal/agent"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
 )
 
 func init() {
@@ -16,17 +17,22 @@ func init() {
 
 func newAgentsCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "agents",
-		Short: "Manage coding agents",
+		Use:     "agents",
+		Aliases: []string{"agent"},
+		Short:   "Manage coding agents",
 		Long: `Detect and configure coding agents for LLM-powered features.
 
 Fastest uses your locally installed coding agents (like Claude Code, Aider, etc.)
-to generate natural language summaries and assist with merge conflict resolution.`,
+to generate natural language summaries and assist with merge conflict resolution.
+
+Use 'fst agent run "<task>"' to run the preferred agent on a task in the
+current workspace, with snapshots taken before and after.`,
 		RunE: runAgentsList,
 	}
 
 	cmd.AddCommand(newAgentsListCmd())
 	cmd.AddCommand(newAgentsSetCmd())
+	cmd.AddCommand(newAgentsRunCmd())
 
 	return cmd
 }
@@ -125,3 +131,119 @@ func newAgentsSetCmd() *cobra.Command {
 		},
 	}
 }
+
+func newAgentsRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run <task>",
+		Short: "Run the preferred agent on a task between two snapshots",
+		Long: `Run the preferred coding agent on a task in the current workspace.
+
+The workspace is snapshotted before the agent starts (if it has changes
+since its last snapshot) and again after it finishes, with the agent's name
+recorded on the second snapshot. Every agent session is thus bracketed by
+a clean before/after pair that can be diffed, reviewed or restored.
+
+If the agent fails, no post-run snapshot is taken; its partial changes are
+left in the working tree. Either way, 'fst undo' returns the workspace to
+its state before the run.
+
+Examples:
+  fst agent run "add input validation to the signup form"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgentTask(args[0])
+		},
+	}
+}
+
+func runAgentTask(task string) error {
+	preferred, err := deps.AgentGetPreferred()
+	if err != nil {
+		return err
+	}
+	summary := truncatePreview(task, 60)
+
+	root, preID, err := prepareAgentTask(preferred.Name, summary)
+	if err != nil {
+		return err
+	}
+
+	// The workspace is closed while the agent runs, so the agent itself can
+	// run fst commands (status, snapshot, ...) without waiting on our locks.
+	// Agents work on the current directory, so run them from the workspace root.
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	if err := os.Chdir(root); err != nil {
+		return fmt.Errorf("failed to enter workspace: %w", err)
+	}
+	fmt.Printf("Running %s...\n", preferred.Name)
+	output, invokeErr := deps.AgentInvoke(preferred, task)
+	if err := os.Chdir(cwd); err != nil {
+		return fmt.Errorf("failed to return to %s: %w", cwd, err)
+	}
+	if invokeErr != nil {
+		fmt.Println("Changes made before the failure are in the working tree; 'fst undo' reverts them.")
+		return invokeErr
+	}
+	if output != "" {
+		fmt.Println()
+		fmt.Println(output)
+		fmt.Println()
+	}
+
+	ws, err := workspace.OpenAt(root)
+	if err != nil {
+		return fmt.Errorf("failed to reopen workspace: %w", err)
+	}
+	defer ws.Close()
+
+	report := workspaceDriftAt(ws.Store(), ws.Root(), ws.CurrentSnapshotID())
+	if report != nil && !report.HasChanges() {
+		// Any snapshots the agent took itself are part of the run.
+		_ = ws.RecordUndoResult(ws.CurrentSnapshotID())
+		fmt.Printf("%s made no changes.\n", preferred.Name)
+		return nil
+	}
+	result, err := ws.Snapshot(workspace.SnapshotOpts{
+		Message: fmt.Sprintf("%s: %s", preferred.Name, summary),
+		Agent:   preferred.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create post-run snapshot: %w", err)
+	}
+	if err := ws.RecordUndoResult(result.SnapshotID); err != nil {
+		fmt.Printf("Warning: Could not record undo point: %v\n", err)
+	}
+	fmt.Printf("Created snapshot %s with %s's changes\n", result.SnapshotID, preferred.Name)
+	if preID != "" {
+		fmt.Printf("Review with 'fst status --base %s'; revert with 'fst undo'.\n", preID)
+	}
+	return nil
+}
+
+// prepareAgentTask takes the pre-run snapshot and undo point for an agent
+// run, releasing the workspace locks before returning. It returns the
+// workspace root and the snapshot the run starts from.
+func prepareAgentTask(agentName, summary string) (string, string, error) {
+	ws, err := workspace.Open()
+	if err != nil {
+		return "", "", fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+	defer ws.Close()
+
+	preID, err := ws.AutoSnapshot(fmt.Sprintf("Before %s: %s", agentName, summary))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create pre-run snapshot: %w", err)
+	}
+	if preID == "" {
+		preID = ws.CurrentSnapshotID()
+	} else {
+		fmt.Printf("Created snapshot %s before running %s\n", preID, agentName)
+	}
+	if err := ws.RecordUndoPoint("agent run", agentName+" run", preID); err != nil {
+		fmt.Printf("Warning: Could not record undo point: %v\n", err)
+	}
+	return ws.Root(), preID, nil
+}
@@ -1,9 +1,17 @@
 package commands
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ankitiscracked/fastest/cli/internal/agent"
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
 )
 
 func TestAgentsSetPreferredRequiresNameInNonInteractiveMode(t *testing.T) {
@@ -34,3 +42,97 @@ func TestAgentsSetPreferredRequiresNameInNonInteractiveMode(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestAgentRunBracketsWithSnapshots(t *testing.T) {
+	root := setupWorkspace(t, "ws-agent-run", map[string]string{
+		"main.go": "package main\n",
+	})
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	SetDeps(Deps{
+		AgentGetPreferred: func() (*agent.Agent, error) {
+			return mockAgent(), nil
+		},
+		AgentInvoke: func(a *agent.Agent, prompt string) (string, error) {
+			return "done", os.WriteFile("added.go", []byte("package main\n"), 0644)
+		},
+	})
+	defer ResetDeps()
+
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"agent", "run", "add a file"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("agent run failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "added.go")); err != nil {
+		t.Fatalf("expected the agent to run in the workspace root: %v", err)
+	}
+	cfg, err := config.LoadAt(root)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	s := store.OpenFromWorkspace(root)
+	post, err := s.LoadSnapshotMeta(cfg.CurrentSnapshotID)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if post.Agent != "mock" || !strings.Contains(post.Message, "add a file") {
+		t.Fatalf("expected post-run snapshot by mock, got %+v", post)
+	}
+	if len(post.ParentSnapshotIDs) != 1 {
+		t.Fatalf("expected post-run snapshot to follow the pre-run one, got parents %v", post.ParentSnapshotIDs)
+	}
+	pre, err := s.LoadSnapshotMeta(post.ParentSnapshotIDs[0])
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if pre.Agent != "" || !strings.HasPrefix(pre.Message, "Before mock") {
+		t.Fatalf("unexpected pre-run snapshot %+v", pre)
+	}
+}
+
+func TestAgentRunReleasesWorkspaceLockWhileAgentRuns(t *testing.T) {
+	root := setupWorkspace(t, "ws-agent-lock", map[string]string{
+		"main.go": "package main\n",
+	})
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	SetDeps(Deps{
+		AgentGetPreferred: func() (*agent.Agent, error) {
+			return mockAgent(), nil
+		},
+		AgentInvoke: func(a *agent.Agent, prompt string) (string, error) {
+			locked := make(chan error, 1)
+			go func() {
+				lock, err := workspace.AcquireWorkspaceLock(root)
+				if err == nil {
+					lock.Release()
+				}
+				locked <- err
+			}()
+			select {
+			case err := <-locked:
+				if err != nil {
+					return "", err
+				}
+			case <-time.After(5 * time.Second):
+				return "", fmt.Errorf("workspace lock still held while the agent runs")
+			}
+			return "done", os.WriteFile("added.go", []byte("package main\n"), 0644)
+		},
+	})
+	defer ResetDeps()
+
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"agent", "run", "add a file"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("agent run failed: %v", err)
+	}
+}
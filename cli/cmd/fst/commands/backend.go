@@ -1,11 +1,13 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/ankitiscracked/fastest/cli/internal/gitutil"
 	"github.com/ankitiscracked/fastest/cli/internal/manifest"
 	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/ui"
 	"github.com/ankitiscracked/fastest/cli/internal/workspace"
 )
 
@@ -51,24 +54,35 @@ func newBackendSetCmd() *cobra.Command {
 }
 
 func newBackendSetGitHubCmd() *cobra.Command {
-	var createRepo bool
-	var privateRepo bool
-	var remoteName string
-	var forceRemote bool
+	var opts githubBackendOptions
 
 	cmd := &cobra.Command{
 		Use:   "github <owner/repo>",
 		Short: "Set GitHub as the storage backend",
-		Args:  cobra.ExactArgs(1),
+		Long: `Set GitHub as the storage backend: export all snapshots to git, add the
+repository as a remote, push, and save the backend in the project config.
+
+Use --dry-run to check the repository and remote and see what would be
+exported and pushed, without changing anything.
+
+The remote URL uses HTTPS unless --ssh is given, which uses
+git@github.com:owner/repo.git instead so pushes authenticate with your SSH
+keys. 'fst config set --global github.protocol ssh' makes SSH the default.
+An existing remote that points at the same repository over the other
+transport is kept as-is, unless --ssh (or --ssh=false) asks to switch it.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runBackendSetGitHub(args[0], createRepo, privateRepo, remoteName, forceRemote)
+			opts.sshSet = cmd.Flags().Changed("ssh")
+			return runBackendSetGitHub(args[0], opts)
 		},
 	}
 
-	cmd.Flags().BoolVar(&createRepo, "create", false, "Create the GitHub repo if it doesn't exist (requires gh)")
-	cmd.Flags().BoolVar(&privateRepo, "private", false, "Create repo as private (requires --create)")
-	cmd.Flags().StringVar(&remoteName, "remote", "origin", "Remote name to use")
-	cmd.Flags().BoolVar(&forceRemote, "force-remote", false, "Overwrite remote URL if it already exists")
+	cmd.Flags().BoolVar(&opts.createRepo, "create", false, "Create the GitHub repo if it doesn't exist (requires gh)")
+	cmd.Flags().BoolVar(&opts.privateRepo, "private", false, "Create repo as private (requires --create)")
+	cmd.Flags().StringVar(&opts.remoteName, "remote", "origin", "Remote name to use")
+	cmd.Flags().BoolVar(&opts.forceRemote, "force-remote", false, "Overwrite remote URL if it already exists")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show what would be done without making changes")
+	cmd.Flags().BoolVar(&opts.ssh, "ssh", false, "Use an SSH remote URL (git@github.com:owner/repo.git)")
 
 	return cmd
 }
@@ -96,13 +110,19 @@ func newBackendOffCmd() *cobra.Command {
 }
 
 func newBackendStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show current backend configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runBackendStatus()
+			return runBackendStatus(jsonOutput)
 		},
 	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
 }
 
 func newBackendPushCmd() *cobra.Command {
@@ -135,46 +155,62 @@ func findProjectRootAndConfig() (string, *config.ProjectConfig, error) {
 }
 
 // backendAutoExport spawns a background subprocess to sync with the backend.
-// Skips silently if another backend operation is already running.
-// Prints a warning if the previous background sync failed.
+// Skips silently if a previously spawned sync is still waiting to start; the
+// subprocess is the only one to take the backend lock, so it runs after any
+// operation in progress. Prints a warning if the previous background sync
+// failed.
 func backendAutoExport(projectRoot string) {
 	logPath := filepath.Join(projectRoot, ".fst", "backend-export.log")
 
 	// Check if the previous background sync failed
-	checkPreviousSyncLog(logPath)
-
-	// Try to acquire lock non-blocking to check if another operation is running.
-	// We release it immediately — the subprocess will acquire its own lock.
-	lock, err := workspace.TryAcquireBackendLock(projectRoot)
-	if err != nil {
-		return
+	if parentCfg, err := config.LoadProjectConfigAt(projectRoot); err == nil && parentCfg.LastSync != nil {
+		if !parentCfg.LastSync.Success {
+			fmt.Printf("Warning: last background %s failed: %s (see .fst/backend-export.log)\n", parentCfg.LastSync.Operation, parentCfg.LastSync.Error)
+		}
+	} else {
+		checkPreviousSyncLog(logPath)
 	}
-	if lock == nil {
-		// Another backend operation is running, skip
+
+	// A pending spawn will export this operation's snapshots too, so a
+	// second one would only hit the remote again.
+	spawnLock, err := workspace.TryAcquireBackendSpawnLock(projectRoot)
+	if err != nil || spawnLock == nil {
 		return
 	}
-	lock.Release()
 
 	fstBin, err := os.Executable()
 	if err != nil {
+		spawnLock.Release()
 		return
 	}
 
-	cmd := exec.Command(fstBin, "sync")
+	// The child inherits the spawn lock as fd 3 and releases it once it
+	// holds the backend lock.
+	cmd := exec.Command(fstBin, "sync", "--background")
 	cmd.Dir = projectRoot
+	cmd.ExtraFiles = []*os.File{spawnLock.File()}
 	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err == nil {
 		cmd.Stdout = logFile
 		cmd.Stderr = logFile
 	}
-	_ = cmd.Start()
+	if err := cmd.Start(); err != nil {
+		spawnLock.Release()
+	} else {
+		spawnLock.Close()
+	}
 	if logFile != nil {
 		logFile.Close()
 	}
 }
 
+// backgroundSpawnLockFD is the descriptor of the spawn lock a background
+// sync inherits from backendAutoExport (the first of exec.Cmd.ExtraFiles).
+const backgroundSpawnLockFD = 3
+
 // checkPreviousSyncLog reads the previous background sync log and prints a
-// warning if it contains error indicators.
+// warning if it contains error indicators. It is only used for projects that
+// have no structured last-sync record yet.
 func checkPreviousSyncLog(logPath string) {
 	data, err := os.ReadFile(logPath)
 	if err != nil {
@@ -190,6 +226,33 @@ func checkPreviousSyncLog(logPath string) {
 	}
 }
 
+// newerSideMode resolves ConflictModeNewer for a divergence: local wins
+// (ConflictModeOurs) unless the remote head was created strictly after the
+// local head, so ties keep the local side; unreadable timestamps take the
+// remote side.
+func newerSideMode(s *store.Store, localHead, remoteHead string) ConflictMode {
+	localTime, err := snapshotCreatedAt(s, localHead)
+	if err != nil {
+		return ConflictModeTheirs
+	}
+	remoteTime, err := snapshotCreatedAt(s, remoteHead)
+	if err != nil {
+		return ConflictModeTheirs
+	}
+	if remoteTime.After(localTime) {
+		return ConflictModeTheirs
+	}
+	return ConflictModeOurs
+}
+
+func snapshotCreatedAt(s *store.Store, snapshotID string) (time.Time, error) {
+	meta, err := s.LoadSnapshotMeta(snapshotID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, meta.CreatedAt)
+}
+
 // buildOnDivergence creates an OnDivergence callback that uses the existing
 // merge infrastructure to reconcile diverged workspace heads.
 func buildOnDivergence(mode ConflictMode) func(backend.DivergenceInfo) (string, error) {
@@ -225,7 +288,7 @@ func buildOnDivergence(mode ConflictMode) func(backend.DivergenceInfo) (string,
 		}
 		defer os.RemoveAll(tempDir)
 
-		if err := gitstore.RestoreFilesFromManifest(tempDir, s, remoteManifest); err != nil {
+		if err := gitstore.RestoreFilesFromManifest(tempDir, s, remoteManifest, gitstore.RestoreOptions{}); err != nil {
 			return "", fmt.Errorf("failed to materialize remote snapshot: %w", err)
 		}
 
@@ -240,6 +303,10 @@ func buildOnDivergence(mode ConflictMode) func(backend.DivergenceInfo) (string,
 		fmt.Printf("  Conflicts:          %d files\n", len(mergeActions.conflicts))
 		fmt.Printf("  Already in sync:    %d files\n", len(mergeActions.inSync))
 
+		recordSyncUndo(div.WorkspaceRoot, func(ws *workspace.Workspace) error {
+			return ws.RecordUndoPoint("sync", "sync merge with remote", div.LocalHead)
+		})
+
 		// Apply non-conflicting changes
 		for _, action := range mergeActions.toApply {
 			if err := applyChange(div.WorkspaceRoot, tempDir, action); err != nil {
@@ -249,7 +316,16 @@ func buildOnDivergence(mode ConflictMode) func(backend.DivergenceInfo) (string,
 
 		// Handle conflicts
 		if len(mergeActions.conflicts) > 0 {
-			switch mode {
+			conflictMode := mode
+			if conflictMode == ConflictModeNewer {
+				conflictMode = newerSideMode(s, div.LocalHead, div.RemoteHead)
+				if conflictMode == ConflictModeOurs {
+					fmt.Println("  Local head is newer or same age: keeping local versions of conflicting files")
+				} else {
+					fmt.Println("  Remote head is newer: taking remote versions of conflicting files")
+				}
+			}
+			switch conflictMode {
 			case ConflictModeAgent:
 				preferredAgent, err := deps.AgentGetPreferred()
 				if err != nil {
@@ -266,7 +342,6 @@ func buildOnDivergence(mode ConflictMode) func(backend.DivergenceInfo) (string,
 						return "", err
 					}
 				}
-				fmt.Println("Conflicts written with markers. Resolve them, then run 'fst snapshot'.")
 			case ConflictModeTheirs:
 				for _, conflict := range mergeActions.conflicts {
 					if err := applyChange(div.WorkspaceRoot, tempDir, conflict); err != nil {
@@ -280,11 +355,34 @@ func buildOnDivergence(mode ConflictMode) func(backend.DivergenceInfo) (string,
 
 		// Create merge snapshot with both parents
 		mergeParents := normalizeMergeParents(div.LocalHead, div.RemoteHead)
+
+		// Conflict markers must not be snapshotted into synced history: leave
+		// the merge pending so the user's next 'fst snapshot' completes it.
+		if mode == ConflictModeManual && len(mergeActions.conflicts) > 0 {
+			if err := config.WritePendingMergeParentsAt(div.WorkspaceRoot, mergeParents); err != nil {
+				return "", fmt.Errorf("failed to record merge parents: %w", err)
+			}
+			fmt.Println()
+			fmt.Println(dag.RenderMergeDiagram(dag.MergeDiagramOpts{
+				CurrentID:     div.LocalHead,
+				SourceID:      div.RemoteHead,
+				MergeBaseID:   div.MergeBase,
+				CurrentLabel:  "local",
+				SourceLabel:   "remote",
+				Message:       "Sync merge",
+				Pending:       true,
+				ConflictCount: len(mergeActions.conflicts),
+				Colorize:      true,
+			}))
+			fmt.Println("Conflicts written with markers. Resolve them, run 'fst snapshot', then 'fst sync' again.")
+			return "", backend.ErrMergePending
+		}
+
 		if err := config.WritePendingMergeParentsAt(div.ProjectRoot, mergeParents); err != nil {
 			fmt.Printf("Warning: Could not record merge parents: %v\n", err)
 		}
 
-		if err := runSnapshot("Backend sync merge", false); err != nil {
+		if err := runSnapshot(snapshotOptions{message: "Backend sync merge", noVerify: true}); err != nil {
 			return "", fmt.Errorf("failed to create merge snapshot: %w", err)
 		}
 
@@ -293,42 +391,102 @@ func buildOnDivergence(mode ConflictMode) func(backend.DivergenceInfo) (string,
 		if err != nil {
 			return "", fmt.Errorf("failed to read merged snapshot ID: %w", err)
 		}
+		recordSyncUndo(div.WorkspaceRoot, func(ws *workspace.Workspace) error {
+			return ws.RecordUndoResult(wsCfg.CurrentSnapshotID)
+		})
 
-		hasConflicts := mode == ConflictModeManual && len(mergeActions.conflicts) > 0
 		fmt.Println()
 		fmt.Println(dag.RenderMergeDiagram(dag.MergeDiagramOpts{
-			CurrentID:     div.LocalHead,
-			SourceID:      div.RemoteHead,
-			MergeBaseID:   div.MergeBase,
-			MergedID:      wsCfg.CurrentSnapshotID,
-			CurrentLabel:  "local",
-			SourceLabel:   "remote",
-			Message:       "Sync merge",
-			Pending:       hasConflicts,
-			ConflictCount: len(mergeActions.conflicts),
-			Colorize:      true,
+			CurrentID:    div.LocalHead,
+			SourceID:     div.RemoteHead,
+			MergeBaseID:  div.MergeBase,
+			MergedID:     wsCfg.CurrentSnapshotID,
+			CurrentLabel: "local",
+			SourceLabel:  "remote",
+			Message:      "Sync merge",
+			Colorize:     true,
 		}))
 
 		return wsCfg.CurrentSnapshotID, nil
 	}
 }
 
-func runBackendSetGitHub(repo string, createRepo, privateRepo bool, remoteName string, forceRemote bool) error {
+// recordSyncUndo updates the undo history of the workspace at root with
+// record. Failures only warn: the sync itself goes ahead.
+func recordSyncUndo(root string, record func(ws *workspace.Workspace) error) {
+	ws, err := workspace.OpenAt(root)
+	if err == nil {
+		err = record(ws)
+		ws.Close()
+	}
+	if err != nil {
+		fmt.Printf("Warning: Could not record undo point: %v\n", err)
+	}
+}
+
+// githubBackendOptions holds the flags of `fst backend set github`.
+type githubBackendOptions struct {
+	createRepo  bool
+	privateRepo bool
+	remoteName  string
+	forceRemote bool
+	dryRun      bool
+	ssh         bool
+	sshSet      bool // --ssh was given explicitly
+}
+
+// preferSSH reports whether to use an SSH remote URL: --ssh if given,
+// otherwise the global github.protocol setting.
+func (o githubBackendOptions) preferSSH() bool {
+	if o.sshSet {
+		return o.ssh
+	}
+	return config.PrefersGitHubSSH()
+}
+
+// reconcileRemote decides whether an existing remote URL must be changed to
+// remoteURL. A remote for the same repository over another transport is
+// only switched when --ssh was given; any other repository needs
+// --force-remote.
+func (o githubBackendOptions) reconcileRemote(existingURL, remoteURL string) (bool, error) {
+	switch {
+	case existingURL == remoteURL:
+		return false, nil
+	case sameGitHubRepo(existingURL, remoteURL):
+		return o.sshSet, nil
+	case o.forceRemote:
+		return true, nil
+	default:
+		return false, fmt.Errorf("remote '%s' already set to %s (use --force-remote to override)", o.remoteName, existingURL)
+	}
+}
+
+func runBackendSetGitHub(repo string, opts githubBackendOptions) error {
+	createRepo, privateRepo, remoteName := opts.createRepo, opts.privateRepo, opts.remoteName
+
 	projectRoot, parentCfg, err := findProjectRootAndConfig()
 	if err != nil {
 		return err
 	}
 
-	lock, err := workspace.AcquireBackendLock(projectRoot)
+	slug, remoteURL, err := parseGitHubRepo(repo)
 	if err != nil {
 		return err
 	}
-	defer lock.Release()
+	if opts.preferSSH() {
+		remoteURL = githubRemoteURL(slug, true)
+	} else if opts.sshSet {
+		remoteURL = githubRemoteURL(slug, false)
+	}
+	if opts.dryRun {
+		return previewBackendSetGitHub(projectRoot, slug, remoteURL, opts)
+	}
 
-	slug, remoteURL, err := parseGitHubRepo(repo)
+	lock, err := workspace.AcquireBackendLock(projectRoot)
 	if err != nil {
 		return err
 	}
+	defer lock.Release()
 
 	if createRepo {
 		if !hasGH() {
@@ -356,10 +514,11 @@ func runBackendSetGitHub(repo string, createRepo, privateRepo bool, remoteName s
 		return err
 	}
 	if exists {
-		if existingURL != remoteURL {
-			if !forceRemote {
-				return fmt.Errorf("remote '%s' already set to %s (use --force-remote to override)", remoteName, existingURL)
-			}
+		update, err := opts.reconcileRemote(existingURL, remoteURL)
+		if err != nil {
+			return err
+		}
+		if update {
 			if err := gitutil.RunCommand(projectRoot, "remote", "set-url", remoteName, remoteURL); err != nil {
 				return fmt.Errorf("failed to update remote '%s': %w", remoteName, err)
 			}
@@ -390,6 +549,103 @@ func runBackendSetGitHub(repo string, createRepo, privateRepo bool, remoteName s
 	return nil
 }
 
+// previewBackendSetGitHub reports what runBackendSetGitHub would do, without
+// changing anything. It fails where the real run would fail before pushing.
+func previewBackendSetGitHub(projectRoot, slug, remoteURL string, opts githubBackendOptions) error {
+	fmt.Println("Dry run - no changes will be made.")
+	fmt.Println()
+	fmt.Printf("Repository: %s (%s)\n", slug, remoteURL)
+	if !isGitHubSlug(slug) {
+		return fmt.Errorf("invalid GitHub repository %q (expected owner/repo)", slug)
+	}
+
+	switch {
+	case !hasGH():
+		if opts.createRepo {
+			return fmt.Errorf("gh CLI required to create repos (install gh)")
+		}
+		fmt.Println("GitHub:     unknown (gh not installed, cannot check the repository)")
+	case runGHCommand(projectRoot, "repo", "view", slug, "--json", "name") == nil:
+		if opts.createRepo {
+			fmt.Println("GitHub:     repository exists; --create would fail")
+		} else {
+			fmt.Println("GitHub:     repository exists")
+		}
+	case opts.createRepo:
+		visibility := "public"
+		if opts.privateRepo {
+			visibility = "private"
+		}
+		fmt.Printf("GitHub:     repository not found; would be created (%s)\n", visibility)
+	default:
+		fmt.Println(ui.Yellow("GitHub:     repository not found or not accessible; the push would fail (use --create)"))
+	}
+
+	gitInitialized := true
+	if _, err := os.Stat(filepath.Join(projectRoot, ".git")); os.IsNotExist(err) {
+		gitInitialized = false
+		fmt.Printf("Git:        would initialize a repository at %s\n", projectRoot)
+	}
+
+	if !gitInitialized {
+		fmt.Printf("Remote:     would add '%s' -> %s\n", opts.remoteName, remoteURL)
+	} else {
+		existingURL, exists, err := getGitRemoteURL(projectRoot, opts.remoteName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			fmt.Printf("Remote:     would add '%s' -> %s\n", opts.remoteName, remoteURL)
+		} else {
+			update, err := opts.reconcileRemote(existingURL, remoteURL)
+			if err != nil {
+				return err
+			}
+			if update {
+				fmt.Printf("Remote:     would change '%s' from %s to %s\n", opts.remoteName, existingURL, remoteURL)
+			} else {
+				fmt.Printf("Remote:     '%s' already points to %s\n", opts.remoteName, existingURL)
+			}
+		}
+	}
+
+	total, pending, err := countUnexportedSnapshots(projectRoot)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Export:     %d of %d snapshots would be exported as new git commits\n", pending, total)
+	fmt.Printf("Push:       workspace branches would be pushed to '%s'\n", opts.remoteName)
+	fmt.Printf("Config:     backend would be set to github (%s)\n", slug)
+	return nil
+}
+
+// countUnexportedSnapshots returns the number of snapshots reachable from
+// workspace heads and how many of them have no git commit yet.
+func countUnexportedSnapshots(projectRoot string) (total, pending int, err error) {
+	s := store.OpenAt(projectRoot)
+	workspaces, err := s.ListWorkspaces()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	var heads []string
+	for _, ws := range workspaces {
+		if ws.CurrentSnapshotID != "" {
+			heads = append(heads, ws.CurrentSnapshotID)
+		}
+	}
+	mapping, err := gitstore.LoadGitMapping(filepath.Join(projectRoot, ".fst"))
+	if err != nil {
+		return 0, 0, err
+	}
+	for id := range s.BuildReachableSet(heads) {
+		total++
+		if _, ok := mapping.Snapshots[id]; !ok {
+			pending++
+		}
+	}
+	return total, pending, nil
+}
+
 func runBackendSetGit() error {
 	projectRoot, parentCfg, err := findProjectRootAndConfig()
 	if err != nil {
@@ -435,12 +691,32 @@ func runBackendOff() error {
 	return nil
 }
 
-func runBackendStatus() error {
+func runBackendStatus(jsonOutput bool) error {
 	_, parentCfg, err := findProjectRootAndConfig()
 	if err != nil {
 		return err
 	}
 
+	if jsonOutput {
+		payload := map[string]any{
+			"configured": parentCfg.Backend != nil,
+			"type":       "",
+			"repo":       "",
+			"remote":     "",
+		}
+		if parentCfg.Backend != nil {
+			payload["type"] = parentCfg.Backend.Type
+			payload["repo"] = parentCfg.Backend.Repo
+			payload["remote"] = parentCfg.Backend.Remote
+		}
+		if parentCfg.LastSync != nil {
+			payload["last_sync"] = parentCfg.LastSync
+		}
+		enc, _ := json.MarshalIndent(payload, "", "  ")
+		fmt.Println(string(enc))
+		return nil
+	}
+
 	if parentCfg.Backend == nil {
 		fmt.Println("Backend: none")
 		return nil
@@ -453,9 +729,28 @@ func runBackendStatus() error {
 	if parentCfg.Backend.Remote != "" {
 		fmt.Printf("Remote:  %s\n", parentCfg.Backend.Remote)
 	}
+	if last := parentCfg.LastSync; last != nil {
+		fmt.Printf("Last %s: %s\n", last.Operation, formatSyncRecord(last))
+	}
 	return nil
 }
 
+// formatSyncRecord renders a last-sync record, e.g. "3 mins ago ✓ (2 commits)".
+func formatSyncRecord(rec *config.SyncRecord) string {
+	when := rec.Time
+	if t, err := time.Parse(time.RFC3339, rec.Time); err == nil {
+		when = formatTimeAgo(t)
+	}
+	if !rec.Success {
+		return fmt.Sprintf("%s ✗ %s", when, rec.Error)
+	}
+	noun := "commits"
+	if rec.Commits == 1 {
+		noun = "commit"
+	}
+	return fmt.Sprintf("%s ✓ (%d %s)", when, rec.Commits, noun)
+}
+
 func runBackendPush() error {
 	projectRoot, parentCfg, err := findProjectRootAndConfig()
 	if err != nil {
@@ -1,8 +1,11 @@
 package commands
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -212,6 +215,158 @@ func TestBackendSetGit(t *testing.T) {
 	}
 }
 
+func TestBackendSetGitHubDryRun(t *testing.T) {
+	projectRoot := t.TempDir()
+	if err := config.SaveProjectConfigAt(projectRoot, &config.ProjectConfig{
+		ProjectID:   "proj-dry-run",
+		ProjectName: "dry-run",
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+	s := store.OpenAt(projectRoot)
+	if err := s.EnsureDirs(); err != nil {
+		t.Fatalf("EnsureDirs: %v", err)
+	}
+	wsRoot := filepath.Join(projectRoot, "main")
+	if err := os.MkdirAll(wsRoot, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := config.InitAt(wsRoot, "proj-dry-run", "ws-1", "main", ""); err != nil {
+		t.Fatalf("InitAt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(wsRoot, "hello.txt"), []byte("world"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	wsCfg, err := config.LoadAt(wsRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	snapID, err := gitstore.CreateImportedSnapshot(s, wsRoot, wsCfg, nil, "initial", time.Now().UTC().Format(time.RFC3339), "Test", "test@test.com", "")
+	if err != nil {
+		t.Fatalf("CreateImportedSnapshot: %v", err)
+	}
+	_ = s.RegisterWorkspace(store.WorkspaceInfo{
+		WorkspaceID:       wsCfg.WorkspaceID,
+		WorkspaceName:     "main",
+		Path:              wsRoot,
+		CurrentSnapshotID: snapID,
+	})
+
+	restoreCwd := chdir(t, projectRoot)
+	defer restoreCwd()
+
+	var output string
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"backend", "set", "github", "owner/repo", "--dry-run"})
+		return cmd.Execute()
+	}, &output)
+	if err != nil {
+		t.Fatalf("backend set github --dry-run: %v", err)
+	}
+	for _, want := range []string{
+		"would initialize a repository",
+		"would add 'origin' -> https://github.com/owner/repo.git",
+		"1 of 1 snapshots would be exported",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, ".git")); !os.IsNotExist(err) {
+		t.Fatalf("expected no git repository to be created")
+	}
+	parentCfg, err := config.LoadProjectConfigAt(projectRoot)
+	if err != nil {
+		t.Fatalf("LoadProjectConfigAt: %v", err)
+	}
+	if parentCfg.Backend != nil {
+		t.Fatalf("expected backend config to be unchanged, got %+v", parentCfg.Backend)
+	}
+
+	if err := gitutil.RunCommand(projectRoot, "init"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	if err := gitutil.RunCommand(projectRoot, "remote", "add", "origin", "https://github.com/other/repo.git"); err != nil {
+		t.Fatalf("git remote add: %v", err)
+	}
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"backend", "set", "github", "owner/repo", "--dry-run"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--force-remote") {
+		t.Fatalf("expected conflicting remote to be reported, got %v", err)
+	}
+}
+
+func TestBackendSetGitHubSSH(t *testing.T) {
+	setenv(t, "XDG_CONFIG_HOME", t.TempDir())
+	projectRoot := t.TempDir()
+	if err := config.SaveProjectConfigAt(projectRoot, &config.ProjectConfig{
+		ProjectID:   "proj-ssh",
+		ProjectName: "ssh",
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+	if err := store.OpenAt(projectRoot).EnsureDirs(); err != nil {
+		t.Fatalf("EnsureDirs: %v", err)
+	}
+
+	restoreCwd := chdir(t, projectRoot)
+	defer restoreCwd()
+
+	dryRun := func(args ...string) (string, error) {
+		var output string
+		err := captureStdout(func() error {
+			cmd := NewRootCmd()
+			cmd.SetArgs(append([]string{"backend", "set", "github", "owner/repo", "--dry-run"}, args...))
+			return cmd.Execute()
+		}, &output)
+		return output, err
+	}
+
+	output, err := dryRun("--ssh")
+	if err != nil {
+		t.Fatalf("backend set github --ssh: %v", err)
+	}
+	if !strings.Contains(output, "would add 'origin' -> git@github.com:owner/repo.git") {
+		t.Fatalf("expected ssh remote URL, got:\n%s", output)
+	}
+
+	if err := config.SaveGlobalGitHubPrefs(&config.GitHubPrefs{Protocol: config.GitHubProtocolSSH}); err != nil {
+		t.Fatalf("SaveGlobalGitHubPrefs: %v", err)
+	}
+	output, err = dryRun()
+	if err != nil {
+		t.Fatalf("backend set github: %v", err)
+	}
+	if !strings.Contains(output, "would add 'origin' -> git@github.com:owner/repo.git") {
+		t.Fatalf("expected global preference to select ssh, got:\n%s", output)
+	}
+
+	// An https remote for the same repository is not a conflict.
+	if err := gitutil.RunCommand(projectRoot, "init"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	if err := gitutil.RunCommand(projectRoot, "remote", "add", "origin", "https://github.com/Owner/repo.git"); err != nil {
+		t.Fatalf("git remote add: %v", err)
+	}
+	output, err = dryRun()
+	if err != nil {
+		t.Fatalf("expected same repository over https to be accepted, got %v", err)
+	}
+	if !strings.Contains(output, "'origin' already points to https://github.com/Owner/repo.git") {
+		t.Fatalf("expected existing remote to be kept, got:\n%s", output)
+	}
+	output, err = dryRun("--ssh")
+	if err != nil {
+		t.Fatalf("backend set github --ssh: %v", err)
+	}
+	if !strings.Contains(output, "would change 'origin' from https://github.com/Owner/repo.git to git@github.com:owner/repo.git") {
+		t.Fatalf("expected --ssh to switch the remote, got:\n%s", output)
+	}
+}
+
 func TestBackendOff(t *testing.T) {
 	projectRoot := t.TempDir()
 	if err := config.SaveProjectConfigAt(projectRoot, &config.ProjectConfig{
@@ -274,6 +429,23 @@ func TestBackendStatus(t *testing.T) {
 	if err := cmd.Execute(); err != nil {
 		t.Fatalf("backend status: %v", err)
 	}
+
+	var out string
+	err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"backend", "status", "--json"})
+		return cmd.Execute()
+	}, &out)
+	if err != nil {
+		t.Fatalf("backend status --json: %v", err)
+	}
+	var status map[string]any
+	if err := json.Unmarshal([]byte(out), &status); err != nil {
+		t.Fatalf("parse backend status JSON: %v\n%s", err, out)
+	}
+	if status["configured"] != true || status["type"] != "github" || status["repo"] != "owner/repo" || status["remote"] != "origin" {
+		t.Fatalf("unexpected backend status JSON: %v", status)
+	}
 }
 
 func TestBackendAutoExport(t *testing.T) {
@@ -763,3 +935,147 @@ func trimAll(s string) string {
 	}
 	return result
 }
+
+func TestBuildOnDivergenceManualLeavesMergePending(t *testing.T) {
+	projectRoot := t.TempDir()
+	if err := config.SaveProjectConfigAt(projectRoot, &config.ProjectConfig{
+		ProjectID:   "proj-pending",
+		ProjectName: "pending-test",
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+	s := store.OpenAt(projectRoot)
+	if err := s.EnsureDirs(); err != nil {
+		t.Fatalf("EnsureDirs: %v", err)
+	}
+
+	wsRoot := filepath.Join(projectRoot, "main")
+	if err := os.MkdirAll(wsRoot, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := config.InitAt(wsRoot, "proj-pending", "ws-pending", "main", ""); err != nil {
+		t.Fatalf("InitAt: %v", err)
+	}
+	wsCfg, _ := config.LoadAt(wsRoot)
+	now := time.Now().UTC().Format(time.RFC3339)
+	target := filepath.Join(wsRoot, "test.txt")
+
+	if err := os.WriteFile(target, []byte("base\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	snapBase, err := gitstore.CreateImportedSnapshot(s, wsRoot, wsCfg, nil, "base", now, "Test", "test@test.com", "")
+	if err != nil {
+		t.Fatalf("base snapshot: %v", err)
+	}
+	if err := os.WriteFile(target, []byte("remote change\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	snapRemote, err := gitstore.CreateImportedSnapshot(s, wsRoot, wsCfg, []string{snapBase}, "remote", now, "Test", "test@test.com", "")
+	if err != nil {
+		t.Fatalf("remote snapshot: %v", err)
+	}
+	if err := os.WriteFile(target, []byte("local edit here\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	snapLocal, err := gitstore.CreateImportedSnapshot(s, wsRoot, wsCfg, []string{snapBase}, "local", now, "Test", "test@test.com", "")
+	if err != nil {
+		t.Fatalf("local snapshot: %v", err)
+	}
+	wsCfg.CurrentSnapshotID = snapLocal
+	if err := config.SaveAt(wsRoot, wsCfg); err != nil {
+		t.Fatalf("SaveAt: %v", err)
+	}
+
+	onDivergence := buildOnDivergence(ConflictModeManual)
+	mergedID, err := onDivergence(backend.DivergenceInfo{
+		ProjectRoot:   projectRoot,
+		WorkspaceName: "main",
+		WorkspaceRoot: wsRoot,
+		LocalHead:     snapLocal,
+		RemoteHead:    snapRemote,
+		MergeBase:     snapBase,
+	})
+	if !errors.Is(err, backend.ErrMergePending) {
+		t.Fatalf("expected ErrMergePending, got %v", err)
+	}
+	if mergedID != "" {
+		t.Fatalf("expected no merged snapshot, got %s", mergedID)
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(data), "<<<<<<<") {
+		t.Fatalf("expected conflict markers, got %q", data)
+	}
+
+	cfg, err := config.LoadAt(wsRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if cfg.CurrentSnapshotID != snapLocal {
+		t.Fatalf("expected head to stay at %s, got %s", snapLocal, cfg.CurrentSnapshotID)
+	}
+	parents, err := config.ReadPendingMergeParentsAt(wsRoot)
+	if err != nil {
+		t.Fatalf("ReadPendingMergeParentsAt: %v", err)
+	}
+	if len(parents) != 2 {
+		t.Fatalf("expected 2 pending merge parents, got %v", parents)
+	}
+
+	entries, err := config.ReadUndoStackAt(wsRoot)
+	if err != nil {
+		t.Fatalf("ReadUndoStackAt: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Operation != "sync" || entries[0].SnapshotID != snapLocal {
+		t.Fatalf("expected a sync undo point at %s, got %+v", snapLocal, entries)
+	}
+	restoreCwd := chdir(t, wsRoot)
+	defer restoreCwd()
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"undo"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "local edit here\n" {
+		t.Fatalf("expected undo to restore the local version, got %q", data)
+	}
+}
+
+func TestNewerSideMode(t *testing.T) {
+	s := store.OpenAt(t.TempDir())
+	if err := s.EnsureDirs(); err != nil {
+		t.Fatalf("EnsureDirs: %v", err)
+	}
+	for id, createdAt := range map[string]string{
+		"snap-old":   "2024-01-01T10:00:00Z",
+		"snap-new":   "2024-01-01T11:00:00Z",
+		"snap-same":  "2024-01-01T11:00:00Z",
+		"snap-bogus": "yesterday",
+	} {
+		if err := s.WriteSnapshotMeta(&store.SnapshotMeta{ID: id, CreatedAt: createdAt}); err != nil {
+			t.Fatalf("WriteSnapshotMeta: %v", err)
+		}
+	}
+
+	cases := []struct {
+		local, remote string
+		want          ConflictMode
+	}{
+		{"snap-new", "snap-old", ConflictModeOurs},
+		{"snap-old", "snap-new", ConflictModeTheirs},
+		{"snap-new", "snap-same", ConflictModeOurs},
+		{"snap-bogus", "snap-old", ConflictModeTheirs},
+		{"snap-missing", "snap-old", ConflictModeTheirs},
+	}
+	for _, c := range cases {
+		if got := newerSideMode(s, c.local, c.remote); got != c.want {
+			t.Fatalf("newerSideMode(%s, %s) = %v, want %v", c.local, c.remote, got, c.want)
+		}
+	}
+}
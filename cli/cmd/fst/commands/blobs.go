@@ -0,0 +1,420 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
+)
+
+func init() {
+	register(func(root *cobra.Command) { root.AddCommand(newBlobsCmd()) })
+}
+
+func newBlobsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "blobs",
+		Aliases: []string{"blob"},
+		Short:   "Inspect the project's shared blob store",
+	}
+
+	cmd.AddCommand(newBlobsDuCmd())
+	cmd.AddCommand(newBlobsImportCmd())
+	cmd.AddCommand(newBlobsExportCmd())
+	cmd.AddCommand(newBlobsVerifyCmd())
+	cmd.AddCommand(newBlobsPruneCmd())
+
+	return cmd
+}
+
+func newBlobsDuCmd() *cobra.Command {
+	var byWorkspace bool
+
+	cmd := &cobra.Command{
+		Use:   "du",
+		Short: "Report disk usage of the shared store",
+		Long: `Report the disk usage of the project's shared store: total bytes and
+number of blobs, manifests and snapshots.
+
+With --by-workspace, blob bytes are attributed to the workspace whose history
+reaches them. Blobs reachable from more than one workspace are counted once
+under "shared"; blobs reachable from none are reported as unreferenced and
+can be reclaimed with 'fst gc'.
+
+Must be run from within a project folder.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBlobsDu(byWorkspace)
+		},
+	}
+
+	cmd.Flags().BoolVar(&byWorkspace, "by-workspace", false, "Attribute blob bytes to workspaces")
+
+	return cmd
+}
+
+func runBlobsDu(byWorkspace bool) error {
+	projectRoot, err := findBlobsProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	s := store.OpenAt(projectRoot)
+	usage, err := s.DiskUsage()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Blobs:      %6d  %s\n", usage.Blobs.Count, formatBytes(usage.Blobs.Bytes))
+	fmt.Printf("Manifests:  %6d  %s\n", usage.Manifests.Count, formatBytes(usage.Manifests.Bytes))
+	fmt.Printf("Snapshots:  %6d  %s\n", usage.Snapshots.Count, formatBytes(usage.Snapshots.Bytes))
+	fmt.Printf("Total:              %s\n", formatBytes(usage.TotalBytes()))
+
+	if !byWorkspace {
+		return nil
+	}
+
+	byWS, err := s.UsageByWorkspace()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("Blob usage by workspace:")
+	for _, ws := range byWS.Workspaces {
+		name := ws.WorkspaceName
+		if name == "" {
+			name = ws.WorkspaceID
+		}
+		fmt.Printf("  %-20s %6d  %s\n", name, ws.Blobs.Count, formatBytes(ws.Blobs.Bytes))
+	}
+	fmt.Printf("  %-20s %6d  %s\n", "shared", byWS.Shared.Count, formatBytes(byWS.Shared.Bytes))
+	if byWS.Unreferenced.Count > 0 {
+		fmt.Printf("  %-20s %6d  %s\n", "unreferenced", byWS.Unreferenced.Count, formatBytes(byWS.Unreferenced.Bytes))
+	}
+
+	return nil
+}
+
+func newBlobsImportCmd() *cobra.Command {
+	var expect string
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Store a file's content as a blob and print its hash",
+		Long: `Hash a file, store its content in the project's blob store and print
+the hash.
+
+Use --expect to repair a store by re-adding a known-good copy of a missing
+or corrupt blob: the file is only imported if its content hashes to the
+expected value. A stored blob whose content no longer matches its hash is
+replaced atomically.
+
+Must be run from within a project folder.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBlobsImport(args[0], expect)
+		},
+	}
+
+	cmd.Flags().StringVar(&expect, "expect", "", "Only import if the content hashes to this value")
+
+	return cmd
+}
+
+func runBlobsImport(path, expect string) error {
+	if expect != "" && !isBlobHash(expect) {
+		return fmt.Errorf("invalid blob hash: %s", expect)
+	}
+
+	projectRoot, err := findBlobsProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if expect != "" && !strings.EqualFold(hash, expect) {
+		return fmt.Errorf("%s hashes to %s, expected %s", path, hash, strings.ToLower(expect))
+	}
+
+	// The content was just hashed, so a stored copy that no longer
+	// matches its hash is replaced rather than kept.
+	blobs := store.OpenAt(projectRoot).Blobs()
+	if blobs.Has(hash) && !blobMatches(blobs, hash) {
+		if err := blobs.Overwrite(hash, data); err != nil {
+			return fmt.Errorf("failed to replace corrupt blob: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Replaced corrupt blob %s\n", hash)
+	} else if err := blobs.PutHashed(hash, data); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+
+	fmt.Println(hash)
+	return nil
+}
+
+func newBlobsExportCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export <hash>",
+		Short: "Write a blob's content to a file or stdout",
+		Long: `Write the content of the blob stored under <hash> to the file given by
+-o, or to stdout if -o is not set. The content is checked against the hash
+before it is written.
+
+Must be run from within a project folder.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBlobsExport(args[0], output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the blob to this file instead of stdout")
+
+	return cmd
+}
+
+func runBlobsExport(hash, output string) error {
+	if !isBlobHash(hash) {
+		return fmt.Errorf("invalid blob hash: %s", hash)
+	}
+	hash = strings.ToLower(hash)
+
+	projectRoot, err := findBlobsProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	data, err := store.OpenAt(projectRoot).Blobs().Get(hash)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != hash {
+		return fmt.Errorf("blob %s is corrupt (content hashes to %s)", hash, got)
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	return nil
+}
+
+func newBlobsVerifyCmd() *cobra.Command {
+	var keepGoing bool
+	var failFast bool
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the blob store for corrupt or missing blobs",
+		Long: `Check that every stored blob's content matches its hash and that every
+blob referenced by a snapshot is present.
+
+By default (--keep-going) the whole store is scanned and all problems are
+listed in a summary at the end, so one run is a full health audit. Use
+--fail-fast to stop at the first problem. Exits with status 1 if any
+problem was found; corrupt or missing blobs can be repaired with
+'fst blobs import --expect <hash>'.
+
+Must be run from within a project folder.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBlobsVerify(failFast || !keepGoing)
+		},
+	}
+
+	cmd.Flags().BoolVar(&keepGoing, "keep-going", true, "Scan the whole store and report every problem")
+	cmd.Flags().BoolVar(&failFast, "fail-fast", false, "Stop at the first problem (same as --keep-going=false)")
+
+	return cmd
+}
+
+func runBlobsVerify(failFast bool) error {
+	projectRoot, err := findBlobsProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	result, err := store.OpenAt(projectRoot).VerifyBlobs(store.VerifyBlobsOpts{FailFast: failFast})
+	if err != nil {
+		return err
+	}
+
+	if len(result.Problems) == 0 {
+		fmt.Printf("All %d blobs OK.\n", result.Checked)
+		return nil
+	}
+
+	var corrupt, missing int
+	for _, p := range result.Problems {
+		switch p.Kind {
+		case store.BlobCorrupt:
+			corrupt++
+		case store.BlobMissing:
+			missing++
+		}
+		fmt.Printf("%-8s %s  %s\n", p.Kind, p.Hash, p.Detail)
+	}
+	fmt.Println()
+	if failFast {
+		fmt.Println("Stopped at the first problem (--fail-fast).")
+	}
+	fmt.Printf("Checked %d blobs: %d corrupt, %d missing.\n", result.Checked, corrupt, missing)
+	return SilentExit(1)
+}
+
+func newBlobsPruneCmd() *cobra.Command {
+	var unreachable bool
+	var olderThan string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete old blobs that no snapshot references",
+		Long: `Delete blobs that are not referenced by any snapshot reachable from a
+workspace and whose file is older than --older-than.
+
+Like 'fst gc', prune takes the exclusive project lock, so it waits for
+running workspace operations and no snapshot can write or reuse a blob
+while it runs. Reachability alone decides what is garbage; --older-than
+only narrows it further, e.g. to keep blobs of a recently abandoned
+snapshot. Unlike 'fst gc', snapshots, manifests and packed blobs are left
+untouched.
+
+--older-than accepts a number of days (e.g. 30d) or a Go duration
+(e.g. 12h). Use --dry-run to see what would be reclaimed.
+
+Must be run from within a project folder.
+
+Examples:
+  fst blobs prune --unreachable --older-than 30d
+  fst blobs prune --unreachable --older-than 12h --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !unreachable {
+				return fmt.Errorf("only unreachable blobs can be pruned - pass --unreachable")
+			}
+			if olderThan == "" {
+				return fmt.Errorf("--older-than is required (e.g. --older-than 30d)")
+			}
+			age, err := parseAge(olderThan)
+			if err != nil {
+				return err
+			}
+			return runBlobsPrune(age, dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&unreachable, "unreachable", false, "Prune blobs no reachable snapshot references")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Only prune blobs older than this (e.g. 30d, 12h)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be pruned without deleting")
+
+	return cmd
+}
+
+func runBlobsPrune(olderThan time.Duration, dryRun bool) error {
+	projectRoot, err := findBlobsProjectRoot()
+	if err != nil {
+		return err
+	}
+
+	// Same exclusive lock as gc: no workspace operation can add or reuse
+	// blobs while reachability is computed and blobs are removed.
+	lock, err := workspace.AcquireGCLock(projectRoot)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	result, err := store.OpenAt(projectRoot).PruneBlobs(store.PruneBlobsOpts{
+		OlderThan: olderThan,
+		DryRun:    dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		for _, hash := range result.Pruned {
+			fmt.Println(hash)
+		}
+		fmt.Printf("Would prune %d blobs (%s).\n", len(result.Pruned), formatBytes(result.Bytes))
+	} else {
+		fmt.Printf("Pruned %d blobs, reclaimed %s.\n", len(result.Pruned), formatBytes(result.Bytes))
+	}
+	if result.SkippedRecent > 0 {
+		fmt.Printf("Kept %d unreachable blobs newer than the age threshold.\n", result.SkippedRecent)
+	}
+	return nil
+}
+
+// parseAge parses an age such as "30d" or a Go duration such as "12h".
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n >= 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid age %q (use e.g. 30d or 12h)", s)
+}
+
+// findBlobsProjectRoot returns the root of the project containing the
+// current directory.
+func findBlobsProjectRoot() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	projectRoot, _, err := config.FindProjectRootFrom(cwd)
+	if err != nil {
+		if errors.Is(err, config.ErrProjectNotFound) {
+			return "", fmt.Errorf("not in a project folder - run 'fst project init' first")
+		}
+		return "", err
+	}
+	return projectRoot, nil
+}
+
+// blobMatches reports whether the blob stored under hash can be read and
+// its content hashes to hash.
+func blobMatches(blobs store.BlobStore, hash string) bool {
+	data, err := blobs.Get(hash)
+	if err != nil {
+		return false
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) == hash
+}
+
+// isBlobHash reports whether hash looks like a hex SHA-256 blob hash.
+func isBlobHash(hash string) bool {
+	if len(hash) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
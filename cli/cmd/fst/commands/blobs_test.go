@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+func TestBlobsImportExport(t *testing.T) {
+	root := t.TempDir()
+	if err := config.SaveProjectConfigAt(root, &config.ProjectConfig{
+		ProjectID:   "proj-blobs",
+		ProjectName: "blobs",
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+	if err := store.OpenAt(root).EnsureDirs(); err != nil {
+		t.Fatalf("EnsureDirs: %v", err)
+	}
+
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	input := filepath.Join(root, "input.txt")
+	if err := os.WriteFile(input, []byte("blob content\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	var out string
+	err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"blob", "import", input})
+		return cmd.Execute()
+	}, &out)
+	if err != nil {
+		t.Fatalf("blob import failed: %v", err)
+	}
+	hash := strings.TrimSpace(out)
+	if !store.OpenAt(root).BlobExists(hash) {
+		t.Fatalf("expected blob %q to be stored", hash)
+	}
+
+	output := filepath.Join(root, "output.txt")
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"blob", "export", hash, "-o", output})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("blob export failed: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "blob content\n" {
+		t.Fatalf("unexpected exported content: %q", data)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"blob", "import", input, "--expect", strings.Repeat("0", 64)})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected import with mismatched --expect to fail")
+	}
+
+	// A corrupt stored copy is repaired by re-importing the good content.
+	if err := os.WriteFile(store.OpenAt(root).BlobPath(hash), []byte("corrupt"), 0644); err != nil {
+		t.Fatalf("corrupt blob: %v", err)
+	}
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"blob", "import", input, "--expect", hash})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("blob import --expect failed: %v", err)
+	}
+	data, err = store.OpenAt(root).ReadBlob(hash)
+	if err != nil || string(data) != "blob content\n" {
+		t.Fatalf("expected the corrupt blob to be repaired, got %q (%v)", data, err)
+	}
+}
+
+func TestBlobsVerify(t *testing.T) {
+	root := t.TempDir()
+	if err := config.SaveProjectConfigAt(root, &config.ProjectConfig{
+		ProjectID:   "proj-verify",
+		ProjectName: "verify",
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+	s := store.OpenAt(root)
+	if err := s.EnsureDirs(); err != nil {
+		t.Fatalf("EnsureDirs: %v", err)
+	}
+
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	var hashes []string
+	for _, content := range []string{"first", "second"} {
+		hash, err := s.Blobs().Put([]byte(content))
+		if err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	var out string
+	err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"blobs", "verify"})
+		return cmd.Execute()
+	}, &out)
+	if err != nil {
+		t.Fatalf("verify of a clean store failed: %v\n%s", err, out)
+	}
+
+	for _, hash := range hashes {
+		if err := os.WriteFile(s.BlobPath(hash), []byte("tampered "+hash), 0644); err != nil {
+			t.Fatalf("corrupt blob: %v", err)
+		}
+	}
+
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"blobs", "verify"})
+		return cmd.Execute()
+	}, &out)
+	if ExitCode(err) != 1 {
+		t.Fatalf("expected exit code 1, got %v", err)
+	}
+	if !strings.Contains(out, "2 corrupt, 0 missing") {
+		t.Fatalf("expected both corrupt blobs in the summary, got:\n%s", out)
+	}
+
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"blobs", "verify", "--fail-fast"})
+		return cmd.Execute()
+	}, &out)
+	if ExitCode(err) != 1 {
+		t.Fatalf("expected exit code 1, got %v", err)
+	}
+	if !strings.Contains(out, "1 corrupt, 0 missing") {
+		t.Fatalf("expected --fail-fast to stop after one problem, got:\n%s", out)
+	}
+}
+
+func TestBlobsPrune(t *testing.T) {
+	root := t.TempDir()
+	if err := config.SaveProjectConfigAt(root, &config.ProjectConfig{
+		ProjectID:   "proj-prune",
+		ProjectName: "prune",
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+	s := store.OpenAt(root)
+	if err := s.EnsureDirs(); err != nil {
+		t.Fatalf("EnsureDirs: %v", err)
+	}
+	if err := s.WriteSnapshotMeta(&store.SnapshotMeta{ID: "snap-1"}); err != nil {
+		t.Fatalf("WriteSnapshotMeta: %v", err)
+	}
+
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	hash, err := s.Blobs().Put([]byte("unreferenced"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	past := time.Now().Add(-40 * 24 * time.Hour)
+	if err := os.Chtimes(s.BlobPath(hash), past, past); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"blobs", "prune", "--older-than", "30d"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected prune without --unreachable to fail")
+	}
+
+	var out string
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"blobs", "prune", "--unreachable", "--older-than", "30d"})
+		return cmd.Execute()
+	}, &out)
+	if err != nil {
+		t.Fatalf("blobs prune failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Pruned 1 blobs") {
+		t.Fatalf("expected one blob to be pruned, got:\n%s", out)
+	}
+	if s.BlobExists(hash) {
+		t.Fatal("expected the old unreferenced blob to be deleted")
+	}
+}
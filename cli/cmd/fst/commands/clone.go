@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ankitiscracked/fastest/cli/internal/backend"
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/gitstore"
+	"github.com/ankitiscracked/fastest/cli/internal/gitutil"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+func init() {
+	register(func(root *cobra.Command) { root.AddCommand(newCloneCmd()) })
+}
+
+func newCloneCmd() *cobra.Command {
+	var remoteName string
+
+	cmd := &cobra.Command{
+		Use:   "clone <remote-url> [dir]",
+		Short: "Set up a project from a remote exported by fst",
+		Long: `Create a project from a git remote that fst has exported to.
+
+The remote's branches and export metadata (refs/fst/meta) are fetched into
+a new git repository at <dir>, every exported workspace is imported with
+its snapshot history, and each workspace directory is populated with the
+files of its latest snapshot. The remote is then configured as the
+project's github backend, so 'fst pull', 'fst push' and 'fst sync' work
+right away.
+
+<remote-url> can be a GitHub owner/repo, a GitHub URL, or any git URL.
+An owner/repo is fetched over HTTPS, or over SSH when the global
+github.protocol setting is ssh. <dir> defaults to the repository name and
+must not exist yet.
+
+Examples:
+  fst clone owner/repo                          # Clone into ./repo
+  fst clone https://github.com/owner/repo my-proj
+  fst clone git@example.com:team/app.git`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := ""
+			if len(args) > 1 {
+				dir = args[1]
+			}
+			return runClone(args[0], dir, remoteName)
+		},
+	}
+
+	cmd.Flags().StringVar(&remoteName, "remote", "origin", "Name of the git remote to create")
+
+	return cmd
+}
+
+func runClone(remote, dir, remoteName string) error {
+	slug, remoteURL := "", remote
+	if isGitHubSlug(remote) || strings.Contains(remote, "github.com") {
+		var err error
+		slug, remoteURL, err = parseGitHubRepo(remote)
+		if err != nil {
+			return err
+		}
+		if isGitHubSlug(remote) {
+			remoteURL = githubRemoteURL(slug, config.PrefersGitHubSSH())
+		}
+	}
+
+	if dir == "" {
+		dir = cloneDirName(remoteURL)
+	}
+	projectRoot, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target directory: %w", err)
+	}
+	if _, err := os.Stat(projectRoot); err == nil {
+		return fmt.Errorf("target directory already exists: %s", projectRoot)
+	}
+	if err := os.MkdirAll(projectRoot, 0755); err != nil {
+		return fmt.Errorf("failed to create project directory: %w", err)
+	}
+
+	fmt.Printf("Cloning %s into %s...\n", remoteURL, projectRoot)
+	if err := cloneProject(projectRoot, remoteURL, remoteName, slug); err != nil {
+		os.RemoveAll(projectRoot)
+		return err
+	}
+	return nil
+}
+
+// cloneProject fetches remoteURL into a new git repository at projectRoot
+// and builds the project from its export metadata.
+func cloneProject(projectRoot, remoteURL, remoteName, slug string) error {
+	if err := gitutil.RunCommand(projectRoot, "init", "-q"); err != nil {
+		return fmt.Errorf("failed to initialize git repository: %w", err)
+	}
+	if err := gitutil.RunCommand(projectRoot, "remote", "add", remoteName, remoteURL); err != nil {
+		return fmt.Errorf("failed to add remote '%s': %w", remoteName, err)
+	}
+	if err := backend.FetchFromRemote(projectRoot, remoteName); err != nil {
+		return fmt.Errorf("failed to fetch from remote: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp("", "fst-clone-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+	git := gitutil.NewEnv(projectRoot, tempDir, filepath.Join(tempDir, "index"))
+
+	meta, err := gitstore.LoadExportMetadata(git)
+	if err != nil {
+		return fmt.Errorf("failed to load fst export metadata: %w", err)
+	}
+	if meta == nil || len(meta.Workspaces) == 0 {
+		return fmt.Errorf("no fst export metadata found on remote (missing refs/fst/meta)")
+	}
+
+	projectID := meta.ProjectID
+	if projectID == "" {
+		projectID = generateProjectID()
+	}
+	parentCfg := &config.ProjectConfig{
+		ProjectID:   projectID,
+		ProjectName: filepath.Base(projectRoot),
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Backend: &config.BackendConfig{
+			Type:   "github",
+			Repo:   slug,
+			Remote: remoteName,
+		},
+	}
+	if err := config.SaveProjectConfigAt(projectRoot, parentCfg); err != nil {
+		return fmt.Errorf("failed to create project: %w", err)
+	}
+	s := store.OpenAt(projectRoot)
+	if err := s.EnsureDirs(); err != nil {
+		return fmt.Errorf("failed to create store directories: %w", err)
+	}
+
+	if err := backend.FastForwardBranches(projectRoot, remoteName); err != nil {
+		return fmt.Errorf("failed to create local branches: %w", err)
+	}
+	if _, err := backend.IncrementalImportFromGit(projectRoot); err != nil {
+		return err
+	}
+
+	// The import records snapshots only; check out each workspace's head.
+	for _, entry := range meta.Workspaces {
+		name := entry.WorkspaceName
+		if name == "" {
+			name = entry.Branch
+		}
+		wsRoot := filepath.Join(projectRoot, name)
+		wsCfg, err := config.LoadAt(wsRoot)
+		if err != nil || wsCfg.CurrentSnapshotID == "" {
+			continue
+		}
+		m, err := loadRestorableManifest(s, wsCfg.CurrentSnapshotID)
+		if err != nil {
+			return err
+		}
+		if err := gitstore.RestoreFilesFromManifest(wsRoot, s, m, gitstore.RestoreOptions{}); err != nil {
+			return fmt.Errorf("failed to restore files for workspace '%s': %w", name, err)
+		}
+		fmt.Printf("Checked out workspace '%s' (%d files)\n", name, len(m.FileEntries()))
+	}
+
+	fmt.Println()
+	if slug != "" {
+		fmt.Printf("✓ Cloned project '%s' with backend github (%s)\n", parentCfg.ProjectName, slug)
+	} else {
+		fmt.Printf("✓ Cloned project '%s' with backend github (%s)\n", parentCfg.ProjectName, remoteURL)
+	}
+	return nil
+}
+
+// cloneDirName derives a project directory name from a remote URL, the way
+// git clone does: the last path component without a .git suffix.
+func cloneDirName(remoteURL string) string {
+	name := strings.TrimRight(remoteURL, "/")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.TrimSuffix(name, ".git")
+}
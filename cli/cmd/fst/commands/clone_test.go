@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+)
+
+func TestClone(t *testing.T) {
+	repo := setupExportRepo(t, "proj-clone", map[string][]commitSpec{
+		"main": {
+			{Message: "main work", Files: map[string]string{"main.txt": "hello"}},
+		},
+		"feature": {
+			{Message: "feature work", Files: map[string]string{"feature.txt": "world"}},
+		},
+	})
+
+	root := t.TempDir()
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"clone", repo, "cloned"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("clone failed: %v", err)
+	}
+
+	projectRoot := filepath.Join(root, "cloned")
+	parentCfg, err := config.LoadProjectConfigAt(projectRoot)
+	if err != nil {
+		t.Fatalf("LoadProjectConfigAt: %v", err)
+	}
+	if parentCfg.ProjectID != "proj-clone" {
+		t.Fatalf("expected project ID from export metadata, got %s", parentCfg.ProjectID)
+	}
+	if parentCfg.BackendType() != "github" || parentCfg.Backend.Remote != "origin" {
+		t.Fatalf("expected github backend on origin, got %+v", parentCfg.Backend)
+	}
+
+	others := map[string]string{"main": "feature.txt", "feature": "main.txt"}
+	for wsName, file := range map[string]string{"main": "main.txt", "feature": "feature.txt"} {
+		wsRoot := filepath.Join(projectRoot, wsName)
+		cfg, err := config.LoadAt(wsRoot)
+		if err != nil {
+			t.Fatalf("LoadAt %s: %v", wsName, err)
+		}
+		if cfg.CurrentSnapshotID == "" {
+			t.Fatalf("expected snapshots imported for %s", wsName)
+		}
+		for _, name := range []string{"base.txt", file} {
+			if _, err := os.Stat(filepath.Join(wsRoot, name)); err != nil {
+				t.Fatalf("expected %s checked out in %s: %v", name, wsName, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(wsRoot, others[wsName])); !os.IsNotExist(err) {
+			t.Fatalf("%s should not contain %s", wsName, others[wsName])
+		}
+	}
+
+	// The backend is usable straight away.
+	restoreWs := chdir(t, filepath.Join(projectRoot, "main"))
+	defer restoreWs()
+	if err := runPull(0); err != nil {
+		t.Fatalf("pull after clone: %v", err)
+	}
+
+	// Cloning into an existing directory is refused.
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"clone", repo, filepath.Join(root, "cloned")})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected clone into existing directory to fail")
+	}
+}
+
+func TestCloneUsesGitHubProtocolPreference(t *testing.T) {
+	setenv(t, "XDG_CONFIG_HOME", t.TempDir())
+	// Fail the fetch at once instead of reaching out to GitHub.
+	setenv(t, "GIT_SSH_COMMAND", "false")
+	if err := config.SaveGlobalGitHubPrefs(&config.GitHubPrefs{Protocol: config.GitHubProtocolSSH}); err != nil {
+		t.Fatalf("SaveGlobalGitHubPrefs: %v", err)
+	}
+
+	root := t.TempDir()
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	var output string
+	err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"clone", "owner/repo"})
+		return cmd.Execute()
+	}, &output)
+	if err == nil {
+		t.Fatal("expected the clone to fail without a reachable remote")
+	}
+	if !strings.Contains(output, "Cloning git@github.com:owner/repo.git") {
+		t.Fatalf("expected an ssh remote URL, got:\n%s", output)
+	}
+}
+
+func TestCloneRequiresMetadata(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init")
+	runGit(t, repo, "config", "user.name", "Test")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("hi"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "-m", "init")
+
+	root := t.TempDir()
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"clone", repo, "plain"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected clone without export metadata to fail")
+	}
+	if _, err := os.Stat(filepath.Join(root, "plain")); !os.IsNotExist(err) {
+		t.Fatalf("failed clone should remove the target directory")
+	}
+}
+
+func TestCloneDirName(t *testing.T) {
+	cases := map[string]string{
+		"https://github.com/owner/repo.git": "repo",
+		"git@example.com:team/app.git":      "app",
+		"/srv/git/project/":                 "project",
+	}
+	for in, want := range cases {
+		if got := cloneDirName(in); got != want {
+			t.Fatalf("cloneDirName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
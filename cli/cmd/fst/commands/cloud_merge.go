@@ -15,6 +15,8 @@ import (
 	"github.com/ankitiscracked/fastest/cli/internal/agent"
 	"github.com/ankitiscracked/fastest/cli/internal/config"
 	"github.com/ankitiscracked/fastest/cli/internal/manifest"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
 )
 
 // mergeAction represents a single file merge action for cloud sync/pull.
@@ -35,79 +37,41 @@ type mergeActions struct {
 	skipped   []mergeAction
 }
 
+// computeMergeActions classifies the three-way diff of base, current and
+// source (see manifest.ThreeWayDiff) for cloud sync/pull. Unlike 'fst merge',
+// sync never deletes local files: a file deleted locally but still present
+// in the source is restored from it, and files the source deleted are kept.
 func computeMergeActions(base, current, source *manifest.Manifest) *mergeActions {
 	result := &mergeActions{}
 
-	baseFiles := make(map[string]manifest.FileEntry)
-	for _, f := range base.FileEntries() {
-		baseFiles[f.Path] = f
-	}
-
-	currentFiles := make(map[string]manifest.FileEntry)
-	for _, f := range current.FileEntries() {
-		currentFiles[f.Path] = f
-	}
-
-	sourceFiles := make(map[string]manifest.FileEntry)
-	for _, f := range source.FileEntries() {
-		sourceFiles[f.Path] = f
-	}
-
-	allPaths := make(map[string]bool)
-	for path := range baseFiles {
-		allPaths[path] = true
-	}
-	for path := range currentFiles {
-		allPaths[path] = true
-	}
-	for path := range sourceFiles {
-		allPaths[path] = true
-	}
-
-	for path := range allPaths {
-		baseFile, inBase := baseFiles[path]
-		currentFile, inCurrent := currentFiles[path]
-		sourceFile, inSource := sourceFiles[path]
-
-		action := mergeAction{path: path}
-		if inBase {
-			action.baseHash = baseFile.Hash
+	for _, c := range manifest.ThreeWayDiff(base, current, source) {
+		action := mergeAction{
+			path:       c.Path,
+			sourceMode: c.Mode,
 		}
-		if inCurrent {
-			action.currentHash = currentFile.Hash
+		if c.Base != nil {
+			action.baseHash = c.Base.Hash
 		}
-		if inSource {
-			action.sourceHash = sourceFile.Hash
-			action.sourceMode = sourceFile.Mode
+		if c.Current != nil {
+			action.currentHash = c.Current.Hash
+		}
+		if c.Source != nil {
+			action.sourceHash = c.Source.Hash
 		}
 
-		currentChanged := !inBase && inCurrent || (inBase && inCurrent && baseFile.Hash != currentFile.Hash)
-		sourceChanged := !inBase && inSource || (inBase && inSource && baseFile.Hash != sourceFile.Hash)
-		currentDeleted := inBase && !inCurrent
-		sourceDeleted := inBase && !inSource
-
+		kind := c.Kind
 		switch {
-		case !inSource && !sourceDeleted:
-			continue
-		case !inCurrent && inSource:
-			action.actionType = "apply"
-			result.toApply = append(result.toApply, action)
-		case currentDeleted && inSource:
-			action.actionType = "conflict"
-			result.conflicts = append(result.conflicts, action)
-		case sourceDeleted && inCurrent:
-			action.actionType = "in_sync"
-			result.inSync = append(result.inSync, action)
-		case inCurrent && inSource && currentFile.Hash == sourceFile.Hash:
-			action.actionType = "in_sync"
-			result.inSync = append(result.inSync, action)
-		case !currentChanged && sourceChanged:
+		case c.Current == nil && c.Source != nil:
+			kind = manifest.ChangeApply
+		case c.Source == nil:
+			kind = manifest.ChangeInSync
+		}
+
+		switch kind {
+		case manifest.ChangeApply:
 			action.actionType = "apply"
 			result.toApply = append(result.toApply, action)
-		case currentChanged && !sourceChanged:
-			action.actionType = "in_sync"
-			result.inSync = append(result.inSync, action)
-		case currentChanged && sourceChanged:
+		case manifest.ChangeConflict:
 			action.actionType = "conflict"
 			result.conflicts = append(result.conflicts, action)
 		default:
@@ -135,14 +99,23 @@ func printCloudMergePlan(actions *mergeActions) {
 	}
 }
 
+// projectBlobStore returns the blob store of the project containing the
+// current workspace.
+func projectBlobStore() (store.BlobStore, error) {
+	root, err := config.FindWorkspaceRoot()
+	if err != nil {
+		return nil, err
+	}
+	return store.OpenFromWorkspace(root).Blobs(), nil
+}
+
 func readSnapshotContent(root, relPath, expectedHash string, mode uint32) ([]byte, os.FileMode, error) {
 	if expectedHash == "" {
 		return nil, 0, os.ErrNotExist
 	}
 
-	if blobDir, err := config.GetBlobsDir(); err == nil {
-		blobPath := filepath.Join(blobDir, expectedHash)
-		if data, err := os.ReadFile(blobPath); err == nil {
+	if blobs, err := projectBlobStore(); err == nil {
+		if data, err := blobs.Get(expectedHash); err == nil {
 			return data, cloudFileModeOrDefault(mode, 0644), nil
 		}
 	}
@@ -188,7 +161,7 @@ func applyChange(currentRoot, sourceRoot string, action mergeAction) error {
 		return fmt.Errorf("failed to read source snapshot: %w", err)
 	}
 
-	return os.WriteFile(currentPath, content, mode)
+	return workspace.WriteFileMode(currentPath, content, mode)
 }
 
 func resolveConflictWithAgent(currentRoot, sourceRoot string, action mergeAction, ag *agent.Agent, baseManifest *manifest.Manifest, invoke agent.InvokeFunc) error {
@@ -206,10 +179,8 @@ func resolveConflictWithAgent(currentRoot, sourceRoot string, action mergeAction
 
 	var baseContent []byte
 	if action.baseHash != "" {
-		blobDir, err := config.GetBlobsDir()
-		if err == nil {
-			blobPath := filepath.Join(blobDir, action.baseHash)
-			baseContent, _ = os.ReadFile(blobPath)
+		if blobs, err := projectBlobStore(); err == nil {
+			baseContent, _ = blobs.Get(action.baseHash)
 		}
 	}
 
@@ -233,16 +204,7 @@ func resolveConflictWithAgent(currentRoot, sourceRoot string, action mergeAction
 
 	showMergeDiff(string(currentContent), mergeResult.MergedCode)
 
-	mode := cloudFileModeOrDefault(action.sourceMode, 0)
-	if mode == 0 {
-		if info, err := os.Stat(currentPath); err == nil {
-			mode = info.Mode()
-		} else {
-			mode = 0644
-		}
-	}
-
-	return os.WriteFile(currentPath, []byte(mergeResult.MergedCode), mode)
+	return workspace.WriteFileMode(currentPath, []byte(mergeResult.MergedCode), workspace.ConflictFileMode(currentPath, action.sourceMode))
 }
 
 func createConflictMarkers(currentRoot, sourceRoot string, action mergeAction) error {
@@ -280,7 +242,7 @@ func createConflictMarkers(currentRoot, sourceRoot string, action mergeAction) e
 		return err
 	}
 
-	return os.WriteFile(currentPath, []byte(result.String()), 0644)
+	return workspace.WriteFileMode(currentPath, []byte(result.String()), workspace.ConflictFileMode(currentPath, action.sourceMode))
 }
 
 func normalizeMergeParents(parents ...string) []string {
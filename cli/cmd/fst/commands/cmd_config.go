@@ -25,6 +25,7 @@ func newConfigCmd() *cobra.Command {
 
 With no arguments, opens an interactive form.
 Use 'set' to set a specific field, 'get' to show fields.
+Use 'edit' to open the project or workspace config in $EDITOR.
 
 Examples:
   fst config                              # interactive form (project-level)
@@ -33,7 +34,9 @@ Examples:
   fst config set email "john@example.com" # set project-level email
   fst config set --global name "John Doe" # set global name
   fst config get                          # show resolved author
-  fst config get name                     # show specific field`,
+  fst config get name                     # show specific field
+  fst config set --global github.protocol ssh  # SSH remotes for new github backends
+  fst config edit                         # edit .fst/config.json`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runConfigInteractive(global)
@@ -43,6 +46,7 @@ Examples:
 	cmd.Flags().BoolVar(&global, "global", false, "Set globally (~/.config/fst/)")
 	cmd.AddCommand(newConfigSetCmd())
 	cmd.AddCommand(newConfigGetCmd())
+	cmd.AddCommand(newConfigEditCmd())
 
 	return cmd
 }
@@ -57,10 +61,15 @@ func newConfigSetCmd() *cobra.Command {
 
 Valid keys: name, email
 
+The global-only key github.protocol ("https" or "ssh") picks the remote URL
+scheme that 'fst clone' and 'fst backend set github' (unless given --ssh)
+use for owner/repo arguments.
+
 Examples:
   fst config set name "John Doe"
   fst config set email "john@example.com"
-  fst config set --global name "John Doe"`,
+  fst config set --global name "John Doe"
+  fst config set --global github.protocol ssh`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runConfigSet(args[0], args[1], global)
@@ -80,7 +89,7 @@ func newConfigGetCmd() *cobra.Command {
 
 Without a key, shows all fields. With a key, shows that specific field.
 
-Valid keys: name, email
+Valid keys: name, email, github.protocol
 
 Examples:
   fst config get          # show all
@@ -98,6 +107,19 @@ Examples:
 }
 
 func runConfigGetField(key string) error {
+	if key == "github.protocol" {
+		prefs, err := config.LoadGlobalGitHubPrefs()
+		if err != nil {
+			return err
+		}
+		if prefs.Protocol == "" {
+			fmt.Println(config.GitHubProtocolHTTPS)
+		} else {
+			fmt.Println(prefs.Protocol)
+		}
+		return nil
+	}
+
 	author, err := config.LoadAuthor()
 	if err != nil {
 		return err
@@ -116,7 +138,7 @@ func runConfigGetField(key string) error {
 			fmt.Println(author.Email)
 		}
 	default:
-		return fmt.Errorf("unknown config key: %s (valid keys: name, email)", key)
+		return fmt.Errorf("unknown config key: %s (valid keys: name, email, github.protocol)", key)
 	}
 	return nil
 }
@@ -136,6 +158,10 @@ func runConfigShow() error {
 }
 
 func runConfigSet(key, value string, global bool) error {
+	if key == "github.protocol" {
+		return runConfigSetGitHubProtocol(value, global)
+	}
+
 	var author *config.Author
 	var err error
 
@@ -154,7 +180,7 @@ func runConfigSet(key, value string, global bool) error {
 	case "email":
 		author.Email = value
 	default:
-		return fmt.Errorf("unknown config key: %s (valid keys: name, email)", key)
+		return fmt.Errorf("unknown config key: %s (valid keys: name, email, github.protocol)", key)
 	}
 
 	if global {
@@ -174,6 +200,25 @@ func runConfigSet(key, value string, global bool) error {
 	return nil
 }
 
+func runConfigSetGitHubProtocol(value string, global bool) error {
+	if !global {
+		return fmt.Errorf("github.protocol is a global setting - use 'fst config set --global github.protocol %s'", value)
+	}
+	if value != config.GitHubProtocolHTTPS && value != config.GitHubProtocolSSH {
+		return fmt.Errorf("invalid github.protocol %q (expected https or ssh)", value)
+	}
+	prefs, err := config.LoadGlobalGitHubPrefs()
+	if err != nil {
+		return err
+	}
+	prefs.Protocol = value
+	if err := config.SaveGlobalGitHubPrefs(prefs); err != nil {
+		return err
+	}
+	fmt.Printf("Set github.protocol %s (global).\n", value)
+	return nil
+}
+
 func runConfigInteractive(global bool) error {
 	var existing *config.Author
 	var err error
@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ankitiscracked/fastest/cli/internal/backend"
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+func newConfigEditCmd() *cobra.Command {
+	var project bool
+
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit the project or workspace config in $EDITOR",
+		Long: `Open .fst/config.json in $EDITOR and validate it before saving.
+
+Inside a workspace this edits the workspace config; use --project to edit
+the parent project config instead. The edited file must be valid JSON of
+the same config type, and a backend type (if set) must be one fst knows
+("github" or "git"). Invalid edits are never written: you can reopen the
+editor to fix them or abort and keep the original file.
+
+Examples:
+  fst config edit             # edit the nearest config
+  fst config edit --project   # edit the project config`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigEdit(project)
+		},
+	}
+
+	cmd.Flags().BoolVar(&project, "project", false, "Edit the project config even inside a workspace")
+
+	return cmd
+}
+
+func runConfigEdit(project bool) error {
+	root, kind, err := findEditableConfigRoot(project)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(root, config.ConfigDirName, config.ConfigFileName)
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	tmp, err := os.CreateTemp("", "fst-config-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+	if _, err := tmp.Write(original); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	for {
+		if err := runEditor(tmpPath); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return fmt.Errorf("failed to read edited config: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			fmt.Println("No changes.")
+			return nil
+		}
+		if err := validateConfigData(edited, kind); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid config: %v\n", err)
+			if confirmReopenEditor() {
+				continue
+			}
+			return fmt.Errorf("config not saved: %w", err)
+		}
+		if err := store.AtomicWriteFile(path, edited, 0644); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("Saved %s config: %s\n", kind, path)
+		return nil
+	}
+}
+
+// findEditableConfigRoot returns the directory whose .fst/config.json should
+// be edited and its config type. The workspace config wins unless project
+// is set or the cwd is not inside a workspace.
+func findEditableConfigRoot(project bool) (string, string, error) {
+	if !project {
+		if wsRoot, err := config.FindWorkspaceRoot(); err == nil {
+			return wsRoot, config.ConfigTypeWorkspace, nil
+		}
+	}
+	projectRoot, _, err := findProjectRootAndConfig()
+	if err != nil {
+		return "", "", err
+	}
+	return projectRoot, config.ConfigTypeProject, nil
+}
+
+// validateConfigData checks that data is a well-formed config of the given
+// type, including a backend type that backend.FromConfig understands.
+func validateConfigData(data []byte, kind string) error {
+	switch kind {
+	case config.ConfigTypeProject:
+		var cfg config.ProjectConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		if cfg.Type != config.ConfigTypeProject {
+			return fmt.Errorf("type must be %q, got %q", config.ConfigTypeProject, cfg.Type)
+		}
+		if cfg.ProjectID == "" || cfg.ProjectName == "" {
+			return fmt.Errorf("project_id and project_name are required")
+		}
+		if cfg.Backend != nil && backend.FromConfig(cfg.Backend, nil) == nil {
+			return fmt.Errorf("unknown backend type %q (valid types: github, git)", cfg.Backend.Type)
+		}
+	case config.ConfigTypeWorkspace:
+		var cfg config.WorkspaceConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		if cfg.Type != config.ConfigTypeWorkspace {
+			return fmt.Errorf("type must be %q, got %q", config.ConfigTypeWorkspace, cfg.Type)
+		}
+		if cfg.ProjectID == "" {
+			return fmt.Errorf("project_id is required")
+		}
+	default:
+		return fmt.Errorf("unknown config type %q", kind)
+	}
+	return nil
+}
+
+// runEditor opens path in $VISUAL or $EDITOR (falling back to vi) attached
+// to the current terminal. The editor value may include arguments.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "fst-editor", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+func confirmReopenEditor() bool {
+	fmt.Print("Reopen the editor to fix it? [Y/n] ")
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil && response == "" {
+		return false
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "" || response == "y" || response == "yes"
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+)
+
+func TestConfigEdit(t *testing.T) {
+	root := t.TempDir()
+	if err := config.SaveProjectConfigAt(root, &config.ProjectConfig{
+		ProjectID:   "proj-edit",
+		ProjectName: "edit",
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	// No input on stdin, so the "reopen editor?" prompt aborts.
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	oldStdin := os.Stdin
+	os.Stdin = devNull
+	defer func() { os.Stdin = oldStdin }()
+
+	writeEditor := func(name, content string) string {
+		src := filepath.Join(root, name+".json")
+		if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		script := filepath.Join(root, name+".sh")
+		body := "#!/bin/sh\ncp '" + src + "' \"$1\"\n"
+		if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		return script
+	}
+	setenv(t, "VISUAL", "")
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"bad-json", `{"type": "project",`, "invalid JSON"},
+		{"bad-backend", `{"type": "project", "project_id": "proj-edit", "project_name": "edit", "backend": {"type": "svn"}}`, "unknown backend type"},
+	}
+	for _, tt := range tests {
+		setenv(t, "EDITOR", writeEditor(tt.name, tt.content))
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"config", "edit"})
+		err := captureStdout(func() error { return cmd.Execute() }, new(string))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Fatalf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+		cfg, err := config.LoadProjectConfigAt(root)
+		if err != nil {
+			t.Fatalf("%s: config was corrupted: %v", tt.name, err)
+		}
+		if cfg.Backend != nil {
+			t.Fatalf("%s: invalid edit was saved", tt.name)
+		}
+	}
+
+	setenv(t, "EDITOR", writeEditor("good", `{"type": "project", "project_id": "proj-edit", "project_name": "edit", "backend": {"type": "git"}}`))
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"config", "edit"})
+	if err := captureStdout(func() error { return cmd.Execute() }, new(string)); err != nil {
+		t.Fatalf("config edit failed: %v", err)
+	}
+	cfg, err := config.LoadProjectConfigAt(root)
+	if err != nil {
+		t.Fatalf("LoadProjectConfigAt: %v", err)
+	}
+	if cfg.Backend == nil || cfg.Backend.Type != "git" {
+		t.Fatalf("expected git backend to be saved, got %+v", cfg.Backend)
+	}
+}
+
+func TestConfigSetGitHubProtocolKeepsUnreadablePrefs(t *testing.T) {
+	configHome := t.TempDir()
+	setenv(t, "XDG_CONFIG_HOME", configHome)
+	prefsPath := filepath.Join(configHome, "fst", "github.json")
+	if err := os.MkdirAll(filepath.Dir(prefsPath), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(prefsPath, []byte("{not json"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"config", "set", "--global", "github.protocol", "ssh"})
+	if err := captureStdout(cmd.Execute, new(string)); err == nil {
+		t.Fatal("expected an unreadable github.json to be reported")
+	}
+	if data, _ := os.ReadFile(prefsPath); string(data) != "{not json" {
+		t.Fatalf("expected github.json to be left alone, got %q", data)
+	}
+}
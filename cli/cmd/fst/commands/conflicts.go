@@ -10,7 +10,9 @@ import (
 	"github.com/ankitiscracked/fastest/cli/internal/agent"
 	"github.com/ankitiscracked/fastest/cli/internal/config"
 	"github.com/ankitiscracked/fastest/cli/internal/conflicts"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
 	"github.com/ankitiscracked/fastest/cli/internal/ui"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
 )
 
 func init() {
@@ -18,10 +20,17 @@ func init() {
 }
 
 func newConflictsCmd() *cobra.Command {
+	cmd := newConflictsDetectCmd()
+	cmd.AddCommand(newConflictsSummarizeCmd())
+	return cmd
+}
+
+func newConflictsDetectCmd() *cobra.Command {
 	var showAll bool
 	var includeDirty bool
 	var jsonOutput bool
 	var summary bool
+	var baseRef string
 
 	cmd := &cobra.Command{
 		Use:        "conflicts <workspace-path>",
@@ -47,12 +56,13 @@ from the other) for meaningful conflict detection.
 
 Examples:
   fst merge --dry-run ../feature-workspace   # Preferred way
-  fst conflicts ../feature-workspace         # Deprecated`,
+  fst conflicts ../feature-workspace         # Deprecated
+  fst conflicts ../feature-workspace --base abc123  # Compare against a chosen ancestor`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			fmt.Println(ui.Yellow("Note: 'fst conflicts' is deprecated. Use 'fst merge --dry-run' instead."))
 			fmt.Println()
-			return runConflicts(args[0], showAll, includeDirty, jsonOutput, summary)
+			return runConflicts(args[0], baseRef, showAll, includeDirty, jsonOutput, summary)
 		},
 	}
 
@@ -60,11 +70,12 @@ Examples:
 	cmd.Flags().BoolVar(&includeDirty, "include-dirty", false, "Include other workspace's uncommitted changes in comparison")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
 	cmd.Flags().BoolVar(&summary, "summary", false, "Generate LLM summary of conflicts (requires configured agent)")
+	cmd.Flags().StringVar(&baseRef, "base", "", "Snapshot to use as the common ancestor instead of the inferred base")
 
 	return cmd
 }
 
-func runConflicts(otherWorkspace string, showAll, includeDirty, jsonOutput, generateSummary bool) error {
+func runConflicts(otherWorkspace, baseRef string, showAll, includeDirty, jsonOutput, generateSummary bool) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
@@ -90,8 +101,20 @@ func runConflicts(otherWorkspace string, showAll, includeDirty, jsonOutput, gene
 		return fmt.Errorf("not a workspace: %s", otherRoot)
 	}
 
+	var base *conflicts.Base
+	if baseRef != "" {
+		baseID, err := store.OpenFromWorkspace(root).ResolveSnapshotID(baseRef)
+		if err != nil {
+			return fmt.Errorf("invalid --base: %w", err)
+		}
+		base, err = conflicts.LoadBase(root, baseID)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Detect git-style conflicts
-	report, err := conflicts.Detect(root, otherRoot, includeDirty)
+	report, err := conflicts.Detect(root, otherRoot, includeDirty, base)
 	if err != nil {
 		return fmt.Errorf("failed to detect conflicts: %w", err)
 	}
@@ -129,6 +152,9 @@ func runConflicts(otherWorkspace string, showAll, includeDirty, jsonOutput, gene
 	// Human-readable output
 	fmt.Printf("Workspace: %s\n", cfg.WorkspaceName)
 	fmt.Printf("Comparing against: %s\n", otherRoot)
+	if base != nil {
+		fmt.Printf("Base snapshot: %s\n", base.SnapshotID)
+	}
 	fmt.Println()
 
 	// Summary
@@ -192,6 +218,104 @@ func runConflicts(otherWorkspace string, showAll, includeDirty, jsonOutput, gene
 	return nil
 }
 
+func newConflictsSummarizeCmd() *cobra.Command {
+	var from string
+
+	cmd := &cobra.Command{
+		Use:   "summarize [workspace]",
+		Short: "Summarize what would conflict when merging another workspace",
+		Long: `Summarize the conflicts between this workspace and another one.
+
+This detects overlapping changes the same way 'fst merge --dry-run' does and
+asks your coding agent for a short natural-language summary of them. No
+merge is run or planned.
+
+The source workspace is looked up in the project registry by name and can
+be given as an argument or with --from.
+
+Examples:
+  fst conflicts summarize feature
+  fst conflicts summarize --from feature`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := from
+			if len(args) > 0 {
+				if from != "" && from != args[0] {
+					return fmt.Errorf("workspace given both as an argument and with --from")
+				}
+				source = args[0]
+			}
+			if source == "" {
+				return fmt.Errorf("specify the workspace to compare against, e.g. 'fst conflicts summarize feature'")
+			}
+			return runConflictsSummarize(source)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Workspace to compare against")
+
+	return cmd
+}
+
+func runConflictsSummarize(sourceName string) error {
+	ws, err := workspace.Open()
+	if err != nil {
+		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+	defer ws.Close()
+
+	sourceInfo, err := ws.Store().FindWorkspaceByName(sourceName)
+	if err != nil {
+		return fmt.Errorf("workspace '%s' not found in project registry\nRun 'fst workspaces' to see available workspaces", sourceName)
+	}
+	if sourceInfo.Path == "" {
+		return fmt.Errorf("workspace '%s' has no local path", sourceName)
+	}
+	if sourceInfo.WorkspaceID == ws.WorkspaceID() {
+		return fmt.Errorf("cannot compare workspace '%s' with itself", sourceName)
+	}
+
+	// Compare against the merge base the merge itself would use, falling
+	// back to the recorded base when the histories can't be related.
+	var base *conflicts.Base
+	if cur, src := ws.CurrentSnapshotID(), sourceInfo.CurrentSnapshotID; cur != "" && src != "" {
+		if baseID, err := ws.Store().GetMergeBase(cur, src); err == nil && baseID != "" {
+			base, err = conflicts.LoadBase(ws.Root(), baseID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	report, err := conflicts.Detect(ws.Root(), sourceInfo.Path, true, base)
+	if err != nil {
+		return fmt.Errorf("failed to detect conflicts: %w", err)
+	}
+	if report.TrueConflicts == 0 {
+		fmt.Printf("✓ No conflicts with %s\n", sourceInfo.WorkspaceName)
+		return nil
+	}
+
+	fmt.Printf("%d conflicting files with %d overlapping regions with %s.\n",
+		report.TrueConflicts, countHunks(report), sourceInfo.WorkspaceName)
+
+	preferredAgent, err := deps.AgentGetPreferred()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Generating summary with %s...\n", preferredAgent.Name)
+
+	conflictContext := agent.BuildConflictContext(buildConflictInfosFromReport(report))
+	summaryText, err := agent.InvokeConflictSummary(preferredAgent, conflictContext, deps.AgentInvoke)
+	if err != nil {
+		return fmt.Errorf("failed to generate summary: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Printf("Summary:\n  %s\n", summaryText)
+	return nil
+}
+
 // buildConflictInfos converts conflicts.Report to agent.ConflictInfo slice
 func buildConflictInfos(report *conflicts.Report) []agent.ConflictInfo {
 	var infos []agent.ConflictInfo
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/agent"
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+func TestConflictsBaseOverride(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"base.txt": "base\nours\n"},
+		map[string]string{"base.txt": "base\ntheirs\n"},
+	)
+
+	cfg, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	baseID, err := store.OpenFromWorkspace(targetRoot).SnapshotPrimaryParentID(cfg.CurrentSnapshotID)
+	if err != nil || baseID == "" {
+		t.Fatalf("SnapshotPrimaryParentID: %q, %v", baseID, err)
+	}
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	var output string
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"conflicts", "../ws-source", "--all", "--include-dirty", "--base", baseID[:12]})
+		return cmd.Execute()
+	}, &output)
+	if err != nil {
+		t.Fatalf("conflicts --base failed: %v", err)
+	}
+	if !strings.Contains(output, "Base snapshot: "+baseID) {
+		t.Fatalf("expected base snapshot in output, got:\n%s", output)
+	}
+	if !strings.Contains(output, "base.txt") {
+		t.Fatalf("expected base.txt as modified in both, got:\n%s", output)
+	}
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"conflicts", "../ws-source", "--base", "nonexistent"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected unknown --base snapshot to fail")
+	}
+}
+
+func TestConflictsSummarize(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"base.txt": "target version\nline2\n"},
+		map[string]string{"base.txt": "source version\nline2\n"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	var prompt string
+	SetDeps(Deps{
+		AgentGetPreferred: func() (*agent.Agent, error) {
+			return mockAgent(), nil
+		},
+		AgentInvoke: func(a *agent.Agent, p string) (string, error) {
+			prompt = p
+			return "Both sides rewrote the first line of base.txt.", nil
+		},
+	})
+	defer ResetDeps()
+
+	for _, args := range [][]string{
+		{"conflicts", "summarize", "ws-source"},
+		{"conflicts", "summarize", "--from", "ws-source"},
+	} {
+		prompt = ""
+		var output string
+		err := captureStdout(func() error {
+			cmd := NewRootCmd()
+			cmd.SetArgs(args)
+			return cmd.Execute()
+		}, &output)
+		if err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		if !strings.Contains(prompt, "base.txt") {
+			t.Fatalf("%v: expected the conflict context in the prompt, got:\n%s", args, prompt)
+		}
+		if !strings.Contains(output, "Both sides rewrote the first line of base.txt.") {
+			t.Fatalf("%v: expected the summary in the output, got:\n%s", args, output)
+		}
+		if strings.Contains(output, "deprecated") {
+			t.Fatalf("%v: summarize should not print the deprecation notice, got:\n%s", args, output)
+		}
+	}
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"conflicts", "summarize"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected summarize without a workspace to fail")
+	}
+}
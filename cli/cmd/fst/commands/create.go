@@ -9,8 +9,12 @@ import (
 	"time"
 
 	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/gitstore"
+	"github.com/ankitiscracked/fastest/cli/internal/gitutil"
 	"github.com/ankitiscracked/fastest/cli/internal/ignore"
+	"github.com/ankitiscracked/fastest/cli/internal/manifest"
 	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
 )
 
 type createBackend string
@@ -25,7 +29,7 @@ var errCloneUnsupportedPlatform = errors.New("clone backend is not supported on
 
 var cloneFileFunc = cloneFile
 
-func runCreate(args []string, fromWorkspace, backendArg string) error {
+func runCreate(args []string, fromWorkspace, backendArg, track string, bare bool) error {
 	backend, err := parseCreateBackend(backendArg)
 	if err != nil {
 		return err
@@ -80,6 +84,41 @@ func runCreate(args []string, fromWorkspace, backendArg string) error {
 		projectID = parentCfg.ProjectID
 	}
 
+	if track != "" {
+		if fromWorkspace != "" || bare {
+			return fmt.Errorf("--track cannot be combined with --from or --bare")
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("workspace name is required")
+		}
+		return createTrackingWorkspace(parentRoot, parentCfg, args[0], track)
+	}
+
+	if bare {
+		if fromWorkspace != "" {
+			return fmt.Errorf("--bare cannot be combined with --from")
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("workspace name is required")
+		}
+		return createBareWorkspace(parentRoot, projectID, args[0])
+	}
+
+	// --from may name a snapshot instead of a workspace. Workspace names
+	// win; otherwise fork from the snapshot, keeping the default source
+	// workspace for the workspace mode.
+	var fromSnapshotID string
+	if fromWorkspace != "" {
+		if _, _, wsErr := findSourceWorkspace(fromWorkspace, projectID, parentRoot); wsErr != nil {
+			snapshotID, snapErr := store.OpenAt(parentRoot).ResolveSnapshotID(fromWorkspace)
+			if snapErr != nil {
+				return fmt.Errorf("'%s' is neither a workspace nor a snapshot: %w", fromWorkspace, wsErr)
+			}
+			fromSnapshotID = snapshotID
+			fromWorkspace = ""
+		}
+	}
+
 	// Resolve source workspace when --from is specified or we're at project root
 	if fromWorkspace != "" || sourceWorkspaceRoot == "" {
 		sourceName := fromWorkspace
@@ -100,6 +139,9 @@ func runCreate(args []string, fromWorkspace, backendArg string) error {
 	// Get the source workspace's latest snapshot (fork point)
 	s := store.OpenFromWorkspace(sourceWorkspaceRoot)
 	forkSnapshotID := sourceWorkspaceCfg.CurrentSnapshotID
+	if fromSnapshotID != "" {
+		forkSnapshotID = fromSnapshotID
+	}
 	if forkSnapshotID == "" {
 		latestID, _ := s.GetLatestSnapshotIDForWorkspace(sourceWorkspaceCfg.WorkspaceID)
 		forkSnapshotID = latestID
@@ -117,15 +159,22 @@ func runCreate(args []string, fromWorkspace, backendArg string) error {
 	}
 	workspaceName := args[0]
 
+	// A snapshot fork point must be fully restorable before we create anything.
+	var forkManifest *manifest.Manifest
+	if fromSnapshotID != "" {
+		forkManifest, err = loadRestorableManifest(s, fromSnapshotID)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Determine target directory (atomic mkdir to avoid TOCTOU race)
 	targetDir := filepath.Join(parentRoot, workspaceName)
 
-	fmt.Printf("Creating workspace '%s' from '%s'...\n", workspaceName, sourceWorkspaceCfg.WorkspaceName)
-
-	// Copy files from source workspace (respecting .fstignore)
-	matcher, err := ignore.LoadFromDir(sourceWorkspaceRoot)
-	if err != nil {
-		return fmt.Errorf("failed to load ignore patterns: %w", err)
+	if forkManifest != nil {
+		fmt.Printf("Creating workspace '%s' from snapshot %s...\n", workspaceName, fromSnapshotID[:12])
+	} else {
+		fmt.Printf("Creating workspace '%s' from '%s'...\n", workspaceName, sourceWorkspaceCfg.WorkspaceName)
 	}
 
 	if err := os.Mkdir(targetDir, 0755); err != nil {
@@ -135,71 +184,16 @@ func runCreate(args []string, fromWorkspace, backendArg string) error {
 		return fmt.Errorf("failed to create workspace directory: %w", err)
 	}
 
-	fmt.Printf("Copying files...\n")
-
-	copied := 0
-	cloned := 0
-	err = filepath.Walk(sourceWorkspaceRoot, func(path string, info os.FileInfo, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
-		}
-
-		relPath, err := filepath.Rel(sourceWorkspaceRoot, path)
+	if forkManifest == nil {
+		fmt.Printf("Copying files...\n")
+		copied, cloned, err := copyWorkspaceTree(sourceWorkspaceRoot, targetDir, backend)
 		if err != nil {
-			return err
+			os.RemoveAll(targetDir)
+			return fmt.Errorf("failed to copy files: %w", err)
 		}
-		if relPath == "." {
-			return nil
-		}
-
-		relPath = filepath.ToSlash(relPath)
-		if matcher.Match(relPath, info.IsDir()) {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		targetPath := filepath.Join(targetDir, relPath)
-
-		// Handle symlinks: recreate the link rather than copying the target
-		if info.Mode()&os.ModeSymlink != 0 {
-			linkTarget, err := os.Readlink(path)
-			if err != nil {
-				return fmt.Errorf("failed to read symlink %s: %w", relPath, err)
-			}
-			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-				return err
-			}
-			if err := os.Symlink(linkTarget, targetPath); err != nil {
-				return fmt.Errorf("failed to create symlink %s: %w", relPath, err)
-			}
-			copied++
-			return nil
-		}
-
-		if info.IsDir() {
-			return os.MkdirAll(targetPath, info.Mode())
-		}
-
-		usedClone, err := materializeWorkspaceFile(path, targetPath, info.Mode(), backend)
-		if err != nil {
-			return fmt.Errorf("failed to copy %s: %w", relPath, err)
-		}
-		if usedClone {
-			cloned++
-		} else {
-			copied++
-		}
-		return nil
-	})
-	if err != nil {
-		os.RemoveAll(targetDir)
-		return fmt.Errorf("failed to copy files: %w", err)
+		fmt.Printf("Materialized %d files (cloned: %d, copied: %d).\n", copied+cloned, cloned, copied)
 	}
 
-	fmt.Printf("Materialized %d files (cloned: %d, copied: %d).\n", copied+cloned, cloned, copied)
-
 	// Initialize .fst config in the new workspace
 	workspaceID := generateWorkspaceID()
 	if err := config.InitAt(targetDir, projectID, workspaceID, workspaceName, forkSnapshotID); err != nil {
@@ -244,6 +238,17 @@ func runCreate(args []string, fromWorkspace, backendArg string) error {
 		}
 	}
 
+	// A snapshot fork point is materialized through the workspace so that
+	// symlinks and empty directories come back too.
+	if forkManifest != nil {
+		fmt.Printf("Restoring files...\n")
+		if err := restoreNewWorkspace(targetDir, forkSnapshotID); err != nil {
+			os.RemoveAll(targetDir)
+			return err
+		}
+		fmt.Printf("Restored %d files.\n", len(forkManifest.FileEntries()))
+	}
+
 	// Register in project-level workspace registry
 	projectStore := store.OpenAt(parentRoot)
 	if err := projectStore.RegisterWorkspace(store.WorkspaceInfo{
@@ -262,7 +267,11 @@ func runCreate(args []string, fromWorkspace, backendArg string) error {
 	fmt.Println()
 	fmt.Printf("  Workspace: %s\n", workspaceName)
 	fmt.Printf("  Directory: %s\n", targetDir)
-	fmt.Printf("  Forked:    %s (%s)\n", sourceWorkspaceCfg.WorkspaceName, forkSnapshotID[:12])
+	if forkManifest != nil {
+		fmt.Printf("  Forked:    snapshot %s\n", forkSnapshotID[:12])
+	} else {
+		fmt.Printf("  Forked:    %s (%s)\n", sourceWorkspaceCfg.WorkspaceName, forkSnapshotID[:12])
+	}
 	fmt.Println()
 	fmt.Println("Next steps:")
 	fmt.Printf("  cd %s\n", targetDir)
@@ -272,6 +281,313 @@ func runCreate(args []string, fromWorkspace, backendArg string) error {
 	return nil
 }
 
+// createTrackingWorkspace creates a workspace from the history of the
+// remote branch track ("<remote>/<branch>") and records in the export
+// metadata that the workspace tracks that branch, so that 'fst sync' keeps
+// importing it and exports the workspace's snapshots back to it.
+func createTrackingWorkspace(parentRoot string, parentCfg *config.ProjectConfig, workspaceName, track string) error {
+	remote, branch, ok := strings.Cut(track, "/")
+	if !ok || remote == "" || branch == "" {
+		return fmt.Errorf("--track expects <remote>/<branch>, e.g. origin/feature")
+	}
+	if _, err := os.Stat(filepath.Join(parentRoot, ".git")); err != nil {
+		return fmt.Errorf("no git repository at the project root - run 'fst git export --init' first")
+	}
+	targetDir := filepath.Join(parentRoot, workspaceName)
+	if _, err := os.Stat(targetDir); err == nil {
+		return fmt.Errorf("target directory already exists: %s", targetDir)
+	}
+
+	tempDir, err := os.MkdirTemp("", "fst-track-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+	git := gitutil.NewEnv(parentRoot, tempDir, filepath.Join(tempDir, "index"))
+
+	// The branch is exported under its own name, so it must not already
+	// belong to another workspace.
+	s := store.OpenAt(parentRoot)
+	meta, err := gitstore.LoadExportMetadata(git)
+	if err != nil {
+		return fmt.Errorf("failed to load export metadata: %w", err)
+	}
+	if meta != nil {
+		for _, entry := range meta.Workspaces {
+			if entry.Branch == branch {
+				return fmt.Errorf("branch %s is already exported by workspace '%s'", branch, entry.WorkspaceName)
+			}
+		}
+	}
+	if info, err := s.FindWorkspaceByName(branch); err == nil {
+		return fmt.Errorf("branch %s would clash with the export of workspace '%s'", branch, info.WorkspaceName)
+	}
+
+	remoteRef := "refs/remotes/" + remote + "/" + branch
+	remoteSHA, err := gitutil.RefSHA(git, remoteRef)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("Fetching %s from %s...\n", branch, remote)
+		if err := gitutil.RunCommand(parentRoot, "fetch", remote, "+refs/heads/"+branch+":"+remoteRef); err != nil {
+			return fmt.Errorf("failed to fetch %s: %w", track, err)
+		}
+		remoteSHA, err = gitutil.RefSHA(git, remoteRef)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", track, err)
+	}
+
+	localSHA, err := gitutil.RefSHA(git, "refs/heads/"+branch)
+	createdBranch := false
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if err := gitutil.UpdateBranchRef(git, branch, remoteSHA); err != nil {
+			return fmt.Errorf("failed to create branch %s: %w", branch, err)
+		}
+		createdBranch = true
+	case err != nil:
+		return err
+	case localSHA != remoteSHA:
+		return fmt.Errorf("local branch %s already exists and differs from %s", branch, track)
+	}
+
+	// Undo everything created so far if a later step fails, so a retry
+	// starts from a clean slate.
+	workspaceID := generateWorkspaceID()
+	created := false
+	defer func() {
+		if created {
+			return
+		}
+		os.RemoveAll(targetDir)
+		_ = s.UnregisterWorkspace(workspaceID)
+		if createdBranch {
+			_ = gitutil.DeleteBranchRef(git, branch)
+		}
+	}()
+
+	fmt.Printf("Creating workspace '%s' tracking %s...\n", workspaceName, track)
+	if err := s.EnsureDirs(); err != nil {
+		return fmt.Errorf("failed to create store directories: %w", err)
+	}
+	commitToSnapshot, err := importWorkspaceFromGit(git, s, importTarget{
+		WorkspaceName: workspaceName,
+		WorkspaceID:   workspaceID,
+		Branch:        branch,
+		Root:          targetDir,
+		ProjectID:     parentCfg.ProjectID,
+	}, false)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadAt(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to load new workspace config: %w", err)
+	}
+	if _, err := loadRestorableManifest(s, cfg.CurrentSnapshotID); err != nil {
+		return err
+	}
+	if err := restoreNewWorkspace(targetDir, cfg.CurrentSnapshotID); err != nil {
+		return err
+	}
+
+	// Map the imported commits so sync only imports newer ones, and so the
+	// workspace's own snapshots are exported on top of them.
+	configDir := filepath.Join(parentRoot, ".fst")
+	mapping, err := gitstore.LoadGitMapping(configDir)
+	if err != nil {
+		return fmt.Errorf("failed to load git mapping: %w", err)
+	}
+	mapping.RepoPath = parentRoot
+	for commit, snapshotID := range commitToSnapshot {
+		mapping.Snapshots[snapshotID] = commit
+	}
+	if err := gitstore.SaveGitMapping(configDir, mapping); err != nil {
+		return fmt.Errorf("failed to save git mapping: %w", err)
+	}
+
+	metaDir := filepath.Join(tempDir, "meta")
+	if err := os.Mkdir(metaDir, 0755); err != nil {
+		return fmt.Errorf("failed to create metadata work directory: %w", err)
+	}
+	metaGit := gitutil.NewEnv(parentRoot, metaDir, filepath.Join(tempDir, "meta-index"))
+	if err := gitstore.TrackExportBranch(metaGit, cfg, branch); err != nil {
+		return fmt.Errorf("failed to record tracking branch: %w", err)
+	}
+
+	if b := parentCfg.Backend; b != nil && b.Type == "github" {
+		syncRemote := b.Remote
+		if syncRemote == "" {
+			syncRemote = "origin"
+		}
+		if syncRemote != remote {
+			fmt.Printf("Warning: 'fst sync' uses remote '%s', not '%s'\n", syncRemote, remote)
+		}
+	}
+
+	created = true
+
+	fmt.Println()
+	fmt.Println("✓ Workspace created!")
+	fmt.Println()
+	fmt.Printf("  Workspace: %s\n", workspaceName)
+	fmt.Printf("  Directory: %s\n", targetDir)
+	fmt.Printf("  Tracking:  %s (%s)\n", track, cfg.CurrentSnapshotID[:12])
+	fmt.Println()
+	fmt.Println("Next steps:")
+	fmt.Printf("  cd %s\n", targetDir)
+	fmt.Printf("  fst sync          # Pull new commits from %s\n", track)
+
+	return nil
+}
+
+// createBareWorkspace creates an empty workspace with no initial snapshot.
+// Its first 'fst snapshot' becomes the root of its history.
+func createBareWorkspace(parentRoot, projectID, workspaceName string) error {
+	targetDir := filepath.Join(parentRoot, workspaceName)
+	fmt.Printf("Creating bare workspace '%s'...\n", workspaceName)
+
+	if err := os.Mkdir(targetDir, 0755); err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("target directory already exists: %s", targetDir)
+		}
+		return fmt.Errorf("failed to create workspace directory: %w", err)
+	}
+
+	workspaceID := generateWorkspaceID()
+	if err := config.InitAt(targetDir, projectID, workspaceID, workspaceName, ""); err != nil {
+		os.RemoveAll(targetDir)
+		return fmt.Errorf("failed to initialize workspace: %w", err)
+	}
+
+	projectStore := store.OpenAt(parentRoot)
+	if err := projectStore.RegisterWorkspace(store.WorkspaceInfo{
+		WorkspaceID:   workspaceID,
+		WorkspaceName: workspaceName,
+		Path:          targetDir,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		fmt.Printf("Warning: Could not register workspace in project: %v\n", err)
+	}
+
+	fmt.Println()
+	fmt.Println("✓ Workspace created!")
+	fmt.Println()
+	fmt.Printf("  Workspace: %s\n", workspaceName)
+	fmt.Printf("  Directory: %s\n", targetDir)
+	fmt.Println("  Snapshot:  none (bare)")
+	fmt.Println()
+	fmt.Println("Next steps:")
+	fmt.Printf("  cd %s\n", targetDir)
+	fmt.Println("  fst snapshot      # Capture the files you add")
+
+	return nil
+}
+
+// copyWorkspaceTree copies sourceRoot's files (respecting .fstignore) into
+// targetDir, returning how many files were copied and cloned.
+func copyWorkspaceTree(sourceRoot, targetDir string, backend createBackend) (int, int, error) {
+	matcher, err := ignore.LoadFromDir(sourceRoot)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load ignore patterns: %w", err)
+	}
+
+	copied := 0
+	cloned := 0
+	err = filepath.Walk(sourceRoot, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		relPath, err := filepath.Rel(sourceRoot, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		relPath = filepath.ToSlash(relPath)
+		if matcher.Match(relPath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		targetPath := filepath.Join(targetDir, relPath)
+
+		// Handle symlinks: recreate the link rather than copying the target
+		if info.Mode()&os.ModeSymlink != 0 {
+			linkTarget, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("failed to read symlink %s: %w", relPath, err)
+			}
+			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+				return err
+			}
+			if err := os.Symlink(linkTarget, targetPath); err != nil {
+				return fmt.Errorf("failed to create symlink %s: %w", relPath, err)
+			}
+			copied++
+			return nil
+		}
+
+		if info.IsDir() {
+			return os.MkdirAll(targetPath, info.Mode())
+		}
+
+		usedClone, err := materializeWorkspaceFile(path, targetPath, info.Mode(), backend)
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %w", relPath, err)
+		}
+		if usedClone {
+			cloned++
+		} else {
+			copied++
+		}
+		return nil
+	})
+	return copied, cloned, err
+}
+
+// restoreNewWorkspace materializes snapshotID into the freshly created
+// workspace at root, including symlinks and empty directories.
+func restoreNewWorkspace(root, snapshotID string) error {
+	ws, err := workspace.OpenAt(root)
+	if err != nil {
+		return fmt.Errorf("failed to open new workspace: %w", err)
+	}
+	defer ws.Close()
+	result, err := ws.Restore(workspace.RestoreOpts{SnapshotID: snapshotID})
+	if err != nil {
+		return fmt.Errorf("failed to restore files: %w", err)
+	}
+	if len(result.MissingBlobs) > 0 {
+		return fmt.Errorf("failed to restore files: %d blob(s) missing", len(result.MissingBlobs))
+	}
+	return nil
+}
+
+// loadRestorableManifest loads the manifest of snapshotID and checks that
+// every file's blob is present, so a workspace can be materialized from it.
+func loadRestorableManifest(s *store.Store, snapshotID string) (*manifest.Manifest, error) {
+	manifestHash, err := s.ManifestHashFromSnapshotID(snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", snapshotID, err)
+	}
+	m, err := s.LoadManifest(manifestHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifest for snapshot %s: %w", snapshotID, err)
+	}
+	for _, f := range m.FileEntries() {
+		if !s.BlobExists(f.Hash) {
+			return nil, fmt.Errorf("snapshot %s is not restorable: missing blob for %s", snapshotID, f.Path)
+		}
+	}
+	return m, nil
+}
+
 // resolveMainWorkspaceName finds the main workspace name for the project.
 func resolveMainWorkspaceName(parentRoot string, parentCfg *config.ProjectConfig) string {
 	// Try to find main workspace from project-level registry
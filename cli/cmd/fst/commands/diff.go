@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/sergi/go-diff/diffmatchpatch"
@@ -24,6 +25,8 @@ func newDiffCmd() *cobra.Command {
 	var contextLines int
 	var noColor bool
 	var namesOnly bool
+	var nameOnly bool
+	var nameStatus bool
 
 	cmd := &cobra.Command{
 		Use:   "diff [workspace] [file...]",
@@ -44,7 +47,9 @@ Examples:
   fst diff main                # Diff against workspace named "main"
   fst diff ../other            # Diff against workspace at path
   fst diff main src/file.go    # Diff specific file against "main"
-  fst diff --names-only        # Just list changed files (like drift)`,
+  fst diff --names-only        # Just list changed files (like drift)
+  fst diff main --name-only    # Plain sorted paths, for scripting
+  fst diff main --name-status  # Status letter (A/M/D), tab, path`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var target string
 			var files []string
@@ -55,18 +60,41 @@ Examples:
 					files = args[1:]
 				}
 			}
-			return runDiff(cmd, target, files, contextLines, noColor, namesOnly)
+			if nameOnly && nameStatus {
+				return fmt.Errorf("cannot use both --name-only and --name-status")
+			}
+			format := diffFormatPatch
+			switch {
+			case nameOnly:
+				format = diffFormatNameOnly
+			case nameStatus:
+				format = diffFormatNameStatus
+			case namesOnly:
+				format = diffFormatNames
+			}
+			return runDiff(cmd, target, files, contextLines, noColor, format)
 		},
 	}
 
 	cmd.Flags().IntVarP(&contextLines, "context", "C", 3, "Number of context lines around changes")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
 	cmd.Flags().BoolVar(&namesOnly, "names-only", false, "Only show names of changed files")
+	cmd.Flags().BoolVar(&nameOnly, "name-only", false, "Print only the sorted paths of changed files")
+	cmd.Flags().BoolVar(&nameStatus, "name-status", false, "Print a status letter and path for each changed file")
 
 	return cmd
 }
 
-func runDiff(cmd *cobra.Command, target string, files []string, contextLines int, noColor, namesOnly bool) error {
+type diffFormat int
+
+const (
+	diffFormatPatch diffFormat = iota
+	diffFormatNames
+	diffFormatNameOnly
+	diffFormatNameStatus
+)
+
+func runDiff(cmd *cobra.Command, target string, files []string, contextLines int, noColor bool, format diffFormat) error {
 	if noColor {
 		ui.Disable()
 	}
@@ -180,14 +208,24 @@ func runDiff(cmd *cobra.Command, target string, files []string, contextLines int
 		deleted = filterFiles(deleted, fileSet)
 	}
 
+	if format == diffFormatNameOnly || format == diffFormatNameStatus {
+		for _, line := range formatNameStatus(added, modified, deleted, format == diffFormatNameStatus) {
+			fmt.Println(line)
+		}
+		if len(added) == 0 && len(modified) == 0 && len(deleted) == 0 {
+			return nil
+		}
+		cmd.SilenceErrors = true
+		return SilentExit(1)
+	}
+
 	if len(added) == 0 && len(modified) == 0 && len(deleted) == 0 {
 		fmt.Printf("No differences between %s and %s\n", cfg.WorkspaceName, otherName)
 		return nil
 	}
 
-
 	// Names only mode
-	if namesOnly {
+	if format == diffFormatNames {
 		for _, f := range added {
 			fmt.Println(ui.Green("A " + f))
 		}
@@ -373,3 +411,30 @@ func printUnifiedDiff(diffs []diffmatchpatch.Diff, contextLines int) {
 func isPath(s string) bool {
 	return strings.Contains(s, "/") || strings.HasPrefix(s, ".")
 }
+
+// formatNameStatus renders changed paths for --name-only / --name-status,
+// sorted by path so the output is stable for scripts.
+func formatNameStatus(added, modified, deleted []string, withStatus bool) []string {
+	type change struct{ status, path string }
+	changes := make([]change, 0, len(added)+len(modified)+len(deleted))
+	for _, f := range added {
+		changes = append(changes, change{"A", f})
+	}
+	for _, f := range modified {
+		changes = append(changes, change{"M", f})
+	}
+	for _, f := range deleted {
+		changes = append(changes, change{"D", f})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].path < changes[j].path })
+
+	lines := make([]string, 0, len(changes))
+	for _, c := range changes {
+		if withStatus {
+			lines = append(lines, c.status+"\t"+c.path)
+		} else {
+			lines = append(lines, c.path)
+		}
+	}
+	return lines
+}
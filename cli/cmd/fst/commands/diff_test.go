@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatNameStatusIsSortedByPath(t *testing.T) {
+	added := []string{"z.txt", "b/new.go"}
+	modified := []string{"a.txt"}
+	deleted := []string{"b/old.go"}
+
+	got := formatNameStatus(added, modified, deleted, true)
+	want := []string{"M\ta.txt", "A\tb/new.go", "D\tb/old.go", "A\tz.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("name-status = %q, want %q", got, want)
+	}
+
+	got = formatNameStatus(added, modified, deleted, false)
+	want = []string{"a.txt", "b/new.go", "b/old.go", "z.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("name-only = %q, want %q", got, want)
+	}
+}
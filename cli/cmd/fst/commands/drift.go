@@ -3,6 +3,7 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -45,6 +46,7 @@ type fileConflictSummary struct {
 func newDriftCmd() *cobra.Command {
 	var jsonOutput bool
 	var summary bool
+	var summarize bool
 	var noDirty bool
 
 	cmd := &cobra.Command{
@@ -68,25 +70,27 @@ Examples:
   fst drift feature-branch     # Drift vs workspace named "feature-branch"
   fst drift --no-dirty         # Compare committed snapshots only
   fst drift --json             # Output as JSON
-  fst drift --agent-summary    # Generate AI risk assessment`,
+  fst drift --agent-summary    # Generate AI risk assessment
+  fst drift --summarize        # One-line AI summary of each side's changes`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var target string
 			if len(args) > 0 {
 				target = args[0]
 			}
-			return runDrift(cmd, target, jsonOutput, summary, noDirty)
+			return runDrift(cmd, target, jsonOutput, summary, summarize, noDirty)
 		},
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
 	cmd.Flags().BoolVar(&summary, "agent-summary", false, "Generate AI drift risk assessment (requires configured agent)")
+	cmd.Flags().BoolVar(&summarize, "summarize", false, "Generate a one-line AI summary of each side's changes (cached per drift)")
 	cmd.Flags().BoolVar(&noDirty, "no-dirty", false, "Compare committed snapshots only, skip dirty changes")
 
 	return cmd
 }
 
-func runDrift(cmd *cobra.Command, target string, jsonOutput, generateSummary, noDirty bool) error {
+func runDrift(cmd *cobra.Command, target string, jsonOutput, generateSummary, summarize, noDirty bool) error {
 	ws, err := workspace.Open()
 	if err != nil {
 		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
@@ -108,6 +112,11 @@ func runDrift(cmd *cobra.Command, target string, jsonOutput, generateSummary, no
 		dirtySummary = subtractConflicts(dr.DirtyConflicts, dr.SnapshotConflicts)
 	}
 
+	if summarize {
+		summarizeChanges(ws.Root(), dr.OurChanges)
+		summarizeChanges(ws.Root(), dr.TheirChanges)
+	}
+
 	var summaryText string
 	if generateSummary {
 		summaryText = generateDriftSummary(dr.OurName, dr.TheirName, dr.OurChanges, dr.TheirChanges, snapshotSummary, dirtySummary)
@@ -206,11 +215,11 @@ func subtractConflicts(full, baseline *conflicts.Report) *conflictSummary {
 func generateDriftSummary(ourName, theirName string, ourChanges, theirChanges *drift.Report, snapshotConflicts, dirtyConflicts *conflictSummary) string {
 	preferredAgent, err := deps.AgentGetPreferred()
 	if err != nil {
-		fmt.Printf("Warning: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 		return ""
 	}
 
-	fmt.Printf("Generating drift assessment with %s...\n", preferredAgent.Name)
+	fmt.Fprintf(os.Stderr, "Generating drift assessment with %s...\n", preferredAgent.Name)
 
 	var snapshotList, dirtyList []agent.FileConflictSummary
 	if snapshotConflicts != nil {
@@ -239,12 +248,47 @@ func generateDriftSummary(ourName, theirName string, ourChanges, theirChanges *d
 
 	summaryText, err := agent.InvokeDriftSummary(preferredAgent, context, deps.AgentInvoke)
 	if err != nil {
-		fmt.Printf("Warning: Failed to generate summary: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Warning: Failed to generate summary: %v\n", err)
 		return ""
 	}
 	return summaryText
 }
 
+// summarizeChanges fills report.Summary with a one-line description of the
+// changed files. Summaries are cached in the workspace keyed by the report's
+// fingerprint so unchanged drift does not trigger another agent call.
+func summarizeChanges(root string, report *drift.Report) {
+	if report == nil || !report.HasChanges() {
+		return
+	}
+
+	fingerprint := report.Fingerprint()
+	if cached, ok := drift.CachedSummary(root, fingerprint); ok {
+		report.Summary = cached
+		return
+	}
+
+	preferredAgent, err := deps.AgentGetPreferred()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+
+	context := agent.BuildDiffContext(report.FilesAdded, report.FilesModified, report.FilesDeleted, nil)
+	summaryText, err := agent.InvokeChangeSummary(preferredAgent, context, deps.AgentInvoke)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to generate summary: %v\n", err)
+		return
+	}
+
+	report.Summary = summaryText
+	if summaryText != "" {
+		if err := drift.SaveSummary(root, fingerprint, summaryText); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Could not cache summary: %v\n", err)
+		}
+	}
+}
+
 func printDriftReport(result driftResult, includeDirty bool) {
 	fmt.Printf("Drift: %s <-> %s\n", result.OurWorkspace, result.TheirWorkspace)
 	fmt.Printf("Common ancestor: %s\n", result.CommonAncestorID)
@@ -301,6 +345,10 @@ func printDriftReport(result driftResult, includeDirty bool) {
 }
 
 func printChanges(report *drift.Report) {
+	if report.Summary != "" {
+		fmt.Printf("  %s\n", ui.Dim(report.Summary))
+	}
+
 	if len(report.FilesAdded) > 0 {
 		fmt.Printf("  Added (%d):\n", len(report.FilesAdded))
 		for _, f := range report.FilesAdded {
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -17,6 +18,15 @@ import (
 func newExportGitCmd() *cobra.Command {
 	var initRepo bool
 	var rebuild bool
+	var sign bool
+	var autosquash bool
+	var authorMap string
+	var messagePrefix string
+	var worktree bool
+	var pruneMapping bool
+	var dateOrder bool
+	var topoOrder bool
+	var noMetaRef bool
 
 	cmd := &cobra.Command{
 		Use:   "export",
@@ -34,22 +44,114 @@ This will:
 The mapping is stored in .fst/export/git-map.json to enable incremental exports.
 Subsequent exports only create commits for new snapshots.
 
+Use --sign (or "commit": {"sign": true, "signing_key": "..."} in the
+project config) to GPG/SSH-sign the exported commits.
+
+Use --autosquash to fold snapshots taken with 'fst snapshot --fixup' into
+the commits of the snapshots they amend. A fixup folds only when its target
+hasn't been exported yet (or with --rebuild) and the files it changes weren't
+modified in between; otherwise it is exported as an ordinary commit.
+
+Snapshots made by an agent are attributed to <agent>@fastest.local. Use
+--author-map <file> (or "commit": {"author_map": "<file>"}) to attribute
+them to real identities instead, one "agent = Name <email>" per line;
+unmapped agents keep the default.
+
+Use --message-prefix (or "commit": {"message_prefix": "..."}) to prepend a
+tag to every exported commit message, e.g. --message-prefix "[{workspace}] "
+to mark each commit with the workspace (branch) it was exported from.
+Messages that already start with the prefix are left as-is.
+
+Export never touches the git working tree. Use --worktree to check the
+exported branch out afterwards (the current workspace's branch, or the main
+workspace's outside a workspace) so it can be reviewed or pushed with normal
+git tooling. The branch's commit is checked out with a detached HEAD, so
+later exports that move the branch do not leave the working tree behind
+its HEAD; run --worktree again to catch up. The export is refused if the
+git working tree has uncommitted changes to tracked files, and git's
+checkout refuses to overwrite untracked files.
+
+Commits keep their snapshot's creation time as both author and committer
+date (--topo-order, the default), so snapshots taken on machines with
+skewed clocks can make dates jump backwards in 'git log'. Use --date-order
+to make committer dates increase monotonically along each branch instead:
+a commit whose snapshot is not newer than its parents is committed one
+second after its latest parent. Author dates always stay the real snapshot
+times. Only commits created by the export are affected; combine with
+--rebuild to re-date existing ones.
+
+Each export records the exported workspaces in the refs/fst/meta ref (as
+.fst-export/meta.json), which 'fst git import' uses to discover them. Use
+--no-meta-ref to leave that ref untouched, e.g. when pushing to a shared
+repository; the branches are still exported, but importing them then
+requires naming each one with 'fst git import --branch'.
+
+Use --prune-mapping to drop git-map.json entries that have gone stale: those
+whose snapshot no longer exists (e.g. after 'fst gc') or whose commit is no
+longer in the git repository.
+
 Examples:
   fst git export                     # Export all workspaces
   fst git export --init              # Initialize git repo if needed
-  fst git export --rebuild           # Rebuild all commits from scratch`,
+  fst git export --rebuild           # Rebuild all commits from scratch
+  fst git export --sign              # Sign exported commits
+  fst git export --autosquash        # Fold fixup snapshots into their targets
+  fst git export --author-map authors.txt
+  fst git export --message-prefix "[{workspace}] "
+  fst git export --worktree          # Export, then check out the branch
+  fst git export --prune-mapping     # Drop stale mapping entries
+  fst git export --date-order        # Keep committer dates monotonic
+  fst git export --no-meta-ref       # Don't write refs/fst/meta`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runExportGit(initRepo, rebuild)
+			if dateOrder && topoOrder {
+				return fmt.Errorf("--date-order and --topo-order cannot be combined")
+			}
+			return runExportGit(exportGitOptions{
+				initRepo:      initRepo,
+				rebuild:       rebuild,
+				sign:          sign,
+				autosquash:    autosquash,
+				authorMap:     authorMap,
+				messagePrefix: messagePrefix,
+				worktree:      worktree,
+				pruneMapping:  pruneMapping,
+				dateOrder:     dateOrder,
+				noMetaRef:     noMetaRef,
+			})
 		},
 	}
 
 	cmd.Flags().BoolVar(&initRepo, "init", false, "Initialize git repo if it doesn't exist")
 	cmd.Flags().BoolVar(&rebuild, "rebuild", false, "Rebuild all commits from scratch (ignores existing mapping)")
+	cmd.Flags().BoolVar(&sign, "sign", false, "GPG/SSH-sign exported commits")
+	cmd.Flags().BoolVar(&autosquash, "autosquash", false, "Fold fixup snapshots into the commits they amend")
+	cmd.Flags().StringVar(&authorMap, "author-map", "", "File mapping agent names to git authors (agent = Name <email>)")
+	cmd.Flags().BoolVar(&worktree, "worktree", false, "Check out the exported branch in the git working tree afterwards")
+	cmd.Flags().StringVar(&messagePrefix, "message-prefix", "", "Prefix for exported commit messages ({workspace} expands to the workspace name)")
+	cmd.Flags().BoolVar(&pruneMapping, "prune-mapping", false, "Drop mapping entries for deleted snapshots or missing commits")
+	cmd.Flags().BoolVar(&dateOrder, "date-order", false, "Make committer dates increase monotonically along each branch")
+	cmd.Flags().BoolVar(&topoOrder, "topo-order", false, "Keep each snapshot's real time as the committer date (default)")
+	cmd.Flags().BoolVar(&noMetaRef, "no-meta-ref", false, "Don't write the fst export metadata ref (refs/fst/meta)")
 
 	return cmd
 }
 
-func runExportGit(initRepo bool, rebuild bool) error {
+// exportGitOptions holds the flags of a single export run.
+type exportGitOptions struct {
+	initRepo      bool
+	rebuild       bool
+	sign          bool
+	autosquash    bool
+	authorMap     string // author map file; overrides commit.author_map
+	messagePrefix string // overrides commit.message_prefix
+	worktree      bool   // check out the exported branch afterwards
+	pruneMapping  bool   // drop stale git-map entries before exporting
+	since         string // export only snapshots after this one (sync --since)
+	dateOrder     bool   // make committer dates monotonic along each branch
+	noMetaRef     bool   // leave refs/fst/meta untouched
+}
+
+func runExportGit(opts exportGitOptions) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
@@ -65,16 +167,46 @@ func runExportGit(initRepo bool, rebuild bool) error {
 		}
 	}
 
-	return RunExportGitAt(projectRoot, initRepo, rebuild)
+	return exportGitAt(projectRoot, opts)
 }
 
 // RunExportGitAt exports all workspace snapshots to Git commits at the given project root.
 func RunExportGitAt(projectRoot string, initRepo bool, rebuild bool) error {
+	return exportGitAt(projectRoot, exportGitOptions{initRepo: initRepo, rebuild: rebuild})
+}
+
+func exportGitAt(projectRoot string, opts exportGitOptions) error {
+	initRepo, rebuild := opts.initRepo, opts.rebuild
+
 	parentCfg, err := config.LoadProjectConfigAt(projectRoot)
 	if err != nil {
 		return fmt.Errorf("failed to load project config: %w", err)
 	}
 
+	var signingKey string
+	sign := opts.sign
+	if parentCfg.Commit != nil {
+		sign = sign || parentCfg.Commit.Sign
+		signingKey = parentCfg.Commit.SigningKey
+	}
+
+	messagePrefix := opts.messagePrefix
+	if messagePrefix == "" && parentCfg.Commit != nil {
+		messagePrefix = parentCfg.Commit.MessagePrefix
+	}
+
+	authorMapPath := opts.authorMap
+	if authorMapPath == "" && parentCfg.Commit != nil && parentCfg.Commit.AuthorMap != "" {
+		authorMapPath = filepath.Join(projectRoot, parentCfg.Commit.AuthorMap)
+	}
+	var authors gitstore.AuthorMap
+	if authorMapPath != "" {
+		authors, err = gitstore.LoadAuthorMap(authorMapPath)
+		if err != nil {
+			return err
+		}
+	}
+
 	s := store.OpenAt(projectRoot)
 	configDir := filepath.Join(projectRoot, ".fst")
 
@@ -102,6 +234,24 @@ func RunExportGitAt(projectRoot string, initRepo bool, rebuild bool) error {
 		}
 	}
 
+	var worktree *worktreeCheckout
+	if opts.worktree {
+		branch, err := resolveWorktreeBranch(projectRoot, parentCfg)
+		if err != nil {
+			return err
+		}
+		worktree, err = prepareWorktreeCheckout(projectRoot, branch)
+		if err != nil {
+			return err
+		}
+	}
+	exported := false
+	defer func() {
+		if worktree != nil && !exported {
+			worktree.abort()
+		}
+	}()
+
 	tempDir, err := os.MkdirTemp("", "fst-export-git-")
 	if err != nil {
 		return fmt.Errorf("failed to create temp export directory: %w", err)
@@ -136,6 +286,10 @@ func RunExportGitAt(projectRoot string, initRepo bool, rebuild bool) error {
 			return fmt.Errorf("failed to load git mapping: %w", err)
 		}
 		mapping.RepoPath = projectRoot
+		if opts.pruneMapping {
+			removed := gitstore.PruneGitMapping(git, s, mapping)
+			fmt.Printf("Pruned %d stale mapping entries\n", len(removed))
+		}
 	}
 
 	// List all workspaces
@@ -147,6 +301,22 @@ func RunExportGitAt(projectRoot string, initRepo bool, rebuild bool) error {
 		return fmt.Errorf("no workspaces found in project")
 	}
 
+	if opts.since != "" {
+		// New commits must build on the --since snapshot's commit: rewriting
+		// them as root commits would record truncated history in the mapping.
+		sha, ok := mapping.Snapshots[opts.since]
+		if !ok || !gitutil.CommitExists(git, sha) {
+			return fmt.Errorf("--since snapshot %s has not been exported yet; export it first or sync without --since", opts.since)
+		}
+		fmt.Printf("Exporting only snapshots after %s\n", opts.since)
+	}
+
+	// Workspaces created with --track keep exporting to their branch.
+	exportMeta, err := gitstore.LoadExportMetadata(metaGit)
+	if err != nil {
+		return fmt.Errorf("failed to load export metadata: %w", err)
+	}
+
 	totalNewCommits := 0
 	exportedWorkspaces := 0
 
@@ -157,16 +327,26 @@ func RunExportGitAt(projectRoot string, initRepo bool, rebuild bool) error {
 		}
 
 		branchName := ws.WorkspaceName
+		if tracked := gitstore.TrackedBranch(exportMeta, ws.WorkspaceID); tracked != "" {
+			branchName = tracked
+		}
 		fmt.Printf("\n--- Workspace: %s (branch: %s) ---\n", ws.WorkspaceName, branchName)
 
 		newCommits, err := exportWorkspaceSnapshots(exportWorkspaceParams{
-			store:      s,
-			git:        git,
-			mapping:    mapping,
-			branchName: branchName,
-			snapshotID: ws.CurrentSnapshotID,
-			wsName:     ws.WorkspaceName,
-			rebuild:    rebuild,
+			store:         s,
+			git:           git,
+			mapping:       mapping,
+			branchName:    branchName,
+			snapshotID:    ws.CurrentSnapshotID,
+			wsName:        ws.WorkspaceName,
+			since:         opts.since,
+			dateOrder:     opts.dateOrder,
+			rebuild:       rebuild,
+			sign:          sign,
+			signingKey:    signingKey,
+			autosquash:    opts.autosquash,
+			authors:       authors,
+			messagePrefix: strings.ReplaceAll(messagePrefix, "{workspace}", ws.WorkspaceName),
 		})
 		if err != nil {
 			// Save mapping so progress from previous workspaces isn't lost
@@ -176,6 +356,10 @@ func RunExportGitAt(projectRoot string, initRepo bool, rebuild bool) error {
 		totalNewCommits += newCommits
 		exportedWorkspaces++
 
+		if opts.noMetaRef {
+			continue
+		}
+
 		// Update export metadata for this workspace
 		wsCfg := &config.WorkspaceConfig{
 			ProjectID:     parentCfg.ProjectID,
@@ -187,6 +371,10 @@ func RunExportGitAt(projectRoot string, initRepo bool, rebuild bool) error {
 		}
 	}
 
+	if exportedWorkspaces == 0 {
+		return fmt.Errorf("no workspace has snapshots to export - run 'fst snapshot' first")
+	}
+
 	// Save mapping
 	if err := gitstore.SaveGitMapping(configDir, mapping); err != nil {
 		return fmt.Errorf("failed to save mapping: %w", err)
@@ -199,17 +387,37 @@ func RunExportGitAt(projectRoot string, initRepo bool, rebuild bool) error {
 		fmt.Printf("All %d workspaces up to date\n", exportedWorkspaces)
 	}
 
+	exported = true
+	if worktree != nil {
+		return worktree.checkout()
+	}
 	return nil
 }
 
 type exportWorkspaceParams struct {
-	store      *store.Store
-	git        gitutil.Env
-	mapping    *gitstore.GitMapping
-	branchName string
-	snapshotID string // workspace head
-	wsName     string // for display
-	rebuild    bool
+	store         *store.Store
+	git           gitutil.Env
+	mapping       *gitstore.GitMapping
+	branchName    string
+	snapshotID    string // workspace head
+	wsName        string // for display
+	since         string // boundary snapshot; it and its history are not exported
+	dateOrder     bool
+	rebuild       bool
+	sign          bool
+	signingKey    string
+	autosquash    bool
+	authors       gitstore.AuthorMap
+	messagePrefix string // already expanded for this workspace
+}
+
+// prefixCommitMessage prepends prefix to msg unless msg already starts with
+// it, so re-exporting (or exporting imported commits) never doubles it.
+func prefixCommitMessage(msg, prefix string) string {
+	if prefix == "" || strings.HasPrefix(msg, prefix) {
+		return msg
+	}
+	return prefix + msg
 }
 
 func exportWorkspaceSnapshots(p exportWorkspaceParams) (int, error) {
@@ -226,7 +434,7 @@ func exportWorkspaceSnapshots(p exportWorkspaceParams) (int, error) {
 	}
 
 	// Build snapshot DAG
-	chain, err := gitstore.BuildSnapshotDAG(p.store, p.snapshotID)
+	chain, err := gitstore.BuildSnapshotDAG(p.store, p.snapshotID, p.since)
 	if err != nil {
 		return 0, fmt.Errorf("failed to build snapshot chain: %w", err)
 	}
@@ -237,6 +445,21 @@ func exportWorkspaceSnapshots(p exportWorkspaceParams) (int, error) {
 
 	fmt.Printf("Found %d snapshots\n", len(chain))
 
+	var squash *gitstore.AutosquashPlan
+	if p.autosquash {
+		var warnings []string
+		squash, warnings, err = gitstore.PlanAutosquash(p.store, chain, func(id string) bool {
+			sha, ok := p.mapping.Snapshots[id]
+			return ok && !p.rebuild && gitutil.CommitExists(p.git, sha)
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to plan autosquash: %w", err)
+		}
+		for _, w := range warnings {
+			fmt.Printf("  warning: %s\n", w)
+		}
+	}
+
 	newCommits := 0
 	var lastCommitSHA string
 
@@ -251,14 +474,25 @@ func exportWorkspaceSnapshots(p exportWorkspaceParams) (int, error) {
 			fmt.Printf("  %s: mapped commit missing, re-exporting\n", snap.ID[:12])
 		}
 
+		// A folded fixup maps to the rewritten commit of its parent, which
+		// already carries its changes.
+		if parentID, ok := squash.FoldedInto(snap.ID); ok {
+			sha := p.mapping.Snapshots[parentID]
+			p.mapping.Snapshots[snap.ID] = sha
+			lastCommitSHA = sha
+			fmt.Printf("  %s: folded into %s\n", snap.ID[:12], snap.FixupOf[:12])
+			continue
+		}
+
 		// Load manifest
 		m, err := p.store.LoadManifest(snap.ManifestHash)
 		if err != nil {
 			return 0, fmt.Errorf("failed to load manifest for %s: %w", snap.ID[:12], err)
 		}
+		m = squash.Apply(snap.ID, m)
 
 		// Restore files from blobs to temp working directory
-		if err := gitstore.RestoreFilesFromManifest(p.git.WorkTree, p.store, m); err != nil {
+		if err := gitstore.RestoreFilesFromManifest(p.git.WorkTree, p.store, m, gitstore.RestoreOptions{PruneEmptyDirs: true}); err != nil {
 			return 0, fmt.Errorf("failed to restore files for %s: %w", snap.ID[:12], err)
 		}
 
@@ -272,6 +506,7 @@ func exportWorkspaceSnapshots(p exportWorkspaceParams) (int, error) {
 		if commitMsg == "" {
 			commitMsg = fmt.Sprintf("Snapshot %s", snap.ID[:12])
 		}
+		commitMsg = prefixCommitMessage(commitMsg, p.messagePrefix)
 
 		parentSHAs, err := gitstore.ResolveGitParentSHAs(p.git, p.mapping, snap.ParentSnapshotIDs)
 		if err != nil {
@@ -286,7 +521,28 @@ func exportWorkspaceSnapshots(p exportWorkspaceParams) (int, error) {
 			return 0, fmt.Errorf("failed to write tree for %s: %w", snap.ID[:12], err)
 		}
 
-		meta := gitstore.CommitMetaFromSnapshot(snap)
+		meta := gitstore.CommitMetaWithAuthors(snap, p.authors)
+		if p.dateOrder && len(parentSHAs) > 0 {
+			var parentDates []time.Time
+			for _, sha := range parentSHAs {
+				if d, err := gitutil.CommitterDate(p.git, sha); err == nil {
+					parentDates = append(parentDates, d)
+				}
+			}
+			if date := gitstore.MonotonicCommitterDate(snap.CreatedAt, parentDates); date != snap.CreatedAt {
+				if meta == nil {
+					meta = &gitutil.CommitMeta{}
+				}
+				meta.CommitterDate = date
+			}
+		}
+		if p.sign {
+			if meta == nil {
+				meta = &gitutil.CommitMeta{}
+			}
+			meta.Sign = true
+			meta.SigningKey = p.signingKey
+		}
 		sha, err := gitutil.CreateCommitWithParents(p.git, treeSHA, commitMsg, parentSHAs, meta)
 		if err != nil {
 			return 0, fmt.Errorf("failed to create commit for %s: %w", snap.ID[:12], err)
@@ -312,4 +568,3 @@ func exportWorkspaceSnapshots(p exportWorkspaceParams) (int, error) {
 
 	return newCommits, nil
 }
-
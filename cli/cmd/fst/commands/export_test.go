@@ -227,6 +227,170 @@ func TestExportGitSingleWorkspace(t *testing.T) {
 	}
 }
 
+func TestExportGitWorktree(t *testing.T) {
+	projectRoot, wsARoot, _ := setupExportProject(t,
+		map[string]string{"a.txt": "from a"},
+		map[string]string{"b.txt": "from b"},
+	)
+
+	runExport := func() error {
+		restoreCwd := chdir(t, wsARoot)
+		defer restoreCwd()
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"git", "export", "--init", "--worktree"})
+		return cmd.Execute()
+	}
+
+	if err := runExport(); err != nil {
+		t.Fatalf("export --worktree: %v", err)
+	}
+	if head, tip := gitOutput(t, projectRoot, "rev-parse", "HEAD"), gitOutput(t, projectRoot, "rev-parse", "ws-a"); head != tip {
+		t.Fatalf("expected ws-a's commit checked out, got %s (ws-a at %s)", head, tip)
+	}
+	if data, err := os.ReadFile(filepath.Join(projectRoot, "a.txt")); err != nil || string(data) != "from a" {
+		t.Fatalf("expected a.txt in the working tree, got %q, %v", data, err)
+	}
+
+	// A new snapshot on the checked-out branch updates the working tree.
+	if err := os.WriteFile(filepath.Join(wsARoot, "c.txt"), []byte("later"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	runSnapshotCmd(t, wsARoot, "add c")
+
+	// A plain export moves the branch but leaves the working tree clean.
+	restoreCwd := chdir(t, wsARoot)
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"git", "export"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	restoreCwd()
+	if status := gitOutput(t, projectRoot, "status", "--porcelain", "--untracked-files=no"); status != "" {
+		t.Fatalf("expected a clean working tree after a plain export, got:\n%s", status)
+	}
+
+	if err := runExport(); err != nil {
+		t.Fatalf("second export --worktree: %v", err)
+	}
+	if head, tip := gitOutput(t, projectRoot, "rev-parse", "HEAD"), gitOutput(t, projectRoot, "rev-parse", "ws-a"); head != tip {
+		t.Fatalf("expected ws-a's new commit checked out, got %s (ws-a at %s)", head, tip)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "c.txt")); err != nil {
+		t.Fatalf("expected c.txt in the working tree: %v", err)
+	}
+
+	// Uncommitted changes to tracked files are never clobbered.
+	if err := os.WriteFile(filepath.Join(projectRoot, "a.txt"), []byte("edited by hand"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	err := runExport()
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Fatalf("expected dirty working tree to be refused, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(projectRoot, "a.txt")); string(data) != "edited by hand" {
+		t.Fatalf("expected local edit to survive, got %q", data)
+	}
+}
+
+func TestExportGitAutosquash(t *testing.T) {
+	projectRoot := t.TempDir()
+
+	if err := config.SaveProjectConfigAt(projectRoot, &config.ProjectConfig{
+		ProjectID:   "proj-squash",
+		ProjectName: "squash",
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+	s := store.OpenAt(projectRoot)
+	if err := s.EnsureDirs(); err != nil {
+		t.Fatalf("EnsureDirs: %v", err)
+	}
+
+	wsRoot := filepath.Join(projectRoot, "ws-one")
+	if err := os.MkdirAll(wsRoot, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := config.InitAt(wsRoot, "proj-squash", "ws-one-id", "ws-one", ""); err != nil {
+		t.Fatalf("InitAt: %v", err)
+	}
+	if err := s.RegisterWorkspace(store.WorkspaceInfo{
+		WorkspaceID:   "ws-one-id",
+		WorkspaceName: "ws-one",
+		Path:          wsRoot,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		t.Fatalf("RegisterWorkspace: %v", err)
+	}
+
+	restoreCwd := chdir(t, wsRoot)
+	snapshot := func(file, content string, args ...string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(wsRoot, file), []byte(content), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		cmd := NewRootCmd()
+		cmd.SetArgs(append([]string{"snapshot"}, args...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("snapshot %v: %v", args, err)
+		}
+	}
+	snapshot("a.txt", "v1", "-m", "first commit")
+	cfg, err := config.LoadAt(wsRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	firstID := cfg.CurrentSnapshotID
+	snapshot("b.txt", "two", "-m", "second commit")
+	// Folds: a.txt is untouched by the second snapshot.
+	snapshot("a.txt", "v1 fixed", "--fixup", firstID[:12])
+	// Doesn't fold: b.txt was added after the target.
+	snapshot("b.txt", "two, fixed", "--fixup", firstID[:12])
+	restoreCwd()
+
+	meta, err := s.LoadSnapshotMeta(firstID)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if meta.FixupOf != "" {
+		t.Fatalf("expected target snapshot to have no fixup_of, got %q", meta.FixupOf)
+	}
+
+	restoreCwd = chdir(t, projectRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"git", "export", "--init", "--autosquash"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	out := gitOutput(t, projectRoot, "log", "--format=%s", "ws-one", "--")
+	subjects := nonEmptyLines(out)
+	want := []string{"fixup! first commit", "second commit", "first commit"}
+	if strings.Join(subjects, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected commits %v, got %v", want, subjects)
+	}
+
+	if got := gitOutput(t, projectRoot, "show", "ws-one~2:a.txt"); got != "v1 fixed" {
+		t.Fatalf("expected fixup folded into first commit, got a.txt=%q", got)
+	}
+	if got := gitOutput(t, projectRoot, "show", "ws-one~1:b.txt"); got != "two" {
+		t.Fatalf("expected second commit unaffected, got b.txt=%q", got)
+	}
+	if got := gitOutput(t, projectRoot, "show", "ws-one:b.txt"); got != "two, fixed" {
+		t.Fatalf("expected unfoldable fixup exported as its own commit, got b.txt=%q", got)
+	}
+
+	mapping, err := gitstore.LoadGitMapping(filepath.Join(projectRoot, ".fst"))
+	if err != nil {
+		t.Fatalf("LoadGitMapping: %v", err)
+	}
+	if len(mapping.Snapshots) != 4 {
+		t.Fatalf("expected all 4 snapshots mapped, got %d", len(mapping.Snapshots))
+	}
+}
+
 func TestExportGitMultiWorkspace(t *testing.T) {
 	projectRoot, _, _ := setupExportProject(t,
 		map[string]string{"a.txt": "target"},
@@ -517,6 +681,145 @@ func TestExportGitRebuild(t *testing.T) {
 	}
 }
 
+func TestExportGitSince(t *testing.T) {
+	projectRoot, wsARoot, _ := setupExportProject(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+	aCfg, err := config.LoadAt(wsARoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	s := store.OpenAt(projectRoot)
+	head, err := s.LoadSnapshotMeta(aCfg.CurrentSnapshotID)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	baseID := head.ParentSnapshotIDs[0]
+
+	restoreCwd := chdir(t, projectRoot)
+	defer restoreCwd()
+
+	// The base was never exported, so there is no commit to build on.
+	if err := exportGitAt(projectRoot, exportGitOptions{initRepo: true, since: baseID}); err == nil || !strings.Contains(err.Error(), "has not been exported") {
+		t.Fatalf("expected export --since an unexported snapshot to fail, got %v", err)
+	}
+	if err := exportGitAt(projectRoot, exportGitOptions{initRepo: true}); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	// Once the base is exported, new commits build on its commit.
+	restoreWs := chdir(t, wsARoot)
+	if err := os.WriteFile(filepath.Join(wsARoot, "c.txt"), []byte("new"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "after since"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	restoreWs()
+
+	if err := exportGitAt(projectRoot, exportGitOptions{since: head.ID}); err != nil {
+		t.Fatalf("export --since head: %v", err)
+	}
+	lines := nonEmptyLines(gitOutput(t, projectRoot, "log", "--oneline", "ws-a", "--"))
+	if len(lines) != 3 {
+		t.Fatalf("expected the new commit on top of the exported head, got %d commits", len(lines))
+	}
+}
+
+func TestExportGitDateOrder(t *testing.T) {
+	projectRoot, wsARoot, _ := setupExportProject(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+	aCfg, err := config.LoadAt(wsARoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+
+	// Simulate clock skew: the head claims to predate its parent.
+	s := store.OpenAt(projectRoot)
+	head, err := s.LoadSnapshotMeta(aCfg.CurrentSnapshotID)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	head.CreatedAt = "2000-01-01T00:00:00Z"
+	if err := s.WriteSnapshotMeta(head); err != nil {
+		t.Fatalf("WriteSnapshotMeta: %v", err)
+	}
+
+	restoreCwd := chdir(t, projectRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"git", "export", "--init", "--date-order"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export --date-order: %v", err)
+	}
+
+	dates := nonEmptyLines(gitOutput(t, projectRoot, "log", "--format=%aI %cI", "ws-a", "--"))
+	if len(dates) != 2 {
+		t.Fatalf("expected 2 commits, got %v", dates)
+	}
+	head1 := strings.Fields(dates[0])
+	base := strings.Fields(dates[1])
+	if !strings.HasPrefix(head1[0], "2000-01-01T00:00:00") {
+		t.Fatalf("expected the real author date to be kept, got %s", head1[0])
+	}
+	headCommitted, _ := time.Parse(time.RFC3339, head1[1])
+	baseCommitted, _ := time.Parse(time.RFC3339, base[1])
+	if !headCommitted.After(baseCommitted) {
+		t.Fatalf("expected committer dates to increase, got %s after %s", head1[1], base[1])
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"git", "export", "--date-order", "--topo-order"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected --date-order with --topo-order to fail")
+	}
+}
+
+func TestExportGitNoMetaRef(t *testing.T) {
+	projectRoot, _, _ := setupExportProject(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, projectRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"git", "export", "--init", "--no-meta-ref"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export --no-meta-ref: %v", err)
+	}
+
+	if branches := gitOutput(t, projectRoot, "branch", "--list", "ws-a", "ws-b"); !strings.Contains(branches, "ws-a") || !strings.Contains(branches, "ws-b") {
+		t.Fatalf("expected both workspace branches, got %q", branches)
+	}
+	if refs := gitOutput(t, projectRoot, "for-each-ref", gitstore.FstMetaRef); refs != "" {
+		t.Fatalf("expected no meta ref, got %q", refs)
+	}
+}
+
+func TestPrefixCommitMessage(t *testing.T) {
+	tests := []struct {
+		msg, prefix, want string
+	}{
+		{"add login", "", "add login"},
+		{"add login", "[feature-x] ", "[feature-x] add login"},
+		{"[feature-x] add login", "[feature-x] ", "[feature-x] add login"},
+		{"[feature-y] add login", "[feature-x] ", "[feature-x] [feature-y] add login"},
+	}
+	for _, tt := range tests {
+		if got := prefixCommitMessage(tt.msg, tt.prefix); got != tt.want {
+			t.Errorf("prefixCommitMessage(%q, %q) = %q, want %q", tt.msg, tt.prefix, got, tt.want)
+		}
+	}
+}
+
 func TestExportGitSkipsEmptyWorkspace(t *testing.T) {
 	projectRoot, _, _ := setupExportProject(t,
 		map[string]string{"a.txt": "one"},
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/gitstore"
+	"github.com/ankitiscracked/fastest/cli/internal/gitutil"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+// worktreeCheckout checks an exported branch out into the project's real
+// git working tree for 'fst git export --worktree'.
+type worktreeCheckout struct {
+	git      gitutil.Env
+	branch   string
+	detached bool // HEAD was detached from branch so the export could move it
+}
+
+// resolveWorktreeBranch picks the branch --worktree checks out: the current
+// workspace's branch, or the main workspace's when run outside a workspace.
+// A workspace created with --track uses its tracked branch.
+func resolveWorktreeBranch(projectRoot string, parentCfg *config.ProjectConfig) (string, error) {
+	var id, name string
+	if wsRoot, err := config.FindWorkspaceRoot(); err == nil {
+		if wsCfg, err := config.LoadAt(wsRoot); err == nil && wsCfg.WorkspaceName != "" {
+			id, name = wsCfg.WorkspaceID, wsCfg.WorkspaceName
+		}
+	}
+	if name == "" && parentCfg.MainWorkspaceID != "" {
+		if info, err := store.OpenAt(projectRoot).FindWorkspaceByID(parentCfg.MainWorkspaceID); err == nil {
+			id, name = info.WorkspaceID, info.WorkspaceName
+		}
+	}
+	if name == "" {
+		return "", fmt.Errorf("--worktree needs a branch: run it from a workspace directory or set a main workspace")
+	}
+	meta, err := gitstore.LoadExportMetadataFromRepo(projectRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to load export metadata: %w", err)
+	}
+	if tracked := gitstore.TrackedBranch(meta, id); tracked != "" {
+		return tracked, nil
+	}
+	return name, nil
+}
+
+// prepareWorktreeCheckout refuses to continue if the working tree at
+// projectRoot has uncommitted changes to tracked files. If branch is already
+// checked out, HEAD is detached at its current commit so the export can move
+// the branch and the later checkout updates the files like a fast-forward.
+func prepareWorktreeCheckout(projectRoot, branch string) (*worktreeCheckout, error) {
+	git := gitutil.NewEnv(projectRoot, projectRoot, filepath.Join(projectRoot, ".git", "index"))
+	w := &worktreeCheckout{git: git, branch: branch}
+
+	status, err := git.Output("status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check git working tree: %w", err)
+	}
+	if status != "" {
+		return nil, fmt.Errorf("refusing --worktree: the git working tree at %s has uncommitted changes:\n%s\nCommit or stash them first", projectRoot, status)
+	}
+
+	if current, err := gitutil.CurrentBranch(git); err == nil && current == branch {
+		if err := git.Run("checkout", "--quiet", "--detach"); err != nil {
+			return nil, fmt.Errorf("failed to detach HEAD from %s: %w", branch, err)
+		}
+		w.detached = true
+	}
+	return w, nil
+}
+
+// checkout switches the working tree to the exported branch's commit with
+// a detached HEAD, so later exports that move the branch leave the working
+// tree clean. git refuses to overwrite untracked files, so nothing outside
+// the repository's history is clobbered.
+func (w *worktreeCheckout) checkout() error {
+	if exists, err := gitutil.BranchExists(w.git, w.branch); err != nil || !exists {
+		w.abort()
+		return fmt.Errorf("branch %s was not exported (does the workspace have snapshots?)", w.branch)
+	}
+	if err := w.git.Run("checkout", "--quiet", "--detach", w.branch); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", w.branch, err)
+	}
+	fmt.Printf("Checked out branch %s (detached HEAD) in %s\n", w.branch, w.git.WorkTree)
+	return nil
+}
+
+// abort reattaches HEAD to the branch if prepareWorktreeCheckout detached
+// it, for use when the export fails.
+func (w *worktreeCheckout) abort() {
+	if w.detached {
+		if err := w.git.Run("checkout", "--quiet", w.branch); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: HEAD left detached; run 'git checkout %s': %v\n", w.branch, err)
+		}
+	}
+}
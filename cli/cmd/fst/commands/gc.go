@@ -18,6 +18,7 @@ func init() {
 
 func newGCCmd() *cobra.Command {
 	var dryRun bool
+	var repack bool
 
 	cmd := &cobra.Command{
 		Use:   "gc",
@@ -29,18 +30,24 @@ A snapshot is reachable if it is an ancestor of any workspace's current or
 base snapshot. Unreachable snapshots are leftovers from history rewriting
 (drop, squash, rebase) and can be safely removed.
 
+Use --repack to also consolidate the remaining blobs into a single pack
+file with an index, instead of one file per blob. This speeds up stores
+with very many small blobs on filesystems that handle many files poorly.
+Blobs written later stay loose until the next repack.
+
 Must be run from within a project folder.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runGC(dryRun)
+			return runGC(dryRun, repack)
 		},
 	}
 
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be deleted without deleting")
+	cmd.Flags().BoolVar(&repack, "repack", false, "Consolidate loose blobs into a pack file")
 
 	return cmd
 }
 
-func runGC(dryRun bool) error {
+func runGC(dryRun, repack bool) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
@@ -62,10 +69,15 @@ func runGC(dryRun bool) error {
 	defer gcLock.Release()
 
 	s := store.OpenAt(projectRoot)
-	result, err := s.GC(store.GCOpts{DryRun: dryRun})
+	result, err := s.GC(store.GCOpts{DryRun: dryRun, Repack: repack})
 	if err != nil {
 		return err
 	}
+	defer printRepackResult(result.Repack)
+
+	if dryRun && repack {
+		defer fmt.Println("Would repack remaining blobs into a pack file.")
+	}
 
 	if len(result.UnreachableSnapshots) == 0 && len(result.OrphanedBlobs) == 0 {
 		fmt.Println("No unreachable snapshots or orphaned blobs found - nothing to collect.")
@@ -102,3 +114,21 @@ func runGC(dryRun bool) error {
 
 	return nil
 }
+
+func printRepackResult(r *store.RepackResult) {
+	if r == nil {
+		return
+	}
+	if r.Loose == 0 && r.Packs == 0 && r.Dropped == 0 {
+		fmt.Println("Blobs already packed - nothing to repack.")
+		return
+	}
+	fmt.Printf("Repacked %d blob(s) (%d loose) into one pack of %s", r.Packed, r.Loose, formatBytes(r.Bytes))
+	if r.Packs > 0 {
+		fmt.Printf(", replacing %d pack(s)", r.Packs)
+	}
+	fmt.Println(".")
+	if r.Skipped > 0 {
+		fmt.Printf("Left %d corrupt blob(s) loose - run 'fst blobs verify'.\n", r.Skipped)
+	}
+}
@@ -119,7 +119,7 @@ func runGitHubExport(repo string, initRepo bool, rebuild bool, remoteName string
 		}
 	}
 
-	if err := runExportGit(initRepo, rebuild); err != nil {
+	if err := runExportGit(exportGitOptions{initRepo: initRepo, rebuild: rebuild}); err != nil {
 		return err
 	}
 
@@ -172,7 +172,7 @@ func runGitHubImport(repo string, projectName string, rebuild bool, noGH bool) e
 		return fmt.Errorf("failed to fetch export metadata refs: %w", err)
 	}
 
-	return runImportGit(tempRepoDir, projectName, rebuild)
+	return runImportGit(tempRepoDir, projectName, nil, rebuild)
 }
 
 func hasGH() bool {
@@ -270,6 +270,23 @@ func parseGitHubRepo(input string) (string, string, error) {
 	return "", "", fmt.Errorf("unsupported GitHub repo format: %s", repo)
 }
 
+// githubRemoteURL returns the remote URL of a GitHub owner/repo slug, over
+// SSH or HTTPS.
+func githubRemoteURL(slug string, ssh bool) string {
+	if ssh {
+		return "git@github.com:" + slug + ".git"
+	}
+	return "https://github.com/" + slug + ".git"
+}
+
+// sameGitHubRepo reports whether two remote URLs point at the same GitHub
+// repository, regardless of transport (SSH or HTTPS).
+func sameGitHubRepo(a, b string) bool {
+	slugA, _, errA := parseGitHubRepo(a)
+	slugB, _, errB := parseGitHubRepo(b)
+	return errA == nil && errB == nil && strings.EqualFold(slugA, slugB)
+}
+
 func isGitHubHost(host string) bool {
 	host = strings.ToLower(strings.TrimSpace(host))
 	if host == "github.com" {
@@ -18,6 +18,7 @@ import (
 func newImportGitCmd() *cobra.Command {
 	var projectName string
 	var rebuild bool
+	var branches []string
 
 	cmd := &cobra.Command{
 		Use:   "import <repo-path>",
@@ -30,18 +31,25 @@ If no project exists, a new one is created.
 The repository must contain fst export metadata (refs/fst/meta),
 which is written by 'fst git export'.
 
+Use --branch (repeatable) to import only the named branches. Branches
+that aren't in the export metadata, e.g. from an export made with
+--no-meta-ref, are imported as new workspaces named after the branch; with
+--branch the metadata ref may be missing altogether.
+
 Examples:
   fst git import /path/to/repo              # Import into current or new project
   fst git import /path/to/repo --project my-project  # Create named project
-  fst git import /path/to/repo --rebuild    # Overwrite existing snapshots`,
+  fst git import /path/to/repo --rebuild    # Overwrite existing snapshots
+  fst git import /path/to/repo --branch main --branch feature`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runImportGit(args[0], projectName, rebuild)
+			return runImportGit(args[0], projectName, branches, rebuild)
 		},
 	}
 
 	cmd.Flags().StringVarP(&projectName, "project", "p", "", "Project name when creating a new project")
 	cmd.Flags().BoolVar(&rebuild, "rebuild", false, "Rebuild snapshots from scratch (overwrites existing snapshot history)")
+	cmd.Flags().StringArrayVar(&branches, "branch", nil, "Import only this branch (repeatable; works without export metadata)")
 
 	return cmd
 }
@@ -55,7 +63,7 @@ type importTarget struct {
 	Existing      bool
 }
 
-func runImportGit(repoPath, projectName string, rebuild bool) error {
+func runImportGit(repoPath, projectName string, branches []string, rebuild bool) error {
 	repoRoot, err := filepath.Abs(repoPath)
 	if err != nil {
 		return fmt.Errorf("failed to resolve repo path: %w", err)
@@ -77,8 +85,13 @@ func runImportGit(repoPath, projectName string, rebuild bool) error {
 	if err != nil {
 		return fmt.Errorf("failed to load fst export metadata: %w", err)
 	}
-	if meta == nil || len(meta.Workspaces) == 0 {
-		return fmt.Errorf("no fst export metadata found (missing refs/fst/meta)")
+	if len(branches) > 0 {
+		meta, err = selectImportBranches(git, meta, branches)
+		if err != nil {
+			return err
+		}
+	} else if meta == nil || len(meta.Workspaces) == 0 {
+		return fmt.Errorf("no fst export metadata found (missing refs/fst/meta); name branches with --branch to import them anyway")
 	}
 
 	cwd, err := os.Getwd()
@@ -137,13 +150,48 @@ func runImportGit(repoPath, projectName string, rebuild bool) error {
 	}
 
 	for _, target := range targets {
-		if err := importWorkspaceFromGit(git, s, target, rebuild); err != nil {
+		if _, err := importWorkspaceFromGit(git, s, target, rebuild); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// selectImportBranches narrows meta to the named branches. A branch the
+// metadata doesn't know about (or any branch, when meta is nil) becomes a
+// workspace named after it.
+func selectImportBranches(git gitutil.Env, meta *gitstore.ExportMeta, branches []string) (*gitstore.ExportMeta, error) {
+	selected := &gitstore.ExportMeta{Workspaces: make(map[string]gitstore.ExportWorkspaceMeta)}
+	if meta != nil {
+		selected.ProjectID = meta.ProjectID
+	}
+	for _, branch := range branches {
+		exists, err := gitutil.BranchExists(git, branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check branch: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("branch not found: %s", branch)
+		}
+		found := false
+		if meta != nil {
+			for key, entry := range meta.Workspaces {
+				if entry.Branch == branch {
+					selected.Workspaces[key] = entry
+					found = true
+				}
+			}
+		}
+		if !found {
+			selected.Workspaces["branch:"+branch] = gitstore.ExportWorkspaceMeta{
+				WorkspaceName: branch,
+				Branch:        branch,
+			}
+		}
+	}
+	return selected, nil
+}
+
 func buildImportTargets(parentRoot string, parentCfg *config.ProjectConfig, meta *gitstore.ExportMeta) ([]importTarget, error) {
 	if parentCfg == nil {
 		return nil, fmt.Errorf("missing project configuration")
@@ -202,52 +250,54 @@ func existingWorkspaceConfig(root string) (bool, *config.WorkspaceConfig, error)
 	return true, cfg, nil
 }
 
-func importWorkspaceFromGit(git gitutil.Env, s *store.Store, target importTarget, rebuild bool) error {
+// importWorkspaceFromGit imports the history of target.Branch into the
+// target workspace and returns the snapshot created for each commit.
+func importWorkspaceFromGit(git gitutil.Env, s *store.Store, target importTarget, rebuild bool) (map[string]string, error) {
 	targetRoot := target.Root
 	if targetRoot == "" {
-		return fmt.Errorf("missing workspace path")
+		return nil, fmt.Errorf("missing workspace path")
 	}
 	if target.ProjectID == "" {
-		return fmt.Errorf("missing project ID for workspace import")
+		return nil, fmt.Errorf("missing project ID for workspace import")
 	}
 
 	if target.Existing {
 		if _, err := os.Stat(filepath.Join(targetRoot, ".fst", "config.json")); err != nil {
-			return fmt.Errorf("workspace config missing at %s", targetRoot)
+			return nil, fmt.Errorf("workspace config missing at %s", targetRoot)
 		}
 	} else {
 		if _, err := os.Stat(targetRoot); err == nil {
-			return fmt.Errorf("target workspace directory already exists: %s", targetRoot)
+			return nil, fmt.Errorf("target workspace directory already exists: %s", targetRoot)
 		}
 		if err := os.MkdirAll(targetRoot, 0755); err != nil {
-			return fmt.Errorf("failed to create workspace directory: %w", err)
+			return nil, fmt.Errorf("failed to create workspace directory: %w", err)
 		}
 		workspaceID := target.WorkspaceID
 		if workspaceID == "" {
 			workspaceID = generateWorkspaceID()
 		}
 		if err := config.InitAt(targetRoot, target.ProjectID, workspaceID, target.WorkspaceName, ""); err != nil {
-			return fmt.Errorf("failed to initialize workspace: %w", err)
+			return nil, fmt.Errorf("failed to initialize workspace: %w", err)
 		}
 		target.WorkspaceID = workspaceID
 	}
 
 	cfg, err := config.LoadAt(targetRoot)
 	if err != nil {
-		return fmt.Errorf("failed to load workspace config: %w", err)
+		return nil, fmt.Errorf("failed to load workspace config: %w", err)
 	}
 	if target.WorkspaceID != "" && cfg.WorkspaceID != target.WorkspaceID {
-		return fmt.Errorf("workspace ID mismatch for %s", targetRoot)
+		return nil, fmt.Errorf("workspace ID mismatch for %s", targetRoot)
 	}
 	if cfg.ProjectID != "" && cfg.ProjectID != target.ProjectID {
-		return fmt.Errorf("project ID mismatch for %s", targetRoot)
+		return nil, fmt.Errorf("project ID mismatch for %s", targetRoot)
 	}
 	if cfg.WorkspaceName == "" && target.WorkspaceName != "" {
 		cfg.WorkspaceName = target.WorkspaceName
 	}
 
 	if cfg.CurrentSnapshotID != "" && !rebuild {
-		return fmt.Errorf("workspace %s already has snapshots (use --rebuild to overwrite)", cfg.WorkspaceName)
+		return nil, fmt.Errorf("workspace %s already has snapshots (use --rebuild to overwrite)", cfg.WorkspaceName)
 	}
 
 	if rebuild {
@@ -257,13 +307,13 @@ func importWorkspaceFromGit(git gitutil.Env, s *store.Store, target importTarget
 
 	tempWorkDir, err := os.MkdirTemp("", "fst-import-worktree-")
 	if err != nil {
-		return fmt.Errorf("failed to create temp worktree: %w", err)
+		return nil, fmt.Errorf("failed to create temp worktree: %w", err)
 	}
 	defer os.RemoveAll(tempWorkDir)
 
 	tempIndexDir, err := os.MkdirTemp("", "fst-import-index-")
 	if err != nil {
-		return fmt.Errorf("failed to create temp index dir: %w", err)
+		return nil, fmt.Errorf("failed to create temp index dir: %w", err)
 	}
 	defer os.RemoveAll(tempIndexDir)
 
@@ -272,33 +322,34 @@ func importWorkspaceFromGit(git gitutil.Env, s *store.Store, target importTarget
 
 	commits, err := gitutil.RevList(importGit, target.Branch)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(commits) == 0 {
-		return fmt.Errorf("no commits found for branch %s", target.Branch)
+		return nil, fmt.Errorf("no commits found for branch %s", target.Branch)
 	}
 
 	fmt.Printf("\n--- Workspace: %s (branch: %s) ---\n", target.WorkspaceName, target.Branch)
 	fmt.Printf("Found %d commits\n", len(commits))
 
 	commitToSnapshot := make(map[string]string, len(commits))
+	storedBlobs := make(map[string]bool)
 	var firstSnapshot string
 	var lastSnapshot string
 
 	for _, commit := range commits {
 		info, err := gitutil.ReadCommitInfo(importGit, commit)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if err := gitutil.CheckoutTree(importGit, commit); err != nil {
-			return err
+			return nil, err
 		}
 
 		parentSnapshots := make([]string, 0, len(info.Parents))
 		for _, parent := range info.Parents {
 			snapID, ok := commitToSnapshot[parent]
 			if !ok {
-				return fmt.Errorf("parent commit %s not imported for %s", parent, commit)
+				return nil, fmt.Errorf("parent commit %s not imported for %s", parent, commit)
 			}
 			parentSnapshots = append(parentSnapshots, snapID)
 		}
@@ -308,9 +359,9 @@ func importWorkspaceFromGit(git gitutil.Env, s *store.Store, target importTarget
 			agentName = info.AuthorName
 		}
 
-		snapshotID, err := gitstore.CreateImportedSnapshot(s, tempWorkDir, cfg, parentSnapshots, info.Subject, info.AuthorDate, info.AuthorName, info.AuthorEmail, agentName)
+		snapshotID, err := gitstore.CreateImportedSnapshotWithBlobs(s, tempWorkDir, cfg, parentSnapshots, info.Subject, info.AuthorDate, info.AuthorName, info.AuthorEmail, agentName, storedBlobs)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		commitToSnapshot[commit] = snapshotID
 		if firstSnapshot == "" {
@@ -324,7 +375,7 @@ func importWorkspaceFromGit(git gitutil.Env, s *store.Store, target importTarget
 		cfg.BaseSnapshotID = firstSnapshot
 	}
 	if err := config.SaveAt(targetRoot, cfg); err != nil {
-		return fmt.Errorf("failed to save workspace config: %w", err)
+		return nil, fmt.Errorf("failed to save workspace config: %w", err)
 	}
 
 	// Register in project-level registry
@@ -340,6 +391,105 @@ func importWorkspaceFromGit(git gitutil.Env, s *store.Store, target importTarget
 	}
 
 	fmt.Printf("Imported %d commits into workspace '%s'\n", len(commits), cfg.WorkspaceName)
-	return nil
+	return commitToSnapshot, nil
 }
 
+// importGitBranches imports the history of every local branch of the git
+// repository at mainRoot into the project at projectRoot. The checked-out
+// branch is imported into the existing workspace at mainRoot; every other
+// branch becomes a sibling workspace. Returns the number of snapshots created.
+// If the import fails, the sibling workspaces it created and the project and
+// main workspace metadata are removed, leaving no half-initialized project.
+func importGitBranches(projectRoot, projectID, mainRoot, mainWorkspaceID, mainWorkspaceName string) (int, error) {
+	var createdRoots []string
+	imported := false
+	defer func() {
+		if imported {
+			return
+		}
+		for _, root := range createdRoots {
+			os.RemoveAll(root)
+		}
+		os.RemoveAll(filepath.Join(mainRoot, ".fst"))
+		os.RemoveAll(filepath.Join(projectRoot, ".fst"))
+	}()
+
+	tempRepoDir, err := os.MkdirTemp("", "fst-init-import-")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temp import directory: %w", err)
+	}
+	defer os.RemoveAll(tempRepoDir)
+
+	git := gitutil.NewEnv(mainRoot, tempRepoDir, filepath.Join(tempRepoDir, "index"))
+
+	branches, err := gitutil.ListBranches(git)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list git branches: %w", err)
+	}
+	if len(branches) == 0 {
+		return 0, fmt.Errorf("git repository has no commits to import")
+	}
+
+	// A detached HEAD is imported into the main workspace as-is.
+	currentBranch, err := gitutil.CurrentBranch(git)
+	if err != nil {
+		return 0, fmt.Errorf("failed to determine current git branch: %w", err)
+	}
+
+	s := store.OpenAt(projectRoot)
+	if err := s.EnsureDirs(); err != nil {
+		return 0, fmt.Errorf("failed to create store directories: %w", err)
+	}
+
+	targets := []importTarget{{
+		WorkspaceID:   mainWorkspaceID,
+		WorkspaceName: mainWorkspaceName,
+		Branch:        currentBranch,
+		Root:          mainRoot,
+		ProjectID:     projectID,
+		Existing:      true,
+	}}
+	for _, branch := range branches {
+		if branch == currentBranch {
+			continue
+		}
+		name := strings.ReplaceAll(branch, "/", "-")
+		root := filepath.Join(projectRoot, name)
+		if name == mainWorkspaceName {
+			fmt.Printf("Warning: Skipping branch %s (conflicts with workspace '%s')\n", branch, mainWorkspaceName)
+			continue
+		}
+		if _, err := os.Stat(root); err == nil {
+			fmt.Printf("Warning: Skipping branch %s (%s already exists)\n", branch, root)
+			continue
+		}
+		targets = append(targets, importTarget{
+			WorkspaceName: name,
+			Branch:        branch,
+			Root:          root,
+			ProjectID:     projectID,
+		})
+	}
+
+	// History shared between branches maps to the same snapshots, so count
+	// distinct snapshot IDs rather than commits per branch.
+	snapshots := make(map[string]struct{})
+	for _, target := range targets {
+		if !target.Existing {
+			if _, err := os.Stat(target.Root); err == nil {
+				return 0, fmt.Errorf("target workspace directory already exists: %s", target.Root)
+			}
+			createdRoots = append(createdRoots, target.Root)
+		}
+		commitToSnapshot, err := importWorkspaceFromGit(git, s, target, false)
+		if err != nil {
+			return 0, err
+		}
+		for _, snapshotID := range commitToSnapshot {
+			snapshots[snapshotID] = struct{}{}
+		}
+	}
+
+	imported = true
+	return len(snapshots), nil
+}
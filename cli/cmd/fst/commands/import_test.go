@@ -105,6 +105,47 @@ func TestImportGitRequiresMetadata(t *testing.T) {
 	}
 }
 
+func TestImportGitNamedBranchWithoutMetadata(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init")
+	runGit(t, repo, "config", "user.name", "Test")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("hi"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "-m", "init")
+	runGit(t, repo, "branch", "-M", "main")
+	runGit(t, repo, "branch", "other")
+
+	root := t.TempDir()
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"git", "import", repo, "--project", "demo", "--branch", "main"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("import --branch failed: %v", err)
+	}
+
+	latest, err := config.GetLatestSnapshotIDAt(filepath.Join(root, "demo", "main"))
+	if err != nil {
+		t.Fatalf("GetLatestSnapshotIDAt: %v", err)
+	}
+	if latest == "" {
+		t.Fatalf("expected snapshots to be imported")
+	}
+	if _, err := os.Stat(filepath.Join(root, "demo", "other")); !os.IsNotExist(err) {
+		t.Fatalf("expected unnamed branch to be skipped, got %v", err)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"git", "import", repo, "--branch", "missing"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected import of a missing branch to fail")
+	}
+}
+
 func TestImportGitCreatesProjectAndWorkspace(t *testing.T) {
 	repo := t.TempDir()
 	runGit(t, repo, "init")
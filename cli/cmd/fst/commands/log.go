@@ -13,6 +13,7 @@ import (
 
 	"github.com/ankitiscracked/fastest/cli/internal/config"
 	"github.com/ankitiscracked/fastest/cli/internal/dag"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
 	"github.com/ankitiscracked/fastest/cli/internal/ui"
 )
 
@@ -24,17 +25,55 @@ func newLogCmd() *cobra.Command {
 	var limit int
 	var showAll bool
 	var showGraph bool
+	var followRenames bool
 
 	cmd := &cobra.Command{
-		Use:   "log",
+		Use:   "log [<from>..<to> | <path>]",
 		Short: "Show snapshot history",
 		Long: `Display the history of snapshots for the current workspace.
 
 Shows snapshots in reverse chronological order, starting from the current base.
 Each entry shows the snapshot ID, timestamp, file count, and description.
 
-Use --graph to see the DAG structure with merge and fork lines.`,
+Use --graph to see the DAG structure with merge and fork lines. Snapshots
+that are a workspace's current head are labelled with the workspace name.
+Combine it with --all to draw the history of every workspace in the project
+in one graph, showing where workspaces forked and merged.
+
+With a <from>..<to> range, shows only the snapshots reachable from <to>
+that come after <from> (the walk stops at <from>).
+
+With a <path>, shows only the snapshots that added, modified or deleted
+that file. Use --follow-renames to continue the history across renames,
+detected by a file with identical content disappearing in the same snapshot
+(like git log --follow).
+
+Examples:
+  fst log                      # History of the current workspace
+  fst log --all --graph        # Combined DAG of every workspace
+  fst log abc123..def456       # Snapshots after abc123 up to def456
+  fst log src/main.go --follow-renames`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 && !isSnapshotRangeArg(args[0]) {
+				if showAll || showGraph {
+					return fmt.Errorf("cannot combine a path with --all or --graph")
+				}
+				return runLogFile(args[0], followRenames, limit)
+			}
+			if followRenames {
+				return fmt.Errorf("--follow-renames requires a <path>")
+			}
+			if len(args) > 0 {
+				if showAll || showGraph {
+					return fmt.Errorf("cannot combine a snapshot range with --all or --graph")
+				}
+				from, to, err := parseSnapshotRange(args[0])
+				if err != nil {
+					return err
+				}
+				return runLogRange(from, to, limit)
+			}
 			return runLog(limit, showAll, showGraph)
 		},
 	}
@@ -42,6 +81,7 @@ Use --graph to see the DAG structure with merge and fork lines.`,
 	cmd.Flags().IntVarP(&limit, "limit", "n", 10, "Maximum number of snapshots to show")
 	cmd.Flags().BoolVarP(&showAll, "all", "a", false, "Show all snapshots, not just the current chain")
 	cmd.Flags().BoolVarP(&showGraph, "graph", "g", false, "Show DAG graph alongside history")
+	cmd.Flags().BoolVar(&followRenames, "follow-renames", false, "Follow a file's history across renames")
 
 	return cmd
 }
@@ -62,6 +102,25 @@ type logSnapshotMeta struct {
 	Size              int64    `json:"size"`
 }
 
+// newLogSnapshotMeta converts store snapshot metadata for display by the
+// log command.
+func newLogSnapshotMeta(m *store.SnapshotMeta) *logSnapshotMeta {
+	return &logSnapshotMeta{
+		ID:                m.ID,
+		WorkspaceID:       m.WorkspaceID,
+		WorkspaceName:     m.WorkspaceName,
+		ManifestHash:      m.ManifestHash,
+		ParentSnapshotIDs: m.ParentSnapshotIDs,
+		AuthorName:        m.AuthorName,
+		AuthorEmail:       m.AuthorEmail,
+		Message:           m.Message,
+		Agent:             m.Agent,
+		CreatedAt:         m.CreatedAt,
+		Files:             m.Files,
+		Size:              m.Size,
+	}
+}
+
 func runLog(limit int, showAll bool, showGraph bool) error {
 	cfg, err := config.Load()
 	if err != nil {
@@ -143,6 +202,157 @@ func runLog(limit int, showAll bool, showGraph bool) error {
 	return nil
 }
 
+func runLogRange(fromArg, toArg string, limit int) error {
+	root, err := config.FindWorkspaceRoot()
+	if err != nil {
+		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+
+	s := store.OpenFromWorkspace(root)
+	from, err := s.ResolveSnapshotID(fromArg)
+	if err != nil {
+		return err
+	}
+	to, err := s.ResolveSnapshotID(toArg)
+	if err != nil {
+		return err
+	}
+
+	metas, err := s.SnapshotsBetween(from, to)
+	if err != nil {
+		return fmt.Errorf("failed to walk snapshot history: %w", err)
+	}
+
+	rangeIDs := shortenIDs([]string{from, to}, 12)
+	if len(metas) == 0 {
+		fmt.Printf("No snapshots between %s and %s.\n", rangeIDs[from], rangeIDs[to])
+		return nil
+	}
+
+	// Newest first, matching the default log order
+	toShow := make([]*logSnapshotMeta, 0, len(metas))
+	for i := len(metas) - 1; i >= 0; i-- {
+		toShow = append(toShow, newLogSnapshotMeta(metas[i]))
+	}
+	total := len(toShow)
+	if limit > 0 && len(toShow) > limit {
+		toShow = toShow[:limit]
+	}
+
+	ids := make([]string, 0, len(toShow))
+	for _, snap := range toShow {
+		ids = append(ids, snap.ID)
+	}
+	shortIDs := shortenIDs(ids, 12)
+
+	fmt.Printf("Snapshots %s..%s (%d):\n", rangeIDs[from], rangeIDs[to], total)
+	fmt.Println()
+
+	for _, snap := range toShow {
+		displaySnapshot(snap, false, shortIDs)
+	}
+
+	if limit > 0 && len(toShow) == limit && total > limit {
+		fmt.Printf("  ... use -n to show more\n")
+	}
+
+	return nil
+}
+
+// isSnapshotRangeArg reports whether a log argument is a <from>..<to> range
+// rather than a file path. An existing path always wins, and snapshot IDs
+// never contain path separators.
+func isSnapshotRangeArg(arg string) bool {
+	if _, err := os.Lstat(arg); err == nil {
+		return false
+	}
+	return strings.Contains(arg, "..") && !strings.ContainsAny(arg, `/\`)
+}
+
+func runLogFile(pathArg string, followRenames bool, limit int) error {
+	root, err := config.FindWorkspaceRoot()
+	if err != nil {
+		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+	cfg, err := config.LoadAt(root)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.CurrentSnapshotID == "" {
+		fmt.Println("No snapshots found.")
+		return nil
+	}
+
+	relPath, err := workspaceRelPath(root, pathArg)
+	if err != nil {
+		return err
+	}
+
+	s := store.OpenFromWorkspace(root)
+	changes, err := s.FileHistory(cfg.CurrentSnapshotID, relPath, followRenames)
+	if err != nil {
+		return fmt.Errorf("failed to walk file history: %w", err)
+	}
+	if len(changes) == 0 {
+		fmt.Printf("No history for %s.\n", relPath)
+		return nil
+	}
+
+	total := len(changes)
+	if limit > 0 && len(changes) > limit {
+		changes = changes[:limit]
+	}
+
+	ids := make([]string, 0, len(changes))
+	for _, c := range changes {
+		ids = append(ids, c.Snapshot.ID)
+	}
+	shortIDs := shortenIDs(ids, 12)
+
+	fmt.Printf("History of %s (%d):\n", relPath, total)
+	fmt.Println()
+
+	for _, c := range changes {
+		switch c.Status {
+		case "renamed":
+			fmt.Printf("  %s %s → %s\n", ui.Cyan("renamed"), c.OldPath, c.Path)
+		case "added":
+			fmt.Printf("  %s %s\n", ui.Green("added"), c.Path)
+		case "deleted":
+			fmt.Printf("  %s %s\n", ui.Red("deleted"), c.Path)
+		default:
+			fmt.Printf("  %s %s\n", ui.Yellow("modified"), c.Path)
+		}
+		displaySnapshot(newLogSnapshotMeta(c.Snapshot), false, shortIDs)
+	}
+
+	if limit > 0 && total > limit {
+		fmt.Printf("  ... use -n to show more\n")
+	}
+
+	return nil
+}
+
+// workspaceRelPath converts a path given on the command line (relative to the
+// current directory) into a slash-separated path relative to the workspace root.
+func workspaceRelPath(root, pathArg string) (string, error) {
+	abs, err := filepath.Abs(pathArg)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %q: %w", pathArg, err)
+	}
+	if resolvedRoot, err := filepath.EvalSymlinks(root); err == nil {
+		if resolvedDir, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+			root = resolvedRoot
+			abs = filepath.Join(resolvedDir, filepath.Base(abs))
+		}
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q is outside the workspace", pathArg)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
 func runLogGraph(snapshots []*logSnapshotMeta, cfg *config.WorkspaceConfig, limit int, showAll bool) error {
 	// Build snapshot map for DAG operations
 	byID := make(map[string]*logSnapshotMeta, len(snapshots))
@@ -156,10 +366,20 @@ func runLogGraph(snapshots []*logSnapshotMeta, cfg *config.WorkspaceConfig, limi
 		}
 	}
 
+	labels := workspaceHeadLabels(cfg)
+
 	// Determine heads
 	var heads []string
 	if showAll {
-		// Find all tips (snapshots not referenced as parent by anyone)
+		// Every workspace head, plus any tips (snapshots not referenced as
+		// parent by anyone) no workspace points at
+		seen := make(map[string]bool)
+		for id := range labels {
+			if _, ok := byID[id]; ok && !seen[id] {
+				seen[id] = true
+				heads = append(heads, id)
+			}
+		}
 		isParent := make(map[string]bool)
 		for _, s := range snapshots {
 			for _, pid := range s.ParentSnapshotIDs {
@@ -167,7 +387,8 @@ func runLogGraph(snapshots []*logSnapshotMeta, cfg *config.WorkspaceConfig, limi
 			}
 		}
 		for _, s := range snapshots {
-			if !isParent[s.ID] {
+			if !isParent[s.ID] && !seen[s.ID] {
+				seen[s.ID] = true
 				heads = append(heads, s.ID)
 			}
 		}
@@ -226,7 +447,7 @@ func runLogGraph(snapshots []*logSnapshotMeta, cfg *config.WorkspaceConfig, limi
 
 		// Print node line with snapshot info
 		isCurrent := snap.ID == cfg.CurrentSnapshotID
-		displayGraphSnapshot(row.NodeLine, snap, isCurrent, shortIDs)
+		displayGraphSnapshot(row.NodeLine, snap, isCurrent, labels[snap.ID], shortIDs)
 
 		// Print post-lines (fork-out)
 		for _, line := range row.PostLines {
@@ -241,13 +462,53 @@ func runLogGraph(snapshots []*logSnapshotMeta, cfg *config.WorkspaceConfig, limi
 	return nil
 }
 
-func displayGraphSnapshot(graphPrefix string, snap *logSnapshotMeta, isCurrent bool, shortIDs map[string]string) {
+// workspaceHeadLabels maps each registered workspace's head snapshot to the
+// names of the workspaces pointing at it. cfg's own head is always included.
+func workspaceHeadLabels(cfg *config.WorkspaceConfig) map[string][]string {
+	labels := make(map[string][]string)
+	add := func(id, name string) {
+		if id == "" || name == "" {
+			return
+		}
+		for _, existing := range labels[id] {
+			if existing == name {
+				return
+			}
+		}
+		labels[id] = append(labels[id], name)
+	}
+
+	add(cfg.CurrentSnapshotID, cfg.WorkspaceName)
+	if root, err := config.FindWorkspaceRoot(); err == nil {
+		if wsList, err := store.OpenFromWorkspace(root).ListWorkspaces(); err == nil {
+			for _, ws := range wsList {
+				if ws.WorkspaceID == cfg.WorkspaceID {
+					continue
+				}
+				head := ws.CurrentSnapshotID
+				if wsCfg, err := config.LoadAt(ws.Path); ws.Path != "" && err == nil && wsCfg.CurrentSnapshotID != "" {
+					head = wsCfg.CurrentSnapshotID
+				}
+				add(head, ws.WorkspaceName)
+			}
+		}
+	}
+	for _, names := range labels {
+		sort.Strings(names)
+	}
+	return labels
+}
+
+func displayGraphSnapshot(graphPrefix string, snap *logSnapshotMeta, isCurrent bool, labels []string, shortIDs map[string]string) {
 	timeStr := formatSnapshotTime(snap.CreatedAt)
 	shortID := shortIDs[snap.ID]
 
-	agentTag := ""
+	tags := ""
 	if snap.Agent != "" {
-		agentTag = " " + ui.Cyan("["+snap.Agent+"]")
+		tags = " " + ui.Cyan("["+snap.Agent+"]")
+	}
+	if len(labels) > 0 {
+		tags += " " + ui.Green("("+strings.Join(labels, ", ")+")")
 	}
 
 	// Determine graph indent for continuation lines
@@ -260,7 +521,7 @@ func displayGraphSnapshot(graphPrefix string, snap *logSnapshotMeta, isCurrent b
 		ui.Dim(timeStr),
 		snap.Files,
 		formatBytes(snap.Size),
-		agentTag,
+		tags,
 	)
 
 	// Author (indented with graph continuation)
@@ -319,12 +580,12 @@ func loadAllSnapshots(manifestDir string) ([]*logSnapshotMeta, error) {
 			continue
 		}
 
-		var meta logSnapshotMeta
+		var meta store.SnapshotMeta
 		if err := json.Unmarshal(data, &meta); err != nil {
 			continue
 		}
 
-		snapshots = append(snapshots, &meta)
+		snapshots = append(snapshots, newLogSnapshotMeta(&meta))
 	}
 
 	return snapshots, nil
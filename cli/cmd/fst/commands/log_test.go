@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+func TestLogAllGraphLabelsWorkspaceHeads(t *testing.T) {
+	projectRoot, targetRoot, sourceRoot := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "target"},
+		map[string]string{"b.txt": "source"},
+	)
+
+	s := store.OpenAt(projectRoot)
+	for _, root := range []string{targetRoot, sourceRoot} {
+		cfg, err := config.LoadAt(root)
+		if err != nil {
+			t.Fatalf("LoadAt: %v", err)
+		}
+		if err := s.RegisterWorkspace(store.WorkspaceInfo{
+			WorkspaceID:       cfg.WorkspaceID,
+			WorkspaceName:     cfg.WorkspaceName,
+			Path:              root,
+			CurrentSnapshotID: cfg.CurrentSnapshotID,
+		}); err != nil {
+			t.Fatalf("RegisterWorkspace: %v", err)
+		}
+	}
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	var out string
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"log", "--all", "--graph", "-n", "0"})
+		return cmd.Execute()
+	}, &out); err != nil {
+		t.Fatalf("log --all --graph failed: %v", err)
+	}
+
+	for _, label := range []string{"(ws-target)", "(ws-source)"} {
+		if !strings.Contains(out, label) {
+			t.Fatalf("expected label %s in output:\n%s", label, out)
+		}
+	}
+	if !strings.Contains(out, "all workspaces") {
+		t.Fatalf("expected all-workspaces header:\n%s", out)
+	}
+}
+
+func TestLogPathWithDotsIsNotARange(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t, nil, nil)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	writeFile(t, filepath.Join(targetRoot, "a..b"), "x")
+
+	var out string
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"log", "a..b"})
+		return cmd.Execute()
+	}, &out); err != nil {
+		t.Fatalf("log a..b: %v", err)
+	}
+	if !strings.Contains(out, "No history for a..b.") {
+		t.Fatalf("expected a file history lookup, got:\n%s", out)
+	}
+}
@@ -1,14 +1,22 @@
 package commands
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
 	"github.com/ankitiscracked/fastest/cli/internal/agent"
+	"github.com/ankitiscracked/fastest/cli/internal/config"
 	"github.com/ankitiscracked/fastest/cli/internal/conflicts"
 	"github.com/ankitiscracked/fastest/cli/internal/dag"
+	"github.com/ankitiscracked/fastest/cli/internal/gitstore"
 	"github.com/ankitiscracked/fastest/cli/internal/store"
 	"github.com/ankitiscracked/fastest/cli/internal/ui"
 	"github.com/ankitiscracked/fastest/cli/internal/workspace"
@@ -26,6 +34,7 @@ const (
 	ConflictModeManual                     // Write conflict markers
 	ConflictModeTheirs                     // Take source version
 	ConflictModeOurs                       // Keep target version
+	ConflictModeNewer                      // Keep the side with the newer head (sync only)
 )
 
 func newMergeCmd() *cobra.Command {
@@ -37,6 +46,25 @@ func newMergeCmd() *cobra.Command {
 	var noPreSnapshot bool
 	var force bool
 	var abort bool
+	var recordOnly bool
+	var summaryFile string
+	var showReport bool
+	var reportFile string
+	var verbose bool
+	var into string
+	var abortIfDirty bool
+	var applyOrder string
+	var agentModel string
+	var chunkSize int
+	var chunkBytes int
+	var contextLines int
+	var preserveDeletes bool
+	var sourceRef string
+	var interactive bool
+	var verify string
+	var snapshotAfter bool
+	var noSnapshotAfter bool
+	var message string
 
 	cmd := &cobra.Command{
 		Use:   "merge [workspace]",
@@ -56,10 +84,35 @@ Non-conflicting changes are applied automatically. For conflicts:
 - Manual (--manual): Creates conflict markers for you to resolve
 - Theirs (--theirs): Take source version for all conflicts
 - Ours (--ours): Keep current version for all conflicts
+- Interactive (--interactive): Choose for each conflicting file
+
+A file deleted on one side and modified on the other follows --theirs or
+--ours; --interactive asks, and otherwise it gets conflict markers.
 
-Use --dry-run to preview the merge and see line-level conflict details.
 By default, a pre-merge snapshot is created only if the target has local changes.
-After a successful conflict-free merge, a snapshot is created automatically.
+After a successful conflict-free merge, a snapshot is created automatically
+with both heads as parents.
+
+The "merge" section of the project config sets per-file policies and
+defaults for several flags:
+
+  "merge": {
+    "regenerate": {"package-lock.json": "npm install"},
+    "attributes": {"generated/*": "theirs", "local-notes/*": "ours"},
+    "apply_order": "deps", "apply_priority": ["*.toml"],
+    "agent_model": "haiku", "abort_if_dirty": true
+  }
+
+Files matching regenerate are never text-merged: the regenerate_side
+version ("ours" by default) is kept and the command run after the merge.
+Conflicts in files matching attributes always use the given strategy.
+
+Examples:
+  fst merge feature --dry-run --summary-file plan.md   # preview the plan
+  fst merge feature --interactive                      # pick per file
+  fst merge feature --verify "go test ./..."           # roll back if it fails
+  fst merge feature --chunk-size 5 --agent-model haiku # batch agent calls
+  fst merge feature --into main --report               # summarize the result
 
 Exit codes:
   0  Merge completed without conflicts
@@ -83,6 +136,9 @@ Exit codes:
 			if modeCount > 1 {
 				return fmt.Errorf("only one of --manual, --theirs, --ours can be specified")
 			}
+			if interactive && (modeCount > 0 || dryRun) {
+				return fmt.Errorf("--interactive cannot be combined with --manual, --theirs, --ours or --dry-run")
+			}
 
 			mode := ConflictModeAgent
 			if manual {
@@ -96,19 +152,100 @@ Exit codes:
 			if len(args) == 0 {
 				return fmt.Errorf("must specify workspace name")
 			}
+			if chunkSize < 1 {
+				return fmt.Errorf("--chunk-size must be at least 1")
+			}
+			if chunkBytes < 0 {
+				return fmt.Errorf("--chunk-bytes must not be negative")
+			}
+			if contextLines < 0 {
+				return fmt.Errorf("--context-lines must not be negative")
+			}
+			if contextLines > 0 && chunkSize > 1 {
+				return fmt.Errorf("--context-lines cannot be combined with --chunk-size")
+			}
 
-			return runMerge(cmd, args[0], mode, dryRun, dryRunSummary, noPreSnapshot, force)
+			if snapshotAfter && noSnapshotAfter {
+				return fmt.Errorf("only one of --snapshot-after, --no-snapshot-after can be specified")
+			}
+			if noSnapshotAfter && message != "" {
+				return fmt.Errorf("--message names the post-merge snapshot; it cannot be used with --no-snapshot-after")
+			}
+			if dryRun && (snapshotAfter || noSnapshotAfter || message != "") {
+				return fmt.Errorf("--snapshot-after, --no-snapshot-after and --message cannot be used with --dry-run")
+			}
+
+			if recordOnly {
+				if modeCount > 0 || interactive || dryRun || sourceRef != "" || verify != "" || snapshotAfter || noSnapshotAfter || message != "" {
+					return fmt.Errorf("--record-only cannot be combined with --manual, --theirs, --ours, --interactive, --dry-run, --source-ref, --verify or the post-merge snapshot flags")
+				}
+				return runMergeRecordOnly(args[0], into)
+			}
+
+			if dryRun && (showReport || reportFile != "") {
+				return fmt.Errorf("--report and --report-file describe an applied merge; use --summary-file with --dry-run")
+			}
+			if verify != "" && noPreSnapshot {
+				return fmt.Errorf("--verify needs the pre-merge snapshot to roll back to; drop --no-pre-snapshot")
+			}
+
+			_, err := runMerge(cmd, args[0], mergeOptions{
+				mode:            mode,
+				interactive:     interactive,
+				dryRun:          dryRun,
+				agentSummary:    dryRunSummary,
+				noPreSnapshot:   noPreSnapshot,
+				force:           force,
+				summaryFile:     summaryFile,
+				report:          showReport,
+				reportFile:      reportFile,
+				verbose:         verbose,
+				into:            into,
+				abortIfDirty:    abortIfDirty,
+				abortIfDirtySet: cmd.Flags().Changed("abort-if-dirty"),
+				applyOrder:      applyOrder,
+				agentModel:      agentModel,
+				chunkSize:       chunkSize,
+				chunkBytes:      chunkBytes,
+				contextLines:    contextLines,
+				preserveDeletes: preserveDeletes,
+				sourceRef:       sourceRef,
+				verify:          verify,
+				snapshotAfter:   snapshotAfter,
+				noSnapshotAfter: noSnapshotAfter,
+				message:         message,
+			})
+			return err
 		},
 	}
 
 	cmd.Flags().BoolVar(&manual, "manual", false, "Create conflict markers for manual resolution")
 	cmd.Flags().BoolVar(&theirs, "theirs", false, "Take source version for all conflicts")
 	cmd.Flags().BoolVar(&ours, "ours", false, "Keep current version for all conflicts")
+	cmd.Flags().BoolVar(&interactive, "interactive", false, "Choose theirs, ours, agent, manual or skip for each conflicting file")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview merge with line-level conflict details")
 	cmd.Flags().BoolVar(&dryRunSummary, "agent-summary", false, "Generate LLM summary of conflicts (with --dry-run)")
 	cmd.Flags().BoolVar(&noPreSnapshot, "no-pre-snapshot", false, "Skip pre-merge snapshot (only created if dirty)")
 	cmd.Flags().BoolVar(&force, "force", false, "Allow merge without a common base (two-way merge)")
 	cmd.Flags().BoolVar(&abort, "abort", false, "Abort an in-progress merge (clears pending merge state)")
+	cmd.Flags().BoolVar(&recordOnly, "record-only", false, "Record the source as merged without applying changes (after reconciling by hand)")
+	cmd.Flags().StringVar(&summaryFile, "summary-file", "", "Write a markdown report of the merge plan (or outcome) to this file")
+	cmd.Flags().BoolVar(&showReport, "report", false, "Print a markdown summary of the merge's net effect when done")
+	cmd.Flags().StringVar(&reportFile, "report-file", "", "Write the --report summary to this file")
+	cmd.Flags().BoolVar(&verbose, "verbose", false, "List every applied file instead of showing a progress line")
+	cmd.Flags().StringVar(&into, "into", "", "Merge into this workspace instead of the current one")
+	cmd.Flags().BoolVar(&abortIfDirty, "abort-if-dirty", false, "Refuse to merge if the target has uncommitted changes; merge.abort_if_dirty sets the default")
+	cmd.Flags().IntVar(&chunkSize, "chunk-size", 1, "Number of conflicting files to resolve per agent invocation")
+	cmd.Flags().IntVar(&chunkBytes, "chunk-bytes", 256*1024, "Maximum combined size of the files in one agent invocation (0 = no limit)")
+	cmd.Flags().IntVar(&contextLines, "context-lines", 0, "Send the agent only conflicting hunks with this many lines of context (0 = whole files; not with --chunk-size)")
+	cmd.Flags().StringVar(&agentModel, "agent-model", "", "Model for the agent to use when resolving conflicts; merge.agent_model sets the default")
+	cmd.Flags().BoolVar(&preserveDeletes, "preserve-deletes", false, "Delete files the source deleted if they are unchanged here")
+	cmd.Flags().StringVar(&sourceRef, "source-ref", "", "Merge this snapshot from the source workspace's history instead of its latest one")
+	cmd.Flags().StringVar(&verify, "verify", "", "Command to run through sh after merging; roll back and fail if it exits nonzero")
+	cmd.Flags().BoolVar(&snapshotAfter, "snapshot-after", false, "Always snapshot the merge, even if nothing changed; fail if files are left unresolved")
+	cmd.Flags().BoolVar(&noSnapshotAfter, "no-snapshot-after", false, "Leave a clean merge unsnapshotted for review")
+	cmd.Flags().StringVarP(&message, "message", "m", "", "Message for the post-merge snapshot")
+	cmd.Flags().StringVar(&applyOrder, "apply-order", "", "Order for writing source files: path (default) or deps (parents before children)")
 
 	return cmd
 }
@@ -127,42 +264,260 @@ func runMergeAbort() error {
 	return nil
 }
 
-func runMerge(cmd *cobra.Command, sourceName string, mode ConflictMode, dryRun bool, dryRunSummary bool, noPreSnapshot bool, force bool) error {
-	ws, err := workspace.Open()
+func runMergeRecordOnly(sourceName, into string) error {
+	ws, err := openMergeTarget(sourceName, into)
 	if err != nil {
-		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+		return err
 	}
 	defer ws.Close()
 
-	// Resolve source workspace via project registry
 	sourceInfo, err := ws.Store().FindWorkspaceByName(sourceName)
 	if err != nil {
 		return fmt.Errorf("workspace '%s' not found in project registry\nRun 'fst workspaces' to see available workspaces", sourceName)
 	}
-
+	if err := checkNotSelfMerge(ws, sourceInfo); err != nil {
+		return err
+	}
 	sourceSnapshotID := sourceInfo.CurrentSnapshotID
 	if sourceSnapshotID == "" {
 		return fmt.Errorf("source workspace '%s' has no snapshots - run 'fst snapshot' in that workspace first", sourceName)
 	}
-
 	currentSnapshotID := ws.CurrentSnapshotID()
 	if currentSnapshotID == "" {
 		return fmt.Errorf("current workspace has no snapshots - run 'fst snapshot' before merging")
 	}
 
-	fmt.Printf("Merging from: %s\n", sourceInfo.WorkspaceName)
+	if ws.Store().IsAncestorOf(sourceSnapshotID, currentSnapshotID) {
+		fmt.Printf("Already merged: %s (%s) is in this workspace's history.\n", sourceInfo.WorkspaceName, shortenIDs([]string{sourceSnapshotID}, 12)[sourceSnapshotID])
+		return nil
+	}
+
+	result, err := ws.RecordMerge(sourceSnapshotID, fmt.Sprintf("Recorded merge of %s", sourceInfo.WorkspaceName))
+	if err != nil {
+		return fmt.Errorf("failed to record merge: %w", err)
+	}
+
+	base, err := ws.Store().GetMergeBase(result.SnapshotID, sourceSnapshotID)
+	if err != nil {
+		base = sourceSnapshotID
+	}
+	ids := shortenIDs([]string{result.SnapshotID, base}, 12)
+
+	fmt.Printf("Recorded merge of %s into %s without applying changes.\n", sourceInfo.WorkspaceName, ws.WorkspaceName())
+	fmt.Printf("  Snapshot:   %s\n", ids[result.SnapshotID])
+	fmt.Printf("  Merge base: %s (used by future merges from %s)\n", ids[base], sourceInfo.WorkspaceName)
+	return nil
+}
+
+// mergeOptions holds the flags for a single runMerge invocation.
+type mergeOptions struct {
+	mode          ConflictMode
+	interactive   bool // ask how to resolve each conflict; overrides mode
+	dryRun        bool
+	agentSummary  bool
+	noPreSnapshot bool
+	force         bool
+	summaryFile   string
+	report        bool   // print the net effect of the merge when done
+	reportFile    string // write the net effect of the merge to this file
+	verbose       bool
+	into          string // target workspace name; empty means the current workspace
+
+	// abortIfDirty refuses to merge into a workspace with uncommitted
+	// changes. Unless abortIfDirtySet, merge.abort_if_dirty can enable it.
+	abortIfDirty    bool
+	abortIfDirtySet bool
+
+	applyOrder string // "path" or "deps"; empty defers to merge.apply_order
+	agentModel string // model hint for the agent; empty defers to merge.agent_model
+	chunkSize  int    // conflicting files per agent invocation
+	chunkBytes int    // input bytes per agent invocation; 0 means no limit
+
+	contextLines int // context around each hunk sent to the agent; 0 sends whole files
+
+	preserveDeletes bool // propagate source deletions of files unchanged in the target
+
+	sourceRef string // snapshot of the source to merge; empty means its latest snapshot
+	verify    string // command that must succeed after applying, or the merge is rolled back
+
+	// snapshotAfter requires the post-merge snapshot: it is taken even if
+	// no files changed, and unresolved files fail the merge.
+	// noSnapshotAfter skips it. message names it.
+	snapshotAfter   bool
+	noSnapshotAfter bool
+	message         string
+}
+
+// openMergeTarget opens the workspace a merge writes into: the workspace
+// named into, resolved through the project registry, or the current one.
+func openMergeTarget(sourceName, into string) (*workspace.Workspace, error) {
+	if into == "" {
+		ws, err := workspace.Open()
+		if err != nil {
+			return nil, fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+		}
+		return ws, nil
+	}
+
+	if into == sourceName {
+		return nil, fmt.Errorf("cannot merge workspace '%s' into itself", into)
+	}
+	projectRoot, _, err := findProjectRootAndConfig()
+	if err != nil {
+		return nil, err
+	}
+	targetInfo, err := store.OpenAt(projectRoot).FindWorkspaceByName(into)
+	if err != nil {
+		return nil, fmt.Errorf("workspace '%s' not found in project registry\nRun 'fst workspaces' to see available workspaces", into)
+	}
+	ws, err := workspace.OpenAt(targetInfo.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workspace '%s': %w", into, err)
+	}
+	return ws, nil
+}
+
+// checkNotSelfMerge rejects merging a workspace into itself, e.g. when the
+// source is named after the current workspace or registered at its path.
+func checkNotSelfMerge(ws *workspace.Workspace, sourceInfo *store.WorkspaceInfo) error {
+	sameID := sourceInfo.WorkspaceID != "" && sourceInfo.WorkspaceID == ws.WorkspaceID()
+	samePath := sourceInfo.Path != "" && filepath.Clean(sourceInfo.Path) == filepath.Clean(ws.Root())
+	if sameID || samePath {
+		return fmt.Errorf("cannot merge workspace '%s' into itself", sourceInfo.WorkspaceName)
+	}
+	return nil
+}
+
+// withAgentModel returns a copy of a using the model selected by flag,
+// falling back to merge.agent_model. Agents that can't select a model are
+// returned unchanged, with a note when a model was requested.
+func withAgentModel(a *agent.Agent, flag string, mergeCfg *config.MergeConfig) *agent.Agent {
+	model := flag
+	if model == "" && mergeCfg != nil {
+		model = mergeCfg.AgentModel
+	}
+	if model == "" {
+		return a
+	}
+	if !agent.SupportsModel(a.Name) {
+		fmt.Printf("Note: %s does not support model selection; ignoring model %s\n", a.Name, model)
+		return a
+	}
+	withModel := *a
+	withModel.Model = model
+	return &withModel
+}
+
+// resolveApplyOrder returns the apply order selected by flag, falling back
+// to merge.apply_order and then to path order.
+func resolveApplyOrder(flag string, mergeCfg *config.MergeConfig) (string, error) {
+	order := flag
+	if order == "" && mergeCfg != nil {
+		order = mergeCfg.ApplyOrder
+	}
+	switch order {
+	case "", store.ApplyOrderPath:
+		return store.ApplyOrderPath, nil
+	case store.ApplyOrderDeps:
+		return order, nil
+	default:
+		return "", fmt.Errorf("invalid apply order %q (expected %q or %q)", order, store.ApplyOrderPath, store.ApplyOrderDeps)
+	}
+}
+
+// checkMergeTargetClean returns an error if ws has uncommitted changes
+// relative to its current snapshot.
+func checkMergeTargetClean(ws *workspace.Workspace, currentSnapshotID string) error {
+	report := workspaceDriftAt(ws.Store(), ws.Root(), currentSnapshotID)
+	if report == nil {
+		return fmt.Errorf("cannot check workspace '%s' for uncommitted changes", ws.WorkspaceName())
+	}
+	if !report.HasChanges() {
+		return nil
+	}
+	return fmt.Errorf("workspace '%s' has uncommitted changes (%d added, %d modified, %d deleted)\nRun 'fst snapshot' first, or merge without --abort-if-dirty",
+		ws.WorkspaceName(), len(report.FilesAdded), len(report.FilesModified), len(report.FilesDeleted))
+}
+
+// mergeProgressThreshold is the number of files applied from the source
+// above which merge shows a progress line instead of listing each file.
+const mergeProgressThreshold = 200
+
+// runMerge merges sourceName into the target workspace, printing progress
+// as it goes, and returns what was applied. The result is nil for dry runs
+// and empty when there was nothing to merge. When conflicts remain and cmd
+// is set, the result comes with a SilentExit(1) error.
+func runMerge(cmd *cobra.Command, sourceName string, opts mergeOptions) (*workspace.MergeResult, error) {
+	ws, err := openMergeTarget(sourceName, opts.into)
+	if err != nil {
+		return nil, err
+	}
+	defer ws.Close()
+
+	// Resolve source workspace via project registry
+	sourceInfo, err := ws.Store().FindWorkspaceByName(sourceName)
+	if err != nil {
+		return nil, fmt.Errorf("workspace '%s' not found in project registry\nRun 'fst workspaces' to see available workspaces", sourceName)
+	}
+	if err := checkNotSelfMerge(ws, sourceInfo); err != nil {
+		return nil, err
+	}
+
+	sourceSnapshotID := sourceInfo.CurrentSnapshotID
+	if sourceSnapshotID == "" {
+		return nil, fmt.Errorf("source workspace '%s' has no snapshots - run 'fst snapshot' in that workspace first", sourceName)
+	}
+	sourceLabel := sourceInfo.WorkspaceName
+	if opts.sourceRef != "" {
+		sourceSnapshotID, err = resolveSourceRef(ws.Store(), sourceInfo, opts.sourceRef)
+		if err != nil {
+			return nil, err
+		}
+		sourceLabel = fmt.Sprintf("%s@%s", sourceInfo.WorkspaceName, shortenIDs([]string{sourceSnapshotID}, 12)[sourceSnapshotID])
+	}
+
+	currentSnapshotID := ws.CurrentSnapshotID()
+	if currentSnapshotID == "" {
+		return nil, fmt.Errorf("current workspace has no snapshots - run 'fst snapshot' before merging")
+	}
+
+	var mergeCfg *config.MergeConfig
+	if _, projectCfg, err := config.FindProjectRootFrom(ws.Root()); err == nil {
+		mergeCfg = projectCfg.Merge
+	}
+	abortIfDirty := opts.abortIfDirty
+	if !opts.abortIfDirtySet && mergeCfg != nil {
+		abortIfDirty = mergeCfg.AbortIfDirty
+	}
+	if abortIfDirty && !opts.dryRun {
+		if err := checkMergeTargetClean(ws, currentSnapshotID); err != nil {
+			return nil, err
+		}
+	}
+	applyOrder, err := resolveApplyOrder(opts.applyOrder, mergeCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Merging from: %s\n", sourceLabel)
 	fmt.Printf("Into:         %s (%s)\n", ws.WorkspaceName(), ws.Root())
 	fmt.Println()
 
 	// Plan the merge
-	plan, err := ws.Store().PlanMerge(currentSnapshotID, sourceSnapshotID, force)
+	plan, err := ws.Store().PlanMergeWithOpts(currentSnapshotID, sourceSnapshotID, store.MergeOpts{
+		Force:           opts.force,
+		PreserveDeletes: opts.preserveDeletes,
+	})
 	if err != nil {
-		return fmt.Errorf("merge planning failed: %w", err)
+		return nil, fmt.Errorf("merge planning failed: %w", err)
+	}
+	if applyOrder == store.ApplyOrderDeps {
+		store.OrderMergeActionsByDeps(plan.ToApply, mergeCfg.ApplyPriorityGlobs())
 	}
 
 	if plan.MergeBaseID != "" {
 		fmt.Printf("Using merge base: %s\n", plan.MergeBaseID)
-	} else if force {
+	} else if opts.force {
 		fmt.Println("Warning: No common ancestor found. Proceeding with two-way merge.")
 	}
 
@@ -179,15 +534,65 @@ func runMerge(cmd *cobra.Command, sourceName string, mode ConflictMode, dryRun b
 
 	if len(plan.ToApply) == 0 && len(plan.AutoMerged) == 0 && len(plan.Conflicts) == 0 {
 		fmt.Println("Nothing to merge - workspaces are in sync")
-		return nil
+		return &workspace.MergeResult{}, nil
+	}
+
+	regenModes, regenCommands, err := planRegeneration(mergeCfg, plan.Conflicts)
+	if err != nil {
+		return nil, err
+	}
+	pathModes, err := planAttributeModes(mergeCfg, plan.Conflicts, regenModes)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.interactive && !opts.dryRun {
+		var agentFiles int
+		pathModes, agentFiles = promptConflictResolutions(plan.Conflicts, pathModes, conflictHunks(ws, plan), bufio.NewReader(os.Stdin))
+		// Every answer but agent is in pathModes; markers are the fallback
+		// if the agent fails.
+		opts.mode = ConflictModeManual
+		if agentFiles > 0 {
+			opts.mode = ConflictModeAgent
+		}
+	}
+
+	report := &mergeReport{
+		Source: sourceLabel,
+		Target: ws.WorkspaceName(),
+		Plan:   plan,
 	}
 
 	// Dry-run mode
-	if dryRun {
+	if opts.dryRun {
 		printMergePlan(plan)
+		printRegenerationPlan(regenCommands)
+		printAttributePlan(pathModes, regenModes)
 
 		if len(plan.Conflicts) > 0 {
-			printConflictDetails(ws, sourceInfo, dryRunSummary)
+			detailSource := sourceInfo
+			if opts.sourceRef != "" {
+				// Line-level details compare directories, so check the
+				// snapshot out to a scratch directory to stand in for the source.
+				dir, err := materializeSnapshot(ws.Store(), sourceSnapshotID)
+				if err != nil {
+					return nil, err
+				}
+				defer os.RemoveAll(dir)
+				refInfo := *sourceInfo
+				refInfo.Path = dir
+				detailSource = &refInfo
+			}
+			report.Summary = printConflictDetails(ws, detailSource, opts.agentSummary)
+			if opts.mode == ConflictModeAgent && opts.contextLines > 0 {
+				cacheConflictAnalysis(ws, plan)
+			}
+		}
+		if opts.summaryFile != "" {
+			if err := writeMergeReport(opts.summaryFile, report); err != nil {
+				return nil, err
+			}
+			fmt.Printf("Wrote merge report to %s\n", opts.summaryFile)
 		}
 
 		fmt.Println()
@@ -196,42 +601,59 @@ func runMerge(cmd *cobra.Command, sourceName string, mode ConflictMode, dryRun b
 			SourceID:     sourceSnapshotID,
 			MergeBaseID:  plan.MergeBaseID,
 			CurrentLabel: ws.WorkspaceName(),
-			SourceLabel:  sourceInfo.WorkspaceName,
+			SourceLabel:  sourceLabel,
 			Colorize:     true,
 		}))
 		fmt.Println()
 		fmt.Println("(Dry run - no changes made)")
 		fmt.Println()
 		fmt.Println("To merge:")
+		mergeArgs := sourceName
+		if opts.sourceRef != "" {
+			mergeArgs += " --source-ref " + opts.sourceRef
+		}
+		if opts.into != "" {
+			mergeArgs += " --into " + opts.into
+		}
 		if len(plan.Conflicts) > 0 {
-			fmt.Printf("  fst merge %s          # Let AI resolve conflicts\n", sourceName)
-			fmt.Printf("  fst merge %s --manual  # Create conflict markers\n", sourceName)
-			fmt.Printf("  fst merge %s --theirs  # Take their version for conflicts\n", sourceName)
-			fmt.Printf("  fst merge %s --ours    # Keep your version for conflicts\n", sourceName)
+			fmt.Printf("  fst merge %s          # Let AI resolve conflicts\n", mergeArgs)
+			fmt.Printf("  fst merge %s --manual  # Create conflict markers\n", mergeArgs)
+			fmt.Printf("  fst merge %s --theirs  # Take their version for conflicts\n", mergeArgs)
+			fmt.Printf("  fst merge %s --ours    # Keep your version for conflicts\n", mergeArgs)
 		} else {
-			fmt.Printf("  fst merge %s\n", sourceName)
+			fmt.Printf("  fst merge %s\n", mergeArgs)
 		}
-		return nil
+		return nil, nil
 	}
 
 	// Pre-merge auto-snapshot — abort if it fails so the user has a restore point
-	if !noPreSnapshot {
-		snapshotID, err := ws.AutoSnapshot(fmt.Sprintf("Before merge from %s", sourceInfo.WorkspaceName))
+	undoPoint := currentSnapshotID
+	if !opts.noPreSnapshot {
+		snapshotID, err := ws.AutoSnapshot(fmt.Sprintf("Before merge from %s", sourceLabel))
 		if err != nil {
-			return fmt.Errorf("failed to create pre-merge snapshot (use --no-pre-snapshot to skip): %w", err)
+			return nil, fmt.Errorf("failed to create pre-merge snapshot (use --no-pre-snapshot to skip): %w", err)
 		}
 		if snapshotID != "" {
-			fmt.Printf("Created snapshot %s (use 'fst restore' to undo merge)\n", snapshotID)
+			undoPoint = snapshotID
+			fmt.Printf("Created snapshot %s (use 'fst undo' to undo merge)\n", snapshotID)
 			fmt.Println()
 		}
 	}
+	if err := ws.RecordUndoPoint("merge", "merge from "+sourceLabel, undoPoint); err != nil {
+		if opts.verify != "" {
+			return nil, fmt.Errorf("cannot record the pre-merge state to roll back to: %w", err)
+		}
+		fmt.Printf("Warning: Could not record undo point: %v\n", err)
+	}
 
 	// Build merge options
+	var analysis *conflicts.AnalysisCache
 	applyOpts := workspace.ApplyMergeOpts{
-		Plan: plan,
+		Plan:      plan,
+		PathModes: pathModes,
 	}
 
-	switch mode {
+	switch opts.mode {
 	case ConflictModeTheirs:
 		applyOpts.Mode = workspace.ConflictModeTheirs
 	case ConflictModeOurs:
@@ -245,10 +667,18 @@ func runMerge(cmd *cobra.Command, sourceName string, mode ConflictMode, dryRun b
 			fmt.Printf("Warning: %v\n", err)
 			fmt.Println("Falling back to manual conflict markers...")
 		} else {
-			fmt.Printf("Using %s for conflict resolution...\n", preferredAgent.Name)
+			preferredAgent = withAgentModel(preferredAgent, opts.agentModel, mergeCfg)
+			if preferredAgent.Model != "" {
+				fmt.Printf("Using %s (model %s) for conflict resolution...\n", preferredAgent.Name, preferredAgent.Model)
+			} else {
+				fmt.Printf("Using %s for conflict resolution...\n", preferredAgent.Name)
+			}
 			invokeFunc := deps.AgentInvoke
+			if opts.contextLines > 0 {
+				analysis = loadConflictAnalysis(ws, plan)
+			}
 			applyOpts.Resolver = func(path string, current, source, base []byte) ([]byte, error) {
-				result, err := agent.InvokeMerge(preferredAgent, string(base), string(current), string(source), path, invokeFunc)
+				result, err := agentMerge(preferredAgent, path, base, current, source, opts.contextLines, analysis, invokeFunc)
 				if err != nil {
 					return nil, err
 				}
@@ -259,25 +689,73 @@ func runMerge(cmd *cobra.Command, sourceName string, mode ConflictMode, dryRun b
 					}
 				}
 				showMergeDiff(string(current), result.MergedCode)
+				report.AgentResolved = append(report.AgentResolved, path)
 				return []byte(result.MergedCode), nil
 			}
+			if opts.chunkSize > 1 {
+				applyOpts.ChunkSize = opts.chunkSize
+				applyOpts.ChunkBytes = opts.chunkBytes
+				applyOpts.BatchResolver = func(conflicts []workspace.ConflictInput) (map[string][]byte, error) {
+					files := make([]agent.FileConflict, 0, len(conflicts))
+					for _, c := range conflicts {
+						files = append(files, agent.FileConflict{Path: c.Path, Base: string(c.Base), Current: string(c.Current), Source: string(c.Source)})
+						fmt.Printf("  Resolving together: %s\n", c.Path)
+					}
+					results, err := agent.InvokeMergeFiles(preferredAgent, files, invokeFunc)
+					if err != nil {
+						fmt.Printf("    Chunk failed (%v); resolving files one at a time\n", err)
+						return nil, err
+					}
+					if strategy := results[conflicts[0].Path].Strategy; len(strategy) > 0 {
+						fmt.Printf("    Strategy:\n")
+						for _, bullet := range strategy {
+							fmt.Printf("      . %s\n", bullet)
+						}
+					}
+					merged := make(map[string][]byte, len(conflicts))
+					for _, c := range conflicts {
+						showMergeDiff(string(c.Current), results[c.Path].MergedCode)
+						report.AgentResolved = append(report.AgentResolved, c.Path)
+						merged[c.Path] = []byte(results[c.Path].MergedCode)
+					}
+					return merged, nil
+				}
+			}
 		}
 	}
 
 	// Apply merge
 	fmt.Println("Applying merge...")
+	showProgress := !opts.verbose && len(plan.ToApply) > mergeProgressThreshold && term.IsTerminal(int(os.Stdout.Fd()))
+	if showProgress {
+		applyOpts.Progress = func(done, total int) {
+			fmt.Printf("\r  Applying %d/%d files", done, total)
+			if done == total {
+				fmt.Println()
+			}
+		}
+	}
 	result, err := ws.ApplyMerge(applyOpts)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	runRegenerateCommands(ws.Root(), regenCommands, result)
+	if hits := analysis.Hits(); hits > 0 && opts.verbose {
+		fmt.Printf("  Reused the dry run's hunk analysis for %d files\n", hits)
 	}
 
-	// Print per-file results
-	for _, f := range result.Applied {
-		fmt.Printf("  Applied: %s\n", f)
+	// Print per-file results; the progress line already covered the applied files
+	if !showProgress {
+		for _, f := range result.Applied {
+			fmt.Printf("  Applied: %s\n", f)
+		}
 	}
 	for _, f := range result.AutoMerged {
 		fmt.Printf("  Auto-merged: %s\n", f)
 	}
+	for _, f := range result.Deleted {
+		fmt.Printf("  Deleted: %s\n", f)
+	}
 	for _, f := range result.Conflicts {
 		fmt.Printf("  Conflict: %s (needs manual resolution)\n", f)
 	}
@@ -286,19 +764,55 @@ func runMerge(cmd *cobra.Command, sourceName string, mode ConflictMode, dryRun b
 	}
 	fmt.Println()
 
+	if opts.verify != "" {
+		if len(result.Conflicts) > 0 || len(result.Failed) > 0 {
+			fmt.Println("Skipping --verify: the merge left unresolved files.")
+			fmt.Println()
+		} else if err := verifyMerge(ws, opts.verify); err != nil {
+			return nil, err
+		}
+	}
+
 	// Post-merge auto-snapshot (only if clean)
 	var mergedSnapshotID string
-	totalApplied := len(result.Applied) + len(result.AutoMerged)
-	if len(result.Conflicts) == 0 && len(result.Failed) == 0 && totalApplied > 0 {
+	mergeMessage := opts.message
+	if mergeMessage == "" {
+		mergeMessage = fmt.Sprintf("Merged %s", sourceLabel)
+	}
+	totalApplied := len(result.Applied) + len(result.AutoMerged) + len(result.Deleted)
+	unresolved := len(result.Conflicts) + len(result.Failed)
+	switch {
+	case opts.noSnapshotAfter:
+		if unresolved == 0 && totalApplied > 0 {
+			fmt.Printf("Skipped the post-merge snapshot; run 'fst snapshot -m \"%s\"' to record the merge.\n", mergeMessage)
+			fmt.Println()
+		}
+	case unresolved == 0 && (totalApplied > 0 || opts.snapshotAfter):
 		snapResult, err := ws.Snapshot(workspace.SnapshotOpts{
-			Message: fmt.Sprintf("Merged %s", sourceInfo.WorkspaceName),
+			Message: mergeMessage,
 		})
 		if err != nil {
+			if opts.snapshotAfter {
+				return result, fmt.Errorf("failed to create post-merge snapshot: %w", err)
+			}
 			fmt.Printf("Warning: Could not create post-merge snapshot: %v\n", err)
-			fmt.Printf("Run 'fst snapshot -m \"Merged %s\"' to save.\n", sourceInfo.WorkspaceName)
+			fmt.Printf("Run 'fst snapshot -m \"%s\"' to save.\n", mergeMessage)
 		} else {
 			mergedSnapshotID = snapResult.SnapshotID
 		}
+	case opts.snapshotAfter:
+		fmt.Printf("Not snapshotting the merge: %d files are unresolved.\n", unresolved)
+		fmt.Println()
+	}
+
+	if opts.summaryFile != "" {
+		report.Result = result
+		report.MergedSnapshotID = mergedSnapshotID
+		if err := writeMergeReport(opts.summaryFile, report); err != nil {
+			fmt.Printf("Warning: Could not write merge report: %v\n", err)
+		} else {
+			fmt.Printf("Wrote merge report to %s\n", opts.summaryFile)
+		}
 	}
 
 	// Summary
@@ -307,6 +821,9 @@ func runMerge(cmd *cobra.Command, sourceName string, mode ConflictMode, dryRun b
 	if len(result.AutoMerged) > 0 {
 		fmt.Printf("  Auto-merged:  %d files\n", len(result.AutoMerged))
 	}
+	if len(result.Deleted) > 0 {
+		fmt.Printf("  Deleted:      %d files\n", len(result.Deleted))
+	}
 	if len(result.Conflicts) > 0 {
 		fmt.Printf("  Conflicts:    %d files (need resolution)\n", len(result.Conflicts))
 	}
@@ -322,33 +839,117 @@ func runMerge(cmd *cobra.Command, sourceName string, mode ConflictMode, dryRun b
 		MergeBaseID:   plan.MergeBaseID,
 		MergedID:      mergedSnapshotID,
 		CurrentLabel:  ws.WorkspaceName(),
-		SourceLabel:   sourceInfo.WorkspaceName,
-		Message:       fmt.Sprintf("Merged %s", sourceInfo.WorkspaceName),
+		SourceLabel:   sourceLabel,
+		Message:       mergeMessage,
 		Pending:       len(result.Conflicts) > 0,
 		ConflictCount: len(result.Conflicts),
 		Colorize:      true,
 	}))
 
+	if opts.report || opts.reportFile != "" {
+		effect := mergeEffect(sourceLabel, ws.WorkspaceName(), plan, result)
+		if opts.report {
+			fmt.Println()
+			fmt.Println(effect)
+		}
+		if opts.reportFile != "" {
+			if err := os.WriteFile(opts.reportFile, []byte(effect+"\n"), 0644); err != nil {
+				fmt.Printf("Warning: Could not write merge report: %v\n", err)
+			} else {
+				fmt.Printf("Wrote merge report to %s\n", opts.reportFile)
+			}
+		}
+	}
+
 	if len(result.Conflicts) > 0 {
 		fmt.Println()
 		fmt.Println("To resolve conflicts manually:")
 		fmt.Println("  1. Edit the conflicting files (look for <<<<<<< markers)")
 		fmt.Println("  2. Remove the conflict markers")
-		fmt.Println("  3. Run 'fst snapshot' to save the merged state")
+		if opts.message != "" {
+			fmt.Printf("  3. Run 'fst snapshot -m \"%s\"' to save the merged state\n", opts.message)
+		} else {
+			fmt.Println("  3. Run 'fst snapshot' to save the merged state")
+		}
 		if cmd != nil {
 			cmd.SilenceErrors = true
-			return SilentExit(1)
+			return result, SilentExit(1)
 		}
 	}
+	if opts.snapshotAfter && mergedSnapshotID == "" {
+		return result, fmt.Errorf("merge left %d unresolved files; resolve them, then run 'fst snapshot' to record the merge", unresolved)
+	}
 
-	return nil
+	return result, nil
+}
+
+// verifyMerge runs command in the workspace and, if it fails, rolls the
+// workspace back to the undo point recorded before the merge was applied.
+func verifyMerge(ws *workspace.Workspace, command string) error {
+	fmt.Printf("Verifying merge: %s\n", command)
+	c := exec.Command("sh", "-c", command)
+	c.Dir = ws.Root()
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	runErr := c.Run()
+	if runErr == nil {
+		fmt.Println("✓ Verification passed")
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Printf("Verification failed (%v); rolling back merge...\n", runErr)
+	undo, err := ws.Undo(workspace.UndoOpts{Force: true})
+	if err != nil {
+		return fmt.Errorf("verification failed and the rollback failed too: %w\nRun 'fst undo' to retry the rollback", err)
+	}
+	return fmt.Errorf("merge rolled back: verification command failed: %v\nWorkspace restored to %s", runErr, undo.Entry.SnapshotID)
+}
+
+// resolveSourceRef resolves ref to a snapshot ID and checks that it is in
+// the history of the source workspace.
+func resolveSourceRef(s *store.Store, sourceInfo *store.WorkspaceInfo, ref string) (string, error) {
+	id, err := s.ResolveSnapshotID(ref)
+	if err != nil {
+		return "", fmt.Errorf("--source-ref: %w", err)
+	}
+	if !s.IsAncestorOf(id, sourceInfo.CurrentSnapshotID) {
+		return "", fmt.Errorf("snapshot %s is not in the history of workspace '%s'\nRun 'fst log' in that workspace to see its snapshots", id, sourceInfo.WorkspaceName)
+	}
+	return id, nil
+}
+
+// materializeSnapshot writes the files of snapshotID to a new temporary
+// directory and returns its path. The caller removes it.
+func materializeSnapshot(s *store.Store, snapshotID string) (string, error) {
+	manifestHash, err := s.ManifestHashFromSnapshotID(snapshotID)
+	if err != nil {
+		return "", err
+	}
+	m, err := s.LoadManifest(manifestHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to load snapshot manifest: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "fst-merge-source-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	if err := gitstore.RestoreFilesFromManifest(dir, s, m, gitstore.RestoreOptions{}); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to materialize snapshot %s: %w", snapshotID, err)
+	}
+	return dir, nil
 }
 
 func printMergePlan(plan *store.MergePlan) {
 	if len(plan.ToApply) > 0 {
 		fmt.Println("Will apply from source:")
 		for _, a := range plan.ToApply {
-			fmt.Printf("  + %s\n", a.Path)
+			if a.Type == "delete" {
+				fmt.Printf("  - %s (deleted in source)\n", a.Path)
+			} else {
+				fmt.Printf("  + %s\n", a.Path)
+			}
 		}
 	}
 
@@ -362,53 +963,177 @@ func printMergePlan(plan *store.MergePlan) {
 	if len(plan.Conflicts) > 0 {
 		fmt.Println("Conflicts to resolve:")
 		for _, a := range plan.Conflicts {
-			fmt.Printf("  ! %s\n", a.Path)
+			fmt.Printf("  ! %s%s\n", a.Path, deleteConflictNote(a))
 		}
 	}
 }
 
-func printConflictDetails(ws *workspace.Workspace, sourceInfo *store.WorkspaceInfo, agentSummary bool) {
-	if sourceInfo.Path == "" {
+// deleteConflictNote describes a delete/modify conflict for plan listings.
+func deleteConflictNote(a store.MergeAction) string {
+	switch {
+	case a.SourceDeleted():
+		return " (deleted in source, modified here)"
+	case a.CurrentDeleted():
+		return " (deleted here, modified in source)"
+	}
+	return ""
+}
+
+// promptConflictResolutions asks, for each conflict without a mode in
+// modes, whether to take theirs, keep ours, let the agent merge it, write
+// conflict markers or skip it, after showing its conflicting hunks. Answers
+// other than agent are recorded in modes; the number of files left to the
+// agent is returned. Files are skipped once input runs out.
+func promptConflictResolutions(actions []store.MergeAction, modes map[string]workspace.ConflictMode, hunks func(store.MergeAction) []conflicts.Hunk, in *bufio.Reader) (map[string]workspace.ConflictMode, int) {
+	if modes == nil {
+		modes = make(map[string]workspace.ConflictMode)
+	}
+	agentFiles := 0
+	for i, action := range actions {
+		if _, ok := modes[action.Path]; ok {
+			continue
+		}
+		fmt.Printf("\n[%d/%d] %s%s\n", i+1, len(actions), action.Path, deleteConflictNote(action))
+		choices := "[t]heirs, [o]urs, [a]gent, [m]anual, [s]kip"
+		if action.IsDeleteConflict() {
+			choices = "[t]heirs, [o]urs, [m]anual, [s]kip"
+		} else {
+			printHunkPreviews(hunks(action))
+		}
+
+		for {
+			fmt.Printf("  Resolve with %s? ", choices)
+			response, err := in.ReadString('\n')
+			answer := strings.TrimSpace(strings.ToLower(response))
+			if answer == "" && err != nil {
+				answer = "s"
+			}
+			switch answer {
+			case "t", "theirs":
+				modes[action.Path] = workspace.ConflictModeTheirs
+			case "o", "ours":
+				modes[action.Path] = workspace.ConflictModeOurs
+			case "m", "manual":
+				modes[action.Path] = workspace.ConflictModeManual
+			case "s", "skip":
+				modes[action.Path] = workspace.ConflictModeSkip
+			case "a", "agent":
+				if action.IsDeleteConflict() {
+					fmt.Println("  The agent cannot resolve a delete/modify conflict")
+					continue
+				}
+				agentFiles++
+			default:
+				continue
+			}
+			break
+		}
+	}
+	return modes, agentFiles
+}
+
+// conflictHunks returns a function giving the conflicting hunks of a file in
+// plan, reusing the hunk analysis cached by a dry run of the same merge.
+func conflictHunks(ws *workspace.Workspace, plan *store.MergePlan) func(store.MergeAction) []conflicts.Hunk {
+	analysis := loadConflictAnalysis(ws, plan)
+	return func(action store.MergeAction) []conflicts.Hunk {
+		var contents [3]string
+		for i, hash := range []string{action.BaseHash, action.CurrentHash, action.SourceHash} {
+			if hash == "" {
+				continue
+			}
+			data, err := ws.Store().ReadBlob(hash)
+			if err != nil {
+				return nil
+			}
+			contents[i] = string(data)
+		}
+		var hunks []conflicts.Hunk
+		for _, r := range analysis.Regions(action.Path, contents[0], contents[1], contents[2]) {
+			if r.Conflict != nil {
+				hunks = append(hunks, *r.Conflict)
+			}
+		}
+		return hunks
+	}
+}
+
+// loadConflictAnalysis loads the hunk analysis cached for the manifests of
+// plan's snapshots. It returns nil, which analyses without caching, when a
+// manifest cannot be resolved.
+func loadConflictAnalysis(ws *workspace.Workspace, plan *store.MergePlan) *conflicts.AnalysisCache {
+	var hashes [3]string
+	for i, id := range []string{plan.MergeBaseID, plan.CurrentSnapshotID, plan.SourceSnapshotID} {
+		if id == "" {
+			continue
+		}
+		hash, err := ws.Store().ManifestHashFromSnapshotID(id)
+		if err != nil {
+			return nil
+		}
+		hashes[i] = hash
+	}
+	return conflicts.LoadAnalysisCache(ws.Root(), hashes[0], hashes[1], hashes[2])
+}
+
+// cacheConflictAnalysis runs the hunk analysis a merge with --context-lines
+// needs for the conflicting files of plan and saves it in the workspace, so
+// a real merge of the same contents can reuse it.
+func cacheConflictAnalysis(ws *workspace.Workspace, plan *store.MergePlan) {
+	if plan.MergeBaseID == "" {
 		return
 	}
+	analysis := loadConflictAnalysis(ws, plan)
+	if analysis == nil {
+		return
+	}
+	for _, c := range plan.Conflicts {
+		if c.BaseHash == "" || c.IsDeleteConflict() {
+			continue
+		}
+		base, err := ws.Store().ReadBlob(c.BaseHash)
+		if err != nil {
+			continue
+		}
+		current, err := ws.Store().ReadBlob(c.CurrentHash)
+		if err != nil {
+			continue
+		}
+		source, err := ws.Store().ReadBlob(c.SourceHash)
+		if err != nil {
+			continue
+		}
+		analysis.Regions(c.Path, string(base), string(current), string(source))
+	}
+	if err := analysis.Save(ws.Root()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not cache conflict analysis: %v\n", err)
+	}
+}
+
+// printConflictDetails prints line-level conflict details and, when
+// agentSummary is set, an agent-generated summary, which it also returns.
+func printConflictDetails(ws *workspace.Workspace, sourceInfo *store.WorkspaceInfo, agentSummary bool) string {
+	if sourceInfo.Path == "" {
+		return ""
+	}
 
 	fmt.Println()
 	fmt.Println("Conflict details:")
-	conflictReport, err := conflicts.Detect(ws.Root(), sourceInfo.Path, true)
+	conflictReport, err := conflicts.Detect(ws.Root(), sourceInfo.Path, true, nil)
 	if err != nil {
 		fmt.Printf("  (Could not analyze conflicts: %v)\n", err)
-		return
+		return ""
 	}
 
 	if conflictReport.TrueConflicts == 0 {
 		fmt.Println("  Files are modified in both workspaces but changes don't overlap.")
 		fmt.Println("  These can be auto-merged.")
-		return
+		return ""
 	}
 
 	for _, c := range conflictReport.Conflicts {
 		fmt.Printf("\n  %s (%d conflicting regions)\n", ui.Red(c.Path), len(c.Hunks))
-		for i, h := range c.Hunks {
-			if h.EndLine > h.StartLine {
-				fmt.Printf("    Region %d: lines %d-%d\n", i+1, h.StartLine, h.EndLine)
-			} else {
-				fmt.Printf("    Region %d: line %d\n", i+1, h.StartLine)
-			}
-			if len(h.CurrentLines) > 0 {
-				fmt.Printf("      Current: %s", truncatePreview(h.CurrentLines[0], 60))
-				if len(h.CurrentLines) > 1 {
-					fmt.Printf(" (+%d more lines)", len(h.CurrentLines)-1)
-				}
-				fmt.Println()
-			}
-			if len(h.SourceLines) > 0 {
-				fmt.Printf("      Source:  %s", truncatePreview(h.SourceLines[0], 60))
-				if len(h.SourceLines) > 1 {
-					fmt.Printf(" (+%d more lines)", len(h.SourceLines)-1)
-				}
-				fmt.Println()
-			}
-		}
+		printHunkPreviews(c.Hunks)
 	}
 
 	autoMergeCount := len(conflictReport.OverlappingFiles) - conflictReport.TrueConflicts
@@ -430,9 +1155,98 @@ func printConflictDetails(ws *workspace.Workspace, sourceInfo *store.WorkspaceIn
 				fmt.Printf("Warning: Failed to generate summary: %v\n", err)
 			} else {
 				fmt.Printf("\nSummary:\n  %s\n", summaryText)
+				return summaryText
 			}
 		}
 	}
+	return ""
+}
+
+// printHunkPreviews prints the line range and the first current and source
+// line of each conflicting hunk.
+func printHunkPreviews(hunks []conflicts.Hunk) {
+	for i, h := range hunks {
+		if h.EndLine > h.StartLine {
+			fmt.Printf("    Region %d: lines %d-%d\n", i+1, h.StartLine, h.EndLine)
+		} else {
+			fmt.Printf("    Region %d: line %d\n", i+1, h.StartLine)
+		}
+		if len(h.CurrentLines) > 0 {
+			fmt.Printf("      Current: %s", truncatePreview(h.CurrentLines[0], 60))
+			if len(h.CurrentLines) > 1 {
+				fmt.Printf(" (+%d more lines)", len(h.CurrentLines)-1)
+			}
+			fmt.Println()
+		}
+		if len(h.SourceLines) > 0 {
+			fmt.Printf("      Source:  %s", truncatePreview(h.SourceLines[0], 60))
+			if len(h.SourceLines) > 1 {
+				fmt.Printf(" (+%d more lines)", len(h.SourceLines)-1)
+			}
+			fmt.Println()
+		}
+	}
+}
+
+// agentMerge resolves a conflicting file with the agent. With contextLines
+// > 0 only the conflicting hunks, each with that many merged lines around
+// it, are sent, and the agent's resolutions are spliced back into the
+// three-way merge of the file, taken from analysis when it has it. Without
+// a base it sends whole files.
+func agentMerge(ag *agent.Agent, path string, base, current, source []byte, contextLines int, analysis *conflicts.AnalysisCache, invoke agent.InvokeFunc) (*agent.MergeResult, error) {
+	if contextLines <= 0 || len(base) == 0 {
+		return agent.InvokeMerge(ag, string(base), string(current), string(source), path, invoke)
+	}
+
+	regions := analysis.Regions(path, string(base), string(current), string(source))
+	var hunks []agent.MergeHunk
+	for i, r := range regions {
+		if r.Conflict == nil {
+			continue
+		}
+		h := agent.MergeHunk{
+			Base:    strings.Join(r.Conflict.BaseLines, "\n"),
+			Current: strings.Join(r.Conflict.CurrentLines, "\n"),
+			Source:  strings.Join(r.Conflict.SourceLines, "\n"),
+		}
+		if i > 0 && regions[i-1].Conflict == nil {
+			before := regions[i-1].Lines
+			if len(before) > contextLines {
+				before = before[len(before)-contextLines:]
+			}
+			h.Before = strings.Join(before, "\n")
+		}
+		if i+1 < len(regions) && regions[i+1].Conflict == nil {
+			after := regions[i+1].Lines
+			if len(after) > contextLines {
+				after = after[:contextLines]
+			}
+			h.After = strings.Join(after, "\n")
+		}
+		hunks = append(hunks, h)
+	}
+	if len(hunks) == 0 {
+		return agent.InvokeMerge(ag, string(base), string(current), string(source), path, invoke)
+	}
+	fmt.Printf("    Sending %d conflicting hunks with %d lines of context\n", len(hunks), contextLines)
+
+	result, err := agent.InvokeMergeHunks(ag, path, hunks, invoke)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	next := 0
+	for _, r := range regions {
+		if r.Conflict == nil {
+			lines = append(lines, r.Lines...)
+			continue
+		}
+		if resolved := result.Resolved[next]; resolved != "" {
+			lines = append(lines, strings.Split(resolved, "\n")...)
+		}
+		next++
+	}
+	return &agent.MergeResult{Strategy: result.Strategy, MergedCode: strings.Join(lines, "\n")}, nil
 }
 
 func showMergeDiff(before, after string) {
@@ -526,3 +1340,141 @@ func buildConflictInfosFromReport(report *conflicts.Report) []agent.ConflictInfo
 
 	return infos
 }
+
+// planRegeneration matches conflicting paths against merge.regenerate. It
+// returns the conflict mode to use for each matching path and the paths to
+// regenerate grouped by command.
+func planRegeneration(cfg *config.MergeConfig, conflicts []store.MergeAction) (map[string]workspace.ConflictMode, map[string][]string, error) {
+	if cfg == nil || len(cfg.Regenerate) == 0 {
+		return nil, nil, nil
+	}
+
+	side := workspace.ConflictModeOurs
+	switch cfg.RegenerateSide {
+	case "", "ours":
+	case "theirs":
+		side = workspace.ConflictModeTheirs
+	default:
+		return nil, nil, fmt.Errorf("invalid merge.regenerate_side %q (expected: ours, theirs)", cfg.RegenerateSide)
+	}
+
+	modes := make(map[string]workspace.ConflictMode)
+	commands := make(map[string][]string)
+	for _, action := range conflicts {
+		command, ok := cfg.RegenerateCommand(action.Path)
+		if !ok {
+			continue
+		}
+		modes[action.Path] = side
+		commands[command] = append(commands[command], action.Path)
+	}
+	return modes, commands, nil
+}
+
+// planAttributeModes adds the merge.attributes strategy of each conflicting
+// path to modes. Paths already handled by merge.regenerate keep their mode.
+func planAttributeModes(cfg *config.MergeConfig, conflicts []store.MergeAction, modes map[string]workspace.ConflictMode) (map[string]workspace.ConflictMode, error) {
+	if cfg == nil || len(cfg.Attributes) == 0 {
+		return modes, nil
+	}
+	if modes == nil {
+		modes = make(map[string]workspace.ConflictMode)
+	}
+	for _, action := range conflicts {
+		if _, ok := modes[action.Path]; ok {
+			continue
+		}
+		strategy, ok := cfg.Attribute(action.Path)
+		if !ok {
+			continue
+		}
+		switch strategy {
+		case "ours":
+			modes[action.Path] = workspace.ConflictModeOurs
+		case "theirs":
+			modes[action.Path] = workspace.ConflictModeTheirs
+		case "manual":
+			modes[action.Path] = workspace.ConflictModeManual
+		default:
+			return nil, fmt.Errorf("invalid merge.attributes strategy %q for %s (expected: ours, theirs, manual)", strategy, action.Path)
+		}
+	}
+	return modes, nil
+}
+
+func printAttributePlan(pathModes, regenModes map[string]workspace.ConflictMode) {
+	var paths []string
+	for p := range pathModes {
+		if _, ok := regenModes[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+	sort.Strings(paths)
+	names := map[workspace.ConflictMode]string{
+		workspace.ConflictModeOurs:   "ours",
+		workspace.ConflictModeTheirs: "theirs",
+		workspace.ConflictModeManual: "manual",
+	}
+	fmt.Println()
+	fmt.Println("Resolved by merge.attributes:")
+	for _, p := range paths {
+		fmt.Printf("  %s  (%s)\n", p, names[pathModes[p]])
+	}
+}
+
+func printRegenerationPlan(commands map[string][]string) {
+	if len(commands) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println("Regenerate after merge:")
+	for _, command := range sortedKeys(commands) {
+		fmt.Printf("  %s  (%s)\n", strings.Join(commands[command], ", "), command)
+	}
+}
+
+// runRegenerateCommands runs each regeneration command once from the
+// workspace root. Files whose command fails are moved from Applied to Failed
+// so the merge is not auto-snapshotted with stale generated content.
+func runRegenerateCommands(root string, commands map[string][]string, result *workspace.MergeResult) {
+	for _, command := range sortedKeys(commands) {
+		paths := commands[command]
+		fmt.Printf("  Regenerating %s: %s\n", strings.Join(paths, ", "), command)
+
+		c := exec.Command("sh", "-c", command)
+		c.Dir = root
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		err := c.Run()
+		if err == nil {
+			continue
+		}
+		fmt.Printf("  Warning: regeneration failed for %s: %v\n", strings.Join(paths, ", "), err)
+
+		failed := make(map[string]struct{}, len(paths))
+		for _, p := range paths {
+			failed[p] = struct{}{}
+		}
+		applied := result.Applied[:0]
+		for _, p := range result.Applied {
+			if _, ok := failed[p]; ok {
+				result.Failed = append(result.Failed, p)
+			} else {
+				applied = append(applied, p)
+			}
+		}
+		result.Applied = applied
+	}
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
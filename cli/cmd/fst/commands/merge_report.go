@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
+)
+
+// mergeReport collects what --summary-file writes: the merge plan, the
+// optional agent conflict summary, and, after a real merge, its outcome.
+type mergeReport struct {
+	Source           string
+	Target           string
+	Plan             *store.MergePlan
+	Summary          string
+	Result           *workspace.MergeResult // nil for dry runs
+	AgentResolved    []string
+	MergedSnapshotID string
+}
+
+// Markdown renders the report. Every section is always present (with
+// "_None_" when empty) so reports can be diffed and parsed reliably.
+func (r *mergeReport) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Merge %s into %s\n\n", r.Source, r.Target)
+
+	b.WriteString("## Snapshots\n\n")
+	fmt.Fprintf(&b, "- Source: `%s`\n", r.Plan.SourceSnapshotID)
+	fmt.Fprintf(&b, "- Target: `%s`\n", r.Plan.CurrentSnapshotID)
+	if r.Plan.MergeBaseID != "" {
+		fmt.Fprintf(&b, "- Merge base: `%s`\n", r.Plan.MergeBaseID)
+	} else {
+		b.WriteString("- Merge base: none (two-way merge)\n")
+	}
+	if r.MergedSnapshotID != "" {
+		fmt.Fprintf(&b, "- Merged snapshot: `%s`\n", r.MergedSnapshotID)
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Plan\n\n")
+	writeReportList(&b, "Apply from source", actionPaths(r.Plan.ToApply))
+	writeReportList(&b, "Auto-merge", actionPaths(r.Plan.AutoMerged))
+	writeReportList(&b, "Conflicts", actionPaths(r.Plan.Conflicts))
+	fmt.Fprintf(&b, "Already in sync: %d files\n\n", r.Plan.InSync)
+
+	b.WriteString("## Conflict summary\n\n")
+	if strings.TrimSpace(r.Summary) != "" {
+		b.WriteString(strings.TrimSpace(r.Summary) + "\n\n")
+	} else {
+		b.WriteString("_None_\n\n")
+	}
+
+	b.WriteString("## Result\n\n")
+	if r.Result == nil {
+		b.WriteString("Dry run - no changes made.\n")
+		return b.String()
+	}
+	writeReportList(&b, "Applied", r.Result.Applied)
+	writeReportList(&b, "Auto-merged", r.Result.AutoMerged)
+	writeReportList(&b, "Deleted", r.Result.Deleted)
+	writeReportList(&b, "Resolved by agent", r.AgentResolved)
+	writeReportList(&b, "Unresolved conflicts", r.Result.Conflicts)
+	writeReportList(&b, "Failed", r.Result.Failed)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeMergeReport(path string, r *mergeReport) error {
+	if err := os.WriteFile(path, []byte(r.Markdown()), 0644); err != nil {
+		return fmt.Errorf("failed to write merge report: %w", err)
+	}
+	return nil
+}
+
+func writeReportList(b *strings.Builder, title string, paths []string) {
+	fmt.Fprintf(b, "### %s (%d)\n\n", title, len(paths))
+	if len(paths) == 0 {
+		b.WriteString("_None_\n\n")
+		return
+	}
+	for _, p := range paths {
+		fmt.Fprintf(b, "- `%s`\n", p)
+	}
+	b.WriteString("\n")
+}
+
+func actionPaths(actions []store.MergeAction) []string {
+	paths := make([]string, 0, len(actions))
+	for _, a := range actions {
+		paths = append(paths, a.Path)
+	}
+	return paths
+}
+
+// mergeEffect renders the --report summary of an applied merge: its net
+// effect on the target's files, grouped by outcome, with the side or
+// resolution that decided each changed file. Conflicts resolved in favor
+// of the target leave its files as they were, so they are listed apart
+// under "Kept (ours)".
+func mergeEffect(source, target string, plan *store.MergePlan, result *workspace.MergeResult) string {
+	failed := make(map[string]bool, len(result.Failed))
+	for _, p := range result.Failed {
+		failed[p] = true
+	}
+	var added, changed, deleted, kept []string
+	for _, a := range plan.ToApply {
+		switch {
+		case failed[a.Path]:
+		case a.Type == "delete":
+			deleted = append(deleted, fmt.Sprintf("`%s` (theirs)", a.Path))
+		case a.CurrentHash == "":
+			added = append(added, fmt.Sprintf("`%s`", a.Path))
+		default:
+			changed = append(changed, fmt.Sprintf("`%s` (theirs)", a.Path))
+		}
+	}
+	for _, a := range plan.AutoMerged {
+		if !failed[a.Path] {
+			changed = append(changed, fmt.Sprintf("`%s` (auto-merged)", a.Path))
+		}
+	}
+	for _, a := range plan.Conflicts {
+		how, ok := result.Resolutions[a.Path]
+		if !ok || how == workspace.ResolvedManual || how == workspace.ResolvedSkipped {
+			continue
+		}
+		if how == workspace.ResolvedOurs {
+			kept = append(kept, fmt.Sprintf("`%s`", a.Path))
+			continue
+		}
+		if how == workspace.ResolvedResolver {
+			how = "agent"
+		}
+		entry := fmt.Sprintf("`%s` (%s)", a.Path, how)
+		switch {
+		case how == workspace.ResolvedTheirs && a.SourceDeleted():
+			deleted = append(deleted, entry)
+		case how == workspace.ResolvedTheirs && a.CurrentHash == "":
+			added = append(added, entry)
+		default:
+			changed = append(changed, entry)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Merge report: %s into %s\n\n", source, target)
+	writeEffectGroup(&b, "Added", added)
+	writeEffectGroup(&b, "Changed", changed)
+	writeEffectGroup(&b, "Deleted", deleted)
+	writeEffectGroup(&b, "Kept (ours)", kept)
+	writeEffectGroup(&b, "Conflicted", quotePaths(result.Conflicts))
+	writeEffectGroup(&b, "Failed", quotePaths(result.Failed))
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeEffectGroup(b *strings.Builder, title string, entries []string) {
+	fmt.Fprintf(b, "### %s (%d)\n\n", title, len(entries))
+	if len(entries) == 0 {
+		b.WriteString("_None_\n\n")
+		return
+	}
+	sort.Strings(entries)
+	for _, e := range entries {
+		fmt.Fprintf(b, "- %s\n", e)
+	}
+	b.WriteString("\n")
+}
+
+func quotePaths(paths []string) []string {
+	quoted := make([]string, 0, len(paths))
+	for _, p := range paths {
+		quoted = append(quoted, fmt.Sprintf("`%s`", p))
+	}
+	return quoted
+}
@@ -1,12 +1,17 @@
 package commands
 
 import (
+	"bufio"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/ankitiscracked/fastest/cli/internal/agent"
 	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/conflicts"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
 )
 
 func TestMergeModeValidation(t *testing.T) {
@@ -146,6 +151,244 @@ func TestMergeDryRunPlan(t *testing.T) {
 	}
 }
 
+func TestRunMergeReturnsResult(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one", "shared.txt": "target"},
+		map[string]string{"b.txt": "two", "shared.txt": "source"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	var dryRun *workspace.MergeResult
+	if err := captureStdout(func() error {
+		var err error
+		dryRun, err = runMerge(nil, "ws-source", mergeOptions{mode: ConflictModeTheirs, force: true, dryRun: true})
+		return err
+	}, new(string)); err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if dryRun != nil {
+		t.Fatalf("expected no result for a dry run, got %+v", dryRun)
+	}
+
+	var result *workspace.MergeResult
+	if err := captureStdout(func() error {
+		var err error
+		result, err = runMerge(nil, "ws-source", mergeOptions{mode: ConflictModeManual, force: true})
+		return err
+	}, new(string)); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	if result == nil {
+		t.Fatalf("expected a merge result")
+	}
+	if len(result.Applied) != 1 || result.Applied[0] != "b.txt" {
+		t.Fatalf("expected b.txt applied, got %v", result.Applied)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "shared.txt" {
+		t.Fatalf("expected shared.txt in conflict, got %v", result.Conflicts)
+	}
+}
+
+func TestMergeInto(t *testing.T) {
+	projectRoot, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, projectRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--into", "ws-source"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "into itself") {
+		t.Fatalf("expected self-merge error, got %v", err)
+	}
+
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"merge", "ws-source", "--into", "ws-target", "--theirs", "--force"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("merge --into failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(targetRoot, "b.txt"))
+	if err != nil {
+		t.Fatalf("expected b.txt in target: %v", err)
+	}
+	if string(data) != "two" {
+		t.Fatalf("unexpected b.txt content: %q", data)
+	}
+}
+
+func TestMergeRejectsCurrentWorkspace(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	for _, args := range [][]string{
+		{"merge", "ws-target", "--theirs", "--force"},
+		{"merge", "ws-target", "--record-only"},
+	} {
+		cmd := NewRootCmd()
+		cmd.SetArgs(args)
+		if err := captureStdout(cmd.Execute, new(string)); err == nil || !strings.Contains(err.Error(), "cannot merge workspace 'ws-target' into itself") {
+			t.Fatalf("%v: expected self-merge error, got %v", args, err)
+		}
+	}
+}
+
+func TestMergeSourceRef(t *testing.T) {
+	projectRoot, targetRoot, sourceRoot := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+	s := store.OpenAt(projectRoot)
+	sourceInfo, err := s.FindWorkspaceByName("ws-source")
+	if err != nil {
+		t.Fatalf("FindWorkspaceByName: %v", err)
+	}
+	olderSource := sourceInfo.CurrentSnapshotID
+	targetInfo, err := s.FindWorkspaceByName("ws-target")
+	if err != nil {
+		t.Fatalf("FindWorkspaceByName: %v", err)
+	}
+
+	// Move the source on past the snapshot we will merge.
+	if err := os.WriteFile(filepath.Join(sourceRoot, "c.txt"), []byte("later"), 0644); err != nil {
+		t.Fatalf("write c.txt: %v", err)
+	}
+	restoreCwd := chdir(t, sourceRoot)
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "--message", "later source changes"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("source snapshot failed: %v", err)
+	}
+	restoreCwd()
+
+	restoreCwd = chdir(t, targetRoot)
+	defer restoreCwd()
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--source-ref", targetInfo.CurrentSnapshotID, "--force"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "not in the history") {
+		t.Fatalf("expected history error, got %v", err)
+	}
+
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"merge", "ws-source", "--source-ref", olderSource[:16], "--theirs", "--force"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("merge --source-ref failed: %v", err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(targetRoot, "b.txt")); err != nil || string(data) != "two" {
+		t.Fatalf("expected b.txt from the older snapshot, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(targetRoot, "c.txt")); !os.IsNotExist(err) {
+		t.Fatalf("c.txt is only in the later source snapshot and should not be merged")
+	}
+}
+
+func TestMergeVerify(t *testing.T) {
+	projectRoot, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	before, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"merge", "ws-source", "--theirs", "--force", "--verify", "test ! -f b.txt"})
+		return cmd.Execute()
+	}, new(string))
+	if err == nil || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("expected the merge to be rolled back, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetRoot, "b.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected b.txt to be removed by the rollback")
+	}
+	after, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if after.CurrentSnapshotID != before.CurrentSnapshotID {
+		t.Fatalf("expected head %s after rollback, got %s", before.CurrentSnapshotID, after.CurrentSnapshotID)
+	}
+	if parents, _ := config.ReadPendingMergeParentsAt(targetRoot); len(parents) != 0 {
+		t.Fatalf("expected no pending merge after rollback, got %v", parents)
+	}
+
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"merge", "ws-source", "--theirs", "--force", "--verify", "test -f b.txt"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("merge --verify failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetRoot, "b.txt")); err != nil {
+		t.Fatalf("expected b.txt after a verified merge: %v", err)
+	}
+	s := store.OpenAt(projectRoot)
+	head, err := s.FindWorkspaceByName("ws-target")
+	if err != nil {
+		t.Fatalf("FindWorkspaceByName: %v", err)
+	}
+	meta, err := s.LoadSnapshotMeta(head.CurrentSnapshotID)
+	if err != nil || len(meta.ParentSnapshotIDs) != 2 {
+		t.Fatalf("expected a post-merge snapshot, got %+v (%v)", meta, err)
+	}
+}
+
+func TestMergeAbortIfDirty(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	if err := os.WriteFile(filepath.Join(targetRoot, "local.txt"), []byte("edit"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--theirs", "--force", "--abort-if-dirty"})
+	err := captureStdout(func() error { return cmd.Execute() }, new(string))
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes") {
+		t.Fatalf("expected dirty-target error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetRoot, "b.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected b.txt not to be merged, stat err = %v", err)
+	}
+
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"merge", "ws-source", "--theirs", "--force"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("merge without --abort-if-dirty failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetRoot, "b.txt")); err != nil {
+		t.Fatalf("expected b.txt in target: %v", err)
+	}
+}
+
 func TestMergeAutoSnapshot(t *testing.T) {
 	_, targetRoot, _ := setupProjectWithWorkspaces(t,
 		map[string]string{"a.txt": "one"},
@@ -176,6 +419,93 @@ func TestMergeAutoSnapshot(t *testing.T) {
 	}
 }
 
+func TestMergeSnapshotAfter(t *testing.T) {
+	projectRoot, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"base.txt": "base\nours\n"},
+		map[string]string{"base.txt": "base\ntheirs\n"},
+	)
+	s := store.OpenAt(projectRoot)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--manual", "--snapshot-after"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected --snapshot-after to fail while conflicts remain")
+	}
+	parents, err := config.ReadPendingMergeParentsAt(targetRoot)
+	if err != nil || len(parents) != 2 {
+		t.Fatalf("expected the merge to stay pending, got %v, %v", parents, err)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"merge", "--abort"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("merge --abort: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(targetRoot, "base.txt"), []byte("base\nours\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--theirs", "--snapshot-after", "-m", "take theirs"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("merge --snapshot-after: %v", err)
+	}
+	cfg, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	meta, err := s.LoadSnapshotMeta(cfg.CurrentSnapshotID)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if meta.Message != "take theirs" || len(meta.ParentSnapshotIDs) != 2 {
+		t.Fatalf("expected a merge snapshot named 'take theirs', got %q with parents %v", meta.Message, meta.ParentSnapshotIDs)
+	}
+}
+
+func TestMergeNoSnapshotAfter(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+	targetCfg, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--no-snapshot-after", "-m", "x"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected --message with --no-snapshot-after to fail")
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--theirs", "--force", "--no-snapshot-after"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("merge --no-snapshot-after: %v", err)
+	}
+	after, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if after.CurrentSnapshotID != targetCfg.CurrentSnapshotID {
+		t.Fatalf("expected no post-merge snapshot")
+	}
+	if _, err := os.Stat(filepath.Join(targetRoot, "b.txt")); err != nil {
+		t.Fatalf("expected merged file in the workspace: %v", err)
+	}
+	parents, err := config.ReadPendingMergeParentsAt(targetRoot)
+	if err != nil || len(parents) != 2 {
+		t.Fatalf("expected pending merge parents for the next snapshot, got %v, %v", parents, err)
+	}
+}
+
 func TestMergeAbortClearsPendingParents(t *testing.T) {
 	root := setupWorkspace(t, "ws-merge-abort", map[string]string{
 		"file.txt": "base",
@@ -207,3 +537,319 @@ func TestMergeAbortClearsPendingParents(t *testing.T) {
 		t.Fatalf("expected merge-parents.json to be removed")
 	}
 }
+
+func TestMergeRegeneratesConfiguredFiles(t *testing.T) {
+	projectRoot, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"package-lock.json": "target lock", "notes.txt": "target notes"},
+		map[string]string{"package-lock.json": "source lock", "notes.txt": "source notes"},
+	)
+
+	projectCfg := `{"type":"project","project_id":"proj-test","project_name":"test-project",` +
+		`"merge":{"regenerate":{"*.json":"echo regenerated > package-lock.json"}}}`
+	if err := os.WriteFile(filepath.Join(projectRoot, ".fst", "config.json"), []byte(projectCfg), 0644); err != nil {
+		t.Fatalf("write config.json: %v", err)
+	}
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--manual", "--force"})
+	_ = cmd.Execute() // notes.txt is left with markers, so merge exits 1
+
+	lock, err := os.ReadFile(filepath.Join(targetRoot, "package-lock.json"))
+	if err != nil {
+		t.Fatalf("read package-lock.json: %v", err)
+	}
+	if string(lock) != "regenerated\n" {
+		t.Fatalf("expected regenerated lockfile, got %q", lock)
+	}
+
+	notes, err := os.ReadFile(filepath.Join(targetRoot, "notes.txt"))
+	if err != nil {
+		t.Fatalf("read notes.txt: %v", err)
+	}
+	if !strings.Contains(string(notes), "<<<<<<<") {
+		t.Fatalf("expected conflict markers in notes.txt, got %q", notes)
+	}
+}
+
+func TestMergeRecordOnly(t *testing.T) {
+	projectRoot, targetRoot, sourceRoot := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "target"},
+		map[string]string{"a.txt": "source"},
+	)
+
+	targetBefore, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt target: %v", err)
+	}
+	sourceCfg, err := config.LoadAt(sourceRoot)
+	if err != nil {
+		t.Fatalf("LoadAt source: %v", err)
+	}
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--record-only"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("merge --record-only failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(targetRoot, "a.txt"))
+	if err != nil {
+		t.Fatalf("read a.txt: %v", err)
+	}
+	if string(content) != "target" {
+		t.Fatalf("expected a.txt to be untouched, got %q", content)
+	}
+
+	targetAfter, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt target after: %v", err)
+	}
+	s := store.OpenAt(projectRoot)
+	meta, err := s.LoadSnapshotMeta(targetAfter.CurrentSnapshotID)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if len(meta.ParentSnapshotIDs) != 2 || meta.ParentSnapshotIDs[0] != targetBefore.CurrentSnapshotID || meta.ParentSnapshotIDs[1] != sourceCfg.CurrentSnapshotID {
+		t.Fatalf("expected parents [target, source], got %v", meta.ParentSnapshotIDs)
+	}
+
+	plan, err := s.PlanMerge(targetAfter.CurrentSnapshotID, sourceCfg.CurrentSnapshotID, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+	if plan.MergeBaseID != sourceCfg.CurrentSnapshotID {
+		t.Fatalf("expected merge base %s, got %s", sourceCfg.CurrentSnapshotID, plan.MergeBaseID)
+	}
+}
+
+func TestMergeSummaryFile(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one", "shared.txt": "target"},
+		map[string]string{"b.txt": "two", "shared.txt": "source"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	reportPath := filepath.Join(t.TempDir(), "merge.md")
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--dry-run", "--force", "--summary-file", reportPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("merge --dry-run failed: %v", err)
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	report := string(data)
+	for _, want := range []string{"# Merge ws-source into ws-target", "### Apply from source (1)", "- `b.txt`", "### Conflicts (1)", "- `shared.txt`", "Dry run - no changes made."} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("dry-run report missing %q:\n%s", want, report)
+		}
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--theirs", "--force", "--summary-file", reportPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	data, err = os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	report = string(data)
+	for _, want := range []string{"### Applied (2)", "### Unresolved conflicts (0)", "- Merged snapshot: `"} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("merge report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestMergeReport(t *testing.T) {
+	projectRoot, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"generated/api.ts": "target gen", "notes/me.md": "target notes", "main.go": "target main"},
+		map[string]string{"generated/api.ts": "source gen", "notes/me.md": "source notes", "main.go": "source main", "added.txt": "new"},
+	)
+
+	projectCfg := `{"type":"project","project_id":"proj-test","project_name":"test-project",` +
+		`"merge":{"attributes":{"generated/*":"theirs","notes/*":"ours"}}}`
+	if err := os.WriteFile(filepath.Join(projectRoot, ".fst", "config.json"), []byte(projectCfg), 0644); err != nil {
+		t.Fatalf("write config.json: %v", err)
+	}
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--dry-run", "--force", "--report"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--summary-file") {
+		t.Fatalf("expected --report to be rejected with --dry-run, got %v", err)
+	}
+
+	reportPath := filepath.Join(t.TempDir(), "report.md")
+	var output string
+	_ = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"merge", "ws-source", "--manual", "--force", "--report", "--report-file", reportPath})
+		return cmd.Execute() // main.go is left with markers, so merge exits 1
+	}, &output)
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	report := string(data)
+	for _, want := range []string{
+		"## Merge report: ws-source into ws-target",
+		"### Added (1)\n\n- `added.txt`",
+		"### Changed (1)\n\n- `generated/api.ts` (theirs)\n\n",
+		"### Deleted (0)",
+		"### Kept (ours) (1)\n\n- `notes/me.md`\n\n",
+		"### Conflicted (1)\n\n- `main.go`",
+	} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("report missing %q:\n%s", want, report)
+		}
+	}
+	if !strings.Contains(output, strings.TrimSpace(report)) {
+		t.Fatalf("expected --report to print the summary, got:\n%s", output)
+	}
+}
+
+func TestMergeAttributesOverrideGlobalMode(t *testing.T) {
+	projectRoot, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"generated/api.ts": "target gen", "notes/me.md": "target notes", "main.go": "target main"},
+		map[string]string{"generated/api.ts": "source gen", "notes/me.md": "source notes", "main.go": "source main"},
+	)
+
+	projectCfg := `{"type":"project","project_id":"proj-test","project_name":"test-project",` +
+		`"merge":{"attributes":{"generated/*":"theirs","notes/*":"ours"}}}`
+	if err := os.WriteFile(filepath.Join(projectRoot, ".fst", "config.json"), []byte(projectCfg), 0644); err != nil {
+		t.Fatalf("write config.json: %v", err)
+	}
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"merge", "ws-source", "--manual", "--force"})
+	_ = cmd.Execute() // main.go is left with markers, so merge exits 1
+
+	expect := map[string]string{
+		"generated/api.ts": "source gen",
+		"notes/me.md":      "target notes",
+	}
+	for path, want := range expect {
+		got, err := os.ReadFile(filepath.Join(targetRoot, path))
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		if string(got) != want {
+			t.Fatalf("%s = %q, want %q", path, got, want)
+		}
+	}
+	main, err := os.ReadFile(filepath.Join(targetRoot, "main.go"))
+	if err != nil {
+		t.Fatalf("read main.go: %v", err)
+	}
+	if !strings.Contains(string(main), "<<<<<<<") {
+		t.Fatalf("expected conflict markers in main.go, got %q", main)
+	}
+}
+
+func TestResolveApplyOrder(t *testing.T) {
+	if order, err := resolveApplyOrder("", nil); err != nil || order != store.ApplyOrderPath {
+		t.Fatalf("expected default path order, got %q, %v", order, err)
+	}
+	cfg := &config.MergeConfig{ApplyOrder: "deps"}
+	if order, err := resolveApplyOrder("", cfg); err != nil || order != store.ApplyOrderDeps {
+		t.Fatalf("expected configured deps order, got %q, %v", order, err)
+	}
+	if order, err := resolveApplyOrder("path", cfg); err != nil || order != store.ApplyOrderPath {
+		t.Fatalf("expected flag to override config, got %q, %v", order, err)
+	}
+	if _, err := resolveApplyOrder("random", nil); err == nil {
+		t.Fatalf("expected invalid apply order to be rejected")
+	}
+}
+
+func TestWithAgentModel(t *testing.T) {
+	claude := &agent.Agent{Name: "claude"}
+	if got := withAgentModel(claude, "", nil); got != claude {
+		t.Fatalf("expected agent unchanged without a model")
+	}
+	cfg := &config.MergeConfig{AgentModel: "haiku"}
+	if got := withAgentModel(claude, "", cfg); got.Model != "haiku" {
+		t.Fatalf("expected configured model, got %q", got.Model)
+	}
+	if got := withAgentModel(claude, "opus", cfg); got.Model != "opus" {
+		t.Fatalf("expected flag to override config, got %q", got.Model)
+	}
+	if claude.Model != "" {
+		t.Fatalf("expected the preferred agent not to be modified")
+	}
+	amp := &agent.Agent{Name: "amp"}
+	if got := withAgentModel(amp, "opus", nil); got.Model != "" {
+		t.Fatalf("expected model to be ignored for amp, got %q", got.Model)
+	}
+}
+
+func TestPromptConflictResolutions(t *testing.T) {
+	actions := []store.MergeAction{
+		{Path: "a.txt", Type: "conflict", BaseHash: "b", CurrentHash: "c", SourceHash: "s"},
+		{Path: "b.txt", Type: "conflict", BaseHash: "b", CurrentHash: "c", SourceHash: "s"},
+		{Path: "deleted.txt", Type: "conflict", BaseHash: "b", CurrentHash: "c"},
+		{Path: "preset.txt", Type: "conflict", BaseHash: "b", CurrentHash: "c", SourceHash: "s"},
+		{Path: "c.txt", Type: "conflict", BaseHash: "b", CurrentHash: "c", SourceHash: "s"},
+		{Path: "d.txt", Type: "conflict", BaseHash: "b", CurrentHash: "c", SourceHash: "s"},
+	}
+	preset := map[string]workspace.ConflictMode{"preset.txt": workspace.ConflictModeOurs}
+	hunks := func(a store.MergeAction) []conflicts.Hunk {
+		return []conflicts.Hunk{{StartLine: 3, EndLine: 3, CurrentLines: []string{"ours " + a.Path}, SourceLines: []string{"theirs " + a.Path}}}
+	}
+	// b.txt: an invalid answer is asked again; deleted.txt: the agent is
+	// refused for a delete/modify conflict; d.txt: input runs out.
+	in := bufio.NewReader(strings.NewReader("theirs\n?\na\nagent\nm\ns\n"))
+
+	var modes map[string]workspace.ConflictMode
+	var agentFiles int
+	var output string
+	if err := captureStdout(func() error {
+		modes, agentFiles = promptConflictResolutions(actions, preset, hunks, in)
+		return nil
+	}, &output); err != nil {
+		t.Fatalf("promptConflictResolutions: %v", err)
+	}
+
+	if agentFiles != 1 {
+		t.Fatalf("expected 1 file left to the agent, got %d", agentFiles)
+	}
+	want := map[string]workspace.ConflictMode{
+		"a.txt":       workspace.ConflictModeTheirs,
+		"deleted.txt": workspace.ConflictModeManual,
+		"preset.txt":  workspace.ConflictModeOurs,
+		"c.txt":       workspace.ConflictModeSkip,
+		"d.txt":       workspace.ConflictModeSkip,
+	}
+	if len(modes) != len(want) {
+		t.Fatalf("expected modes %v, got %v", want, modes)
+	}
+	for path, mode := range want {
+		if modes[path] != mode {
+			t.Fatalf("%s: expected mode %d, got %d (all: %v)", path, mode, modes[path], modes)
+		}
+	}
+	if !strings.Contains(output, "Current: ours a.txt") || strings.Contains(output, "preset.txt") {
+		t.Fatalf("expected hunk previews for prompted files only, got:\n%s", output)
+	}
+	if !strings.Contains(output, "cannot resolve a delete/modify conflict") {
+		t.Fatalf("expected the agent to be refused for deleted.txt, got:\n%s", output)
+	}
+}
@@ -24,6 +24,9 @@ func newParentCmd() *cobra.Command {
 	}
 	cmd.AddCommand(newParentInitCmd())
 	cmd.AddCommand(newProjectCreateCmd())
+	cmd.AddCommand(newProjectStatusCmd())
+	cmd.AddCommand(newProjectArchiveCmd())
+	cmd.AddCommand(newProjectRestoreCmd())
 	return cmd
 }
 
@@ -31,23 +34,32 @@ func newParentInitCmd() *cobra.Command {
 	var projectID string
 	var keepWorkspaceName bool
 	var force bool
+	var importGit bool
 
 	cmd := &cobra.Command{
 		Use:   "init [project-name]",
 		Short: "Initialize a project folder",
-		Args:  cobra.RangeArgs(0, 1),
+		Long: `Initialize a project folder around the current directory, which becomes
+the project's main workspace.
+
+With --import-git, the current directory must be a git repository. Instead
+of starting from a single snapshot of the current files, every commit is
+imported as a snapshot: the checked-out branch seeds the main workspace
+and each other local branch becomes a sibling workspace.`,
+		Args: cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectName := ""
 			if len(args) > 0 {
 				projectName = args[0]
 			}
-			return runParentInit(projectName, projectID, keepWorkspaceName, force)
+			return runParentInit(projectName, projectID, keepWorkspaceName, force, importGit)
 		},
 	}
 
 	cmd.Flags().StringVar(&projectID, "project-id", "", "Use an existing project ID")
 	cmd.Flags().BoolVar(&keepWorkspaceName, "keep-name", false, "Keep current workspace folder name instead of renaming to main")
 	cmd.Flags().BoolVar(&force, "force", false, "Skip safety checks (use with caution)")
+	cmd.Flags().BoolVar(&importGit, "import-git", false, "Seed history from the git repository in this directory")
 
 	return cmd
 }
@@ -73,7 +85,7 @@ func newProjectCreateCmd() *cobra.Command {
 	return cmd
 }
 
-func runParentInit(projectName, projectID string, keepWorkspaceName bool, force bool) error {
+func runParentInit(projectName, projectID string, keepWorkspaceName bool, force bool, importGit bool) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
@@ -118,6 +130,15 @@ func runParentInit(projectName, projectID string, keepWorkspaceName bool, force
 		}
 	}
 
+	if importGit {
+		if workspaceCfg != nil {
+			return fmt.Errorf("--import-git cannot be used in an existing workspace")
+		}
+		if _, err := os.Stat(filepath.Join(workspaceRoot, ".git")); err != nil {
+			return fmt.Errorf("not a git repository: %s", workspaceRoot)
+		}
+	}
+
 	if !force {
 		homeDir, _ := os.UserHomeDir()
 		if samePath(workspaceRoot, homeDir) {
@@ -158,16 +179,31 @@ func runParentInit(projectName, projectID string, keepWorkspaceName bool, force
 
 	var workspaceID string
 	var baseSnapshotID string
+	var currentSnapshotID string
+	importedSnapshots := 0
 	if workspaceCfg == nil {
 		workspaceID = generateWorkspaceID()
 		if err := config.InitAt(workspaceRoot, projectID, workspaceID, workspaceName, ""); err != nil {
 			return fmt.Errorf("failed to initialize workspace: %w", err)
 		}
-		snapshotID, err := createInitialSnapshot(workspaceRoot, workspaceID, workspaceName, false)
-		if err != nil {
-			return err
+		if importGit {
+			importedSnapshots, err = importGitBranches(parentPath, projectID, workspaceRoot, workspaceID, workspaceName)
+			if err != nil {
+				return err
+			}
+			cfg, err := config.LoadAt(workspaceRoot)
+			if err != nil {
+				return err
+			}
+			baseSnapshotID = cfg.BaseSnapshotID
+			currentSnapshotID = cfg.CurrentSnapshotID
+		} else {
+			snapshotID, err := createInitialSnapshot(workspaceRoot, workspaceID, workspaceName, false)
+			if err != nil {
+				return err
+			}
+			baseSnapshotID = snapshotID
 		}
-		baseSnapshotID = snapshotID
 	} else {
 		workspaceCfg.WorkspaceName = workspaceName
 		if err := config.SaveAt(workspaceRoot, workspaceCfg); err != nil {
@@ -184,11 +220,12 @@ func runParentInit(projectName, projectID string, keepWorkspaceName bool, force
 	// Register workspace in project-level registry
 	projectStore := store.OpenAt(parentPath)
 	if err := projectStore.RegisterWorkspace(store.WorkspaceInfo{
-		WorkspaceID:   workspaceID,
-		WorkspaceName: workspaceName,
-		Path:          workspaceRoot,
-		BaseSnapshotID: baseSnapshotID,
-		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		WorkspaceID:       workspaceID,
+		WorkspaceName:     workspaceName,
+		Path:              workspaceRoot,
+		CurrentSnapshotID: currentSnapshotID,
+		BaseSnapshotID:    baseSnapshotID,
+		CreatedAt:         time.Now().UTC().Format(time.RFC3339),
 	}); err != nil {
 		fmt.Printf("Warning: Could not register workspace: %v\n", err)
 	}
@@ -205,6 +242,9 @@ func runParentInit(projectName, projectID string, keepWorkspaceName bool, force
 	fmt.Printf("  Project:   %s\n", projectName)
 	fmt.Printf("  ProjectID: %s\n", projectID)
 	fmt.Printf("  Directory: %s\n", parentPath)
+	if importGit {
+		fmt.Printf("  Imported:  %d snapshot(s) from git\n", importedSnapshots)
+	}
 
 	return nil
 }
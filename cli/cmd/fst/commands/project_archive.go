@@ -0,0 +1,495 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
+)
+
+// archiveIndexName is the archive entry holding the archiveIndex. It is
+// written last so the archive can be produced in a single pass.
+const archiveIndexName = "index.json"
+
+const archiveVersion = 1
+
+// archiveIndex is the table of contents of a project archive.
+type archiveIndex struct {
+	Version     int                `json:"version"`
+	ProjectID   string             `json:"project_id"`
+	ProjectName string             `json:"project_name"`
+	CreatedAt   string             `json:"created_at"`
+	Workspaces  []archiveWorkspace `json:"workspaces"`
+	// Files maps every other entry in the archive to the SHA-256 of its
+	// content, checked on restore.
+	Files map[string]string `json:"files"`
+}
+
+// archiveWorkspace records where a workspace lives relative to the project
+// root so restore can recreate the same layout.
+type archiveWorkspace struct {
+	WorkspaceID       string `json:"workspace_id"`
+	WorkspaceName     string `json:"workspace_name"`
+	Path              string `json:"path"` // slash-separated, relative to the project root
+	CurrentSnapshotID string `json:"current_snapshot_id,omitempty"`
+	BaseSnapshotID    string `json:"base_snapshot_id,omitempty"`
+	CreatedAt         string `json:"created_at,omitempty"`
+}
+
+func newProjectArchiveCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Bundle the whole project into a single archive file",
+		Long: `Bundle the project into one portable archive for backups: the project
+config, every workspace's config, all snapshots reachable from a workspace
+(with their manifests) and every blob they reference.
+
+The archive records the SHA-256 of each entry so 'fst project restore' can
+verify it. Uncommitted working-tree changes are not included; snapshot
+first if you want to keep them.
+
+Examples:
+  fst project archive                     # writes <project-name>.fstar
+  fst project archive -o backup.fstar`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProjectArchive(output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Archive file to write (default: <project-name>.fstar)")
+
+	return cmd
+}
+
+func newProjectRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <archive> <dir>",
+		Short: "Recreate a project from an archive",
+		Long: `Recreate a project written by 'fst project archive' in <dir>, which must
+not exist or be empty. Every workspace is recreated at the same place
+relative to the project root, registered in the project, and checked out
+at its latest snapshot.
+
+Every entry is verified against the archive's checksums; if anything is
+missing or corrupt, nothing is left behind in <dir>.
+
+Example:
+  fst project restore backup.fstar ~/restored`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProjectRestore(args[0], args[1])
+		},
+	}
+
+	return cmd
+}
+
+func runProjectArchive(output string) error {
+	projectRoot, projectCfg, err := findProjectRootAndConfig()
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = projectCfg.ProjectName + ".fstar"
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	index, err := writeProjectArchive(f, projectRoot, projectCfg)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write archive: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(output)
+		return err
+	}
+
+	var snapshots, blobs int
+	for name := range index.Files {
+		switch {
+		case strings.HasPrefix(name, "snapshots/"):
+			snapshots++
+		case strings.HasPrefix(name, "blobs/"):
+			blobs++
+		}
+	}
+	fmt.Printf("Archived project '%s' to %s\n", projectCfg.ProjectName, output)
+	fmt.Printf("  Workspaces: %d\n", len(index.Workspaces))
+	fmt.Printf("  Snapshots:  %d\n", snapshots)
+	fmt.Printf("  Blobs:      %d\n", blobs)
+	return nil
+}
+
+// writeProjectArchive writes the project at projectRoot to w as a gzipped
+// tar and returns the index stored in it.
+func writeProjectArchive(w io.Writer, projectRoot string, projectCfg *config.ProjectConfig) (*archiveIndex, error) {
+	s := store.OpenAt(projectRoot)
+	workspaces, err := s.ListWorkspaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	index := &archiveIndex{
+		Version:     archiveVersion,
+		ProjectID:   projectCfg.ProjectID,
+		ProjectName: projectCfg.ProjectName,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Files:       make(map[string]string),
+	}
+	add := func(name string, data []byte) error {
+		if err := writeArchiveEntry(tw, name, data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		sum := sha256.Sum256(data)
+		index.Files[name] = hex.EncodeToString(sum[:])
+		return nil
+	}
+
+	projectData, err := os.ReadFile(filepath.Join(projectRoot, config.ConfigDirName, config.ConfigFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project config: %w", err)
+	}
+	if err := add("project/config.json", projectData); err != nil {
+		return nil, err
+	}
+
+	var roots []string
+	for _, ws := range workspaces {
+		relPath := ws.WorkspaceName
+		if rel, err := filepath.Rel(projectRoot, ws.Path); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			relPath = filepath.ToSlash(rel)
+		}
+		index.Workspaces = append(index.Workspaces, archiveWorkspace{
+			WorkspaceID:       ws.WorkspaceID,
+			WorkspaceName:     ws.WorkspaceName,
+			Path:              relPath,
+			CurrentSnapshotID: ws.CurrentSnapshotID,
+			BaseSnapshotID:    ws.BaseSnapshotID,
+			CreatedAt:         ws.CreatedAt,
+		})
+
+		wsData, err := os.ReadFile(filepath.Join(ws.Path, config.ConfigDirName, config.ConfigFileName))
+		if err != nil {
+			// The workspace directory is gone; rebuild its config from the registry.
+			wsData, err = json.MarshalIndent(&config.WorkspaceConfig{
+				Type:              config.ConfigTypeWorkspace,
+				ProjectID:         projectCfg.ProjectID,
+				WorkspaceID:       ws.WorkspaceID,
+				WorkspaceName:     ws.WorkspaceName,
+				BaseSnapshotID:    ws.BaseSnapshotID,
+				CurrentSnapshotID: ws.CurrentSnapshotID,
+			}, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode config for workspace '%s': %w", ws.WorkspaceName, err)
+			}
+		}
+		if err := add("workspaces/"+ws.WorkspaceID+"/config.json", wsData); err != nil {
+			return nil, err
+		}
+		roots = append(roots, ws.CurrentSnapshotID, ws.BaseSnapshotID)
+	}
+
+	reachable := s.BuildReachableSet(roots)
+	snapshotIDs := make([]string, 0, len(reachable))
+	for id := range reachable {
+		snapshotIDs = append(snapshotIDs, id)
+	}
+	sort.Strings(snapshotIDs)
+
+	manifestsSeen := make(map[string]bool)
+	blobsSeen := make(map[string]bool)
+	for _, id := range snapshotIDs {
+		metaData, err := os.ReadFile(filepath.Join(s.SnapshotsDir(), id+".meta.json"))
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s is missing from the store: %w", id, err)
+		}
+		if err := add("snapshots/"+id+".meta.json", metaData); err != nil {
+			return nil, err
+		}
+
+		meta, err := s.LoadSnapshotMeta(id)
+		if err != nil {
+			return nil, err
+		}
+		if meta.ManifestHash == "" || manifestsSeen[meta.ManifestHash] {
+			continue
+		}
+		manifestsSeen[meta.ManifestHash] = true
+		manifestData, err := s.LoadManifestJSON(meta.ManifestHash)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", id, err)
+		}
+		if err := add("manifests/"+meta.ManifestHash+".json", manifestData); err != nil {
+			return nil, err
+		}
+		m, err := s.LoadManifest(meta.ManifestHash)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", id, err)
+		}
+		for _, entry := range m.FileEntries() {
+			if blobsSeen[entry.Hash] {
+				continue
+			}
+			blobsSeen[entry.Hash] = true
+			data, err := s.ReadBlob(entry.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("snapshot %s: %s: %w", id, entry.Path, err)
+			}
+			if err := add("blobs/"+entry.Hash, data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode archive index: %w", err)
+	}
+	if err := writeArchiveEntry(tw, archiveIndexName, indexData); err != nil {
+		return nil, fmt.Errorf("failed to write archive index: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return index, nil
+}
+
+func writeArchiveEntry(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+func runProjectRestore(archivePath, dir string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	if entries, err := os.ReadDir(absDir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("target directory %s is not empty", absDir)
+	} else if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", absDir, err)
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	if err := os.MkdirAll(absDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", absDir, err)
+	}
+	index, err := restoreProjectArchive(f, absDir)
+	if err != nil {
+		// absDir was empty before, so everything in it came from the archive.
+		if entries, readErr := os.ReadDir(absDir); readErr == nil {
+			for _, entry := range entries {
+				os.RemoveAll(filepath.Join(absDir, entry.Name()))
+			}
+		}
+		return err
+	}
+
+	fmt.Printf("Restored project '%s' to %s\n", index.ProjectName, absDir)
+	for _, ws := range index.Workspaces {
+		fmt.Printf("  %s -> %s\n", ws.WorkspaceName, filepath.Join(absDir, filepath.FromSlash(ws.Path)))
+	}
+	return nil
+}
+
+// restoreProjectArchive unpacks the archive read from r into dir, verifies
+// it against its index, and recreates every workspace.
+func restoreProjectArchive(r io.Reader, dir string) (*archiveIndex, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a project archive: %w", err)
+	}
+	defer gz.Close()
+
+	s := store.OpenAt(dir)
+	if err := s.EnsureDirs(); err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+
+	var index *archiveIndex
+	sums := make(map[string]string)
+	wsConfigs := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+
+		if hdr.Name == archiveIndexName {
+			index = &archiveIndex{}
+			if err := json.Unmarshal(data, index); err != nil {
+				return nil, fmt.Errorf("invalid archive index: %w", err)
+			}
+			continue
+		}
+		sum := sha256.Sum256(data)
+		sums[hdr.Name] = hex.EncodeToString(sum[:])
+
+		dirName, base := path.Split(hdr.Name)
+		if base == "" || base == "." || base == ".." {
+			return nil, fmt.Errorf("invalid archive entry %q", hdr.Name)
+		}
+		switch {
+		case hdr.Name == "project/config.json":
+			err = store.AtomicWriteFile(filepath.Join(dir, config.ConfigDirName, config.ConfigFileName), data, 0644)
+		case dirName == "snapshots/" && strings.HasSuffix(base, ".meta.json"):
+			err = store.AtomicWriteFile(filepath.Join(s.SnapshotsDir(), base), data, 0644)
+		case dirName == "manifests/" && strings.HasSuffix(base, ".json"):
+			err = store.AtomicWriteFile(filepath.Join(s.ManifestsDir(), base), data, 0644)
+		case dirName == "blobs/":
+			if base != sums[hdr.Name] {
+				return nil, fmt.Errorf("blob %s does not match its content", base)
+			}
+			err = s.WriteBlob(base, data)
+		case strings.HasPrefix(dirName, "workspaces/") && base == "config.json":
+			wsID := strings.TrimSuffix(strings.TrimPrefix(dirName, "workspaces/"), "/")
+			if wsID == "" || strings.Contains(wsID, "/") || wsID == ".." {
+				return nil, fmt.Errorf("invalid archive entry %q", hdr.Name)
+			}
+			wsConfigs[wsID] = data
+		default:
+			return nil, fmt.Errorf("unexpected archive entry %q", hdr.Name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", hdr.Name, err)
+		}
+	}
+
+	if index == nil {
+		return nil, fmt.Errorf("archive has no %s - not a project archive", archiveIndexName)
+	}
+	if index.Version != archiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d", index.Version)
+	}
+	if err := verifyArchiveSums(index.Files, sums); err != nil {
+		return nil, err
+	}
+
+	for _, aw := range index.Workspaces {
+		if err := restoreArchivedWorkspace(s, dir, aw, wsConfigs[aw.WorkspaceID]); err != nil {
+			return nil, fmt.Errorf("failed to restore workspace '%s': %w", aw.WorkspaceName, err)
+		}
+	}
+	return index, nil
+}
+
+// verifyArchiveSums checks the checksums of the unpacked entries against
+// the ones recorded in the index.
+func verifyArchiveSums(want, got map[string]string) error {
+	var problems []string
+	for name, sum := range want {
+		actual, ok := got[name]
+		switch {
+		case !ok:
+			problems = append(problems, name+" (missing)")
+		case actual != sum:
+			problems = append(problems, name+" (checksum mismatch)")
+		}
+	}
+	for name := range got {
+		if _, ok := want[name]; !ok {
+			problems = append(problems, name+" (not in index)")
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	if len(problems) > 5 {
+		problems = append(problems[:5], fmt.Sprintf("... and %d more", len(problems)-5))
+	}
+	return fmt.Errorf("archive failed verification: %s", strings.Join(problems, ", "))
+}
+
+// restoreArchivedWorkspace recreates one workspace under dir, registers it
+// and checks out its current snapshot.
+func restoreArchivedWorkspace(s *store.Store, dir string, aw archiveWorkspace, cfgData []byte) error {
+	if cfgData == nil {
+		return errors.New("workspace config missing from archive")
+	}
+	relPath := filepath.FromSlash(aw.Path)
+	if relPath == "" || filepath.IsAbs(relPath) || strings.HasPrefix(filepath.Clean(relPath), "..") {
+		return fmt.Errorf("invalid workspace path %q", aw.Path)
+	}
+	wsRoot := filepath.Join(dir, relPath)
+	if err := os.MkdirAll(filepath.Join(wsRoot, config.ConfigDirName), 0755); err != nil {
+		return err
+	}
+	if err := store.AtomicWriteFile(filepath.Join(wsRoot, config.ConfigDirName, config.ConfigFileName), cfgData, 0644); err != nil {
+		return err
+	}
+	if err := s.RegisterWorkspace(store.WorkspaceInfo{
+		WorkspaceID:       aw.WorkspaceID,
+		WorkspaceName:     aw.WorkspaceName,
+		Path:              wsRoot,
+		CurrentSnapshotID: aw.CurrentSnapshotID,
+		BaseSnapshotID:    aw.BaseSnapshotID,
+		CreatedAt:         aw.CreatedAt,
+	}); err != nil {
+		return err
+	}
+
+	ws, err := workspace.OpenAt(wsRoot)
+	if err != nil {
+		return err
+	}
+	defer ws.Close()
+	if ws.CurrentSnapshotID() == "" {
+		return nil
+	}
+	result, err := ws.Restore(workspace.RestoreOpts{SnapshotID: ws.CurrentSnapshotID()})
+	if err != nil {
+		return err
+	}
+	if len(result.MissingBlobs) > 0 {
+		return fmt.Errorf("%d blobs missing for snapshot %s", len(result.MissingBlobs), ws.CurrentSnapshotID())
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/drift"
+	"github.com/ankitiscracked/fastest/cli/internal/gitstore"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/ui"
+)
+
+func newProjectStatusCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the state of every workspace in the project",
+		Long: `Show a read-only overview of every workspace in the current project:
+its head snapshot, uncommitted changes, last activity, how far it is
+ahead of or behind the main workspace, and whether its head has been
+exported to the configured backend.
+
+Examples:
+  fst project status
+  fst project status --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProjectStatus(jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// projectWorkspaceStatus is one row of `fst project status`.
+type projectWorkspaceStatus struct {
+	Name         string
+	Path         string
+	IsMain       bool
+	IsCurrent    bool
+	Missing      bool // registered but the directory is gone
+	Head         string
+	HeadMessage  string
+	LastActivity time.Time
+	Drift        *drift.Report
+	Ahead        int
+	Behind       int
+	HasAhead     bool // ahead/behind were computed against main
+	Exported     bool
+}
+
+func runProjectStatus(jsonOutput bool) error {
+	projectRoot, parentCfg, err := findProjectRootAndConfig()
+	if err != nil {
+		return err
+	}
+
+	s := store.OpenAt(projectRoot)
+	wsList, err := s.ListWorkspaces()
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	sort.Slice(wsList, func(i, j int) bool {
+		return strings.ToLower(wsList[i].WorkspaceName) < strings.ToLower(wsList[j].WorkspaceName)
+	})
+
+	var mapping *gitstore.GitMapping
+	if parentCfg.Backend != nil {
+		mapping, _ = gitstore.LoadGitMapping(filepath.Join(projectRoot, ".fst"))
+	}
+
+	currentPath := ""
+	if root, findErr := config.FindWorkspaceRoot(); findErr == nil {
+		currentPath = root
+	}
+
+	statuses := make([]projectWorkspaceStatus, 0, len(wsList))
+	mainHead := ""
+	for _, ws := range wsList {
+		st := projectWorkspaceStatus{
+			Name:      ws.WorkspaceName,
+			Path:      ws.Path,
+			IsMain:    ws.WorkspaceID == parentCfg.MainWorkspaceID,
+			IsCurrent: ws.Path != "" && ws.Path == currentPath,
+			Head:      ws.CurrentSnapshotID,
+		}
+		if _, statErr := os.Stat(filepath.Join(ws.Path, ".fst")); ws.Path == "" || statErr != nil {
+			st.Missing = true
+		} else if cfg, cfgErr := config.LoadAt(ws.Path); cfgErr == nil && cfg.CurrentSnapshotID != "" {
+			st.Head = cfg.CurrentSnapshotID
+		}
+		if st.Head != "" {
+			if meta, metaErr := s.LoadSnapshotMeta(st.Head); metaErr == nil {
+				st.HeadMessage = meta.Message
+				if t, parseErr := time.Parse(time.RFC3339, meta.CreatedAt); parseErr == nil {
+					st.LastActivity = t
+				}
+			}
+			if !st.Missing {
+				st.Drift = workspaceDriftAt(s, ws.Path, st.Head)
+			}
+			if mapping != nil {
+				_, st.Exported = mapping.Snapshots[st.Head]
+			}
+		}
+		if st.IsMain {
+			mainHead = st.Head
+		}
+		statuses = append(statuses, st)
+	}
+
+	if mainHead != "" {
+		for i := range statuses {
+			st := &statuses[i]
+			if st.IsMain || st.Head == "" {
+				continue
+			}
+			if ahead, behind, abErr := s.AheadBehind(st.Head, mainHead); abErr == nil {
+				st.Ahead, st.Behind, st.HasAhead = ahead, behind, true
+			}
+		}
+	}
+
+	if jsonOutput {
+		return printProjectStatusJSON(parentCfg, statuses)
+	}
+	printProjectStatusHuman(parentCfg, statuses)
+	return nil
+}
+
+func printProjectStatusJSON(parentCfg *config.ProjectConfig, statuses []projectWorkspaceStatus) error {
+	workspaces := make([]map[string]any, 0, len(statuses))
+	for _, st := range statuses {
+		entry := map[string]any{
+			"name":       st.Name,
+			"path":       st.Path,
+			"is_main":    st.IsMain,
+			"is_current": st.IsCurrent,
+			"missing":    st.Missing,
+			"head":       st.Head,
+		}
+		if st.HeadMessage != "" {
+			entry["head_message"] = st.HeadMessage
+		}
+		if !st.LastActivity.IsZero() {
+			entry["last_activity"] = st.LastActivity.UTC().Format(time.RFC3339)
+		}
+		if st.Drift != nil {
+			entry["drift"] = map[string]any{
+				"added":    len(st.Drift.FilesAdded),
+				"modified": len(st.Drift.FilesModified),
+				"deleted":  len(st.Drift.FilesDeleted),
+			}
+		}
+		if st.HasAhead {
+			entry["ahead_of_main"] = st.Ahead
+			entry["behind_main"] = st.Behind
+		}
+		if parentCfg.Backend != nil {
+			entry["exported"] = st.Exported
+		}
+		workspaces = append(workspaces, entry)
+	}
+
+	payload := map[string]any{
+		"project_id":   parentCfg.ProjectID,
+		"project_name": parentCfg.ProjectName,
+		"workspaces":   workspaces,
+	}
+	if parentCfg.Backend != nil {
+		backendInfo := map[string]any{"type": parentCfg.Backend.Type}
+		if parentCfg.LastSync != nil {
+			backendInfo["last_sync"] = parentCfg.LastSync
+		}
+		payload["backend"] = backendInfo
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func printProjectStatusHuman(parentCfg *config.ProjectConfig, statuses []projectWorkspaceStatus) {
+	fmt.Printf("Project: %s (%s)\n", parentCfg.ProjectName, parentCfg.ProjectID)
+	if parentCfg.Backend != nil {
+		backendLine := parentCfg.Backend.Type
+		if parentCfg.LastSync != nil {
+			backendLine += ", last " + parentCfg.LastSync.Operation + ": " + formatSyncRecord(parentCfg.LastSync)
+		}
+		fmt.Printf("Backend: %s\n", backendLine)
+	} else {
+		fmt.Println("Backend: none")
+	}
+	fmt.Println()
+
+	if len(statuses) == 0 {
+		fmt.Println("No workspaces found.")
+		fmt.Println()
+		fmt.Println("Create one with: fst workspace create <name>")
+		return
+	}
+
+	for _, st := range statuses {
+		indicator := " "
+		if st.IsCurrent {
+			indicator = ui.Bold("*")
+		}
+		name := st.Name
+		if st.IsMain {
+			name += " (main)"
+		}
+		fmt.Printf("%s %s\n", indicator, ui.Bold(name))
+
+		if st.Missing {
+			fmt.Printf("    %s\n", ui.Red("directory missing: "+st.Path))
+			continue
+		}
+
+		if st.Head == "" {
+			fmt.Println("    Head:     (no snapshots)")
+		} else {
+			head := st.Head[:12]
+			if st.HeadMessage != "" {
+				head += " " + firstLine(st.HeadMessage)
+			}
+			fmt.Printf("    Head:     %s\n", head)
+		}
+		if !st.LastActivity.IsZero() {
+			fmt.Printf("    Activity: %s\n", formatTimeAgo(st.LastActivity))
+		}
+		fmt.Printf("    Changes:  %s\n", formatProjectDrift(st.Drift))
+		if st.HasAhead {
+			fmt.Printf("    Main:     %s\n", formatAheadBehind(st.Ahead, st.Behind))
+		}
+		if parentCfg.Backend != nil && st.Head != "" {
+			if st.Exported {
+				fmt.Printf("    Backend:  %s\n", ui.Green("exported"))
+			} else {
+				fmt.Printf("    Backend:  %s\n", ui.Yellow("not exported"))
+			}
+		}
+	}
+}
+
+// workspaceDriftAt compares root against the manifest of its head snapshot.
+// It returns nil when the drift cannot be computed.
+func workspaceDriftAt(s *store.Store, root, headID string) *drift.Report {
+	manifestHash, err := s.ManifestHashFromSnapshotID(headID)
+	if err != nil {
+		return nil
+	}
+	headManifest, err := s.LoadManifest(manifestHash)
+	if err != nil {
+		return nil
+	}
+	report, err := drift.Compute(root, headManifest)
+	if err != nil {
+		return nil
+	}
+	return report
+}
+
+func formatProjectDrift(report *drift.Report) string {
+	if report == nil {
+		return "unknown"
+	}
+	if !report.HasChanges() {
+		return ui.Green("clean")
+	}
+	return fmt.Sprintf("%s %s %s",
+		ui.Green(fmt.Sprintf("+%d", len(report.FilesAdded))),
+		ui.Yellow(fmt.Sprintf("~%d", len(report.FilesModified))),
+		ui.Red(fmt.Sprintf("-%d", len(report.FilesDeleted))))
+}
+
+func formatAheadBehind(ahead, behind int) string {
+	if ahead == 0 && behind == 0 {
+		return "up to date"
+	}
+	return fmt.Sprintf("%d ahead, %d behind", ahead, behind)
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
 )
 
 func TestProjectInitRejectsHome(t *testing.T) {
@@ -74,3 +78,276 @@ func TestProjectCreateRejectsHome(t *testing.T) {
 		t.Fatalf("expected home directory error, got: %v", err)
 	}
 }
+
+func TestProjectInitImportGit(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.name", "Test")
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	writeAndCommit := func(name, content, msg string) {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGit(t, repo, "add", name)
+		runGit(t, repo, "commit", "-m", msg)
+	}
+	writeAndCommit("a.txt", "one", "first")
+	writeAndCommit("a.txt", "two", "second")
+	runGit(t, repo, "checkout", "-b", "feature/x")
+	writeAndCommit("b.txt", "feature", "feature work")
+	runGit(t, repo, "checkout", "main")
+
+	restoreCwd := chdir(t, repo)
+	defer restoreCwd()
+
+	setenv(t, "XDG_CACHE_HOME", filepath.Join(root, "cache"))
+	setenv(t, "XDG_CONFIG_HOME", filepath.Join(root, "config"))
+
+	var output string
+	err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"project", "init", "demo", "--import-git"})
+		return cmd.Execute()
+	}, &output)
+	if err != nil {
+		t.Fatalf("project init --import-git failed: %v", err)
+	}
+	if !strings.Contains(output, "Imported:  3 snapshot(s) from git") {
+		t.Fatalf("expected 3 imported snapshots to be reported, got:\n%s", output)
+	}
+
+	projectPath := filepath.Join(root, "demo")
+	mainCfg, err := config.LoadAt(filepath.Join(projectPath, "main"))
+	if err != nil {
+		t.Fatalf("load main config: %v", err)
+	}
+	if mainCfg.CurrentSnapshotID == "" || mainCfg.CurrentSnapshotID == mainCfg.BaseSnapshotID {
+		t.Fatalf("expected main to be seeded with git history, got current=%q base=%q", mainCfg.CurrentSnapshotID, mainCfg.BaseSnapshotID)
+	}
+	s := store.OpenAt(projectPath)
+	meta, err := s.LoadSnapshotMeta(mainCfg.CurrentSnapshotID)
+	if err != nil {
+		t.Fatalf("load head snapshot: %v", err)
+	}
+	if meta.Message != "second" {
+		t.Fatalf("expected main head to be the tip of main, got %q", meta.Message)
+	}
+
+	featureCfg, err := config.LoadAt(filepath.Join(projectPath, "feature-x"))
+	if err != nil {
+		t.Fatalf("expected feature-x workspace: %v", err)
+	}
+	featureMeta, err := s.LoadSnapshotMeta(featureCfg.CurrentSnapshotID)
+	if err != nil {
+		t.Fatalf("load feature head: %v", err)
+	}
+	if len(featureMeta.ParentSnapshotIDs) != 1 || featureMeta.ParentSnapshotIDs[0] != mainCfg.CurrentSnapshotID {
+		t.Fatalf("expected feature to build on main's tip, got parents %v", featureMeta.ParentSnapshotIDs)
+	}
+
+	usage, err := s.DiskUsage()
+	if err != nil {
+		t.Fatalf("DiskUsage: %v", err)
+	}
+	if usage.Snapshots.Count != 3 {
+		t.Fatalf("expected 3 snapshots (shared history counted once), got %d", usage.Snapshots.Count)
+	}
+}
+
+func TestProjectInitImportGitRollsBackOnFailure(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.name", "Test")
+	runGit(t, repo, "config", "user.email", "test@test.com")
+	if err := os.WriteFile(filepath.Join(repo, "a.txt"), []byte("one"), 0644); err != nil {
+		t.Fatalf("write a.txt: %v", err)
+	}
+	runGit(t, repo, "add", "a.txt")
+	runGit(t, repo, "commit", "-m", "first")
+	// Both branches map to the workspace name "a-b", so the second import
+	// fails after the first has created its workspace.
+	runGit(t, repo, "branch", "a/b")
+	runGit(t, repo, "branch", "a-b")
+
+	restoreCwd := chdir(t, repo)
+	defer restoreCwd()
+
+	setenv(t, "XDG_CACHE_HOME", filepath.Join(root, "cache"))
+	setenv(t, "XDG_CONFIG_HOME", filepath.Join(root, "config"))
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"project", "init", "demo", "--import-git"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected project init --import-git to fail on the clashing branch, got %v", err)
+	}
+
+	projectPath := filepath.Join(root, "demo")
+	for _, path := range []string{
+		filepath.Join(projectPath, ".fst"),
+		filepath.Join(projectPath, "main", ".fst"),
+		filepath.Join(projectPath, "a-b"),
+	} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be rolled back, stat err: %v", path, err)
+		}
+	}
+}
+
+func TestProjectStatusJSON(t *testing.T) {
+	projectRoot, targetRoot, sourceRoot := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+
+	s := store.OpenAt(projectRoot)
+	for _, ws := range []struct{ root, id, name string }{
+		{targetRoot, "ws-target-id", "ws-target"},
+		{sourceRoot, "ws-source-id", "ws-source"},
+	} {
+		cfg, err := config.LoadAt(ws.root)
+		if err != nil {
+			t.Fatalf("LoadAt: %v", err)
+		}
+		if err := s.RegisterWorkspace(store.WorkspaceInfo{
+			WorkspaceID:       ws.id,
+			WorkspaceName:     ws.name,
+			Path:              ws.root,
+			CurrentSnapshotID: cfg.CurrentSnapshotID,
+		}); err != nil {
+			t.Fatalf("RegisterWorkspace: %v", err)
+		}
+	}
+	parentCfg, err := config.LoadProjectConfigAt(projectRoot)
+	if err != nil {
+		t.Fatalf("LoadProjectConfigAt: %v", err)
+	}
+	parentCfg.MainWorkspaceID = "ws-target-id"
+	if err := config.SaveProjectConfigAt(projectRoot, parentCfg); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(sourceRoot, "b.txt"), []byte("two, edited"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	restoreCwd := chdir(t, sourceRoot)
+	defer restoreCwd()
+
+	var output string
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"project", "status", "--json"})
+		return cmd.Execute()
+	}, &output)
+	if err != nil {
+		t.Fatalf("project status failed: %v", err)
+	}
+
+	var payload struct {
+		ProjectName string `json:"project_name"`
+		Workspaces  []struct {
+			Name      string `json:"name"`
+			IsMain    bool   `json:"is_main"`
+			IsCurrent bool   `json:"is_current"`
+			Head      string `json:"head"`
+			Drift     struct {
+				Modified int `json:"modified"`
+			} `json:"drift"`
+		} `json:"workspaces"`
+	}
+	if err := json.Unmarshal([]byte(output), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output)
+	}
+	if payload.ProjectName != "test-project" || len(payload.Workspaces) != 2 {
+		t.Fatalf("unexpected payload:\n%s", output)
+	}
+	source, target := payload.Workspaces[0], payload.Workspaces[1]
+	if source.Name != "ws-source" || target.Name != "ws-target" {
+		t.Fatalf("expected workspaces sorted by name, got:\n%s", output)
+	}
+	if !target.IsMain || source.IsMain {
+		t.Fatalf("expected ws-target to be main, got:\n%s", output)
+	}
+	if !source.IsCurrent || target.IsCurrent {
+		t.Fatalf("expected ws-source to be current, got:\n%s", output)
+	}
+	if source.Head == "" || target.Head == "" {
+		t.Fatalf("expected heads for both workspaces, got:\n%s", output)
+	}
+	if source.Drift.Modified != 1 || target.Drift.Modified != 0 {
+		t.Fatalf("expected one modified file in ws-source only, got:\n%s", output)
+	}
+}
+
+func TestProjectArchiveRestore(t *testing.T) {
+	projectRoot, _, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"src/b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, projectRoot)
+	defer restoreCwd()
+
+	archive := filepath.Join(t.TempDir(), "backup.fstar")
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"project", "archive", "-o", archive})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("project archive failed: %v", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "restored")
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"project", "restore", archive, dest})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("project restore failed: %v", err)
+	}
+
+	for path, want := range map[string]string{
+		"ws-target/a.txt":     "one",
+		"ws-source/src/b.txt": "two",
+	} {
+		data, err := os.ReadFile(filepath.Join(dest, path))
+		if err != nil {
+			t.Fatalf("expected %s to be restored: %v", path, err)
+		}
+		if string(data) != want {
+			t.Fatalf("%s = %q, want %q", path, data, want)
+		}
+	}
+
+	source, err := store.OpenAt(dest).FindWorkspaceByName("ws-source")
+	if err != nil {
+		t.Fatalf("FindWorkspaceByName: %v", err)
+	}
+	if source.Path != filepath.Join(dest, "ws-source") {
+		t.Fatalf("expected ws-source registered at its restored path, got %s", source.Path)
+	}
+	cfg, err := config.LoadAt(source.Path)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if cfg.CurrentSnapshotID != source.CurrentSnapshotID {
+		t.Fatalf("workspace config head %s does not match registry %s", cfg.CurrentSnapshotID, source.CurrentSnapshotID)
+	}
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"project", "restore", archive, dest})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Fatalf("expected restore into a non-empty directory to fail, got %v", err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 func newInitCmd() *cobra.Command {
 	var workspaceName string
 	var noSnapshot bool
+	var bare bool
 	var force bool
 
 	cmd := &cobra.Command{
@@ -33,15 +34,19 @@ This will:
 3. Set up the local .fst/ directory
 4. Create an initial snapshot of current files
 
+With --bare (or --no-snapshot) step 4 is skipped: the workspace starts with
+no snapshot and its first 'fst snapshot' becomes the root of its history.
+
 If no name is provided, the current directory name will be used.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runInit(args, workspaceName, noSnapshot, force)
+			return runInit(args, workspaceName, noSnapshot || bare, force)
 		},
 	}
 
 	cmd.Flags().StringVarP(&workspaceName, "workspace", "w", "", "Name for this workspace (must match directory name)")
 	cmd.Flags().BoolVar(&noSnapshot, "no-snapshot", false, "Don't create initial snapshot")
+	cmd.Flags().BoolVar(&bare, "bare", false, "Set up the workspace without an initial snapshot (same as --no-snapshot)")
 	cmd.Flags().BoolVar(&force, "force", false, "Skip safety checks (use with caution)")
 
 	return cmd
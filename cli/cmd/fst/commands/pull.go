@@ -14,21 +14,34 @@ func init() {
 }
 
 func newPullCmd() *cobra.Command {
+	var depth int
+
 	cmd := &cobra.Command{
 		Use:   "pull",
 		Short: "Pull latest changes from the backend",
 		Long: `Pull the latest changes from the configured backend.
 
-Requires a backend to be configured (see 'fst backend set').`,
+Requires a backend to be configured (see 'fst backend set').
+
+Use --depth N when onboarding onto a large existing repository: only the
+N most recent new commits of each branch are imported as snapshots, and
+older history is left unimported. On later pulls the skipped commits are
+bridged: the oldest imported snapshot is parented on the branch's previous
+tip, so local history stays connected.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runPull()
+			if depth < 0 {
+				return fmt.Errorf("--depth must not be negative")
+			}
+			return runPull(depth)
 		},
 	}
 
+	cmd.Flags().IntVar(&depth, "depth", 0, "Import only the N most recent new commits per branch (0 = all)")
+
 	return cmd
 }
 
-func runPull() error {
+func runPull(depth int) error {
 	projectRoot, parentCfg, err := findProjectRootAndConfig()
 	if err != nil {
 		return err
@@ -38,6 +51,9 @@ func runPull() error {
 	if b == nil {
 		return fmt.Errorf("no backend configured - run 'fst backend set' first")
 	}
+	if gh, ok := b.(*backend.GitHubBackend); ok {
+		gh.ImportDepth = depth
+	}
 
 	lock, err := workspace.AcquireBackendLock(projectRoot)
 	if err != nil {
@@ -1,7 +1,10 @@
 package commands
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -17,9 +20,11 @@ func newRestoreCmd() *cobra.Command {
 	var toSnapshot string
 	var toBase bool
 	var dryRun bool
+	var yes bool
+	var pruneEmptyDirs bool
 
 	cmd := &cobra.Command{
-		Use:   "restore [files...]",
+		Use:   "restore [<snapshot> --] [files...]",
 		Short: "Restore files from a snapshot",
 		Long: `Restore files from a previous snapshot.
 
@@ -27,29 +32,59 @@ By default, restores the entire workspace from the last snapshot (most recent sa
 Use --to to specify a different snapshot.
 Use --to-base to restore to the base/base point snapshot.
 
+"fst restore <snapshot> -- <path>..." restores just the given paths from a
+past snapshot and leaves everything else untouched. A path that doesn't
+exist in that snapshot can be deleted from the working tree after
+confirmation (or with --yes).
+
+A full restore deletes files that are not in the snapshot but keeps the
+directories that held them; use --prune-empty-dirs to remove directories
+left empty, deepest first. Directories recorded in the snapshot and ones
+still holding ignored files are kept.
+
 Examples:
   fst restore src/main.py           # Restore single file from last snapshot
   fst restore src/                  # Restore all files in directory
   fst restore                       # Restore entire workspace to last snapshot
   fst restore --to snap-abc         # Restore to specific snapshot
+  fst restore snap-abc -- a.go b/   # Restore two paths from snap-abc
   fst restore --to-base             # Restore to base point
-  fst restore --dry-run             # Show what would be restored`,
+  fst restore --dry-run             # Show what would be restored
+  fst restore --prune-empty-dirs    # Also remove emptied directories`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			files := args
+			if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+				if dash > 1 {
+					return fmt.Errorf("expected at most one snapshot before '--'")
+				}
+				if dash == 1 {
+					if toSnapshot != "" {
+						return fmt.Errorf("cannot use both a snapshot argument and --to")
+					}
+					toSnapshot = args[0]
+				}
+				files = args[dash:]
+				if len(files) == 0 {
+					return fmt.Errorf("no paths given after '--'")
+				}
+			}
 			if toSnapshot != "" && toBase {
 				return fmt.Errorf("cannot use both --to and --to-base")
 			}
-			return runRestore(args, toSnapshot, toBase, dryRun)
+			return runRestore(files, toSnapshot, toBase, dryRun, yes, pruneEmptyDirs)
 		},
 	}
 
 	cmd.Flags().StringVar(&toSnapshot, "to", "", "Target snapshot ID (default: last snapshot)")
 	cmd.Flags().BoolVar(&toBase, "to-base", false, "Restore to base/base point snapshot")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be restored without making changes")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete paths missing from the snapshot without asking")
+	cmd.Flags().BoolVar(&pruneEmptyDirs, "prune-empty-dirs", false, "Remove directories left empty by deleted files")
 
 	return cmd
 }
 
-func runRestore(files []string, toSnapshot string, toBase bool, dryRun bool) error {
+func runRestore(files []string, toSnapshot string, toBase bool, dryRun bool, yes bool, pruneEmptyDirs bool) error {
 	ws, err := workspace.Open()
 	if err != nil {
 		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
@@ -57,10 +92,11 @@ func runRestore(files []string, toSnapshot string, toBase bool, dryRun bool) err
 	defer ws.Close()
 
 	result, err := ws.Restore(workspace.RestoreOpts{
-		SnapshotID: toSnapshot,
-		ToBase:     toBase,
-		Files:      files,
-		DryRun:     dryRun,
+		SnapshotID:     toSnapshot,
+		ToBase:         toBase,
+		Files:          files,
+		DryRun:         dryRun,
+		PruneEmptyDirs: pruneEmptyDirs,
 	})
 
 	if result != nil && len(result.MissingBlobs) > 0 {
@@ -74,7 +110,7 @@ func runRestore(files []string, toSnapshot string, toBase bool, dryRun bool) err
 		return err
 	}
 
-	if result != nil && len(result.Actions) == 0 {
+	if result != nil && len(result.Actions) == 0 && len(result.NotInSnapshot) == 0 {
 		fmt.Println("Nothing to restore.")
 		return nil
 	}
@@ -85,6 +121,14 @@ func runRestore(files []string, toSnapshot string, toBase bool, dryRun bool) err
 		printRestoreActions(result)
 	}
 
+	if result != nil && len(result.NotInSnapshot) > 0 {
+		fmt.Printf("Not in snapshot %s (%d):\n", result.TargetSnapshotID, len(result.NotInSnapshot))
+		for _, p := range result.NotInSnapshot {
+			fmt.Printf("  %s\n", ui.Red("✗ "+p))
+		}
+		fmt.Println()
+	}
+
 	if dryRun {
 		fmt.Println("(dry run - no changes made)")
 		return nil
@@ -94,6 +138,18 @@ func runRestore(files []string, toSnapshot string, toBase bool, dryRun bool) err
 		return err
 	}
 
+	if len(result.NotInSnapshot) > 0 && (yes || confirmDeleteMissing()) {
+		deleted, err := ws.Restore(workspace.RestoreOpts{
+			SnapshotID:    result.TargetSnapshotID,
+			Files:         result.NotInSnapshot,
+			DeleteMissing: true,
+		})
+		if err != nil {
+			return err
+		}
+		result.Deleted += deleted.Deleted
+	}
+
 	fmt.Printf("✓ Restored %d files", result.Restored)
 	if result.Deleted > 0 {
 		fmt.Printf(", deleted %d files", result.Deleted)
@@ -103,6 +159,14 @@ func runRestore(files []string, toSnapshot string, toBase bool, dryRun bool) err
 	return nil
 }
 
+func confirmDeleteMissing() bool {
+	fmt.Print("Delete these from the working tree? [y/N] ")
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
 func printRestoreActions(result *workspace.RestoreResult) {
 	var restoreActions, deleteActions []workspace.RestoreAction
 	for _, a := range result.Actions {
@@ -263,6 +263,59 @@ func TestRebaseRejectsNonAncestor(t *testing.T) {
 	}
 }
 
+func TestSnapshotReparent(t *testing.T) {
+	root := setupWorkspace(t, "ws-reparent", map[string]string{
+		"file.txt": "v1",
+	})
+	setenv(t, "XDG_CACHE_HOME", filepath.Join(root, "cache"))
+	setenv(t, "XDG_CONFIG_HOME", filepath.Join(root, "config"))
+
+	baseID := createBaseSnapshot(t, root)
+	writeFile(t, filepath.Join(root, "file.txt"), "v2")
+	s1 := runSnapshotCmd(t, root, "s1")
+	writeFile(t, filepath.Join(root, "file.txt"), "v3")
+	s2 := runSnapshotCmd(t, root, "s2")
+
+	// Simulate a lost parent by deleting s1.
+	if err := os.Remove(snapshotMetaPath(root, s1)); err != nil {
+		t.Fatalf("remove s1: %v", err)
+	}
+
+	restoreCwd := chdir(t, root)
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "--reparent", "does-not-exist"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected reparent onto a missing snapshot to fail")
+	}
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "--reparent", s2})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected reparent onto the head itself to fail")
+	}
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "--reparent", baseID})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("reparent failed: %v", err)
+	}
+	restoreCwd()
+
+	cfg, err := config.LoadAt(root)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	newHead := cfg.CurrentSnapshotID
+	if newHead == s2 {
+		t.Fatalf("expected head to move to a new snapshot")
+	}
+	meta := readSnapshotMeta(t, root, newHead)
+	if len(meta.ParentSnapshotIDs) != 1 || meta.ParentSnapshotIDs[0] != baseID {
+		t.Fatalf("expected parent %s, got %v", baseID, meta.ParentSnapshotIDs)
+	}
+	if meta.Message != "s2" {
+		t.Fatalf("expected message to be preserved, got %q", meta.Message)
+	}
+}
+
 func createBaseSnapshot(t *testing.T, root string) string {
 	t.Helper()
 	if err := os.MkdirAll(filepath.Join(root, ".fst", "snapshots"), 0755); err != nil {
@@ -315,3 +368,19 @@ func writeFile(t *testing.T, path, content string) {
 		t.Fatalf("write file: %v", err)
 	}
 }
+
+func TestSnapshotReparentRefusesDescendantHeads(t *testing.T) {
+	_, targetRoot, sourceRoot := setupForkedWorkspaces(t, nil, nil)
+
+	writeFile(t, filepath.Join(sourceRoot, "base.txt"), "source")
+	sourceHead := runSnapshotCmd(t, sourceRoot, "source change")
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "--reparent", sourceHead})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "workspace ws-source has snapshots on top of") {
+		t.Fatalf("expected reparent to be refused, got %v", err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ankitiscracked/fastest/cli/internal/ui"
 )
 
 var (
@@ -29,7 +31,9 @@ func register(r registrar) {
 }
 
 func newRootCmd() *cobra.Command {
-	return &cobra.Command{
+	var color string
+
+	cmd := &cobra.Command{
 		Use:   "fst",
 		Short: "Fastest - parallel agent workflows from the ground up",
 		Long: `Fastest (fst) is infrastructure for parallel agent workflows, built from the
@@ -41,8 +45,19 @@ It provides:
   - Immutable snapshots of project state
   - Three-way merge with agent-assisted conflict resolution
   - Drift detection across workspaces
-  - CLI-first interface for agents and humans alike`,
+  - CLI-first interface for agents and humans alike
+
+Output is colored when stdout is a terminal and NO_COLOR is unset. Use
+--color=always to keep colors when piping (e.g. into less -R) or
+--color=never to turn them off.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return ui.SetColorMode(color)
+		},
 	}
+
+	cmd.PersistentFlags().StringVar(&color, "color", "auto", "When to color output: auto, always or never")
+
+	return cmd
 }
 
 func NewRootCmd() *cobra.Command {
@@ -3,6 +3,8 @@ package commands
 import (
 	"reflect"
 	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/ui"
 )
 
 func TestVersionCommandRuns(t *testing.T) {
@@ -14,6 +16,28 @@ func TestVersionCommandRuns(t *testing.T) {
 }
 
 
+func TestColorFlag(t *testing.T) {
+	t.Cleanup(func() { _ = ui.SetColorMode("auto") })
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"version", "--color", "sometimes"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an invalid --color value to be rejected")
+	}
+
+	if err := ui.SetColorMode("always"); err != nil {
+		t.Fatalf("SetColorMode: %v", err)
+	}
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"version", "--color=never"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("version --color=never: %v", err)
+	}
+	if got := ui.Green("ok"); got != "ok" {
+		t.Fatalf("expected plain output with --color=never, got %q", got)
+	}
+}
+
 func TestRewriteArgsAgentMessageAlias(t *testing.T) {
 	cases := []struct {
 		name string
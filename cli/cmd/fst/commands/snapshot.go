@@ -1,15 +1,22 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ankitiscracked/fastest/cli/internal/agent"
 	"github.com/ankitiscracked/fastest/cli/internal/config"
 	"github.com/ankitiscracked/fastest/cli/internal/drift"
+	"github.com/ankitiscracked/fastest/cli/internal/manifest"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/ui"
 	"github.com/ankitiscracked/fastest/cli/internal/workspace"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,6 +29,20 @@ func init() {
 func newSnapshotCmd() *cobra.Command {
 	var message string
 	var agentMessage bool
+	var yes bool
+	var timeArg string
+	var noVerify bool
+	var skipIfSame bool
+	var unchangedFrom string
+	var fixupOf string
+	var excludeUnchangedMode bool
+	var interactive bool
+	var splitByDir bool
+	var stdinFiles bool
+	var reparent string
+	var noDedupCheck bool
+	var keepMtime bool
+	var attach []string
 
 	cmd := &cobra.Command{
 		Use:     "snapshot",
@@ -35,34 +56,357 @@ This will:
 3. Optionally sync to cloud if authenticated
 4. Update the workspace head to point to this snapshot
 
-Use --agent-message to generate a description using your local coding agent.`,
+Use --agent-message to generate a description using your local coding agent.
+The agent is shown the changed files and their contents, and its message is
+offered for editing before the snapshot is created; add --yes to use it as
+is. If the agent is unavailable or fails, you are asked for a message
+instead.
+
+Use --time to backdate the snapshot when reconstructing history from an
+external source. The timestamp is part of the content-addressed snapshot ID,
+so the same files, parents, author and time always produce the same ID.
+
+Projects can set a message convention in .fst/config.json:
+
+  "snapshot": {
+    "message_template": "# Summary of changes: {changes}\n",
+    "message_pattern": "^(feat|fix|chore)(\\(.+\\))?: .+"
+  }
+
+The template pre-fills the message editor ("#" lines are dropped, and
+{changes} and {workspace} are expanded). Messages that don't match the
+pattern are rejected; use --no-verify to skip the check.
+
+Files larger than 100 MB are captured with a warning. Set
+"large_file_threshold" (bytes, -1 to disable) and "large_files": "refuse"
+in the same "snapshot" section to change the limit or refuse such
+snapshots.
+
+Permission changes are tracked by default: a file whose content is the same
+but whose mode changed (e.g. a tool flipped the executable bit) counts as
+modified. --exclude-unchanged-mode, or "ignore_mode_changes": true in the
+"snapshot" section, treats such files as unchanged and keeps their previous
+mode; the setting also applies to drift in 'fst status'.
+
+For periodic automation, --skip-if-same skips the snapshot when the working
+tree matches the latest snapshot, and --quiet-if-unchanged-from <snapshot>
+does the same against a given snapshot without printing anything. A skipped
+snapshot exits with code 2.
+
+Use --fixup <snapshot> to record a correction to an earlier snapshot of this
+workspace. The message defaults to "fixup! <original message>", and
+'fst git export --autosquash' folds the fixup into the original's commit.
+
+Use --interactive to pick which changed files go into the snapshot from a
+checklist. Unselected files keep their state from the latest snapshot and
+stay as uncommitted changes, so a noisy working tree can be split into
+several focused snapshots.
+
+Use --stdin-files to snapshot only the paths listed on stdin, one per line,
+e.g. the files a formatter or linter reports it fixed. Relative paths are
+taken from the current directory. Every path must be inside the workspace,
+not ignored, and changed (added, modified or deleted) since the latest
+snapshot; otherwise nothing is recorded. Other changes stay uncommitted, as
+with --interactive. --message is required since stdin is taken.
+
+Paths staged with 'fst add' limit the snapshot the same way: only the
+changes at or under them are recorded, and the staging area is cleared
+afterwards. --interactive and --split-by-dir then work on the staged
+changes only. --stdin-files ignores the staging area, and so does a
+snapshot that completes a pending merge, which records all changes.
+
+Use --split-by-dir to record the changes as one snapshot per top-level
+directory instead of a single snapshot, e.g. for a large drop spanning
+independent areas of a monorepo. Changes to files at the workspace root
+come first, then one snapshot per directory in alphabetical order, each
+parented on the previous one; every snapshot's message is the given
+message followed by the directory in brackets. It can be combined with
+--interactive to split only the selected files.
+
+Use --reparent <snapshot> to repair a broken parent chain, e.g. a head whose
+parent was lost to manual store surgery or a botched import. No files are
+captured: the current head is rewritten with <snapshot> as its only parent.
+Because snapshot IDs include their parents this produces a new ID, and every
+workspace head or base that pointed at the old snapshot is moved to it. The
+new parent must exist and must not be a descendant of the head, and no other
+workspace may have snapshots on top of the head. The rewrite holds the
+exclusive project lock, like 'fst gc'.
+
+Each snapshot reports how many blobs it newly wrote to the store and how
+many files reused a blob that was already there. --no-dedup-check skips the
+per-file existence check and writes every blob, replacing any copy already
+in the store, which is faster when the store is known not to have them yet
+(e.g. a first import).
+
+Restored files normally get a fresh modification time, so mtime-based build
+tools (make) rebuild everything after 'fst restore', 'fst undo' or a sync.
+--keep-mtime records each file's modification time in the snapshot, and
+restoring the snapshot sets it back. Because the times are part of the
+manifest, such a snapshot gets a different ID than the same files without
+them.
+
+Use --attach <file>=<path> to record a file from outside the workspace,
+such as a build log or generated report, at <path> in the snapshot without
+copying it into the working tree. It replaces any file at <path> and can be
+repeated. Restoring the snapshot writes the file to <path>; until then the
+working tree doesn't have it, so 'fst status' reports it as deleted.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSnapshot(message, agentMessage)
+			if reparent != "" {
+				return runSnapshotReparent(reparent)
+			}
+			createdAt, err := parseSnapshotTime(timeArg)
+			if err != nil {
+				return err
+			}
+			if skipIfSame || unchangedFrom != "" {
+				skipped, err := snapshotUnchanged(unchangedFrom, unchangedFrom != "", excludeUnchangedMode)
+				if err != nil {
+					return err
+				}
+				if skipped {
+					cmd.SilenceErrors = true
+					return SilentExit(exitSnapshotUnchanged)
+				}
+			}
+			return runSnapshot(snapshotOptions{
+				message:      message,
+				agentMessage: agentMessage,
+				yes:          yes,
+				createdAt:    createdAt,
+				noVerify:     noVerify,
+				fixupOf:      fixupOf,
+				ignoreMode:   excludeUnchangedMode,
+				interactive:  interactive,
+				stdinFiles:   stdinFiles,
+				splitByDir:   splitByDir,
+				noDedupCheck: noDedupCheck,
+				keepMtime:    keepMtime,
+				attach:       attach,
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&message, "message", "m", "", "Description for this snapshot")
 	cmd.Flags().BoolVar(&agentMessage, "agent-message", false, "Generate description using local coding agent")
+	cmd.Flags().BoolVar(&agentMessage, "message-from-diff", false, "Alias for --agent-message")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Use the --agent-message description without confirming it")
+	cmd.Flags().StringVar(&timeArg, "time", "", "Creation time for the snapshot (RFC3339, e.g. 2024-01-02T15:04:05Z)")
+	cmd.Flags().BoolVar(&noVerify, "no-verify", false, "Skip the project's snapshot message format check")
+	cmd.Flags().BoolVar(&skipIfSame, "skip-if-same", false, "Skip (exit 2) if nothing changed since the latest snapshot")
+	cmd.Flags().StringVar(&unchangedFrom, "quiet-if-unchanged-from", "", "Silently skip (exit 2) if nothing changed since this snapshot")
+	cmd.Flags().StringVar(&fixupOf, "fixup", "", "Mark this snapshot as a fixup of an earlier snapshot")
+	cmd.Flags().BoolVar(&excludeUnchangedMode, "exclude-unchanged-mode", false, "Ignore permission-only changes to files whose content is unchanged")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Choose which changed files to include")
+	cmd.Flags().BoolVar(&stdinFiles, "stdin-files", false, "Snapshot only the changed paths listed on stdin")
+	cmd.Flags().BoolVar(&splitByDir, "split-by-dir", false, "Create one chained snapshot per top-level directory")
+	cmd.Flags().BoolVar(&noDedupCheck, "no-dedup-check", false, "Write every blob without checking whether the store already has it")
+	cmd.Flags().BoolVar(&keepMtime, "keep-mtime", false, "Record file modification times so restores preserve them")
+	cmd.Flags().StringArrayVar(&attach, "attach", nil, "Record an outside file in the snapshot (<file>=<path>, repeatable)")
+	cmd.Flags().StringVar(&reparent, "reparent", "", "Rewrite the head snapshot with this snapshot as its parent")
 
 	return cmd
 }
 
-func runSnapshot(message string, agentMessage bool) error {
+// exitSnapshotUnchanged is the exit code of a snapshot skipped by
+// --skip-if-same or --quiet-if-unchanged-from.
+const exitSnapshotUnchanged = 2
+
+// snapshotUnchanged reports whether the working tree matches ref (the
+// latest snapshot when ref is empty), printing a note unless quiet.
+func snapshotUnchanged(ref string, quiet, ignoreMode bool) (bool, error) {
+	ws, err := workspace.Open()
+	if err != nil {
+		return false, fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+	defer ws.Close()
+
+	if ref == "" {
+		ref = ws.CurrentSnapshotID()
+		if ref == "" {
+			return false, nil
+		}
+	} else {
+		resolved, err := ws.Store().ResolveSnapshotID(ref)
+		if err != nil {
+			return false, err
+		}
+		ref = resolved
+	}
+
+	if !ignoreMode {
+		if _, parentCfg, findErr := config.FindProjectRootFrom(ws.Root()); findErr == nil {
+			ignoreMode = parentCfg.Snapshot.IgnoresModeChanges()
+		}
+	}
+
+	unchanged, err := ws.UnchangedFrom(ref, ignoreMode)
+	if err != nil {
+		return false, err
+	}
+	if unchanged && !quiet {
+		fmt.Printf("No changes since %s - snapshot skipped.\n", ref)
+	}
+	return unchanged, nil
+}
+
+// parseSnapshotTime parses a --time value. An empty value means "now" and
+// returns the zero time.
+func parseSnapshotTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --time %q (expected RFC3339, e.g. 2024-01-02T15:04:05Z)", value)
+	}
+	return t, nil
+}
+
+// snapshotOptions holds the flags of a single snapshot run.
+type snapshotOptions struct {
+	message      string
+	agentMessage bool
+	yes          bool // accept the agent's message without editing it
+	createdAt    time.Time
+	noVerify     bool
+	fixupOf      string   // snapshot ID or prefix
+	ignoreMode   bool     // ignore permission-only changes
+	interactive  bool     // pick the changed files to include
+	stdinFiles   bool     // read the changed files to include from stdin
+	splitByDir   bool     // one snapshot per top-level directory
+	noDedupCheck bool     // write blobs without checking the store first
+	keepMtime    bool     // record file modification times
+	attach       []string // <file>=<path> attachments from outside the workspace
+}
+
+func runSnapshot(opts snapshotOptions) error {
+	message, agentMessage, noVerify := opts.message, opts.agentMessage, opts.noVerify
+
 	ws, err := workspace.Open()
 	if err != nil {
 		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
 	}
 	defer ws.Close()
 
+	var fixupOf string
+	if opts.fixupOf != "" {
+		target, err := resolveFixupTarget(ws, opts.fixupOf)
+		if err != nil {
+			return err
+		}
+		fixupOf = target.ID
+		if message == "" && !agentMessage {
+			message = "fixup! " + target.Message
+		}
+	}
+
+	var snapshotCfg *config.SnapshotConfig
+	if _, parentCfg, findErr := config.FindProjectRootFrom(ws.Root()); findErr == nil {
+		snapshotCfg = parentCfg.Snapshot
+	}
+	largeFileThreshold, refuseLargeFiles, err := snapshotCfg.LargeFilePolicy()
+	if err != nil {
+		return err
+	}
+
 	if message != "" && agentMessage {
 		return fmt.Errorf("cannot use --message with --agent-message")
 	}
+	if opts.yes && !agentMessage {
+		return fmt.Errorf("--yes only applies to --agent-message")
+	}
+
+	if opts.splitByDir && fixupOf != "" {
+		return fmt.Errorf("cannot use --split-by-dir with --fixup")
+	}
+	if opts.splitByDir && len(opts.attach) > 0 {
+		return fmt.Errorf("cannot use --split-by-dir with --attach")
+	}
+	attachments, err := parseAttachments(opts.attach)
+	if err != nil {
+		return err
+	}
+
+	if opts.stdinFiles {
+		if opts.interactive {
+			return fmt.Errorf("cannot use --stdin-files with --interactive")
+		}
+		if message == "" {
+			return fmt.Errorf("--stdin-files requires --message")
+		}
+	}
+
+	// Paths staged with 'fst add' limit the snapshot unless --stdin-files
+	// names the paths explicitly. A snapshot that completes a merge must
+	// record the whole merge result, so staging is ignored then.
+	staged, err := config.ReadStagedPathsAt(ws.Root())
+	if err != nil {
+		return fmt.Errorf("failed to read staged paths: %w", err)
+	}
+	mergePending := false
+	if parents, err := config.ReadPendingMergeParentsAt(ws.Root()); err == nil && len(parents) > 0 {
+		mergePending = true
+	}
+	useStaged := len(staged) > 0 && !opts.stdinFiles && !mergePending
+	if len(staged) > 0 && mergePending {
+		fmt.Printf("%s a merge is pending; ignoring staged paths and recording all changes.\n", ui.Yellow("Warning:"))
+	}
+
+	var paths []string
+	if opts.interactive || opts.splitByDir || opts.stdinFiles || useStaged {
+		head := ws.CurrentSnapshotID()
+		var report *drift.Report
+		switch {
+		case head != "":
+			report = workspaceDriftAt(ws.Store(), ws.Root(), head)
+		case useStaged:
+			report, _ = drift.ComputeFromLatestSnapshot(ws.Root())
+		default:
+			return fmt.Errorf("--interactive, --stdin-files and --split-by-dir need an existing snapshot to select changes against")
+		}
+		if report == nil {
+			return fmt.Errorf("failed to compute changes since %s", head)
+		}
+		changes := snapshotChanges(report)
+		if useStaged {
+			changes = stagedChanges(changes, staged)
+		}
+		if opts.stdinFiles {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get current directory: %w", err)
+			}
+			paths, err = readSnapshotPaths(os.Stdin, ws.Root(), cwd)
+			if err != nil {
+				return err
+			}
+			if err := checkSnapshotPaths(ws.Root(), paths, changes); err != nil {
+				return err
+			}
+		} else if len(changes) == 0 {
+			if useStaged {
+				fmt.Println("No staged changes since the latest snapshot. Use 'fst reset' to snapshot all changes.")
+				return nil
+			}
+			fmt.Println("No changes since the latest snapshot.")
+			return nil
+		} else if opts.interactive {
+			paths, err = promptSnapshotPaths(changes)
+			if err != nil {
+				return err
+			}
+		} else {
+			for _, c := range changes {
+				paths = append(paths, c.Path)
+			}
+		}
+	}
 	if message == "" && !agentMessage {
-		entered, err := promptSnapshotMessage("")
+		message, err = promptManualSnapshotMessage(snapshotCfg, ws)
 		if err != nil {
 			return err
 		}
-		message = entered
 	}
 
 	// Resolve author identity (interactive — may prompt via TUI)
@@ -75,36 +419,79 @@ func runSnapshot(message string, agentMessage bool) error {
 
 	agentName := ""
 	if agentMessage {
-		preferredAgent, err := deps.AgentGetPreferred()
-		if err != nil {
-			return err
+		summary, name, err := agentSnapshotMessage(ws)
+		switch {
+		case err != nil:
+			// Never fall through to a message-less snapshot.
+			fmt.Printf("Warning: could not generate a message: %v\n", err)
+			message, err = promptManualSnapshotMessage(snapshotCfg, ws)
+			if err != nil {
+				return err
+			}
+			if message == "" {
+				return fmt.Errorf("a snapshot message is required")
+			}
+		case opts.yes:
+			message, agentName = summary, name
+			fmt.Printf("Message: %s\n", message)
+		default:
+			message, err = promptSnapshotMessage(summary)
+			if err != nil {
+				return err
+			}
+			agentName = name
 		}
-		fmt.Println("Generating message...")
-		summary, err := generateSnapshotSummary(ws.Root(), ws.Config(), preferredAgent, deps.AgentInvoke)
-		if err != nil {
-			return fmt.Errorf("failed to generate message: %w", err)
+	}
+
+	// Fixup messages are dropped when the fixup is folded, so the project's
+	// message format doesn't apply to them.
+	if !noVerify && fixupOf == "" {
+		if err := snapshotCfg.ValidateMessage(message); err != nil {
+			return err
 		}
-		message, err = promptSnapshotMessage(summary)
-		if err != nil {
+	}
+
+	snapOpts := workspace.SnapshotOpts{
+		Message:   message,
+		Agent:     agentName,
+		Author:    author,
+		CreatedAt: opts.createdAt,
+		FixupOf:   fixupOf,
+		Paths:     paths,
+
+		LargeFileThreshold: largeFileThreshold,
+		RefuseLargeFiles:   refuseLargeFiles,
+		IgnoreModeChanges:  opts.ignoreMode || snapshotCfg.IgnoresModeChanges(),
+		SkipDedupCheck:     opts.noDedupCheck,
+		KeepModTime:        opts.keepMtime,
+		Attachments:        attachments,
+	}
+	if opts.splitByDir {
+		if err := runSnapshotSplit(ws, snapOpts); err != nil {
 			return err
 		}
-		agentName = preferredAgent.Name
+		if useStaged || mergePending {
+			_ = config.ClearStagedPathsAt(ws.Root())
+		}
+		return nil
 	}
 
-	result, err := ws.Snapshot(workspace.SnapshotOpts{
-		Message: message,
-		Agent:   agentName,
-		Author:  author,
-	})
+	result, err := ws.Snapshot(snapOpts)
 	if err != nil {
-		return err
+		return largeFilesRefusal(err)
+	}
+	if useStaged || mergePending {
+		_ = config.ClearStagedPathsAt(ws.Root())
+	}
+	if len(result.LargeFiles) > 0 {
+		fmt.Printf("%s %d file(s) larger than %s were captured:\n", ui.Yellow("Warning:"), len(result.LargeFiles), formatBytesLong(largeFileThreshold))
+		printLargeFiles(result.LargeFiles)
+		fmt.Println("  Add them to .fstignore to keep them out of the blob store.")
 	}
 
 	// Output result
 	fmt.Printf("Found %d files (%s)\n", result.Files, formatBytesLong(result.Size))
-	if result.BlobsCached > 0 {
-		fmt.Printf("Cached %d new blobs.\n", result.BlobsCached)
-	}
+	fmt.Printf("%d new blobs, %d reused.\n", result.BlobsCached, result.BlobsReused)
 	fmt.Println()
 	fmt.Println("✓ Snapshot created!")
 	fmt.Println()
@@ -118,6 +505,9 @@ func runSnapshot(message string, agentMessage bool) error {
 	if message != "" {
 		fmt.Printf("  Message:  %s\n", message)
 	}
+	if fixupOf != "" {
+		fmt.Printf("  Fixup of: %s\n", fixupOf)
+	}
 	if ws.BaseSnapshotID() != "" {
 		fmt.Printf("  Base:     %s\n", ws.BaseSnapshotID())
 	}
@@ -133,6 +523,316 @@ func runSnapshot(message string, agentMessage bool) error {
 	return nil
 }
 
+// parseAttachments parses --attach values of the form <file>=<path>. The
+// file is resolved against the current directory.
+func parseAttachments(values []string) ([]workspace.Attachment, error) {
+	var attachments []workspace.Attachment
+	for _, v := range values {
+		file, p, ok := strings.Cut(v, "=")
+		if !ok || file == "" || p == "" {
+			return nil, fmt.Errorf("invalid --attach %q (expected <file>=<path>)", v)
+		}
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", file, err)
+		}
+		attachments = append(attachments, workspace.Attachment{Source: abs, Path: p})
+	}
+	return attachments, nil
+}
+
+// runSnapshotReparent rewrites the workspace head with parentArg as its only
+// parent and moves every reference to the old head over to the rewritten one.
+// It holds the exclusive project lock so no workspace can build on the old
+// head meanwhile.
+func runSnapshotReparent(parentArg string) error {
+	root, err := config.FindWorkspaceRoot()
+	if err != nil {
+		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+	s := store.OpenFromWorkspace(root)
+
+	lock, err := workspace.AcquireGCLock(s.Root())
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	cfg, err := config.LoadAt(root)
+	if err != nil {
+		return fmt.Errorf("failed to load workspace config: %w", err)
+	}
+	oldID := cfg.CurrentSnapshotID
+	if oldID == "" {
+		return fmt.Errorf("no snapshots yet - nothing to reparent")
+	}
+
+	newParent, err := s.ResolveSnapshotID(parentArg)
+	if err != nil {
+		return err
+	}
+	if err := checkNoDescendantHeads(s, cfg.WorkspaceID, oldID); err != nil {
+		return err
+	}
+	newID, err := s.ReparentSnapshot(oldID, newParent)
+	if err != nil {
+		return err
+	}
+
+	cfg.CurrentSnapshotID = newID
+	if cfg.BaseSnapshotID == oldID {
+		cfg.BaseSnapshotID = newID
+	}
+	if err := config.SaveAt(root, cfg); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	moved, err := repointSnapshotRefs(s, cfg.WorkspaceID, oldID, newID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Reparented %s onto %s\n", oldID, newParent)
+	fmt.Printf("  New snapshot: %s\n", newID)
+	if moved > 0 {
+		fmt.Printf("  Updated %d other workspace(s) that referenced the old snapshot\n", moved)
+	}
+	return nil
+}
+
+// checkNoDescendantHeads fails if the head of a workspace other than selfID
+// descends from id: rewriting id would leave that workspace on the old
+// history.
+func checkNoDescendantHeads(s *store.Store, selfID, id string) error {
+	infos, err := s.ListWorkspaces()
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for _, info := range infos {
+		if info.WorkspaceID == selfID || info.CurrentSnapshotID == id {
+			continue
+		}
+		if s.IsAncestorOf(id, info.CurrentSnapshotID) {
+			return fmt.Errorf("workspace %s has snapshots on top of %s; reparenting would leave them on the old history", info.WorkspaceName, id)
+		}
+	}
+	return nil
+}
+
+// repointSnapshotRefs replaces oldID with newID in the registry entries of
+// all workspaces and in the configs of workspaces other than selfID. It
+// returns the number of other workspaces that were updated.
+func repointSnapshotRefs(s *store.Store, selfID, oldID, newID string) (int, error) {
+	infos, err := s.ListWorkspaces()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	moved := 0
+	for _, info := range infos {
+		if info.CurrentSnapshotID != oldID && info.BaseSnapshotID != oldID {
+			continue
+		}
+		if info.CurrentSnapshotID == oldID {
+			info.CurrentSnapshotID = newID
+		}
+		if info.BaseSnapshotID == oldID {
+			info.BaseSnapshotID = newID
+		}
+		if err := s.RegisterWorkspace(info); err != nil {
+			return moved, fmt.Errorf("failed to update workspace %s: %w", info.WorkspaceName, err)
+		}
+		if info.WorkspaceID == selfID {
+			continue
+		}
+		if wsCfg, err := config.LoadAt(info.Path); err == nil {
+			if wsCfg.CurrentSnapshotID == oldID {
+				wsCfg.CurrentSnapshotID = newID
+			}
+			if wsCfg.BaseSnapshotID == oldID {
+				wsCfg.BaseSnapshotID = newID
+			}
+			if err := config.SaveAt(info.Path, wsCfg); err != nil {
+				return moved, fmt.Errorf("failed to update workspace %s: %w", info.WorkspaceName, err)
+			}
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// runSnapshotSplit creates one snapshot per top-level directory group of
+// base.Paths, each parented on the previous one.
+func runSnapshotSplit(ws *workspace.Workspace, base workspace.SnapshotOpts) error {
+	groups := groupPathsByTopDir(base.Paths)
+	fmt.Printf("Splitting into %d snapshots:\n", len(groups))
+	fmt.Println()
+
+	for _, g := range groups {
+		opts := base
+		opts.Paths = g.paths
+		label := g.dir + "/"
+		if g.dir == "" {
+			label = "(root)"
+		}
+		opts.Message = strings.TrimSpace(fmt.Sprintf("%s [%s]", base.Message, label))
+
+		result, err := ws.Snapshot(opts)
+		if err != nil {
+			return largeFilesRefusal(err)
+		}
+		fmt.Printf("  %s  %-20s %d changed\n", result.SnapshotID[:12], label, len(g.paths))
+	}
+
+	fmt.Println()
+	fmt.Println("✓ Snapshots created!")
+
+	if projectRoot, parentCfg, findErr := config.FindProjectRootFrom(ws.Root()); findErr == nil {
+		if parentCfg.Backend != nil {
+			backendAutoExport(projectRoot)
+		}
+	}
+	return nil
+}
+
+// snapshotPathGroup is the changed paths under one top-level directory; dir
+// is empty for files at the workspace root.
+type snapshotPathGroup struct {
+	dir   string
+	paths []string
+}
+
+// groupPathsByTopDir groups paths by their top-level directory, root files
+// first and directories in alphabetical order. A top-level path that names a
+// group (the directory entry itself) belongs to that group.
+func groupPathsByTopDir(paths []string) []snapshotPathGroup {
+	byDir := make(map[string][]string)
+	for _, p := range paths {
+		if dir, _, ok := strings.Cut(p, "/"); ok {
+			byDir[dir] = append(byDir[dir], p)
+		}
+	}
+	for _, p := range paths {
+		if strings.Contains(p, "/") {
+			continue
+		}
+		if _, ok := byDir[p]; ok {
+			byDir[p] = append(byDir[p], p)
+		} else {
+			byDir[""] = append(byDir[""], p)
+		}
+	}
+
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	groups := make([]snapshotPathGroup, 0, len(dirs))
+	for _, dir := range dirs {
+		groups = append(groups, snapshotPathGroup{dir: dir, paths: byDir[dir]})
+	}
+	return groups
+}
+
+// largeFilesRefusal turns a *workspace.LargeFilesError into the snapshot's
+// user-facing refusal, listing the offending files. Other errors are
+// returned unchanged.
+func largeFilesRefusal(err error) error {
+	var largeErr *workspace.LargeFilesError
+	if errors.As(err, &largeErr) {
+		printLargeFiles(largeErr.Files)
+		return fmt.Errorf("snapshot refused: %d file(s) larger than %s - add them to .fstignore or raise snapshot.large_file_threshold",
+			len(largeErr.Files), formatBytesLong(largeErr.Threshold))
+	}
+	return err
+}
+
+func printLargeFiles(files []manifest.FileEntry) {
+	for _, f := range files {
+		fmt.Printf("  %s (%s)\n", f.Path, formatBytesLong(f.Size))
+	}
+}
+
+// resolveFixupTarget resolves ref to a snapshot in the workspace's history,
+// the only snapshots a fixup can amend.
+func resolveFixupTarget(ws *workspace.Workspace, ref string) (*store.SnapshotMeta, error) {
+	targetID, err := ws.Store().ResolveSnapshotID(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --fixup: %w", err)
+	}
+	head := ws.CurrentSnapshotID()
+	if head == "" || !ws.Store().IsAncestorOf(targetID, head) {
+		return nil, fmt.Errorf("cannot fixup %s: not in this workspace's history", targetID[:12])
+	}
+	target, err := ws.Store().LoadSnapshotMeta(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot %s: %w", targetID[:12], err)
+	}
+	return target, nil
+}
+
+// expandMessageTemplate fills the {changes} and {workspace} placeholders of a
+// project message template.
+func expandMessageTemplate(template string, ws *workspace.Workspace) string {
+	changes := "unknown"
+	if report, err := drift.ComputeFromLatestSnapshot(ws.Root()); err == nil {
+		changes = report.FormatSummary()
+	}
+	return strings.NewReplacer(
+		"{changes}", changes,
+		"{workspace}", ws.WorkspaceName(),
+	).Replace(template)
+}
+
+// stripMessageComments drops "#" comment lines and surrounding blank lines
+// from an edited template message.
+func stripMessageComments(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// agentSnapshotMessage asks the preferred agent to describe the workspace's
+// changes and returns its message and name.
+func agentSnapshotMessage(ws *workspace.Workspace) (string, string, error) {
+	preferredAgent, err := deps.AgentGetPreferred()
+	if err != nil {
+		return "", "", err
+	}
+	fmt.Println("Generating message...")
+	summary, err := generateSnapshotSummary(ws.Root(), ws.Config(), preferredAgent, deps.AgentInvoke)
+	if err != nil {
+		return "", "", err
+	}
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", "", fmt.Errorf("%s returned an empty message", preferredAgent.Name)
+	}
+	return summary, preferredAgent.Name, nil
+}
+
+// promptManualSnapshotMessage asks for a snapshot message, starting from
+// the project's message template if it has one.
+func promptManualSnapshotMessage(snapshotCfg *config.SnapshotConfig, ws *workspace.Workspace) (string, error) {
+	template := ""
+	if snapshotCfg != nil && snapshotCfg.MessageTemplate != "" {
+		template = expandMessageTemplate(snapshotCfg.MessageTemplate, ws)
+	}
+	entered, err := promptSnapshotMessage(template)
+	if err != nil {
+		return "", err
+	}
+	if template != "" {
+		entered = stripMessageComments(entered)
+	}
+	return entered, nil
+}
+
 func promptSnapshotMessage(summary string) (string, error) {
 	m := newSnapshotMessageModel(summary)
 	p := tea.NewProgram(m)
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
 )
 
 // fullSnapshotMeta is the full metadata structure including author fields.
@@ -257,3 +259,368 @@ func searchStr(s, sub string) bool {
 	}
 	return false
 }
+
+func TestSnapshotTimeFlagSetsCreatedAt(t *testing.T) {
+	root := setupWorkspace(t, "ws-time", map[string]string{
+		"file.txt": "hello",
+	})
+	setenv(t, "XDG_CACHE_HOME", filepath.Join(root, "cache"))
+	setenv(t, "XDG_CONFIG_HOME", filepath.Join(root, "config"))
+
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "backdated", "--time", "2020-01-02T03:04:05+01:00"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("snapshot --time failed: %v", err)
+	}
+
+	cfg, err := config.LoadAt(root)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	meta := readFullSnapshotMeta(t, root, cfg.CurrentSnapshotID)
+	if meta.CreatedAt != "2020-01-02T02:04:05Z" {
+		t.Fatalf("expected backdated created_at, got %s", meta.CreatedAt)
+	}
+	expected := config.ComputeSnapshotID(meta.ManifestHash, meta.ParentSnapshotIDs, meta.AuthorName, meta.AuthorEmail, meta.CreatedAt)
+	if meta.ID != expected {
+		t.Fatalf("snapshot ID %s doesn't match computed ID %s", meta.ID, expected)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "bad", "--time", "yesterday"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected malformed --time to be rejected")
+	}
+}
+
+func TestSnapshotMessagePatternIsEnforced(t *testing.T) {
+	projectRoot, targetRoot, _ := setupProjectWithWorkspaces(t, nil, nil)
+	projectCfg := `{"type":"project","project_id":"proj-test","project_name":"test-project",` +
+		`"snapshot":{"message_pattern":"^(feat|fix): .+"}}`
+	if err := os.WriteFile(filepath.Join(projectRoot, ".fst", "config.json"), []byte(projectCfg), 0644); err != nil {
+		t.Fatalf("write config.json: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(targetRoot, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatalf("write new.txt: %v", err)
+	}
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "added a file"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected non-conforming message to be rejected")
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "feat: add a file"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected conforming message to pass: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(targetRoot, "other.txt"), []byte("other"), 0644); err != nil {
+		t.Fatalf("write other.txt: %v", err)
+	}
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "wip", "--no-verify"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected --no-verify to bypass the check: %v", err)
+	}
+}
+
+func TestSnapshotLargeFiles(t *testing.T) {
+	projectRoot, targetRoot, _ := setupProjectWithWorkspaces(t, nil, nil)
+	writeProjectCfg := func(policy string) {
+		t.Helper()
+		projectCfg := `{"type":"project","project_id":"proj-test","project_name":"test-project",` +
+			`"snapshot":{"large_file_threshold":16,"large_files":"` + policy + `"}}`
+		if err := os.WriteFile(filepath.Join(projectRoot, ".fst", "config.json"), []byte(projectCfg), 0644); err != nil {
+			t.Fatalf("write config.json: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(targetRoot, "big.bin"), []byte(strings.Repeat("x", 64)), 0644); err != nil {
+		t.Fatalf("write big.bin: %v", err)
+	}
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	before, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+
+	writeProjectCfg("refuse")
+	var output string
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"snapshot", "-m", "add big file"})
+		return cmd.Execute()
+	}, &output)
+	if err == nil || !strings.Contains(err.Error(), "snapshot refused") {
+		t.Fatalf("expected snapshot to be refused, got %v", err)
+	}
+	if !strings.Contains(output, "big.bin") {
+		t.Fatalf("expected refused file to be listed, got:\n%s", output)
+	}
+	after, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if after.CurrentSnapshotID != before.CurrentSnapshotID {
+		t.Fatalf("expected no snapshot to be recorded")
+	}
+
+	writeProjectCfg("warn")
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"snapshot", "-m", "add big file"})
+		return cmd.Execute()
+	}, &output)
+	if err != nil {
+		t.Fatalf("expected snapshot with warning to succeed: %v", err)
+	}
+	if !strings.Contains(output, "Warning:") || !strings.Contains(output, "big.bin") {
+		t.Fatalf("expected large-file warning, got:\n%s", output)
+	}
+}
+
+func TestSnapshotExcludeUnchangedMode(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t, nil, nil)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	if err := os.Chmod(filepath.Join(targetRoot, "base.txt"), 0755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "--skip-if-same", "-m", "mode only"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected a mode change to count by default, got %v", err)
+	}
+	before, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+
+	if err := os.Chmod(filepath.Join(targetRoot, "base.txt"), 0644); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "--skip-if-same", "--exclude-unchanged-mode", "-m", "mode only"})
+	err = cmd.Execute()
+	if ExitCode(err) != exitSnapshotUnchanged {
+		t.Fatalf("expected mode-only change to be skipped, got %v", err)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "--exclude-unchanged-mode", "-m", "forced"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	after, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	s := store.OpenFromWorkspace(targetRoot)
+	beforeHash, _ := s.ManifestHashFromSnapshotID(before.CurrentSnapshotID)
+	afterHash, _ := s.ManifestHashFromSnapshotID(after.CurrentSnapshotID)
+	if beforeHash == "" || beforeHash != afterHash {
+		t.Fatalf("expected the mode-only change not to be recorded (%s vs %s)", beforeHash, afterHash)
+	}
+}
+
+func TestSnapshotSplitByDir(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t, nil, nil)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	before, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	for path, content := range map[string]string{
+		"base.txt":         "root change",
+		"web/index.html":   "<html>",
+		"api/server.go":    "package api",
+		"api/v1/routes.go": "package v1",
+	} {
+		full := filepath.Join(targetRoot, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"snapshot", "--split-by-dir", "-m", "drop"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("snapshot --split-by-dir: %v", err)
+	}
+
+	after, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	s := store.OpenFromWorkspace(targetRoot)
+	var messages []string
+	for id := after.CurrentSnapshotID; id != before.CurrentSnapshotID; {
+		meta, err := s.LoadSnapshotMeta(id)
+		if err != nil {
+			t.Fatalf("LoadSnapshotMeta: %v", err)
+		}
+		messages = append([]string{meta.Message}, messages...)
+		if len(meta.ParentSnapshotIDs) != 1 {
+			t.Fatalf("expected a linear chain, got parents %v", meta.ParentSnapshotIDs)
+		}
+		id = meta.ParentSnapshotIDs[0]
+	}
+	want := "drop [(root)],drop [api/],drop [web/]"
+	if strings.Join(messages, ",") != want {
+		t.Fatalf("unexpected chain %q, want %q", strings.Join(messages, ","), want)
+	}
+
+	unchanged, err := snapshotUnchanged("", true, false)
+	if err != nil {
+		t.Fatalf("snapshotUnchanged: %v", err)
+	}
+	if !unchanged {
+		t.Fatal("expected every change to be captured by the chain")
+	}
+}
+
+func TestSnapshotStdinFiles(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t, nil, nil)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	for path, content := range map[string]string{
+		"fixed.txt": "fixed by a tool",
+		"other.txt": "unrelated edit",
+	} {
+		if err := os.WriteFile(filepath.Join(targetRoot, path), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	runWithStdin := func(input string) error {
+		stdinPath := filepath.Join(t.TempDir(), "stdin")
+		if err := os.WriteFile(stdinPath, []byte(input), 0644); err != nil {
+			t.Fatalf("write stdin: %v", err)
+		}
+		f, err := os.Open(stdinPath)
+		if err != nil {
+			t.Fatalf("open stdin: %v", err)
+		}
+		defer f.Close()
+		oldStdin := os.Stdin
+		os.Stdin = f
+		defer func() { os.Stdin = oldStdin }()
+
+		return captureStdout(func() error {
+			cmd := NewRootCmd()
+			cmd.SetArgs([]string{"snapshot", "--stdin-files", "-m", "tool fixes"})
+			return cmd.Execute()
+		}, new(string))
+	}
+
+	err := runWithStdin("fixed.txt\nbase.txt\nmissing.txt\n")
+	if err == nil || !strings.Contains(err.Error(), "base.txt (unchanged)") || !strings.Contains(err.Error(), "missing.txt (does not exist)") {
+		t.Fatalf("expected unchanged and missing paths to be rejected, got %v", err)
+	}
+	if err := runWithStdin("../outside.txt\n"); err == nil || !strings.Contains(err.Error(), "outside the workspace") {
+		t.Fatalf("expected outside path to be rejected, got %v", err)
+	}
+
+	if err := runWithStdin("\n" + filepath.Join(targetRoot, "fixed.txt") + "\n"); err != nil {
+		t.Fatalf("snapshot --stdin-files: %v", err)
+	}
+	cfg, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	report := workspaceDriftAt(store.OpenFromWorkspace(targetRoot), targetRoot, cfg.CurrentSnapshotID)
+	if report == nil {
+		t.Fatal("failed to compute drift")
+	}
+	if len(report.FilesAdded) != 1 || report.FilesAdded[0] != "other.txt" {
+		t.Fatalf("expected only other.txt left uncommitted, got %+v", report)
+	}
+}
+
+func TestStripMessageComments(t *testing.T) {
+	got := stripMessageComments("# Changes: +1 ~0 -0\n\nfeat: add flag\n  # trailing hint\nbody line\n")
+	if got != "feat: add flag\nbody line" {
+		t.Fatalf("unexpected stripped message %q", got)
+	}
+}
+
+func TestSnapshotSkipIfSame(t *testing.T) {
+	root := setupWorkspace(t, "ws-skip", map[string]string{
+		"file.txt": "hello",
+	})
+	setenv(t, "XDG_CACHE_HOME", filepath.Join(root, "cache"))
+	setenv(t, "XDG_CONFIG_HOME", filepath.Join(root, "config"))
+
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "first"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+	cfg, err := config.LoadAt(root)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	first := cfg.CurrentSnapshotID
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "again", "--skip-if-same"})
+	if err := cmd.Execute(); ExitCode(err) != exitSnapshotUnchanged {
+		t.Fatalf("expected exit code %d for unchanged tree, got %v", exitSnapshotUnchanged, err)
+	}
+
+	var out string
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"snapshot", "-m", "again", "--quiet-if-unchanged-from", first})
+		return cmd.Execute()
+	}, &out)
+	if ExitCode(err) != exitSnapshotUnchanged {
+		t.Fatalf("expected exit code %d, got %v", exitSnapshotUnchanged, err)
+	}
+	if out != "" {
+		t.Fatalf("expected no output in quiet mode, got %q", out)
+	}
+
+	cfg, err = config.LoadAt(root)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if cfg.CurrentSnapshotID != first {
+		t.Fatalf("expected no new snapshot, head moved to %s", cfg.CurrentSnapshotID)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "file.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "changed", "--skip-if-same"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected snapshot of changed tree to succeed: %v", err)
+	}
+}
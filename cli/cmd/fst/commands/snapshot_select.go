@@ -0,0 +1,245 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ankitiscracked/fastest/cli/internal/drift"
+	"github.com/ankitiscracked/fastest/cli/internal/ignore"
+	"github.com/ankitiscracked/fastest/cli/internal/ui"
+)
+
+// snapshotChange is a changed path offered by `fst snapshot --interactive`.
+type snapshotChange struct {
+	Path   string
+	Status string // "added", "modified" or "deleted"
+}
+
+// snapshotChanges lists the paths of a drift report, sorted by path.
+func snapshotChanges(report *drift.Report) []snapshotChange {
+	var changes []snapshotChange
+	for _, p := range report.FilesAdded {
+		changes = append(changes, snapshotChange{Path: p, Status: "added"})
+	}
+	for _, p := range report.FilesModified {
+		changes = append(changes, snapshotChange{Path: p, Status: "modified"})
+	}
+	for _, p := range report.FilesDeleted {
+		changes = append(changes, snapshotChange{Path: p, Status: "deleted"})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// promptSnapshotPaths shows the changed paths in a checklist and returns
+// the ones the user selected. All paths start selected.
+func promptSnapshotPaths(changes []snapshotChange) ([]string, error) {
+	p := tea.NewProgram(newSnapshotSelectModel(changes))
+	final, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+	model, ok := final.(snapshotSelectModel)
+	if !ok {
+		return nil, fmt.Errorf("failed to read file selection")
+	}
+	if model.err != nil {
+		return nil, model.err
+	}
+	return model.selectedPaths(), nil
+}
+
+type snapshotSelectModel struct {
+	changes  []snapshotChange
+	selected []bool
+	cursor   int
+	err      error
+	done     bool
+}
+
+func newSnapshotSelectModel(changes []snapshotChange) snapshotSelectModel {
+	selected := make([]bool, len(changes))
+	for i := range selected {
+		selected[i] = true
+	}
+	return snapshotSelectModel{changes: changes, selected: selected}
+}
+
+func (m snapshotSelectModel) selectedPaths() []string {
+	paths := []string{}
+	for i, c := range m.changes {
+		if m.selected[i] {
+			paths = append(paths, c.Path)
+		}
+	}
+	return paths
+}
+
+func (m snapshotSelectModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m snapshotSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch key.String() {
+	case "ctrl+c", "esc", "q":
+		m.err = fmt.Errorf("snapshot cancelled")
+		m.done = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.changes)-1 {
+			m.cursor++
+		}
+	case " ", "x":
+		m.selected[m.cursor] = !m.selected[m.cursor]
+	case "a":
+		all := true
+		for _, s := range m.selected {
+			all = all && s
+		}
+		for i := range m.selected {
+			m.selected[i] = !all
+		}
+	case "enter":
+		if len(m.selectedPaths()) == 0 {
+			m.err = fmt.Errorf("no files selected")
+		}
+		m.done = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m snapshotSelectModel) View() string {
+	if m.done {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Select files to include in the snapshot:\n\n")
+	for i, c := range m.changes {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		check := "[ ]"
+		if m.selected[i] {
+			check = "[x]"
+		}
+		status := c.Status
+		switch c.Status {
+		case "added":
+			status = ui.Green(status)
+		case "deleted":
+			status = ui.Red(status)
+		default:
+			status = ui.Yellow(status)
+		}
+		fmt.Fprintf(&b, "%s%s %-8s %s\n", cursor, check, status, c.Path)
+	}
+	b.WriteString("\nSpace to toggle, a to toggle all, Enter to confirm, q to cancel")
+	return b.String()
+}
+
+// readSnapshotPaths reads a newline-delimited path list for
+// `fst snapshot --stdin-files`. Relative paths are taken relative to cwd;
+// every path must be inside the workspace root. The returned paths are
+// workspace-relative, slash-separated and deduplicated, in input order.
+func readSnapshotPaths(r io.Reader, root, cwd string) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		abs := line
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(cwd, abs)
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s is outside the workspace", line)
+		}
+		rel = filepath.ToSlash(rel)
+		if !seen[rel] {
+			seen[rel] = true
+			paths = append(paths, rel)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read paths from stdin: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no paths on stdin")
+	}
+	return paths, nil
+}
+
+// checkSnapshotPaths returns an error listing every path that is not one
+// of changes: paths that are ignored, don't exist, or are unchanged.
+func checkSnapshotPaths(root string, paths []string, changes []snapshotChange) error {
+	changed := make(map[string]bool, len(changes))
+	for _, c := range changes {
+		changed[c.Path] = true
+	}
+	matcher, err := ignore.LoadFromDir(root)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, p := range paths {
+		if changed[p] {
+			continue
+		}
+		switch {
+		case isIgnoredPath(matcher, p):
+			problems = append(problems, p+" (ignored)")
+		case !pathExists(filepath.Join(root, filepath.FromSlash(p))):
+			problems = append(problems, p+" (does not exist)")
+		default:
+			problems = append(problems, p+" (unchanged)")
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("cannot snapshot %d path(s) from stdin:\n  %s", len(problems), strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// isIgnoredPath reports whether p or one of its parent directories is
+// matched by the workspace's ignore rules.
+func isIgnoredPath(matcher *ignore.Matcher, p string) bool {
+	if matcher.Match(p, false) {
+		return true
+	}
+	for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+		if matcher.Match(dir, true) {
+			return true
+		}
+	}
+	return false
+}
+
+func pathExists(p string) bool {
+	_, err := os.Lstat(p)
+	return err == nil
+}
@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/drift"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
+)
+
+func init() {
+	register(func(root *cobra.Command) {
+		root.AddCommand(newAddCmd())
+		root.AddCommand(newResetCmd())
+	})
+}
+
+func newAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add <path>...",
+		Short: "Stage changed paths for the next snapshot",
+		Long: `Stage paths so the next 'fst snapshot' records only their changes.
+
+The staged paths are kept in .fst/STAGED. While any are staged,
+'fst snapshot' records the changes at or under them and leaves every other
+change uncommitted, as with --interactive; the staging area is cleared once
+the snapshot is created. With nothing staged, 'fst snapshot' records all
+changes.
+
+Only paths are staged, not their contents: the snapshot captures the files
+as they are when it runs. A directory stages every change beneath it, and
+each path must have at least one change since the latest snapshot
+(including deletions). Nothing can be staged while a merge is pending,
+since the snapshot completing it records all changes. Use 'fst status' to
+see staged and unstaged changes and 'fst reset' to unstage.
+
+Examples:
+  fst add src/main.go      # Stage one file
+  fst add docs             # Stage every change under docs/
+  fst add .                # Stage everything`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAdd(args)
+		},
+	}
+}
+
+func newResetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reset [<path>...]",
+		Short: "Unstage paths staged with 'fst add'",
+		Long: `Remove paths from the staging area built with 'fst add'.
+
+Without arguments, everything is unstaged and the next 'fst snapshot'
+records all changes again. A directory unstages every staged path beneath
+it. The working tree is never touched.
+
+Examples:
+  fst reset                # Unstage everything
+  fst reset src/main.go    # Unstage one file`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReset(args)
+		},
+	}
+}
+
+func runAdd(args []string) error {
+	ws, err := workspace.Open()
+	if err != nil {
+		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+	defer ws.Close()
+	root := ws.Root()
+
+	if parents, err := config.ReadPendingMergeParentsAt(root); err == nil && len(parents) > 0 {
+		return fmt.Errorf("a merge is pending; the next snapshot records all changes, so nothing can be staged")
+	}
+
+	report, err := drift.ComputeFromLatestSnapshot(root)
+	if err != nil {
+		return fmt.Errorf("failed to compute changes: %w", err)
+	}
+	changes := snapshotChanges(report)
+
+	staged, err := config.ReadStagedPathsAt(root)
+	if err != nil {
+		return fmt.Errorf("failed to read staged paths: %w", err)
+	}
+	var unmatched []string
+	for _, arg := range args {
+		p, err := workspaceRelPath(root, arg)
+		if err != nil {
+			return err
+		}
+		if len(stagedChanges(changes, []string{p})) == 0 {
+			unmatched = append(unmatched, arg)
+			continue
+		}
+		staged = append(staged, p)
+	}
+	if len(unmatched) > 0 {
+		return fmt.Errorf("no changes to stage at: %s", strings.Join(unmatched, ", "))
+	}
+
+	if err := config.WriteStagedPathsAt(root, staged); err != nil {
+		return fmt.Errorf("failed to save staged paths: %w", err)
+	}
+	fmt.Printf("Staged %d changed file(s); 'fst snapshot' will record only these.\n", len(stagedChanges(changes, staged)))
+	return nil
+}
+
+func runReset(args []string) error {
+	ws, err := workspace.Open()
+	if err != nil {
+		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+	defer ws.Close()
+	root := ws.Root()
+
+	if len(args) == 0 {
+		if err := config.ClearStagedPathsAt(root); err != nil {
+			return fmt.Errorf("failed to clear staged paths: %w", err)
+		}
+		fmt.Println("Unstaged everything; 'fst snapshot' will record all changes.")
+		return nil
+	}
+
+	staged, err := config.ReadStagedPathsAt(root)
+	if err != nil {
+		return fmt.Errorf("failed to read staged paths: %w", err)
+	}
+	for _, arg := range args {
+		p, err := workspaceRelPath(root, arg)
+		if err != nil {
+			return err
+		}
+		kept := staged[:0]
+		for _, s := range staged {
+			if !isStagedPath(s, []string{p}) {
+				kept = append(kept, s)
+			}
+		}
+		if len(kept) == len(staged) {
+			if isStagedPath(p, staged) {
+				return fmt.Errorf("%s is staged as part of a directory; reset the directory instead", arg)
+			}
+			return fmt.Errorf("%s is not staged", arg)
+		}
+		staged = kept
+	}
+
+	if err := config.WriteStagedPathsAt(root, staged); err != nil {
+		return fmt.Errorf("failed to save staged paths: %w", err)
+	}
+	if len(staged) == 0 {
+		fmt.Println("Nothing staged; 'fst snapshot' will record all changes.")
+	} else {
+		fmt.Printf("%d path(s) still staged\n", len(staged))
+	}
+	return nil
+}
+
+// isStagedPath reports whether p is one of staged or lies under a staged
+// directory.
+func isStagedPath(p string, staged []string) bool {
+	for _, s := range staged {
+		if s == "." || p == s || strings.HasPrefix(p, s+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// stagedChanges returns the changes whose paths are staged.
+func stagedChanges(changes []snapshotChange, staged []string) []snapshotChange {
+	var out []snapshotChange
+	for _, c := range changes {
+		if isStagedPath(c.Path, staged) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// splitStagedReport splits report into the staged changes and the rest.
+func splitStagedReport(report *drift.Report, staged []string) (*drift.Report, *drift.Report) {
+	in, out := &drift.Report{}, &drift.Report{}
+	for _, group := range []struct {
+		paths   []string
+		in, out *[]string
+	}{
+		{report.FilesAdded, &in.FilesAdded, &out.FilesAdded},
+		{report.FilesModified, &in.FilesModified, &out.FilesModified},
+		{report.FilesDeleted, &in.FilesDeleted, &out.FilesDeleted},
+	} {
+		for _, p := range group.paths {
+			if isStagedPath(p, staged) {
+				*group.in = append(*group.in, p)
+			} else {
+				*group.out = append(*group.out, p)
+			}
+		}
+	}
+	return in, out
+}
@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+func TestSnapshotRecordsStagedPaths(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t, nil, nil)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	if err := os.MkdirAll(filepath.Join(targetRoot, "docs"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	for path, content := range map[string]string{
+		"docs/a.md": "a",
+		"docs/b.md": "b",
+		"other.txt": "unrelated edit",
+	} {
+		writeFile(t, filepath.Join(targetRoot, path), content)
+	}
+
+	run := func(args ...string) (string, error) {
+		var out string
+		err := captureStdout(func() error {
+			cmd := NewRootCmd()
+			cmd.SetArgs(args)
+			return cmd.Execute()
+		}, &out)
+		return out, err
+	}
+
+	if _, err := run("add", "base.txt"); err == nil || !strings.Contains(err.Error(), "no changes to stage") {
+		t.Fatalf("expected unchanged path to be rejected, got %v", err)
+	}
+	if _, err := run("add", "docs"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := run("reset", "docs/a.md"); err == nil {
+		t.Fatalf("expected resetting a file inside a staged directory to fail")
+	}
+
+	out, err := run("status", "--staged")
+	if err != nil {
+		t.Fatalf("status --staged: %v", err)
+	}
+	if !strings.Contains(out, "docs/a.md") || !strings.Contains(out, "docs/b.md") || strings.Contains(out, "other.txt") {
+		t.Fatalf("expected only the docs changes to be staged, got:\n%s", out)
+	}
+	out, err = run("status")
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !strings.Contains(out, "Staged for the next snapshot:") || !strings.Contains(out, "Not staged:") {
+		t.Fatalf("expected staged and unstaged sections, got:\n%s", out)
+	}
+
+	if _, err := run("snapshot", "-m", "docs"); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	cfg, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	report := workspaceDriftAt(store.OpenFromWorkspace(targetRoot), targetRoot, cfg.CurrentSnapshotID)
+	if report == nil {
+		t.Fatal("failed to compute drift")
+	}
+	if len(report.FilesAdded) != 1 || report.FilesAdded[0] != "other.txt" {
+		t.Fatalf("expected only other.txt left uncommitted, got %+v", report)
+	}
+	if staged, _ := config.ReadStagedPathsAt(targetRoot); len(staged) != 0 {
+		t.Fatalf("expected the staging area to be cleared, got %v", staged)
+	}
+}
+
+func TestResetUnstagesPaths(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t, nil, nil)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	writeFile(t, filepath.Join(targetRoot, "a.txt"), "a")
+	writeFile(t, filepath.Join(targetRoot, "b.txt"), "b")
+
+	for _, args := range [][]string{{"add", "a.txt", "b.txt"}, {"reset", "a.txt"}} {
+		cmd := NewRootCmd()
+		cmd.SetArgs(args)
+		if err := captureStdout(cmd.Execute, new(string)); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+	}
+	staged, err := config.ReadStagedPathsAt(targetRoot)
+	if err != nil {
+		t.Fatalf("ReadStagedPathsAt: %v", err)
+	}
+	if len(staged) != 1 || staged[0] != "b.txt" {
+		t.Fatalf("expected only b.txt staged, got %v", staged)
+	}
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"reset"})
+	if err := captureStdout(cmd.Execute, new(string)); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if staged, _ := config.ReadStagedPathsAt(targetRoot); len(staged) != 0 {
+		t.Fatalf("expected nothing staged, got %v", staged)
+	}
+}
+
+func TestSnapshotIgnoresStagingWhileMergePending(t *testing.T) {
+	projectRoot, targetRoot, sourceRoot := setupProjectWithWorkspaces(t, nil, map[string]string{"src.txt": "source"})
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	writeFile(t, filepath.Join(targetRoot, "a.txt"), "a")
+	writeFile(t, filepath.Join(targetRoot, "b.txt"), "b")
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"add", "a.txt"})
+	if err := captureStdout(cmd.Execute, new(string)); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	targetCfg, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt target: %v", err)
+	}
+	sourceCfg, err := config.LoadAt(sourceRoot)
+	if err != nil {
+		t.Fatalf("LoadAt source: %v", err)
+	}
+	if err := config.WritePendingMergeParentsAt(targetRoot, []string{targetCfg.CurrentSnapshotID, sourceCfg.CurrentSnapshotID}); err != nil {
+		t.Fatalf("WritePendingMergeParentsAt: %v", err)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"add", "b.txt"})
+	if err := captureStdout(cmd.Execute, new(string)); err == nil || !strings.Contains(err.Error(), "merge is pending") {
+		t.Fatalf("expected add to be refused while a merge is pending, got %v", err)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "merge"})
+	if err := captureStdout(cmd.Execute, new(string)); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	cfg, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if meta := readSnapshotMeta(t, projectRoot, cfg.CurrentSnapshotID); len(meta.ParentSnapshotIDs) != 2 {
+		t.Fatalf("expected a merge snapshot, got parents %v", meta.ParentSnapshotIDs)
+	}
+	report := workspaceDriftAt(store.OpenFromWorkspace(targetRoot), targetRoot, cfg.CurrentSnapshotID)
+	if report == nil || report.HasChanges() {
+		t.Fatalf("expected the merge snapshot to record all changes, got %+v", report)
+	}
+	if staged, _ := config.ReadStagedPathsAt(targetRoot); len(staged) != 0 {
+		t.Fatalf("expected the staging area to be cleared, got %v", staged)
+	}
+}
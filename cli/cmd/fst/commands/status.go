@@ -1,16 +1,23 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ankitiscracked/fastest/cli/internal/config"
 	"github.com/ankitiscracked/fastest/cli/internal/drift"
+	"github.com/ankitiscracked/fastest/cli/internal/gitstore"
+	"github.com/ankitiscracked/fastest/cli/internal/gitutil"
+	"github.com/ankitiscracked/fastest/cli/internal/manifest"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
 	"github.com/ankitiscracked/fastest/cli/internal/ui"
 )
 
@@ -20,6 +27,10 @@ func init() {
 
 func newStatusCmd() *cobra.Command {
 	var jsonOutput bool
+	var watch bool
+	var ignored bool
+	var base string
+	var staged bool
 
 	cmd := &cobra.Command{
 		Use:   "status",
@@ -32,19 +43,69 @@ Without flags, shows detailed status of the current workspace:
 - Upstream workspace (if any)
 - Current drift (files changed since base)
 
+With --watch, the status is redrawn whenever files in the workspace change.
+Changes are debounced so a burst of writes causes a single redraw, and files
+matched by .fstignore (editor temp files, build output) are not watched.
+
+With --ignored (or --untracked), also lists files and directories present in
+the working tree that snapshots skip because of ignore rules, grouped by the
+.fstignore pattern that matched them. Use it to audit .fstignore, e.g.
+before a first snapshot. Ignored directories are listed once, not expanded.
+
+With --json, prints the same information as one JSON object for editor
+integrations and prompt tools: the current and base snapshot IDs, whether a
+merge is in progress (pending merge parents recorded), the project backend
+and whether the head has been exported to it, and how many snapshots the
+head is ahead of and behind its remote branch (ahead_behind) and the
+upstream workspace's head (upstream_ahead_behind). ahead_behind compares
+against the remote-tracking ref as of the last fetch, and is present only
+when the project syncs with GitHub and that ref's commit has been imported.
+
+With --base <snapshot>, changes are computed against that snapshot (an ID
+or unique prefix) instead of the last one, and the changed files are
+listed: "what have I changed since X". The snapshot must be in the local
+store.
+
+While paths are staged with 'fst add', the changes are split into those
+the next snapshot records (staged) and the rest (not staged), and both are
+listed. --staged lists only the staged changes.
+
 Examples:
-  fst status          # Current workspace status`,
+  fst status            # Current workspace status
+  fst status --staged   # Changes the next snapshot records
+  fst status --base a1b2c3  # Changes since snapshot a1b2c3
+  fst status --watch    # Live view while an agent is working
+  fst status --ignored  # Also list files excluded by .fstignore`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runStatus(jsonOutput)
+			if staged {
+				if jsonOutput || watch || ignored || base != "" {
+					return fmt.Errorf("--staged cannot be combined with other flags")
+				}
+				return runStatusStaged()
+			}
+			if watch {
+				if jsonOutput {
+					return fmt.Errorf("--watch cannot be combined with --json")
+				}
+				return runStatusWatch(base)
+			}
+			return runStatus(jsonOutput, ignored, base)
 		},
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Redraw status whenever the workspace changes")
+	cmd.Flags().BoolVar(&ignored, "ignored", false, "List present files excluded by ignore rules")
+	cmd.Flags().BoolVar(&ignored, "untracked", false, "Alias for --ignored")
+	cmd.Flags().StringVar(&base, "base", "", "Show changes relative to this snapshot instead of the last one")
+	cmd.Flags().BoolVar(&staged, "staged", false, "List only the changes staged for the next snapshot")
 
 	return cmd
 }
 
-func runStatus(jsonOutput bool) error {
+// runStatus prints the workspace status. baseRef, if set, names the snapshot
+// to compute changes against instead of the workspace's current snapshot.
+func runStatus(jsonOutput, showIgnored bool, baseRef string) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
@@ -75,12 +136,20 @@ func runStatus(jsonOutput bool) error {
 		}
 	}
 
-	// Get changes since current snapshot
+	// Get changes since current snapshot, or since the requested one
 	var driftReport *drift.Report
-	driftReport, err = drift.ComputeFromLatestSnapshot(root)
-	if err != nil {
-		// Non-fatal, just won't show changes
-		driftReport = nil
+	sinceID := ""
+	if baseRef != "" {
+		sinceID, driftReport, err = driftSinceSnapshot(root, baseRef)
+		if err != nil {
+			return err
+		}
+	} else {
+		driftReport, err = drift.ComputeFromLatestSnapshot(root)
+		if err != nil {
+			// Non-fatal, just won't show changes
+			driftReport = nil
+		}
 	}
 
 	// Get upstream info
@@ -96,14 +165,126 @@ func runStatus(jsonOutput bool) error {
 		}
 	}
 
+	// Ignored paths are only listed on request: it needs a full walk
+	var ignoredPaths []manifest.IgnoredPath
+	if showIgnored {
+		ignoredPaths, err = manifest.ListIgnored(root)
+		if err != nil {
+			return fmt.Errorf("failed to list ignored files: %w", err)
+		}
+		if ignoredPaths == nil {
+			ignoredPaths = []manifest.IgnoredPath{}
+		}
+	}
+
+	// Staging is relative to the current snapshot, so --base ignores it
+	var staged []string
+	if sinceID == "" {
+		staged, _ = config.ReadStagedPathsAt(root)
+	}
+
 	if jsonOutput {
-		return printStatusJSON(cfg, root, driftReport, upstreamName, baseTime, latestSnapshotID, latestSnapshotTime, latestIsMerge)
+		state := loadStatusState(cfg, root, upstreamID, upstreamName)
+		if driftReport != nil && len(staged) > 0 {
+			stagedReport, _ := splitStagedReport(driftReport, staged)
+			state.StagedFiles = len(stagedReport.FilesAdded) + len(stagedReport.FilesModified) + len(stagedReport.FilesDeleted)
+		}
+		return printStatusJSON(cfg, root, driftReport, upstreamName, baseTime, latestSnapshotID, latestSnapshotTime, latestIsMerge, ignoredPaths, state, sinceID)
 	}
 
-	return printStatusHuman(cfg, root, driftReport, upstreamID, upstreamName, baseTime, latestSnapshotID, latestSnapshotTime, latestIsMerge)
+	if err := printStatusHuman(cfg, root, driftReport, upstreamID, upstreamName, baseTime, latestSnapshotID, latestSnapshotTime, latestIsMerge, sinceID); err != nil {
+		return err
+	}
+	if driftReport != nil && driftReport.HasChanges() && len(staged) > 0 {
+		stagedReport, unstagedReport := splitStagedReport(driftReport, staged)
+		fmt.Println()
+		fmt.Println("Staged for the next snapshot:")
+		printChangedFiles(stagedReport)
+		fmt.Println("Not staged:")
+		printChangedFiles(unstagedReport)
+	}
+	if showIgnored {
+		printIgnoredPaths(ignoredPaths)
+	}
+	return nil
 }
 
-func printStatusHuman(cfg *config.WorkspaceConfig, root string, driftReport *drift.Report, upstreamID, upstreamName, baseTime, latestSnapshotID, latestSnapshotTime string, latestIsMerge bool) error {
+// runStatusStaged lists the changes that the next snapshot records.
+func runStatusStaged() error {
+	root, err := config.FindWorkspaceRoot()
+	if err != nil {
+		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+	staged, err := config.ReadStagedPathsAt(root)
+	if err != nil {
+		return fmt.Errorf("failed to read staged paths: %w", err)
+	}
+	if len(staged) == 0 {
+		fmt.Println("Nothing staged - 'fst snapshot' records all changes")
+		return nil
+	}
+	report, err := drift.ComputeFromLatestSnapshot(root)
+	if err != nil {
+		return fmt.Errorf("failed to compute changes: %w", err)
+	}
+	stagedReport, _ := splitStagedReport(report, staged)
+	if !stagedReport.HasChanges() {
+		fmt.Println("No staged changes since last snapshot")
+		return nil
+	}
+	printChangedFiles(stagedReport)
+	return nil
+}
+
+// driftSinceSnapshot resolves ref in the workspace's store and computes the
+// working tree's changes relative to that snapshot.
+func driftSinceSnapshot(root, ref string) (string, *drift.Report, error) {
+	s := store.OpenFromWorkspace(root)
+	id, err := s.ResolveSnapshotID(ref)
+	if err != nil {
+		return "", nil, fmt.Errorf("--base: %w", err)
+	}
+	manifestHash, err := s.ManifestHashFromSnapshotID(id)
+	if err != nil {
+		return "", nil, fmt.Errorf("--base: snapshot %s is not available locally: %w", id, err)
+	}
+	baseManifest, err := s.LoadManifest(manifestHash)
+	if err != nil {
+		return "", nil, fmt.Errorf("--base: snapshot %s is not available locally: %w", id, err)
+	}
+	report, err := drift.Compute(root, baseManifest)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to compute changes since %s: %w", id, err)
+	}
+	return id, report, nil
+}
+
+func runStatusWatch(baseRef string) error {
+	root, err := config.FindWorkspaceRoot()
+	if err != nil {
+		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	redraw := func() {
+		fmt.Print("\033[H\033[2J")
+		if err := runStatus(false, false, baseRef); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		fmt.Println()
+		fmt.Println(ui.Dim("Watching for changes (Ctrl+C to stop)..."))
+	}
+
+	redraw()
+	return drift.Watch(ctx, root, drift.WatchOpts{
+		Interval: 500 * time.Millisecond,
+		Debounce: 300 * time.Millisecond,
+	}, redraw)
+}
+
+func printStatusHuman(cfg *config.WorkspaceConfig, root string, driftReport *drift.Report, upstreamID, upstreamName, baseTime, latestSnapshotID, latestSnapshotTime string, latestIsMerge bool, sinceID string) error {
 	fmt.Printf("Workspace: %s\n", ui.Bold(cfg.WorkspaceName))
 	fmt.Printf("ID:        %s\n", cfg.WorkspaceID)
 	fmt.Printf("Path:      %s\n", root)
@@ -119,6 +300,9 @@ func printStatusHuman(cfg *config.WorkspaceConfig, root string, driftReport *dri
 	if cfg.BaseSnapshotID != "" {
 		snapshotIDs = append(snapshotIDs, cfg.BaseSnapshotID)
 	}
+	if sinceID != "" {
+		snapshotIDs = append(snapshotIDs, sinceID)
+	}
 	shortSnapshotIDs := shortenIDs(snapshotIDs, 12)
 
 	if latestSnapshotID != "" {
@@ -151,24 +335,171 @@ func printStatusHuman(cfg *config.WorkspaceConfig, root string, driftReport *dri
 	fmt.Println()
 
 	// Changes
+	since := "last snapshot"
+	if sinceID != "" {
+		since = "snapshot " + shortSnapshotIDs[sinceID]
+	}
 	if driftReport == nil {
 		fmt.Println("Changes:   (unable to compute)")
 	} else if !driftReport.HasChanges() {
-		fmt.Println(ui.Green("✓ No changes since last snapshot"))
+		fmt.Println(ui.Green("✓ No changes since " + since))
 	} else {
 		added := len(driftReport.FilesAdded)
 		modified := len(driftReport.FilesModified)
 		deleted := len(driftReport.FilesDeleted)
 		total := added + modified + deleted
 
-		fmt.Printf("Changes:   %s since last snapshot (+%d ~%d -%d)\n",
-			ui.Yellow(fmt.Sprintf("%d files changed", total)), added, modified, deleted)
+		fmt.Printf("Changes:   %s since %s (+%d ~%d -%d)\n",
+			ui.Yellow(fmt.Sprintf("%d files changed", total)), since, added, modified, deleted)
+		if sinceID != "" {
+			printChangedFiles(driftReport)
+		}
+	}
+
+	return nil
+}
+
+// printChangedFiles lists the files in report, added, modified and deleted
+// in that order.
+func printChangedFiles(report *drift.Report) {
+	for _, group := range []struct {
+		paths []string
+		mark  func(string) string
+	}{
+		{report.FilesAdded, func(p string) string { return ui.Green("+ " + p) }},
+		{report.FilesModified, func(p string) string { return ui.Yellow("~ " + p) }},
+		{report.FilesDeleted, func(p string) string { return ui.Red("- " + p) }},
+	} {
+		for _, p := range group.paths {
+			fmt.Printf("  %s\n", group.mark(p))
+		}
+	}
+}
+
+// printIgnoredPaths lists ignored paths grouped by the pattern that
+// matched them, patterns in sorted order.
+func printIgnoredPaths(ignored []manifest.IgnoredPath) {
+	fmt.Println()
+	if len(ignored) == 0 {
+		fmt.Println("Ignored:   (none)")
+		return
+	}
+	fmt.Printf("Ignored:   %d paths present but excluded by ignore rules\n", len(ignored))
+
+	byPattern := make(map[string][]string)
+	var patterns []string
+	for _, p := range ignored {
+		if _, ok := byPattern[p.Pattern]; !ok {
+			patterns = append(patterns, p.Pattern)
+		}
+		path := p.Path
+		if p.IsDir {
+			path += "/"
+		}
+		byPattern[p.Pattern] = append(byPattern[p.Pattern], path)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		fmt.Printf("  %s\n", ui.Dim(pattern))
+		for _, path := range byPattern[pattern] {
+			fmt.Printf("    %s\n", path)
+		}
+	}
+}
+
+// statusState is the workspace state that only status --json reports.
+type statusState struct {
+	MergeInProgress bool
+	StagedFiles     int            // changes staged with 'fst add'
+	Backend         map[string]any // nil when the project has no backend
+	AheadBehind     map[string]any // nil when the remote branch head is unknown
+	UpstreamAhead   map[string]any // nil when there is no upstream head
+}
+
+// loadStatusState gathers the merge, backend, remote and upstream state of
+// the workspace at root. Lookups that fail leave the matching field unset.
+func loadStatusState(cfg *config.WorkspaceConfig, root, upstreamID, upstreamName string) statusState {
+	var state statusState
+	if parents, err := config.ReadPendingMergeParentsAt(root); err == nil && len(parents) > 0 {
+		state.MergeInProgress = true
 	}
 
+	s := store.OpenFromWorkspace(root)
+	if projectRoot, parentCfg, err := config.FindProjectRootFrom(root); err == nil && parentCfg.Backend != nil {
+		backendInfo := map[string]any{"type": parentCfg.Backend.Type}
+		mapping, mapErr := gitstore.LoadGitMapping(filepath.Join(projectRoot, ".fst"))
+		if cfg.CurrentSnapshotID != "" && mapErr == nil {
+			_, backendInfo["exported"] = mapping.Snapshots[cfg.CurrentSnapshotID]
+		}
+		if parentCfg.LastSync != nil {
+			backendInfo["last_sync"] = parentCfg.LastSync
+		}
+		state.Backend = backendInfo
+
+		if parentCfg.Backend.Type == "github" && cfg.CurrentSnapshotID != "" && mapErr == nil {
+			state.AheadBehind = remoteAheadBehind(s, cfg, projectRoot, parentCfg.Backend.Remote, mapping)
+		}
+	}
+
+	if upstreamID != "" && cfg.CurrentSnapshotID != "" {
+		if workspaces, err := s.ListWorkspaces(); err == nil {
+			for _, info := range workspaces {
+				if info.WorkspaceID != upstreamID || info.CurrentSnapshotID == "" {
+					continue
+				}
+				if ahead, behind, abErr := s.AheadBehind(cfg.CurrentSnapshotID, info.CurrentSnapshotID); abErr == nil {
+					state.UpstreamAhead = map[string]any{
+						"upstream": upstreamName,
+						"ahead":    ahead,
+						"behind":   behind,
+					}
+				}
+				break
+			}
+		}
+	}
+	return state
+}
+
+// remoteAheadBehind compares the workspace head with the snapshot imported
+// from its branch's remote-tracking ref, as of the last fetch. It returns
+// nil when the ref does not exist or its commit has not been imported.
+func remoteAheadBehind(s *store.Store, cfg *config.WorkspaceConfig, projectRoot, remote string, mapping *gitstore.GitMapping) map[string]any {
+	if _, err := os.Stat(filepath.Join(projectRoot, ".git")); err != nil {
+		return nil
+	}
+	if remote == "" {
+		remote = "origin"
+	}
+	branch := cfg.WorkspaceName
+	if meta, err := gitstore.LoadExportMetadataFromRepo(projectRoot); err == nil {
+		if tracked := gitstore.TrackedBranch(meta, cfg.WorkspaceID); tracked != "" {
+			branch = tracked
+		}
+	}
+	git := gitutil.NewEnv(projectRoot, projectRoot, filepath.Join(projectRoot, ".git", "index"))
+	remoteSHA, err := gitutil.RefSHA(git, "refs/remotes/"+remote+"/"+branch)
+	if err != nil {
+		return nil
+	}
+	for snapshotID, commit := range mapping.Snapshots {
+		if commit != remoteSHA {
+			continue
+		}
+		ahead, behind, err := s.AheadBehind(cfg.CurrentSnapshotID, snapshotID)
+		if err != nil {
+			return nil
+		}
+		return map[string]any{
+			"remote": remote + "/" + branch,
+			"ahead":  ahead,
+			"behind": behind,
+		}
+	}
 	return nil
 }
 
-func printStatusJSON(cfg *config.WorkspaceConfig, root string, driftReport *drift.Report, upstreamName, baseTime, latestSnapshotID, latestSnapshotTime string, latestIsMerge bool) error {
+func printStatusJSON(cfg *config.WorkspaceConfig, root string, driftReport *drift.Report, upstreamName, baseTime, latestSnapshotID, latestSnapshotTime string, latestIsMerge bool, ignored []manifest.IgnoredPath, state statusState, sinceID string) error {
 	fmt.Println("{")
 	fmt.Printf("  \"workspace_name\": %q,\n", cfg.WorkspaceName)
 	fmt.Printf("  \"workspace_id\": %q,\n", cfg.WorkspaceID)
@@ -178,21 +509,47 @@ func printStatusJSON(cfg *config.WorkspaceConfig, root string, driftReport *drif
 	fmt.Printf("  \"latest_snapshot_time\": %q,\n", latestSnapshotTime)
 	fmt.Printf("  \"latest_is_merge\": %t,\n", latestIsMerge)
 	fmt.Printf("  \"base_snapshot_id\": %q,\n", cfg.BaseSnapshotID)
+	fmt.Printf("  \"current_snapshot_id\": %q,\n", cfg.CurrentSnapshotID)
+	fmt.Printf("  \"merge_in_progress\": %t,\n", state.MergeInProgress)
+	fmt.Printf("  \"files_staged\": %d,\n", state.StagedFiles)
 	if upstreamName != "" {
 		fmt.Printf("  \"upstream\": %q,\n", upstreamName)
 	}
+	for _, field := range []struct {
+		name  string
+		value map[string]any
+	}{{"backend", state.Backend}, {"ahead_behind", state.AheadBehind}, {"upstream_ahead_behind", state.UpstreamAhead}} {
+		if field.value == nil {
+			continue
+		}
+		data, err := json.Marshal(field.value)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", field.name, err)
+		}
+		fmt.Printf("  %q: %s,\n", field.name, data)
+	}
 
 	if driftReport != nil {
 		fmt.Printf("  \"files_added\": %d,\n", len(driftReport.FilesAdded))
 		fmt.Printf("  \"files_modified\": %d,\n", len(driftReport.FilesModified))
 		fmt.Printf("  \"files_deleted\": %d,\n", len(driftReport.FilesDeleted))
-		fmt.Printf("  \"since\": %q\n", "last_snapshot")
 	} else {
 		fmt.Printf("  \"files_added\": 0,\n")
 		fmt.Printf("  \"files_modified\": 0,\n")
 		fmt.Printf("  \"files_deleted\": 0,\n")
-		fmt.Printf("  \"since\": %q\n", "last_snapshot")
 	}
+	if ignored != nil {
+		data, err := json.Marshal(ignored)
+		if err != nil {
+			return fmt.Errorf("failed to encode ignored paths: %w", err)
+		}
+		fmt.Printf("  \"ignored\": %s,\n", data)
+	}
+	since := "last_snapshot"
+	if sinceID != "" {
+		since = sinceID
+	}
+	fmt.Printf("  \"since\": %q\n", since)
 	fmt.Println("}")
 	return nil
 }
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
 )
 
 func TestStatusJSONOutput(t *testing.T) {
@@ -46,4 +48,198 @@ func TestStatusJSONOutput(t *testing.T) {
 	if payload["latest_snapshot_time"] == "" {
 		t.Fatalf("expected latest_snapshot_time to be set")
 	}
+	if payload["current_snapshot_id"] != payload["latest_snapshot_id"] {
+		t.Fatalf("current_snapshot_id mismatch: %v", payload["current_snapshot_id"])
+	}
+	if payload["merge_in_progress"] != false {
+		t.Fatalf("expected merge_in_progress false, got %v", payload["merge_in_progress"])
+	}
+
+	head, _ := payload["current_snapshot_id"].(string)
+	if err := config.WritePendingMergeParentsAt(root, []string{head, "other-snapshot"}); err != nil {
+		t.Fatalf("WritePendingMergeParentsAt: %v", err)
+	}
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"status", "--json"})
+		return cmd.Execute()
+	}, &output)
+	if err != nil {
+		t.Fatalf("status --json failed: %v", err)
+	}
+	payload = nil
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &payload); err != nil {
+		t.Fatalf("invalid JSON output: %v\noutput: %s", err, output)
+	}
+	if payload["merge_in_progress"] != true {
+		t.Fatalf("expected merge_in_progress true, got %v", payload["merge_in_progress"])
+	}
+}
+
+func TestStatusIgnored(t *testing.T) {
+	root := setupWorkspace(t, "ws-ignored", map[string]string{
+		"file.txt":   "ok",
+		"debug.log":  "noise",
+		".fstignore": "*.log\n",
+	})
+
+	if err := os.MkdirAll(filepath.Join(root, ".fst", "snapshots"), 0755); err != nil {
+		t.Fatalf("mkdir snapshots: %v", err)
+	}
+	if _, err := createInitialSnapshot(root, "ws-ignored-id", "ws-ignored", false); err != nil {
+		t.Fatalf("createInitialSnapshot: %v", err)
+	}
+
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	var output string
+	err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"status", "--untracked"})
+		return cmd.Execute()
+	}, &output)
+	if err != nil {
+		t.Fatalf("status --untracked failed: %v", err)
+	}
+	if !strings.Contains(output, "*.log") || !strings.Contains(output, "debug.log") {
+		t.Fatalf("expected debug.log listed under *.log, got:\n%s", output)
+	}
+	if strings.Contains(output, "file.txt") {
+		t.Fatalf("expected tracked file.txt not to be listed, got:\n%s", output)
+	}
+
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"status", "--ignored", "--json"})
+		return cmd.Execute()
+	}, &output)
+	if err != nil {
+		t.Fatalf("status --ignored --json failed: %v", err)
+	}
+	var payload struct {
+		Ignored []struct {
+			Path    string `json:"path"`
+			Pattern string `json:"pattern"`
+		} `json:"ignored"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &payload); err != nil {
+		t.Fatalf("invalid JSON output: %v\noutput: %s", err, output)
+	}
+	if len(payload.Ignored) != 1 || payload.Ignored[0].Path != "debug.log" || payload.Ignored[0].Pattern != "*.log" {
+		t.Fatalf("unexpected ignored paths: %+v", payload.Ignored)
+	}
+}
+
+func TestStatusBase(t *testing.T) {
+	root := setupWorkspace(t, "ws-base", map[string]string{
+		"file.txt": "ok",
+	})
+
+	if err := os.MkdirAll(filepath.Join(root, ".fst", "snapshots"), 0755); err != nil {
+		t.Fatalf("mkdir snapshots: %v", err)
+	}
+	firstID, err := createInitialSnapshot(root, "ws-base-id", "ws-base", false)
+	if err != nil {
+		t.Fatalf("createInitialSnapshot: %v", err)
+	}
+
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	if err := os.WriteFile(filepath.Join(root, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatalf("write new.txt: %v", err)
+	}
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"snapshot", "--message", "add new.txt"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+
+	var output string
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"status", "--base", firstID[:12]})
+		return cmd.Execute()
+	}, &output)
+	if err != nil {
+		t.Fatalf("status --base failed: %v", err)
+	}
+	if !strings.Contains(output, "since snapshot") || !strings.Contains(output, "+ new.txt") {
+		t.Fatalf("expected new.txt listed as added since the first snapshot, got:\n%s", output)
+	}
+
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"status", "--base", firstID, "--json"})
+		return cmd.Execute()
+	}, &output)
+	if err != nil {
+		t.Fatalf("status --base --json failed: %v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &payload); err != nil {
+		t.Fatalf("invalid JSON output: %v\noutput: %s", err, output)
+	}
+	if payload["since"] != firstID || payload["files_added"] != float64(1) {
+		t.Fatalf("expected 1 file added since %s, got %v", firstID, payload)
+	}
+
+	err = captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"status", "--base", "no-such-snapshot"})
+		return cmd.Execute()
+	}, &output)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not-found error, got %v", err)
+	}
+}
+
+func TestStatusJSONAheadBehindRemote(t *testing.T) {
+	projectRoot, wsARoot, _ := setupExportProject(t,
+		map[string]string{"a.txt": "a"},
+		map[string]string{"b.txt": "b"},
+	)
+
+	restoreCwd := chdir(t, projectRoot)
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"git", "export", "--init"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export --init: %v", err)
+	}
+	restoreCwd()
+
+	parentCfg, err := config.LoadProjectConfigAt(projectRoot)
+	if err != nil {
+		t.Fatalf("LoadProjectConfigAt: %v", err)
+	}
+	parentCfg.Backend = &config.BackendConfig{Type: "github", Repo: "owner/repo"}
+	if err := config.SaveProjectConfigAt(projectRoot, parentCfg); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+	// The remote last saw the base snapshot's commit.
+	runGit(t, projectRoot, "update-ref", "refs/remotes/origin/ws-a", gitOutput(t, projectRoot, "rev-parse", "ws-a^"))
+
+	restoreCwd = chdir(t, wsARoot)
+	defer restoreCwd()
+	var output string
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"status", "--json"})
+		return cmd.Execute()
+	}, &output); err != nil {
+		t.Fatalf("status --json failed: %v", err)
+	}
+	var payload struct {
+		AheadBehind map[string]any `json:"ahead_behind"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(output)), &payload); err != nil {
+		t.Fatalf("invalid JSON output: %v\noutput: %s", err, output)
+	}
+	ab := payload.AheadBehind
+	if ab["remote"] != "origin/ws-a" || ab["ahead"] != float64(1) || ab["behind"] != float64(0) {
+		t.Fatalf("expected one snapshot ahead of origin/ws-a, got %v", ab)
+	}
 }
@@ -2,11 +2,15 @@ package commands
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ankitiscracked/fastest/cli/internal/backend"
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/manifest"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
 	"github.com/ankitiscracked/fastest/cli/internal/workspace"
 )
 
@@ -18,6 +22,11 @@ func newSyncCmd() *cobra.Command {
 	var manual bool
 	var theirs bool
 	var ours bool
+	var verifyAfter bool
+	var background bool
+	var preferLocalOnTie bool
+	var dryRun bool
+	var since string
 
 	cmd := &cobra.Command{
 		Use:   "sync",
@@ -26,7 +35,29 @@ func newSyncCmd() *cobra.Command {
 
 Requires a backend to be configured (see 'fst backend set').
 If the local and remote heads diverged, this performs a three-way merge
-and creates a new snapshot on success.`,
+and creates a new snapshot on success.
+
+Use --verify-after to re-hash every file of each workspace the sync moved
+to a new snapshot and compare it with that snapshot's manifest. Any
+modified, missing or unexpected file is reported and the command fails,
+catching partially applied merges or corrupt blobs.
+
+Use --prefer-local-on-tie for "last writer wins" reconciliation: files
+changed differently on both sides take the remote version only if the
+remote head snapshot was created strictly after the local head, and keep
+the local version otherwise, including when both were created at the same
+time.
+
+Use --dry-run to preview a sync: it reports how many new commits each
+workspace branch would export and whether a push would be needed, comparing
+against the remote as of the last fetch. Nothing is exported, fetched or
+pushed.
+
+Use --since <snapshot> to publish only recent work: snapshots after the
+given one are exported and pushed on top of its commit, while the snapshot
+itself and its history are treated as already present on the remote. The
+snapshot must have been exported before and must be in the history of the
+current workspace.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			modeCount := 0
 			if manual {
@@ -38,8 +69,14 @@ and creates a new snapshot on success.`,
 			if ours {
 				modeCount++
 			}
+			if preferLocalOnTie {
+				modeCount++
+			}
 			if modeCount > 1 {
-				return fmt.Errorf("only one of --manual, --theirs, --ours can be specified")
+				return fmt.Errorf("only one of --manual, --theirs, --ours, --prefer-local-on-tie can be specified")
+			}
+			if since != "" && dryRun {
+				return fmt.Errorf("--since cannot be used with --dry-run")
 			}
 
 			mode := ConflictModeAgent // default
@@ -49,26 +86,45 @@ and creates a new snapshot on success.`,
 				mode = ConflictModeTheirs
 			} else if ours {
 				mode = ConflictModeOurs
+			} else if preferLocalOnTie {
+				mode = ConflictModeNewer
 			}
 
-			return runSync(mode)
+			return runSync(mode, verifyAfter, background, dryRun, since)
 		},
 	}
 
 	cmd.Flags().BoolVar(&manual, "manual", false, "Create conflict markers for manual resolution")
 	cmd.Flags().BoolVar(&theirs, "theirs", false, "Take remote version for conflicts")
 	cmd.Flags().BoolVar(&ours, "ours", false, "Keep local version for conflicts")
+	cmd.Flags().BoolVar(&preferLocalOnTie, "prefer-local-on-tie", false, "Keep local versions of conflicts unless the remote head is newer")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be exported and pushed without syncing")
+	cmd.Flags().StringVar(&since, "since", "", "Export and push only snapshots after this one")
+	cmd.Flags().BoolVar(&verifyAfter, "verify-after", false, "Verify synced working trees match their new snapshots")
+	cmd.Flags().BoolVar(&background, "background", false, "Run as the background sync spawned after snapshots")
+	_ = cmd.Flags().MarkHidden("background")
 
 	return cmd
 }
 
-func runSync(mode ConflictMode) error {
+func runSync(mode ConflictMode, verifyAfter, background, dryRun bool, since string) error {
 	projectRoot, parentCfg, err := findProjectRootAndConfig()
 	if err != nil {
 		return err
 	}
 
-	b := backend.FromConfig(parentCfg.Backend, RunExportGitAt)
+	export := RunExportGitAt
+	if since != "" {
+		sinceID, err := resolveSyncSince(projectRoot, since)
+		if err != nil {
+			return err
+		}
+		export = func(projectRoot string, initRepo, rebuild bool) error {
+			return exportGitAt(projectRoot, exportGitOptions{initRepo: initRepo, rebuild: rebuild, since: sinceID})
+		}
+	}
+
+	b := backend.FromConfig(parentCfg.Backend, export)
 	if b == nil {
 		return fmt.Errorf("no backend configured - run 'fst backend set' first")
 	}
@@ -78,11 +134,138 @@ func runSync(mode ConflictMode) error {
 		return err
 	}
 	defer lock.Release()
+	if background {
+		// Running now: let the next operation spawn another sync
+		workspace.InheritedLock(backgroundSpawnLockFD).Release()
+	}
+
+	var headsBefore map[string]string
+	if verifyAfter {
+		headsBefore = workspaceHeads(projectRoot)
+	}
 
 	opts := &backend.SyncOptions{
 		OnDivergence: buildOnDivergence(mode),
+		DryRun:       dryRun,
+	}
+	if err := b.Sync(projectRoot, opts); err != nil {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	if verifyAfter {
+		return verifySyncedWorkspaces(projectRoot, headsBefore)
+	}
+	return nil
+}
+
+// resolveSyncSince resolves the --since snapshot and checks that it is in
+// the history of the current workspace's head.
+func resolveSyncSince(projectRoot, ref string) (string, error) {
+	wsRoot, err := config.FindWorkspaceRoot()
+	if err != nil {
+		return "", fmt.Errorf("--since must be run from within a workspace")
+	}
+	wsCfg, err := config.LoadAt(wsRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to load workspace config: %w", err)
+	}
+
+	s := store.OpenAt(projectRoot)
+	id, err := s.ResolveSnapshotID(ref)
+	if err != nil {
+		return "", fmt.Errorf("--since: %w", err)
+	}
+	if !s.IsAncestorOf(id, wsCfg.CurrentSnapshotID) {
+		return "", fmt.Errorf("snapshot %s is not in the history of workspace '%s'\nRun 'fst log' to see its snapshots", id, wsCfg.WorkspaceName)
+	}
+	return id, nil
+}
+
+// workspaceHeads returns the current snapshot of every registered
+// workspace, keyed by workspace root.
+func workspaceHeads(projectRoot string) map[string]string {
+	heads := make(map[string]string)
+	workspaces, err := store.OpenAt(projectRoot).ListWorkspaces()
+	if err != nil {
+		return heads
+	}
+	for _, ws := range workspaces {
+		if ws.Path == "" {
+			continue
+		}
+		if cfg, err := config.LoadAt(ws.Path); err == nil {
+			heads[ws.Path] = cfg.CurrentSnapshotID
+		}
+	}
+	return heads
+}
+
+// verifySyncedWorkspaces checks that every workspace whose head moved since
+// headsBefore has a working tree matching its new snapshot.
+func verifySyncedWorkspaces(projectRoot string, headsBefore map[string]string) error {
+	after := workspaceHeads(projectRoot)
+	roots := make([]string, 0, len(after))
+	for root, head := range after {
+		if head != "" && head != headsBefore[root] {
+			roots = append(roots, root)
+		}
+	}
+	sort.Strings(roots)
+
+	fmt.Println()
+	if len(roots) == 0 {
+		fmt.Println("Verify: no workspace was moved to a new snapshot.")
+		return nil
+	}
+
+	failed := 0
+	for _, root := range roots {
+		mismatches, err := verifyWorkingTree(root, after[root])
+		if err != nil {
+			return fmt.Errorf("failed to verify %s: %w", root, err)
+		}
+		if len(mismatches) == 0 {
+			fmt.Printf("Verify: %s matches %s\n", root, after[root][:12])
+			continue
+		}
+		failed++
+		fmt.Printf("Verify: %s does not match %s:\n", root, after[root][:12])
+		for _, m := range mismatches {
+			fmt.Printf("  %s\n", m)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("sync verification failed for %d workspace(s)", failed)
+	}
+	return nil
+}
+
+// verifyWorkingTree re-hashes every file under root (ignoring the stat
+// cache) and describes each difference from snapshotID's manifest.
+func verifyWorkingTree(root, snapshotID string) ([]string, error) {
+	expected, err := loadManifestByID(root, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifest for %s: %w", snapshotID, err)
+	}
+	actual, err := manifest.Generate(root, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan files: %w", err)
+	}
+	added, modified, deleted := manifest.Diff(expected, actual)
+	var mismatches []string
+	for _, p := range modified {
+		mismatches = append(mismatches, "modified:   "+p)
+	}
+	for _, p := range deleted {
+		mismatches = append(mismatches, "missing:    "+p)
+	}
+	for _, p := range added {
+		mismatches = append(mismatches, "unexpected: "+p)
 	}
-	return b.Sync(projectRoot, opts)
+	return mismatches, nil
 }
 
 func filterMergeActions(actions *mergeActions, files []string) *mergeActions {
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+func TestVerifySyncedWorkspaces(t *testing.T) {
+	projectRoot, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one", "b.txt": "two"},
+		nil,
+	)
+	cfg, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if err := store.OpenAt(projectRoot).RegisterWorkspace(store.WorkspaceInfo{
+		WorkspaceID:       cfg.WorkspaceID,
+		WorkspaceName:     cfg.WorkspaceName,
+		Path:              targetRoot,
+		CurrentSnapshotID: cfg.CurrentSnapshotID,
+	}); err != nil {
+		t.Fatalf("RegisterWorkspace: %v", err)
+	}
+
+	// Head unchanged: nothing to verify, even with local edits.
+	if err := os.WriteFile(filepath.Join(targetRoot, "a.txt"), []byte("edited locally"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var output string
+	err = captureStdout(func() error {
+		return verifySyncedWorkspaces(projectRoot, workspaceHeads(projectRoot))
+	}, &output)
+	if err != nil {
+		t.Fatalf("expected unchanged heads to pass: %v", err)
+	}
+
+	// Head moved: the working tree must match the new snapshot.
+	movedFrom := map[string]string{targetRoot: "previous-head"}
+	if err := os.Remove(filepath.Join(targetRoot, "b.txt")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(targetRoot, "extra.txt"), []byte("extra"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	err = captureStdout(func() error {
+		return verifySyncedWorkspaces(projectRoot, movedFrom)
+	}, &output)
+	if err == nil {
+		t.Fatalf("expected verification to fail")
+	}
+	for _, want := range []string{"modified:   a.txt", "missing:    b.txt", "unexpected: extra.txt"} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	// Restored tree verifies cleanly.
+	os.WriteFile(filepath.Join(targetRoot, "a.txt"), []byte("one"), 0644)
+	os.WriteFile(filepath.Join(targetRoot, "b.txt"), []byte("two"), 0644)
+	os.Remove(filepath.Join(targetRoot, "extra.txt"))
+	err = captureStdout(func() error {
+		return verifySyncedWorkspaces(projectRoot, movedFrom)
+	}, &output)
+	if err != nil {
+		t.Fatalf("expected matching tree to verify: %v\n%s", err, output)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/ankitiscracked/fastest/cli/internal/drift"
 	"github.com/ankitiscracked/fastest/cli/internal/manifest"
 	"github.com/ankitiscracked/fastest/cli/internal/store"
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
 )
 
 func init() {
@@ -409,25 +410,27 @@ func (m model) doMerge(item *workspaceItem) tea.Cmd {
 
 		// Capture merge by running it and tracking results
 		// We need to run the merge logic but capture the outcome
-		err := runMergeForUI(item.WorkspaceName, item.Path)
+		merged, err := runMergeForUI(item.WorkspaceName, item.Path)
 		if err != nil {
 			result.success = false
 			result.errorMsg = err.Error()
 		} else {
 			result.success = true
-			// For now, we don't have detailed counts from runMerge
-			// We could enhance this later
-			result.applied = item.Added + item.Modified
+			if merged != nil {
+				result.applied = len(merged.Applied) + len(merged.AutoMerged) + len(merged.Deleted)
+				result.conflicts = len(merged.Conflicts)
+				result.failed = len(merged.Failed)
+			}
 		}
 
 		return mergeCompleteMsg{result: result}
 	}
 }
 
-// runMergeForUI runs merge silently and returns error status
-func runMergeForUI(workspaceName, workspacePath string) error {
+// runMergeForUI runs merge silently and returns its result
+func runMergeForUI(workspaceName, workspacePath string) (*workspace.MergeResult, error) {
 	// Run merge with agent mode for conflicts
-	return runMerge(nil, workspaceName, ConflictModeAgent, false, false, false, false)
+	return runMerge(nil, workspaceName, mergeOptions{mode: ConflictModeAgent})
 }
 
 func (m *model) filterItems() {
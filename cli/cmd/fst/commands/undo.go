@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ankitiscracked/fastest/cli/internal/workspace"
+)
+
+func init() {
+	register(func(root *cobra.Command) { root.AddCommand(newUndoCmd()) })
+}
+
+func newUndoCmd() *cobra.Command {
+	var list bool
+	var dryRun bool
+	var force bool
+	var pruneEmptyDirs bool
+
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Undo the last merge, sync or agent run",
+		Long: `Undo the last merge, sync or agent run.
+
+Before 'fst merge' and 'fst agent run' change any files, the workspace's
+state is recorded as an undo point (snapshotting uncommitted changes
+first). 'fst sync' records one before it merges remote changes into a
+workspace or moves its head to imported remote snapshots; changes not
+snapshotted before the sync are kept only in its merge snapshot.
+
+'fst undo' restores the working tree to the most recent undo point and
+makes that snapshot the workspace head again, abandoning any pending
+merge. Running it again steps further back; the last 10 undo points are
+kept.
+
+Changes made after the undone command and not snapshotted are discarded,
+so check 'fst status' first. If snapshots were taken after the command
+finished (other than the one completing a merge), undo refuses, since it
+would move the head back past them; pass --force to undo anyway. Snapshots
+created by or after the undone command stay in the store until 'fst gc'.
+--prune-empty-dirs also removes directories left empty by the restore.
+
+Examples:
+  fst undo            # Undo the last merge, sync or agent run
+  fst undo --list     # Show the undo points, newest first
+  fst undo --dry-run  # Show what would be restored`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list {
+				return runUndoList()
+			}
+			return runUndo(dryRun, force, pruneEmptyDirs)
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "List undo points, newest first")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be restored without making changes")
+	cmd.Flags().BoolVar(&force, "force", false, "Undo even if snapshots were taken since the command")
+	cmd.Flags().BoolVar(&pruneEmptyDirs, "prune-empty-dirs", false, "Remove directories left empty by the restore")
+
+	return cmd
+}
+
+func runUndo(dryRun, force, pruneEmptyDirs bool) error {
+	ws, err := workspace.Open()
+	if err != nil {
+		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+	defer ws.Close()
+
+	result, err := ws.Undo(workspace.UndoOpts{DryRun: dryRun, Force: force, PruneEmptyDirs: pruneEmptyDirs})
+	if result != nil && len(result.Restore.MissingBlobs) > 0 {
+		fmt.Printf("Error: Missing cached blobs for %d files:\n", len(result.Restore.MissingBlobs))
+		for _, f := range result.Restore.MissingBlobs {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Undo %s (%s)\n", result.Entry.Description, formatTimeAgo(result.Entry.CreatedAt))
+	fmt.Printf("Restore to: %s\n", result.Entry.SnapshotID)
+	fmt.Println()
+	printRestoreActions(result.Restore)
+
+	if dryRun {
+		fmt.Println("(dry run - no changes made)")
+		return nil
+	}
+
+	fmt.Printf("✓ Restored %d files", result.Restore.Restored)
+	if result.Restore.Deleted > 0 {
+		fmt.Printf(", deleted %d files", result.Restore.Deleted)
+	}
+	fmt.Println()
+	return nil
+}
+
+func runUndoList() error {
+	ws, err := workspace.Open()
+	if err != nil {
+		return fmt.Errorf("not in a workspace directory - run 'fst workspace init' first")
+	}
+	defer ws.Close()
+
+	entries, err := ws.UndoStack()
+	if err != nil {
+		return fmt.Errorf("failed to read undo history: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Println("Nothing to undo.")
+		return nil
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.SnapshotID)
+	}
+	short := shortenIDs(ids, 12)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		fmt.Printf("  %s  %-30s  %s\n", short[e.SnapshotID], e.Description, formatTimeAgo(e.CreatedAt))
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+)
+
+func TestUndoMerge(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	before, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"merge", "ws-source", "--theirs", "--force"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetRoot, "b.txt")); err != nil {
+		t.Fatalf("expected b.txt after merge: %v", err)
+	}
+
+	var output string
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"undo", "--list"})
+		return cmd.Execute()
+	}, &output); err != nil {
+		t.Fatalf("undo --list failed: %v", err)
+	}
+	if !strings.Contains(output, "merge from ws-source") {
+		t.Fatalf("expected the merge in the undo list, got:\n%s", output)
+	}
+
+	if err := captureStdout(func() error {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"undo"})
+		return cmd.Execute()
+	}, new(string)); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetRoot, "b.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected b.txt removed by undo")
+	}
+	after, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if after.CurrentSnapshotID != before.CurrentSnapshotID {
+		t.Fatalf("expected head %s after undo, got %s", before.CurrentSnapshotID, after.CurrentSnapshotID)
+	}
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"undo"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "nothing to undo") {
+		t.Fatalf("expected nothing to undo, got %v", err)
+	}
+}
+
+func TestUndoRefusesAfterLaterSnapshots(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	before, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+
+	run := func(args ...string) error {
+		return captureStdout(func() error {
+			cmd := NewRootCmd()
+			cmd.SetArgs(args)
+			return cmd.Execute()
+		}, new(string))
+	}
+
+	if err := run("merge", "ws-source", "--theirs", "--force"); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	writeFile(t, filepath.Join(targetRoot, "c.txt"), "later")
+	if err := run("snapshot", "-m", "later work"); err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+
+	if err := run("undo"); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected undo to refuse after later snapshots, got %v", err)
+	}
+	if err := run("undo", "--force"); err != nil {
+		t.Fatalf("undo --force failed: %v", err)
+	}
+	after, err := config.LoadAt(targetRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if after.CurrentSnapshotID != before.CurrentSnapshotID {
+		t.Fatalf("expected head %s after undo, got %s", before.CurrentSnapshotID, after.CurrentSnapshotID)
+	}
+}
+
+func TestUndoMergeAllowsCompletingSnapshot(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	for _, args := range [][]string{
+		{"merge", "ws-source", "--theirs", "--force", "--no-snapshot-after"},
+		// The snapshot completing the merge belongs to it.
+		{"snapshot", "-m", "merged"},
+		{"undo"},
+	} {
+		cmd := NewRootCmd()
+		cmd.SetArgs(args)
+		if err := captureStdout(cmd.Execute, new(string)); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(targetRoot, "b.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected b.txt removed by undo")
+	}
+}
+
+func TestUndoPruneEmptyDirs(t *testing.T) {
+	_, targetRoot, _ := setupProjectWithWorkspaces(t,
+		map[string]string{"a.txt": "one"},
+		map[string]string{"lib/deep/b.txt": "two"},
+	)
+
+	restoreCwd := chdir(t, targetRoot)
+	defer restoreCwd()
+
+	for _, args := range [][]string{
+		{"merge", "ws-source", "--theirs", "--force"},
+		{"undo", "--prune-empty-dirs"},
+	} {
+		if err := captureStdout(func() error {
+			cmd := NewRootCmd()
+			cmd.SetArgs(args)
+			return cmd.Execute()
+		}, new(string)); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(targetRoot, "lib")); !os.IsNotExist(err) {
+		t.Fatalf("expected lib/ to be removed by undo --prune-empty-dirs")
+	}
+}
@@ -366,6 +366,54 @@ func TestRestoreOverwritesDirtyFiles(t *testing.T) {
 	}
 }
 
+func TestRestoreSnapshotPathsAfterDash(t *testing.T) {
+	root := setupWorkspace(t, "ws-restore-paths", map[string]string{
+		"a.txt": "a1",
+		"b.txt": "b1",
+	})
+
+	restoreCwd := chdir(t, root)
+	defer restoreCwd()
+
+	setenv(t, "XDG_CACHE_HOME", filepath.Join(root, "cache"))
+	setenv(t, "XDG_CONFIG_HOME", filepath.Join(root, "config"))
+	SetDeps(Deps{})
+	defer ResetDeps()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "v1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("snapshot v1 failed: %v", err)
+	}
+	cfg, err := config.LoadAt(root)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	snapV1 := cfg.CurrentSnapshotID
+
+	for name, content := range map[string]string{"a.txt": "a2", "b.txt": "b2", "c.txt": "c2"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"restore", snapV1, "--yes", "--", "a.txt", "c.txt"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+
+	if content, _ := os.ReadFile(filepath.Join(root, "a.txt")); string(content) != "a1" {
+		t.Fatalf("expected a.txt restored to a1, got %q", content)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "b.txt")); string(content) != "b2" {
+		t.Fatalf("expected b.txt untouched, got %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(root, "c.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected c.txt (not in snapshot) to be deleted with --yes")
+	}
+}
+
 // setupForkedWorkspaces creates two workspaces that share a common base
 // snapshot (guaranteed same ID). Workspace B forks from workspace A's base
 // snapshot, ensuring the merge-base BFS can find a common ancestor regardless
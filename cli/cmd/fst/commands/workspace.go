@@ -30,6 +30,7 @@ func newWorkspaceCmd() *cobra.Command {
 func newWorkspaceInitCmd() *cobra.Command {
 	var workspaceName string
 	var noSnapshot bool
+	var bare bool
 	var force bool
 
 	cmd := &cobra.Command{
@@ -37,12 +38,13 @@ func newWorkspaceInitCmd() *cobra.Command {
 		Short: "Initialize a workspace in the current directory",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runInit(args, workspaceName, noSnapshot, force)
+			return runInit(args, workspaceName, noSnapshot || bare, force)
 		},
 	}
 
 	cmd.Flags().StringVarP(&workspaceName, "workspace", "w", "", "Name for this workspace (must match directory name)")
 	cmd.Flags().BoolVar(&noSnapshot, "no-snapshot", false, "Don't create initial snapshot")
+	cmd.Flags().BoolVar(&bare, "bare", false, "Set up the workspace without an initial snapshot (same as --no-snapshot)")
 	cmd.Flags().BoolVar(&force, "force", false, "Skip safety checks (use with caution)")
 
 	return cmd
@@ -51,6 +53,8 @@ func newWorkspaceInitCmd() *cobra.Command {
 func newWorkspaceCreateCmd() *cobra.Command {
 	var fromWorkspace string
 	var backend string
+	var bare bool
+	var track string
 
 	cmd := &cobra.Command{
 		Use:   "create <workspace-name>",
@@ -62,19 +66,37 @@ the source workspace's current snapshot, with all files copied over.
 
 When run inside a workspace, the current workspace is used as the source.
 When run from the project folder, the main workspace is used as the source.
-Use --from to specify a different source workspace.
+Use --from to specify a different source workspace, or a snapshot ID to
+fork from that point in history: the new workspace's files are restored
+from the snapshot, which also becomes its base for later merges.
+
+Use --bare to create an empty workspace instead, with no files and no
+initial snapshot. Its first 'fst snapshot' starts its history.
+
+Use --track <remote>/<branch> to start a workspace from a branch of the
+project's git repository remote instead: the branch's commits are imported
+as snapshots (fetching the branch first if needed) and the workspace is
+recorded in the export metadata as tracking it. From then on 'fst sync'
+imports new commits on that branch into this workspace and exports the
+workspace's snapshots back to it rather than to a branch named after the
+workspace.
 
 Examples:
   fst workspace create feature-1             # Fork from current/main workspace
-  fst workspace create bugfix --from dev     # Fork from 'dev' workspace`,
+  fst workspace create bugfix --from dev     # Fork from 'dev' workspace
+  fst workspace create retry --from a1b2c3   # Fork from snapshot a1b2c3
+  fst workspace create scratch --bare        # Empty workspace, no snapshot
+  fst workspace create mine --track origin/feature  # Track a remote branch`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCreate(args, fromWorkspace, backend)
+			return runCreate(args, fromWorkspace, backend, track, bare)
 		},
 	}
 
-	cmd.Flags().StringVar(&fromWorkspace, "from", "", "Source workspace to fork from (default: current or main)")
+	cmd.Flags().StringVar(&fromWorkspace, "from", "", "Source workspace or snapshot to fork from (default: current or main)")
 	cmd.Flags().StringVar(&backend, "backend", "auto", "File materialization backend: auto, clone, copy")
+	cmd.Flags().BoolVar(&bare, "bare", false, "Create an empty workspace without an initial snapshot")
+	cmd.Flags().StringVar(&track, "track", "", "Import a remote git branch (<remote>/<branch>) and track it for sync")
 
 	return cmd
 }
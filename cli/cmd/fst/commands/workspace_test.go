@@ -3,9 +3,11 @@ package commands
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/gitstore"
 	"github.com/ankitiscracked/fastest/cli/internal/store"
 )
 
@@ -117,3 +119,302 @@ func TestWorkspaceCreateFromParent(t *testing.T) {
 			cfg.BaseSnapshotID, cfg.CurrentSnapshotID)
 	}
 }
+
+func TestWorkspaceCreateFromSnapshot(t *testing.T) {
+	parent := t.TempDir()
+
+	setenv(t, "XDG_CACHE_HOME", filepath.Join(parent, "cache"))
+	setenv(t, "XDG_CONFIG_HOME", filepath.Join(parent, "config"))
+
+	SetDeps(Deps{})
+	defer ResetDeps()
+
+	if err := config.SaveProjectConfigAt(parent, &config.ProjectConfig{
+		ProjectID:   "proj-123",
+		ProjectName: "demo",
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+
+	mainDir := filepath.Join(parent, "main")
+	if err := os.MkdirAll(mainDir, 0755); err != nil {
+		t.Fatalf("mkdir main: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(mainDir, "readme.md"), []byte("v1"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.Symlink("readme.md", filepath.Join(mainDir, "link")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(mainDir, "empty"), 0755); err != nil {
+		t.Fatalf("mkdir empty: %v", err)
+	}
+	mainWSID := "ws-main-test"
+	if err := config.InitAt(mainDir, "proj-123", mainWSID, "main", ""); err != nil {
+		t.Fatalf("InitAt main: %v", err)
+	}
+	if err := store.OpenAt(parent).RegisterWorkspace(store.WorkspaceInfo{
+		WorkspaceID:   mainWSID,
+		WorkspaceName: "main",
+		Path:          mainDir,
+	}); err != nil {
+		t.Fatalf("RegisterWorkspace: %v", err)
+	}
+
+	restoreCwd := chdir(t, mainDir)
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "first"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+	mainCfg, err := config.LoadAt(mainDir)
+	if err != nil {
+		t.Fatalf("LoadAt main: %v", err)
+	}
+	firstID := mainCfg.CurrentSnapshotID
+
+	if err := os.WriteFile(filepath.Join(mainDir, "readme.md"), []byte("version 2"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(mainDir, "new.txt"), []byte("new"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "second"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("snapshot failed: %v", err)
+	}
+	restoreCwd()
+
+	restoreCwd = chdir(t, parent)
+	defer restoreCwd()
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"workspace", "create", "retry", "--from", firstID[:12]})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("workspace create --from snapshot failed: %v", err)
+	}
+
+	workspaceDir := filepath.Join(parent, "retry")
+	data, err := os.ReadFile(filepath.Join(workspaceDir, "readme.md"))
+	if err != nil {
+		t.Fatalf("read readme.md: %v", err)
+	}
+	if string(data) != "v1" {
+		t.Fatalf("expected readme.md from first snapshot, got %q", string(data))
+	}
+	if _, err := os.Stat(filepath.Join(workspaceDir, "new.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected new.txt to be absent, got err=%v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(workspaceDir, "link")); err != nil || target != "readme.md" {
+		t.Fatalf("expected the snapshot's symlink to be restored, got %q, %v", target, err)
+	}
+	if info, err := os.Stat(filepath.Join(workspaceDir, "empty")); err != nil || !info.IsDir() {
+		t.Fatalf("expected the snapshot's empty directory to be restored, got %v", err)
+	}
+
+	cfg, err := config.LoadAt(workspaceDir)
+	if err != nil {
+		t.Fatalf("LoadAt retry: %v", err)
+	}
+	if cfg.BaseSnapshotID != firstID || cfg.CurrentSnapshotID != firstID {
+		t.Fatalf("expected base and current snapshot %s, got base=%s current=%s",
+			firstID, cfg.BaseSnapshotID, cfg.CurrentSnapshotID)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"workspace", "create", "bogus", "--from", "nonexistent"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected unknown --from to fail")
+	}
+	if _, err := os.Stat(filepath.Join(parent, "bogus")); !os.IsNotExist(err) {
+		t.Fatalf("expected no workspace directory for a failed create")
+	}
+}
+
+func TestWorkspaceCreateBare(t *testing.T) {
+	parent := t.TempDir()
+
+	setenv(t, "XDG_CACHE_HOME", filepath.Join(parent, "cache"))
+	setenv(t, "XDG_CONFIG_HOME", filepath.Join(parent, "config"))
+
+	SetDeps(Deps{})
+	defer ResetDeps()
+
+	if err := config.SaveProjectConfigAt(parent, &config.ProjectConfig{
+		ProjectID:   "proj-bare",
+		ProjectName: "demo",
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+
+	restoreCwd := chdir(t, parent)
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"workspace", "create", "scratch", "--bare"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("workspace create --bare: %v", err)
+	}
+	restoreCwd()
+
+	wsDir := filepath.Join(parent, "scratch")
+	cfg, err := config.LoadAt(wsDir)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if cfg.CurrentSnapshotID != "" || cfg.BaseSnapshotID != "" {
+		t.Fatalf("expected no snapshot in a bare workspace, got current=%q base=%q", cfg.CurrentSnapshotID, cfg.BaseSnapshotID)
+	}
+	info, err := store.OpenAt(parent).FindWorkspaceByName("scratch")
+	if err != nil {
+		t.Fatalf("FindWorkspaceByName: %v", err)
+	}
+	if info.CurrentSnapshotID != "" || info.Path != wsDir {
+		t.Fatalf("unexpected registry entry: %+v", info)
+	}
+
+	restoreCwd = chdir(t, wsDir)
+	defer restoreCwd()
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"status"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("status in bare workspace: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(wsDir, "notes.txt"), []byte("first"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "first"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("snapshot in bare workspace: %v", err)
+	}
+
+	cfg, err = config.LoadAt(wsDir)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if cfg.CurrentSnapshotID == "" {
+		t.Fatalf("expected the first snapshot to become the workspace head")
+	}
+	meta, err := store.OpenAt(parent).LoadSnapshotMeta(cfg.CurrentSnapshotID)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if len(meta.ParentSnapshotIDs) != 0 {
+		t.Fatalf("expected the first snapshot to be a root, got parents %v", meta.ParentSnapshotIDs)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"status"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("status after first snapshot: %v", err)
+	}
+}
+
+func TestWorkspaceCreateTrack(t *testing.T) {
+	projectRoot, _, _ := setupExportProject(t,
+		map[string]string{"a.txt": "a"},
+		map[string]string{"b.txt": "b"},
+	)
+
+	// A teammate's repository with a feature branch of its own.
+	remote := t.TempDir()
+	runGit(t, remote, "init")
+	runGit(t, remote, "config", "user.name", "Teammate")
+	runGit(t, remote, "config", "user.email", "teammate@example.com")
+	runGit(t, remote, "checkout", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(remote, "feature.txt"), []byte("v1"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Symlink("feature.txt", filepath.Join(remote, "link")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	runGit(t, remote, "add", "-A")
+	runGit(t, remote, "commit", "-m", "start feature")
+	if err := os.WriteFile(filepath.Join(remote, "feature.txt"), []byte("v2"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	runGit(t, remote, "commit", "-am", "continue feature")
+	remoteTip := gitOutput(t, remote, "rev-parse", "HEAD")
+
+	restoreCwd := chdir(t, projectRoot)
+	defer restoreCwd()
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"git", "export", "--init"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export --init: %v", err)
+	}
+	runGit(t, projectRoot, "remote", "add", "origin", remote)
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"workspace", "create", "mine", "--track", "origin/feature"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("workspace create --track: %v", err)
+	}
+
+	wsDir := filepath.Join(projectRoot, "mine")
+	content, err := os.ReadFile(filepath.Join(wsDir, "feature.txt"))
+	if err != nil || string(content) != "v2" {
+		t.Fatalf("expected the branch tip's files in the workspace, got %q, %v", content, err)
+	}
+	if target, err := os.Readlink(filepath.Join(wsDir, "link")); err != nil || target != "feature.txt" {
+		t.Fatalf("expected the branch's symlink in the workspace, got %q, %v", target, err)
+	}
+	cfg, err := config.LoadAt(wsDir)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	mapping, err := gitstore.LoadGitMapping(filepath.Join(projectRoot, ".fst"))
+	if err != nil {
+		t.Fatalf("LoadGitMapping: %v", err)
+	}
+	if mapping.Snapshots[cfg.CurrentSnapshotID] != remoteTip {
+		t.Fatalf("expected the head snapshot to map to %s, got %q", remoteTip, mapping.Snapshots[cfg.CurrentSnapshotID])
+	}
+	meta, err := gitstore.LoadExportMetadataFromRepo(projectRoot)
+	if err != nil {
+		t.Fatalf("LoadExportMetadataFromRepo: %v", err)
+	}
+	if entry := meta.Workspaces[cfg.WorkspaceID]; entry.Branch != "feature" || !entry.Tracked {
+		t.Fatalf("expected workspace to track feature, got %+v", entry)
+	}
+
+	// Local snapshots are exported on top of the tracked branch.
+	if err := os.WriteFile(filepath.Join(wsDir, "feature.txt"), []byte("v3"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	restoreWs := chdir(t, wsDir)
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"snapshot", "-m", "local work"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	restoreWs()
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"git", "export"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if parent := gitOutput(t, projectRoot, "rev-parse", "feature^"); parent != remoteTip {
+		t.Fatalf("expected the local commit on top of %s, got parent %s", remoteTip, parent)
+	}
+	if branches := gitOutput(t, projectRoot, "branch", "--list", "mine"); strings.TrimSpace(branches) != "" {
+		t.Fatalf("expected no branch named after the tracking workspace, got %q", branches)
+	}
+	meta, err = gitstore.LoadExportMetadataFromRepo(projectRoot)
+	if err != nil {
+		t.Fatalf("LoadExportMetadataFromRepo: %v", err)
+	}
+	if entry := meta.Workspaces[cfg.WorkspaceID]; entry.Branch != "feature" || !entry.Tracked {
+		t.Fatalf("expected export to keep tracking feature, got %+v", entry)
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"workspace", "create", "other", "--track", "origin/feature"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected tracking an already exported branch to fail")
+	}
+}
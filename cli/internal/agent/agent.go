@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,6 +21,9 @@ type Agent struct {
 	Path        string `json:"path"`
 	Description string `json:"description"`
 	Available   bool   `json:"available"`
+	// Model is an optional model hint passed to the agent's CLI. It is
+	// ignored by agents without a model flag (see SupportsModel).
+	Model string `json:"model,omitempty"`
 }
 
 // KnownAgents lists all agents we know how to detect and invoke
@@ -333,6 +337,32 @@ Assessment:`, driftContext)
 	return invoke(a, prompt)
 }
 
+// InvokeChangeSummary generates a one-line summary of a list of changed files
+func InvokeChangeSummary(a *Agent, changeContext string, invoke InvokeFunc) (string, error) {
+	prompt := fmt.Sprintf(`Summarize what changed in ONE short sentence (under 100 characters). Describe the intent of the change, not the individual files. Reply with the sentence only.
+
+Changed files:
+%s
+
+Summary:`, changeContext)
+
+	out, err := invoke(a, prompt)
+	if err != nil {
+		return "", err
+	}
+	return firstLine(out), nil
+}
+
+// firstLine returns the first non-empty line of s, trimmed.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
 // FileConflictSummary represents aggregated conflict info for a single file
 type FileConflictSummary struct {
 	Path          string
@@ -451,6 +481,204 @@ Example format:
 	return parseMergeOutput(output)
 }
 
+// FileConflict is one conflicting file passed to InvokeMergeFiles.
+type FileConflict struct {
+	Path    string
+	Base    string
+	Current string
+	Source  string
+}
+
+// mergedFileMarker introduces each file in a multi-file merge response.
+const mergedFileMarker = "---MERGED FILE: "
+
+// InvokeMergeFiles invokes an agent once to merge several conflicting files
+// together, so related changes are resolved with shared context. The result
+// maps each path to its merge; the strategy bullets apply to the whole batch
+// and are attached to every result. It fails unless every file is returned.
+func InvokeMergeFiles(a *Agent, files []FileConflict, invoke InvokeFunc) (map[string]*MergeResult, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Merge these %d related files. For each file, two versions diverged from a common base.\n", len(files))
+	for _, f := range files {
+		fmt.Fprintf(&b, `
+##### FILE: %s #####
+
+=== BASE VERSION (common ancestor) ===
+%s
+
+=== CURRENT VERSION (the workspace we're merging into) ===
+%s
+
+=== SOURCE VERSION (the workspace we're merging from) ===
+%s
+`, f.Path, f.Base, f.Current, f.Source)
+	}
+	b.WriteString(`
+First, briefly explain your merge strategy (2-4 bullet points starting with "• ").
+Then, for every file, output a line containing only "---MERGED FILE: <path>---"
+followed by that file's complete merged content.
+
+Example format:
+• Renamed X in both files because...
+• Kept Y from source because...
+
+---MERGED FILE: src/a.go---
+<merged content of src/a.go>
+---MERGED FILE: src/b.go---
+<merged content of src/b.go>`)
+
+	output, err := invoke(a, b.String())
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := parseMergeFilesOutput(output)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if _, ok := results[f.Path]; !ok {
+			return nil, fmt.Errorf("agent did not return merged content for %s", f.Path)
+		}
+	}
+	return results, nil
+}
+
+// parseMergeFilesOutput splits a multi-file merge response into per-file
+// results, sharing the leading strategy bullets among them.
+func parseMergeFilesOutput(output string) (map[string]*MergeResult, error) {
+	lines := strings.Split(output, "\n")
+	var strategyLines []string
+	contents := make(map[string][]string)
+	current := ""
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, mergedFileMarker) && strings.HasSuffix(trimmed, "---") {
+			current = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(trimmed, mergedFileMarker), "---"))
+			contents[current] = nil
+			continue
+		}
+		if current == "" {
+			strategyLines = append(strategyLines, line)
+		} else {
+			contents[current] = append(contents[current], line)
+		}
+	}
+	if len(contents) == 0 {
+		return nil, fmt.Errorf("agent response contains no merged files")
+	}
+
+	strategy := parseStrategyBullets(strings.Join(strategyLines, "\n"))
+	results := make(map[string]*MergeResult, len(contents))
+	for path, body := range contents {
+		code := stripCodeFences(strings.TrimSpace(strings.Join(body, "\n")))
+		if code == "" {
+			return nil, fmt.Errorf("agent returned empty merged code for %s", path)
+		}
+		results[path] = &MergeResult{Strategy: strategy, MergedCode: code}
+	}
+	return results, nil
+}
+
+// MergeHunk is one conflicting region passed to InvokeMergeHunks, with the
+// merged lines around it as context.
+type MergeHunk struct {
+	Before  string
+	Base    string
+	Current string
+	Source  string
+	After   string
+}
+
+// HunkMergeResult contains the agent's resolution of each hunk, in order.
+type HunkMergeResult struct {
+	Strategy []string
+	Resolved []string
+}
+
+// resolvedHunkMarker introduces each hunk in a hunk-scoped merge response.
+const resolvedHunkMarker = "---RESOLVED HUNK "
+
+// InvokeMergeHunks invokes an agent to resolve only the conflicting hunks of
+// a file instead of the whole file. It fails unless every hunk is returned.
+func InvokeMergeHunks(a *Agent, filename string, hunks []MergeHunk, invoke InvokeFunc) (*HunkMergeResult, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Resolve %d conflicting hunks in %s. Both versions diverged from a common base; only the conflicting parts and some surrounding lines are shown.\n", len(hunks), filename)
+	for i, h := range hunks {
+		fmt.Fprintf(&b, `
+##### HUNK %d #####
+
+=== CONTEXT BEFORE (already merged, do not repeat) ===
+%s
+
+=== BASE VERSION (common ancestor) ===
+%s
+
+=== CURRENT VERSION (the workspace we're merging into) ===
+%s
+
+=== SOURCE VERSION (the workspace we're merging from) ===
+%s
+
+=== CONTEXT AFTER (already merged, do not repeat) ===
+%s
+`, i+1, h.Before, h.Base, h.Current, h.Source, h.After)
+	}
+	b.WriteString(`
+First, briefly explain your merge strategy (2-3 bullet points starting with "• ").
+Then, for every hunk, output a line containing only "---RESOLVED HUNK <n>---"
+followed by the lines that replace that hunk, without the context lines.
+
+Example format:
+• Kept X from current because...
+• Added Y from source because...
+
+---RESOLVED HUNK 1---
+<merged lines of hunk 1>
+---RESOLVED HUNK 2---
+<merged lines of hunk 2>`)
+
+	output, err := invoke(a, b.String())
+	if err != nil {
+		return nil, err
+	}
+	return parseMergeHunksOutput(output, len(hunks))
+}
+
+// parseMergeHunksOutput splits a hunk-scoped merge response into the
+// strategy bullets and the resolution of each of count hunks.
+func parseMergeHunksOutput(output string, count int) (*HunkMergeResult, error) {
+	var strategyLines []string
+	bodies := make(map[int][]string)
+	current := 0
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, resolvedHunkMarker) && strings.HasSuffix(trimmed, "---") {
+			n, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(trimmed, resolvedHunkMarker), "---")))
+			if err == nil && n >= 1 && n <= count {
+				current = n
+				bodies[n] = nil
+				continue
+			}
+		}
+		if current == 0 {
+			strategyLines = append(strategyLines, line)
+		} else {
+			bodies[current] = append(bodies[current], line)
+		}
+	}
+
+	result := &HunkMergeResult{Strategy: parseStrategyBullets(strings.Join(strategyLines, "\n"))}
+	for n := 1; n <= count; n++ {
+		body, ok := bodies[n]
+		if !ok {
+			return nil, fmt.Errorf("agent did not return a resolution for hunk %d", n)
+		}
+		result.Resolved = append(result.Resolved, stripCodeFences(strings.Trim(strings.Join(body, "\n"), "\n")))
+	}
+	return result, nil
+}
+
 // parseMergeOutput separates strategy bullets from merged code
 func parseMergeOutput(output string) (*MergeResult, error) {
 	// Look for the separator
@@ -460,21 +688,7 @@ func parseMergeOutput(output string) (*MergeResult, error) {
 	result := &MergeResult{}
 
 	if len(parts) == 2 {
-		// Parse strategy bullets
-		strategyPart := strings.TrimSpace(parts[0])
-		for _, line := range strings.Split(strategyPart, "\n") {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "•") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "*") {
-				// Clean up the bullet point
-				line = strings.TrimPrefix(line, "•")
-				line = strings.TrimPrefix(line, "-")
-				line = strings.TrimPrefix(line, "*")
-				line = strings.TrimSpace(line)
-				if line != "" {
-					result.Strategy = append(result.Strategy, line)
-				}
-			}
-		}
+		result.Strategy = parseStrategyBullets(parts[0])
 
 		// Get merged code
 		result.MergedCode = strings.TrimSpace(parts[1])
@@ -493,30 +707,76 @@ func parseMergeOutput(output string) (*MergeResult, error) {
 	return result, nil
 }
 
+// modelFlags maps the agents whose CLI accepts a model selection to the
+// flag that sets it. amp has no model flag and runs with its defaults.
+var modelFlags = map[string]string{
+	"claude": "--model",
+	"codex":  "--model",
+	"agent":  "--model",
+	"gemini": "--model",
+	"droid":  "--model",
+}
+
+// SupportsModel reports whether the named agent honours Agent.Model.
+func SupportsModel(name string) bool {
+	_, ok := modelFlags[name]
+	return ok
+}
+
+// modelArgs returns the CLI arguments selecting agent.Model, or nil when no
+// model is set or the agent has no model flag.
+func modelArgs(agent *Agent) []string {
+	flag, ok := modelFlags[agent.Name]
+	if !ok || agent.Model == "" {
+		return nil
+	}
+	return []string{flag, agent.Model}
+}
+
+// parseStrategyBullets extracts the "•", "-" or "*" bullet points of text.
+func parseStrategyBullets(text string) []string {
+	var bullets []string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "•") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "*") {
+			// Clean up the bullet point
+			line = strings.TrimPrefix(line, "•")
+			line = strings.TrimPrefix(line, "-")
+			line = strings.TrimPrefix(line, "*")
+			line = strings.TrimSpace(line)
+			if line != "" {
+				bullets = append(bullets, line)
+			}
+		}
+	}
+	return bullets
+}
+
 // Invoke runs the agent with a prompt and returns the response.
 func Invoke(agent *Agent, prompt string) (string, error) {
+	model := modelArgs(agent)
 	switch agent.Name {
 	case "claude":
-		return invokeClaude(prompt)
+		return invokeClaude(prompt, model)
 	case "codex":
-		return invokeCodex(prompt)
+		return invokeCodex(prompt, model)
 	case "amp":
 		return invokeAmp(prompt)
 	case "agent":
-		return invokeCursorAgent(prompt)
+		return invokeCursorAgent(prompt, model)
 	case "gemini":
-		return invokeGemini(prompt)
+		return invokeGemini(prompt, model)
 	case "droid":
-		return invokeDroid(prompt)
+		return invokeDroid(prompt, model)
 	default:
 		return "", fmt.Errorf("agent %s invocation not implemented", agent.Name)
 	}
 }
 
 // invokeClaude invokes Claude Code CLI
-func invokeClaude(prompt string) (string, error) {
-	// Claude Code CLI: claude -p "prompt"
-	cmd := exec.Command("claude", "-p", prompt)
+func invokeClaude(prompt string, model []string) (string, error) {
+	// Claude Code CLI: claude [--model m] -p "prompt"
+	cmd := exec.Command("claude", append(model, "-p", prompt)...)
 	cmd.Stdin = nil
 
 	output, err := cmd.Output()
@@ -535,9 +795,9 @@ func invokeClaude(prompt string) (string, error) {
 	return result, nil
 }
 
-func invokeCodex(prompt string) (string, error) {
-	// Codex CLI: codex exec "prompt"
-	cmd := exec.Command("codex", "exec", prompt)
+func invokeCodex(prompt string, model []string) (string, error) {
+	// Codex CLI: codex exec [--model m] "prompt"
+	cmd := exec.Command("codex", append(append([]string{"exec"}, model...), prompt)...)
 	cmd.Stdin = nil
 	return runAgentCommand(cmd, "codex")
 }
@@ -549,23 +809,23 @@ func invokeAmp(prompt string) (string, error) {
 	return runAgentCommand(cmd, "amp")
 }
 
-func invokeCursorAgent(prompt string) (string, error) {
-	// Cursor Agent CLI: agent -p "prompt"
-	cmd := exec.Command("agent", "-p", prompt)
+func invokeCursorAgent(prompt string, model []string) (string, error) {
+	// Cursor Agent CLI: agent [--model m] -p "prompt"
+	cmd := exec.Command("agent", append(model, "-p", prompt)...)
 	cmd.Stdin = nil
 	return runAgentCommand(cmd, "agent")
 }
 
-func invokeGemini(prompt string) (string, error) {
-	// Gemini CLI: gemini -p "prompt"
-	cmd := exec.Command("gemini", "-p", prompt)
+func invokeGemini(prompt string, model []string) (string, error) {
+	// Gemini CLI: gemini [--model m] -p "prompt"
+	cmd := exec.Command("gemini", append(model, "-p", prompt)...)
 	cmd.Stdin = nil
 	return runAgentCommand(cmd, "gemini")
 }
 
-func invokeDroid(prompt string) (string, error) {
-	// Factory Droid CLI: droid exec "prompt"
-	cmd := exec.Command("droid", "exec", prompt)
+func invokeDroid(prompt string, model []string) (string, error) {
+	// Factory Droid CLI: droid exec [--model m] "prompt"
+	cmd := exec.Command("droid", append(append([]string{"exec"}, model...), prompt)...)
 	cmd.Stdin = nil
 	return runAgentCommand(cmd, "droid")
 }
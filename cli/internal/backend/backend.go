@@ -2,13 +2,21 @@ package backend
 
 import (
 	"errors"
+	"path/filepath"
+	"time"
 
 	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/gitstore"
 )
 
 // ErrNoRemote is returned when a backend has no remote to sync with.
 var ErrNoRemote = errors.New("backend has no remote")
 
+// ErrMergePending is returned by an OnDivergence callback that left the
+// divergence for the user to resolve (conflict markers in the working tree
+// and pending merge parents recorded) instead of creating a merge snapshot.
+var ErrMergePending = errors.New("merge has unresolved conflicts")
+
 // ExportFunc exports local snapshots to git commits at the given project root.
 type ExportFunc func(projectRoot string, initRepo, rebuild bool) error
 
@@ -45,9 +53,14 @@ type DivergenceInfo struct {
 // SyncOptions configures how sync handles divergence.
 type SyncOptions struct {
 	// OnDivergence is called when local and remote have diverged for a workspace.
-	// It should merge the two heads and return the merged snapshot ID.
+	// It should merge the two heads and return the merged snapshot ID, or
+	// return ErrMergePending if the merge was left for the user to finish.
 	// If nil, divergence is reported as an error.
 	OnDivergence func(info DivergenceInfo) (mergedSnapshotID string, err error)
+
+	// DryRun reports what the sync would export and push (see PlanSync)
+	// instead of syncing. The remote is not fetched.
+	DryRun bool
 }
 
 // Backend defines the interface for storage backends.
@@ -68,3 +81,32 @@ type Backend interface {
 	// If opts is nil or OnDivergence is nil, divergence is reported as an error.
 	Sync(projectRoot string, opts *SyncOptions) error
 }
+
+// recordSync runs op and stores its outcome as the project's last-sync
+// record. Failing to write the record never masks op's own result.
+func recordSync(projectRoot, operation string, op func() error) error {
+	before := mappedCommitCount(projectRoot)
+	err := op()
+
+	rec := &config.SyncRecord{
+		Operation: operation,
+		Time:      time.Now().UTC().Format(time.RFC3339),
+		Success:   err == nil,
+	}
+	if n := mappedCommitCount(projectRoot) - before; n > 0 {
+		rec.Commits = n
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	_ = config.SaveLastSyncAt(projectRoot, rec)
+	return err
+}
+
+func mappedCommitCount(projectRoot string) int {
+	mapping, err := gitstore.LoadGitMapping(filepath.Join(projectRoot, ".fst"))
+	if err != nil {
+		return 0
+	}
+	return len(mapping.Snapshots)
+}
@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -109,6 +110,51 @@ func TestGitBackendSyncCallsPush(t *testing.T) {
 	}
 }
 
+func TestGitBackendRecordsLastSync(t *testing.T) {
+	projectRoot := t.TempDir()
+	if err := config.SaveProjectConfigAt(projectRoot, &config.ProjectConfig{
+		ProjectID:   "proj-sync",
+		ProjectName: "sync-test",
+		Backend:     &config.BackendConfig{Type: "git"},
+	}); err != nil {
+		t.Fatalf("SaveProjectConfigAt: %v", err)
+	}
+
+	export := func(projectRoot string, initRepo, rebuild bool) error {
+		mapping := &gitstore.GitMapping{Snapshots: map[string]string{"snap-a": "sha-a", "snap-b": "sha-b"}}
+		return gitstore.SaveGitMapping(filepath.Join(projectRoot, ".fst"), mapping)
+	}
+	b := &GitBackend{ExportGit: export}
+	if err := b.Sync(projectRoot, nil); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	cfg, err := config.LoadProjectConfigAt(projectRoot)
+	if err != nil {
+		t.Fatalf("LoadProjectConfigAt: %v", err)
+	}
+	last := cfg.LastSync
+	if last == nil || !last.Success || last.Operation != "sync" || last.Commits != 2 || last.Time == "" {
+		t.Fatalf("unexpected last-sync record: %+v", last)
+	}
+	if cfg.Backend == nil || cfg.Backend.Type != "git" {
+		t.Fatalf("expected the rest of the project config to be preserved")
+	}
+
+	b = &GitBackend{ExportGit: func(string, bool, bool) error { return errors.New("export exploded") }}
+	if err := b.Push(projectRoot); err == nil {
+		t.Fatalf("expected Push to fail")
+	}
+	cfg, err = config.LoadProjectConfigAt(projectRoot)
+	if err != nil {
+		t.Fatalf("LoadProjectConfigAt: %v", err)
+	}
+	last = cfg.LastSync
+	if last == nil || last.Success || last.Operation != "push" || last.Error != "export exploded" || last.Commits != 0 {
+		t.Fatalf("unexpected failed last-sync record: %+v", last)
+	}
+}
+
 // runGit runs a git command in the given directory.
 func runGit(t *testing.T, dir string, args ...string) {
 	t.Helper()
@@ -196,7 +242,7 @@ func setupProjectWithExport(t *testing.T, projectID, wsName string) (string, str
 	if err != nil {
 		t.Fatalf("LoadManifest: %v", err)
 	}
-	if err := gitstore.RestoreFilesFromManifest(tmpDir, s, m); err != nil {
+	if err := gitstore.RestoreFilesFromManifest(tmpDir, s, m, gitstore.RestoreOptions{}); err != nil {
 		t.Fatalf("RestoreFilesFromManifest: %v", err)
 	}
 	if err := g.Run("add", "-A"); err != nil {
@@ -289,6 +335,68 @@ func TestIncrementalImportFromGit(t *testing.T) {
 	if freshCfg.CurrentSnapshotID == snapID {
 		t.Fatalf("expected snapshot to change")
 	}
+	entries, err := config.ReadUndoStackAt(wsRoot)
+	if err != nil {
+		t.Fatalf("ReadUndoStackAt: %v", err)
+	}
+	if len(entries) != 1 || entries[0].SnapshotID != snapID || entries[0].ResultSnapshotID != freshCfg.CurrentSnapshotID {
+		t.Fatalf("expected an undo point back to %s, got %+v", snapID, entries)
+	}
+}
+
+func TestIncrementalImportFromGitDepth(t *testing.T) {
+	projectRoot, wsRoot, snapID, commitSHA := setupProjectWithExport(t, "proj-depth", "main")
+
+	sha1 := addGitCommit(t, projectRoot, "main", "one.txt", "1", "commit one", commitSHA)
+	sha2 := addGitCommit(t, projectRoot, "main", "two.txt", "2", "commit two", sha1)
+	sha3 := addGitCommit(t, projectRoot, "main", "three.txt", "3", "commit three", sha2)
+
+	result, err := IncrementalImportFromGitDepth(projectRoot, 2)
+	if err != nil {
+		t.Fatalf("IncrementalImportFromGitDepth: %v", err)
+	}
+	if result.NewSnapshots != 2 {
+		t.Fatalf("expected 2 new snapshots, got %d", result.NewSnapshots)
+	}
+
+	mapping, err := gitstore.LoadGitMapping(filepath.Join(projectRoot, ".fst"))
+	if err != nil {
+		t.Fatalf("LoadGitMapping: %v", err)
+	}
+	mapped := make(map[string]string, len(mapping.Snapshots))
+	for snap, sha := range mapping.Snapshots {
+		mapped[sha] = snap
+	}
+	if _, ok := mapped[sha1]; ok {
+		t.Fatalf("expected oldest new commit to stay unimported")
+	}
+	tipSnap, ok := mapped[sha3]
+	if !ok {
+		t.Fatalf("expected branch tip to be mapped")
+	}
+	s := store.OpenAt(projectRoot)
+	tipMeta, err := s.LoadSnapshotMeta(tipSnap)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if len(tipMeta.ParentSnapshotIDs) != 1 || tipMeta.ParentSnapshotIDs[0] != mapped[sha2] {
+		t.Fatalf("expected tip to be parented on commit two, got %v", tipMeta.ParentSnapshotIDs)
+	}
+	oldestMeta, err := s.LoadSnapshotMeta(mapped[sha2])
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if len(oldestMeta.ParentSnapshotIDs) != 1 || oldestMeta.ParentSnapshotIDs[0] != snapID {
+		t.Fatalf("expected oldest imported snapshot to be parented on the previous tip %s, got %v", snapID, oldestMeta.ParentSnapshotIDs)
+	}
+
+	freshCfg, err := config.LoadAt(wsRoot)
+	if err != nil {
+		t.Fatalf("LoadAt: %v", err)
+	}
+	if freshCfg.CurrentSnapshotID != tipSnap || tipSnap == snapID {
+		t.Fatalf("expected head at tip snapshot %s, got %s", tipSnap, freshCfg.CurrentSnapshotID)
+	}
 }
 
 func TestIncrementalImportSkipsKnown(t *testing.T) {
@@ -362,3 +470,70 @@ func TestIncrementalImportDivergence(t *testing.T) {
 		t.Fatalf("expected head to stay at snap-B, got %s", freshCfg.CurrentSnapshotID)
 	}
 }
+
+func TestPlanSync(t *testing.T) {
+	projectRoot, wsRoot, snapA, commitSHA := setupProjectWithExport(t, "proj-plan", "main")
+
+	// A local snapshot that has not been exported yet
+	s := store.OpenAt(projectRoot)
+	wsCfg, _ := config.LoadAt(wsRoot)
+	if err := os.WriteFile(filepath.Join(wsRoot, "test.txt"), []byte("local change"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	snapB, err := gitstore.CreateImportedSnapshot(s, wsRoot, wsCfg, []string{snapA}, "local work",
+		time.Now().UTC().Format(time.RFC3339), "Test", "test@test.com", "")
+	if err != nil {
+		t.Fatalf("CreateImportedSnapshot: %v", err)
+	}
+	if err := s.UpdateWorkspaceHead(wsCfg.WorkspaceID, snapB); err != nil {
+		t.Fatalf("UpdateWorkspaceHead: %v", err)
+	}
+
+	plan, err := PlanSync(projectRoot, "")
+	if err != nil {
+		t.Fatalf("PlanSync: %v", err)
+	}
+	if plan.NewCommits != 1 || len(plan.Branches) != 1 {
+		t.Fatalf("expected 1 new commit on 1 branch, got %+v", plan)
+	}
+	if bp := plan.Branches[0]; bp.Branch != "main" || bp.NewCommits != 1 || bp.NeedsPush || bp.RemoteAhead {
+		t.Fatalf("unexpected plan without remote: %+v", bp)
+	}
+
+	// Never fetched: the branch has to be pushed
+	plan, err = PlanSync(projectRoot, "origin")
+	if err != nil {
+		t.Fatalf("PlanSync: %v", err)
+	}
+	if bp := plan.Branches[0]; !bp.NeedsPush || bp.RemoteAhead {
+		t.Fatalf("expected push needed without remote-tracking ref, got %+v", bp)
+	}
+
+	// Last fetch saw a remote commit the local branch lacks
+	remoteSHA := addGitCommit(t, projectRoot, "main", "remote.txt", "remote change", "remote commit", commitSHA)
+	runGit(t, projectRoot, "update-ref", "refs/remotes/origin/main", remoteSHA)
+	runGit(t, projectRoot, "update-ref", "refs/heads/main", commitSHA)
+	plan, err = PlanSync(projectRoot, "origin")
+	if err != nil {
+		t.Fatalf("PlanSync: %v", err)
+	}
+	if bp := plan.Branches[0]; !bp.NeedsPush || !bp.RemoteAhead {
+		t.Fatalf("expected push needed and remote ahead, got %+v", bp)
+	}
+
+	// A dry-run sync neither exports nor records a sync
+	b := &GitHubBackend{Remote: "origin", ExportGit: func(string, bool, bool) error {
+		t.Fatal("dry run must not export")
+		return nil
+	}}
+	if err := b.Sync(projectRoot, &SyncOptions{DryRun: true}); err != nil {
+		t.Fatalf("Sync dry run: %v", err)
+	}
+	cfg, err := config.LoadProjectConfigAt(projectRoot)
+	if err != nil {
+		t.Fatalf("LoadProjectConfigAt: %v", err)
+	}
+	if cfg.LastSync != nil {
+		t.Fatalf("dry run recorded a sync: %+v", cfg.LastSync)
+	}
+}
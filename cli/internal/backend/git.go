@@ -8,7 +8,9 @@ type GitBackend struct {
 func (b *GitBackend) Type() string { return "git" }
 
 func (b *GitBackend) Push(projectRoot string) error {
-	return b.ExportGit(projectRoot, false, false)
+	return recordSync(projectRoot, "push", func() error {
+		return b.ExportGit(projectRoot, false, false)
+	})
 }
 
 func (b *GitBackend) Pull(projectRoot string) error {
@@ -16,5 +18,10 @@ func (b *GitBackend) Pull(projectRoot string) error {
 }
 
 func (b *GitBackend) Sync(projectRoot string, opts *SyncOptions) error {
-	return b.Push(projectRoot)
+	if ok, err := dryRun(projectRoot, "", opts); ok {
+		return err
+	}
+	return recordSync(projectRoot, "sync", func() error {
+		return b.ExportGit(projectRoot, false, false)
+	})
 }
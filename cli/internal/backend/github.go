@@ -21,11 +21,18 @@ type GitHubBackend struct {
 	Repo      string // "owner/repo"
 	Remote    string // git remote name
 	ExportGit ExportFunc
+	// ImportDepth limits how many of the most recent new commits per
+	// branch are imported as snapshots; zero imports all of them.
+	ImportDepth int
 }
 
 func (b *GitHubBackend) Type() string { return "github" }
 
 func (b *GitHubBackend) Push(projectRoot string) error {
+	return recordSync(projectRoot, "push", func() error { return b.push(projectRoot) })
+}
+
+func (b *GitHubBackend) push(projectRoot string) error {
 	if err := b.ExportGit(projectRoot, false, false); err != nil {
 		return err
 	}
@@ -33,6 +40,13 @@ func (b *GitHubBackend) Push(projectRoot string) error {
 }
 
 func (b *GitHubBackend) Sync(projectRoot string, opts *SyncOptions) error {
+	if ok, err := dryRun(projectRoot, b.Remote, opts); ok {
+		return err
+	}
+	return recordSync(projectRoot, "sync", func() error { return b.sync(projectRoot, opts) })
+}
+
+func (b *GitHubBackend) sync(projectRoot string, opts *SyncOptions) error {
 	// Export any new local snapshots
 	if err := b.ExportGit(projectRoot, false, false); err != nil {
 		return err
@@ -60,7 +74,7 @@ func (b *GitHubBackend) Sync(projectRoot string, opts *SyncOptions) error {
 		return fmt.Errorf("failed to fast-forward branches: %w", err)
 	}
 
-	result, err := IncrementalImportFromGit(projectRoot)
+	result, err := IncrementalImportFromGitDepth(projectRoot, b.ImportDepth)
 	if err != nil {
 		return fmt.Errorf("failed to import remote changes: %w", err)
 	}
@@ -71,6 +85,9 @@ func (b *GitHubBackend) Sync(projectRoot string, opts *SyncOptions) error {
 			return fmt.Errorf("workspace '%s' has diverged from remote; run 'fst sync' interactively to resolve", div.WorkspaceName)
 		}
 		mergedID, mergeErr := opts.OnDivergence(div)
+		if errors.Is(mergeErr, ErrMergePending) {
+			return fmt.Errorf("workspace '%s' has unresolved conflicts; resolve them, run 'fst snapshot', then 'fst sync' again", div.WorkspaceName)
+		}
 		if mergeErr != nil {
 			return fmt.Errorf("failed to merge diverged workspace '%s': %w", div.WorkspaceName, mergeErr)
 		}
@@ -111,7 +128,7 @@ func (b *GitHubBackend) Pull(projectRoot string) error {
 		return fmt.Errorf("failed to fast-forward branches: %w", err)
 	}
 
-	_, err := IncrementalImportFromGit(projectRoot)
+	_, err := IncrementalImportFromGitDepth(projectRoot, b.ImportDepth)
 	return err
 }
 
@@ -192,9 +209,30 @@ type ImportResult struct {
 	Diverged     []DivergenceInfo
 }
 
+// branchImport is the planned import of one exported branch.
+type branchImport struct {
+	ws         gitstore.ExportWorkspaceMeta
+	commits    []string // every commit of the branch, oldest first
+	newCommits []string // the commits to import, oldest first
+	// graft is the snapshot of the branch's previously mapped tip when
+	// depth skipped commits; imported commits whose parents were all
+	// skipped are parented on it so the chain stays connected.
+	graft string
+}
+
 // IncrementalImportFromGit imports new git commits that aren't yet mapped to snapshots.
 // Returns divergence info for workspaces where the local head has drifted.
 func IncrementalImportFromGit(projectRoot string) (*ImportResult, error) {
+	return IncrementalImportFromGitDepth(projectRoot, 0)
+}
+
+// IncrementalImportFromGitDepth is IncrementalImportFromGit limited to the
+// depth most recent new commits of each branch (all of them if depth <= 0).
+// Older commits are left unimported. On a branch that was imported before,
+// imported commits whose parents were all skipped are parented on the
+// previously mapped tip instead, so history stays connected; on a first
+// import the oldest kept commit becomes a root.
+func IncrementalImportFromGitDepth(projectRoot string, depth int) (*ImportResult, error) {
 	result := &ImportResult{}
 
 	configDir := filepath.Join(projectRoot, ".fst")
@@ -254,6 +292,11 @@ func IncrementalImportFromGit(projectRoot string) (*ImportResult, error) {
 	importIndex := filepath.Join(importIndexDir, "index")
 	importGit := gitutil.NewEnv(projectRoot, workTempDir, importIndex)
 
+	// Plan every branch first so the commits to import are known up front.
+	// A commit new on several branches is imported once, with the first.
+	var plans []branchImport
+	var allNew []string
+	planned := make(map[string]bool)
 	for _, ws := range meta.Workspaces {
 		if ws.Branch == "" {
 			continue
@@ -267,7 +310,7 @@ func IncrementalImportFromGit(projectRoot string) (*ImportResult, error) {
 		// Filter to only new commits
 		var newCommits []string
 		for _, commit := range commits {
-			if _, known := commitToSnapshot[commit]; !known {
+			if _, known := commitToSnapshot[commit]; !known && !planned[commit] {
 				newCommits = append(newCommits, commit)
 			}
 		}
@@ -276,6 +319,40 @@ func IncrementalImportFromGit(projectRoot string) (*ImportResult, error) {
 			continue
 		}
 
+		plan := branchImport{ws: ws, commits: commits}
+		if depth > 0 && len(newCommits) > depth {
+			skipped := len(newCommits) - depth
+			newCommits = newCommits[skipped:]
+			fmt.Printf("Skipping %d older commits from branch %s (depth %d)\n", skipped, ws.Branch, depth)
+			for i := len(commits) - 1; i >= 0; i-- {
+				if snap, known := originalCommitToSnapshot[commits[i]]; known {
+					plan.graft = snap
+					break
+				}
+			}
+		}
+		for _, commit := range newCommits {
+			planned[commit] = true
+		}
+		plan.newCommits = newCommits
+		allNew = append(allNew, newCommits...)
+		plans = append(plans, plan)
+	}
+
+	// Read the metadata of all new commits concurrently rather than one
+	// git process at a time during the import.
+	infos, err := gitutil.ReadCommitInfos(importGit, allNew)
+	if err != nil {
+		return nil, err
+	}
+
+	// Blobs known to be stored, shared by every commit of the import so
+	// each blob is checked against the store only once.
+	storedBlobs := make(map[string]bool)
+
+	for _, plan := range plans {
+		ws, commits, newCommits := plan.ws, plan.commits, plan.newCommits
+
 		fmt.Printf("Importing %d new commits from branch %s\n", len(newCommits), ws.Branch)
 
 		wsName := ws.WorkspaceName
@@ -291,10 +368,7 @@ func IncrementalImportFromGit(projectRoot string) (*ImportResult, error) {
 		}
 
 		for _, commit := range newCommits {
-			info, err := gitutil.ReadCommitInfo(importGit, commit)
-			if err != nil {
-				return nil, err
-			}
+			info := infos[commit]
 			if err := gitutil.CheckoutTree(importGit, commit); err != nil {
 				return nil, err
 			}
@@ -306,13 +380,16 @@ func IncrementalImportFromGit(projectRoot string) (*ImportResult, error) {
 					parentSnapshots = append(parentSnapshots, snapID)
 				}
 			}
+			if len(parentSnapshots) == 0 && len(info.Parents) > 0 && plan.graft != "" {
+				parentSnapshots = append(parentSnapshots, plan.graft)
+			}
 
 			agentName := ""
 			if strings.HasSuffix(strings.ToLower(info.AuthorEmail), "@fastest.local") {
 				agentName = info.AuthorName
 			}
 
-			snapshotID, err := gitstore.CreateImportedSnapshot(s, workTempDir, wsCfg, parentSnapshots, info.Subject, info.AuthorDate, info.AuthorName, info.AuthorEmail, agentName)
+			snapshotID, err := gitstore.CreateImportedSnapshotWithBlobs(s, workTempDir, wsCfg, parentSnapshots, info.Subject, info.AuthorDate, info.AuthorName, info.AuthorEmail, agentName, storedBlobs)
 			if err != nil {
 				return nil, err
 			}
@@ -337,6 +414,11 @@ func IncrementalImportFromGit(projectRoot string) (*ImportResult, error) {
 			freshCfg = wsCfg
 		}
 		currentHead := freshCfg.CurrentSnapshotID
+		if wsCfg.CurrentSnapshotID == "" {
+			// The workspace had no head before this import; LoadAt infers
+			// one from the snapshots just imported, which is not local drift.
+			currentHead = ""
+		}
 
 		// Find the previous tip (the last commit we already knew about)
 		previousTipSnap := ""
@@ -356,6 +438,18 @@ func IncrementalImportFromGit(projectRoot string) (*ImportResult, error) {
 			if err := config.SaveAt(wsRoot, freshCfg); err != nil {
 				return nil, fmt.Errorf("failed to save workspace config: %w", err)
 			}
+			if currentHead != "" && currentHead != tipSnapID {
+				// 'fst undo' moves the head back to before the import.
+				if err := config.PushUndoAt(wsRoot, config.UndoEntry{
+					Operation:        "sync",
+					Description:      "import of " + wsName + " from remote",
+					SnapshotID:       currentHead,
+					ResultSnapshotID: tipSnapID,
+					CreatedAt:        time.Now().UTC(),
+				}); err != nil {
+					fmt.Printf("  warning: could not record undo point for %s: %v\n", wsName, err)
+				}
+			}
 			_ = s.RegisterWorkspace(store.WorkspaceInfo{
 				WorkspaceID:       freshCfg.WorkspaceID,
 				WorkspaceName:     freshCfg.WorkspaceName,
@@ -0,0 +1,169 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ankitiscracked/fastest/cli/internal/gitstore"
+	"github.com/ankitiscracked/fastest/cli/internal/gitutil"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+// SyncPlan describes what a sync would do without doing it.
+type SyncPlan struct {
+	Remote     string // remote a sync pushes to; empty if the backend has none
+	Branches   []BranchPlan
+	NewCommits int // distinct snapshots not yet exported, across all branches
+}
+
+// BranchPlan describes the pending export and push of one workspace branch.
+type BranchPlan struct {
+	Branch     string
+	NewCommits int // snapshots in the workspace history not yet exported
+	// NeedsPush is set when the branch would differ from the last fetched
+	// state of the remote branch after exporting.
+	NeedsPush bool
+	// RemoteAhead is set when the last fetched remote branch has commits the
+	// local branch lacks, so a sync would have to fetch, import and merge.
+	RemoteAhead bool
+}
+
+// PlanSync reports, per workspace branch, how many snapshots an export would
+// turn into new commits and whether a push to remote would be needed. It
+// reads only local state: remote-tracking refs are compared as of the last
+// fetch, and nothing is exported, fetched or pushed. remote may be empty for
+// backends that never push.
+func PlanSync(projectRoot, remote string) (*SyncPlan, error) {
+	mapping, err := gitstore.LoadGitMapping(filepath.Join(projectRoot, ".fst"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load git mapping: %w", err)
+	}
+
+	s := store.OpenAt(projectRoot)
+	workspaces, err := s.ListWorkspaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	hasRepo := true
+	if _, err := os.Stat(filepath.Join(projectRoot, ".git")); os.IsNotExist(err) {
+		hasRepo = false
+	}
+	git := gitutil.NewEnv(projectRoot, projectRoot, filepath.Join(projectRoot, ".git", "index"))
+	exported := func(snapshotID string) bool {
+		sha, ok := mapping.Snapshots[snapshotID]
+		return ok && hasRepo && gitutil.CommitExists(git, sha)
+	}
+
+	// Workspaces created with --track export to their tracked branch.
+	var exportMeta *gitstore.ExportMeta
+	if hasRepo {
+		exportMeta, err = gitstore.LoadExportMetadataFromRepo(projectRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load export metadata: %w", err)
+		}
+	}
+
+	plan := &SyncPlan{Remote: remote}
+	pending := make(map[string]bool)
+	for _, ws := range workspaces {
+		if ws.CurrentSnapshotID == "" {
+			continue
+		}
+		chain, err := s.WalkSnapshotDAG(ws.CurrentSnapshotID, store.WalkOpts{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk history of workspace '%s': %w", ws.WorkspaceName, err)
+		}
+
+		branch := ws.WorkspaceName
+		if tracked := gitstore.TrackedBranch(exportMeta, ws.WorkspaceID); tracked != "" {
+			branch = tracked
+		}
+		bp := BranchPlan{Branch: branch}
+		for _, snap := range chain {
+			if !exported(snap.ID) {
+				bp.NewCommits++
+				pending[snap.ID] = true
+			}
+		}
+
+		if remote != "" {
+			bp.NeedsPush = bp.NewCommits > 0
+			if hasRepo {
+				bp.RemoteAhead = remoteAhead(git, remote, branch, &bp.NeedsPush)
+			} else {
+				bp.NeedsPush = true
+			}
+		}
+		plan.Branches = append(plan.Branches, bp)
+	}
+	plan.NewCommits = len(pending)
+	return plan, nil
+}
+
+// dryRun prints the sync plan if opts asks for a dry run and reports
+// whether it did.
+func dryRun(projectRoot, remote string, opts *SyncOptions) (bool, error) {
+	if opts == nil || !opts.DryRun {
+		return false, nil
+	}
+	plan, err := PlanSync(projectRoot, remote)
+	if err != nil {
+		return true, err
+	}
+	printSyncPlan(plan)
+	return true, nil
+}
+
+// remoteAhead compares branch with its remote-tracking ref as of the last
+// fetch. It reports whether the remote has commits the branch lacks, and sets
+// *needsPush when the branch has commits the remote lacks.
+func remoteAhead(git gitutil.Env, remote, branch string, needsPush *bool) bool {
+	remoteSHA, err := gitutil.RefSHA(git, "refs/remotes/"+remote+"/"+branch)
+	if err != nil {
+		*needsPush = true
+		return false
+	}
+	localSHA, err := gitutil.RefSHA(git, "refs/heads/"+branch)
+	if err != nil {
+		*needsPush = true
+		return true
+	}
+	if localSHA == remoteSHA {
+		return false
+	}
+	if !gitutil.IsAncestor(git, localSHA, remoteSHA) {
+		*needsPush = true
+	}
+	return !gitutil.IsAncestor(git, remoteSHA, localSHA)
+}
+
+// printSyncPlan reports plan for 'fst sync --dry-run'.
+func printSyncPlan(plan *SyncPlan) {
+	fmt.Println("Dry run: nothing will be exported, fetched or pushed.")
+	if len(plan.Branches) == 0 {
+		fmt.Println("No workspaces with snapshots to export.")
+		return
+	}
+	for _, bp := range plan.Branches {
+		line := fmt.Sprintf("  %s: %d new commits", bp.Branch, bp.NewCommits)
+		if plan.Remote != "" {
+			if bp.NeedsPush {
+				line += ", push needed"
+			} else {
+				line += ", nothing to push"
+			}
+			if bp.RemoteAhead {
+				line += ", remote has changes to import"
+			}
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("Would export %d new commits\n", plan.NewCommits)
+	if plan.Remote == "" {
+		return
+	}
+	fmt.Printf("Remote '%s' was not fetched; if it has moved since the last fetch,\n", plan.Remote)
+	fmt.Println("the push would be rejected and sync would fetch, import and merge remote changes.")
+}
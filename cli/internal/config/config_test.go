@@ -162,3 +162,89 @@ func TestGetLatestSnapshotIDAt(t *testing.T) {
 		t.Fatalf("latest mismatch: %s", latest)
 	}
 }
+
+func TestMergeConfigRegenerateCommand(t *testing.T) {
+	cfg := &MergeConfig{Regenerate: map[string]string{
+		"package-lock.json": "npm install",
+		"go.sum":            "go mod tidy",
+		"web/*.lock":        "yarn",
+	}}
+
+	cases := map[string]string{
+		"package-lock.json":          "npm install",
+		"frontend/package-lock.json": "npm install",
+		"go.sum":                     "go mod tidy",
+		"web/yarn.lock":              "yarn",
+	}
+	for p, want := range cases {
+		got, ok := cfg.RegenerateCommand(p)
+		if !ok || got != want {
+			t.Fatalf("RegenerateCommand(%q) = %q, %v; want %q", p, got, ok, want)
+		}
+	}
+	if _, ok := cfg.RegenerateCommand("api/yarn.lock"); ok {
+		t.Fatalf("expected no match for api/yarn.lock")
+	}
+	var nilCfg *MergeConfig
+	if _, ok := nilCfg.RegenerateCommand("go.sum"); ok {
+		t.Fatalf("expected no match on nil config")
+	}
+}
+
+func TestSnapshotConfigValidateMessage(t *testing.T) {
+	cfg := &SnapshotConfig{MessagePattern: `^(feat|fix|chore)(\(.+\))?: .+`}
+	if err := cfg.ValidateMessage("feat(cli): add flag"); err != nil {
+		t.Fatalf("expected conventional message to pass: %v", err)
+	}
+	err := cfg.ValidateMessage("added a flag")
+	if err == nil || !strings.Contains(err.Error(), "--no-verify") {
+		t.Fatalf("expected format error mentioning --no-verify, got %v", err)
+	}
+
+	var nilCfg *SnapshotConfig
+	if err := nilCfg.ValidateMessage("anything"); err != nil {
+		t.Fatalf("expected nil config to accept any message: %v", err)
+	}
+	if err := (&SnapshotConfig{MessagePattern: "("}).ValidateMessage("x"); err == nil {
+		t.Fatalf("expected invalid pattern to be reported")
+	}
+}
+
+func TestSnapshotConfigLargeFilePolicy(t *testing.T) {
+	var nilCfg *SnapshotConfig
+	if threshold, refuse, err := nilCfg.LargeFilePolicy(); err != nil || threshold != DefaultLargeFileThreshold || refuse {
+		t.Fatalf("nil config: got %d, %v, %v", threshold, refuse, err)
+	}
+	if threshold, refuse, err := (&SnapshotConfig{LargeFileThreshold: 1024, LargeFiles: "refuse"}).LargeFilePolicy(); err != nil || threshold != 1024 || !refuse {
+		t.Fatalf("refuse config: got %d, %v, %v", threshold, refuse, err)
+	}
+	if threshold, refuse, err := (&SnapshotConfig{LargeFileThreshold: -1, LargeFiles: "refuse"}).LargeFilePolicy(); err != nil || threshold != 0 || refuse {
+		t.Fatalf("disabled config: got %d, %v, %v", threshold, refuse, err)
+	}
+	if _, _, err := (&SnapshotConfig{LargeFiles: "ignore"}).LargeFilePolicy(); err == nil {
+		t.Fatalf("expected invalid large_files value to be reported")
+	}
+}
+
+func TestMergeConfigAttribute(t *testing.T) {
+	cfg := &MergeConfig{Attributes: map[string]string{
+		"generated/*":   "theirs",
+		"local-notes/*": "ours",
+		"*.pb.go":       "theirs",
+	}}
+
+	cases := map[string]string{
+		"generated/api.ts":  "theirs",
+		"local-notes/me.md": "ours",
+		"pkg/foo.pb.go":     "theirs",
+	}
+	for p, want := range cases {
+		got, ok := cfg.Attribute(p)
+		if !ok || got != want {
+			t.Fatalf("Attribute(%q) = %q, %v; want %q", p, got, ok, want)
+		}
+	}
+	if _, ok := cfg.Attribute("src/main.go"); ok {
+		t.Fatalf("expected no attribute for src/main.go")
+	}
+}
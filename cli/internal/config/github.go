@@ -0,0 +1,64 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const githubFileName = "github.json"
+
+// GitHub protocols for the remote URLs of new github backends.
+const (
+	GitHubProtocolHTTPS = "https"
+	GitHubProtocolSSH   = "ssh"
+)
+
+// GitHubPrefs holds global preferences for GitHub remotes.
+type GitHubPrefs struct {
+	Protocol string `json:"protocol,omitempty"` // "https" (default) or "ssh"
+}
+
+// LoadGlobalGitHubPrefs reads ~/.config/fst/github.json.
+func LoadGlobalGitHubPrefs() (*GitHubPrefs, error) {
+	configDir, err := GetGlobalConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(configDir, githubFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &GitHubPrefs{}, nil
+		}
+		return nil, fmt.Errorf("failed to read github config: %w", err)
+	}
+	var p GitHubPrefs
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse github config: %w", err)
+	}
+	return &p, nil
+}
+
+// SaveGlobalGitHubPrefs writes ~/.config/fst/github.json.
+func SaveGlobalGitHubPrefs(p *GitHubPrefs) error {
+	configDir, err := GetGlobalConfigDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal github config: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, githubFileName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write github config: %w", err)
+	}
+	return nil
+}
+
+// PrefersGitHubSSH reports whether new github backends should use SSH
+// remote URLs. Unreadable preferences count as HTTPS.
+func PrefersGitHubSSH() bool {
+	p, err := LoadGlobalGitHubPrefs()
+	return err == nil && p.Protocol == GitHubProtocolSSH
+}
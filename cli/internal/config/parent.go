@@ -5,7 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/ankitiscracked/fastest/cli/internal/store"
 )
@@ -25,14 +29,194 @@ type BackendConfig struct {
 }
 
 type ProjectConfig struct {
-	Type             string         `json:"type"`
-	ProjectID        string         `json:"project_id"`
-	ProjectName      string         `json:"project_name"`
-	CreatedAt        string         `json:"created_at"`
-	BaseSnapshotID   string         `json:"base_snapshot_id,omitempty"`
-	BaseWorkspaceID  string         `json:"base_workspace_id,omitempty"`
-	MainWorkspaceID  string         `json:"main_workspace_id,omitempty"`
-	Backend          *BackendConfig `json:"backend,omitempty"`
+	Type            string          `json:"type"`
+	ProjectID       string          `json:"project_id"`
+	ProjectName     string          `json:"project_name"`
+	CreatedAt       string          `json:"created_at"`
+	BaseSnapshotID  string          `json:"base_snapshot_id,omitempty"`
+	BaseWorkspaceID string          `json:"base_workspace_id,omitempty"`
+	MainWorkspaceID string          `json:"main_workspace_id,omitempty"`
+	Backend         *BackendConfig  `json:"backend,omitempty"`
+	Merge           *MergeConfig    `json:"merge,omitempty"`
+	Snapshot        *SnapshotConfig `json:"snapshot,omitempty"`
+	LastSync        *SyncRecord     `json:"last_sync,omitempty"`
+	Commit          *CommitConfig   `json:"commit,omitempty"`
+}
+
+// CommitConfig configures the git commits created by export.
+type CommitConfig struct {
+	// Sign signs every exported commit, as with `fst git export --sign`.
+	Sign bool `json:"sign,omitempty"`
+	// SigningKey is the gpg key ID (or ssh key, with gpg.format=ssh) to sign
+	// with. Empty uses git's user.signingkey.
+	SigningKey string `json:"signing_key,omitempty"`
+	// AuthorMap is the path (relative to the project root) of a file
+	// mapping agent names to git identities, as with
+	// `fst git export --author-map`.
+	AuthorMap string `json:"author_map,omitempty"`
+	// MessagePrefix is prepended to every exported commit message, as with
+	// `fst git export --message-prefix`.
+	MessagePrefix string `json:"message_prefix,omitempty"`
+}
+
+// SyncRecord is the outcome of the most recent backend push or sync.
+type SyncRecord struct {
+	Operation string `json:"operation"` // "push" or "sync"
+	Time      string `json:"time"`      // RFC3339
+	Success   bool   `json:"success"`
+	// Commits is the number of snapshots newly mapped to git commits
+	// (exported, or imported from the remote during a sync).
+	Commits int    `json:"commits"`
+	Error   string `json:"error,omitempty"`
+}
+
+// SnapshotConfig configures project-wide snapshot message conventions.
+type SnapshotConfig struct {
+	// MessageTemplate pre-fills the snapshot message editor. Lines starting
+	// with "#" are treated as comments and dropped from the saved message.
+	// "{changes}" expands to a summary of the workspace's changes and
+	// "{workspace}" to the workspace name.
+	MessageTemplate string `json:"message_template,omitempty"`
+	// MessagePattern is a regular expression every snapshot message must
+	// match (e.g. "^(feat|fix|chore)(\\(.+\\))?: .+").
+	MessagePattern string `json:"message_pattern,omitempty"`
+	// LargeFileThreshold is the size in bytes above which a file is reported
+	// as large. Zero uses DefaultLargeFileThreshold; a negative value turns
+	// the check off.
+	LargeFileThreshold int64 `json:"large_file_threshold,omitempty"`
+	// LargeFiles is what happens to large files: "warn" (default) snapshots
+	// them with a warning, "refuse" fails the snapshot.
+	LargeFiles string `json:"large_files,omitempty"`
+	// IgnoreModeChanges treats files whose content is unchanged but whose
+	// permission bits changed as unchanged, in drift and in snapshots. By
+	// default mode changes are tracked.
+	IgnoreModeChanges bool `json:"ignore_mode_changes,omitempty"`
+}
+
+// IgnoresModeChanges reports whether mode-only changes are ignored. A nil
+// config tracks them.
+func (c *SnapshotConfig) IgnoresModeChanges() bool {
+	return c != nil && c.IgnoreModeChanges
+}
+
+// DefaultLargeFileThreshold matches GitHub's per-file limit, so large-file
+// warnings also flag files that would break a GitHub backend push.
+const DefaultLargeFileThreshold = 100 << 20
+
+// LargeFilePolicy returns the effective large-file threshold (zero when the
+// check is off) and whether large files are refused. A nil config warns at
+// DefaultLargeFileThreshold.
+func (c *SnapshotConfig) LargeFilePolicy() (int64, bool, error) {
+	if c == nil {
+		return DefaultLargeFileThreshold, false, nil
+	}
+	threshold := c.LargeFileThreshold
+	switch {
+	case threshold == 0:
+		threshold = DefaultLargeFileThreshold
+	case threshold < 0:
+		threshold = 0
+	}
+	switch c.LargeFiles {
+	case "", "warn":
+		return threshold, false, nil
+	case "refuse":
+		return threshold, threshold > 0, nil
+	default:
+		return 0, false, fmt.Errorf("invalid snapshot.large_files %q (expected: warn, refuse)", c.LargeFiles)
+	}
+}
+
+// ValidateMessage checks message against MessagePattern. A nil config or an
+// empty pattern accepts every message.
+func (c *SnapshotConfig) ValidateMessage(message string) error {
+	if c == nil || c.MessagePattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(c.MessagePattern)
+	if err != nil {
+		return fmt.Errorf("invalid snapshot.message_pattern %q: %w", c.MessagePattern, err)
+	}
+	if !re.MatchString(message) {
+		return fmt.Errorf("snapshot message %q does not match the project's required format %q (use --no-verify to skip)", message, c.MessagePattern)
+	}
+	return nil
+}
+
+// MergeConfig configures project-wide merge behavior.
+type MergeConfig struct {
+	// Regenerate maps a glob to a command that regenerates matching files
+	// (e.g. "package-lock.json" -> "npm install"). Conflicts in matching
+	// files are resolved by taking RegenerateSide and then running the
+	// command from the workspace root instead of writing conflict markers.
+	Regenerate map[string]string `json:"regenerate,omitempty"`
+	// RegenerateSide is the version kept before regenerating: "ours"
+	// (default) or "theirs".
+	RegenerateSide string `json:"regenerate_side,omitempty"`
+	// Attributes maps a glob to a conflict strategy ("ours", "theirs" or
+	// "manual"), like .gitattributes merge drivers. Conflicts in matching
+	// files use that strategy regardless of the merge's global mode.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// AbortIfDirty makes merges refuse to start while the target workspace
+	// has uncommitted changes, as if --abort-if-dirty were always passed.
+	AbortIfDirty bool `json:"abort_if_dirty,omitempty"`
+	// ApplyOrder is the default order in which files from the source are
+	// written: "path" (default) or "deps", as for --apply-order.
+	ApplyOrder string `json:"apply_order,omitempty"`
+	// ApplyPriority lists globs whose files are written first, in this
+	// order, when the apply order is "deps" (e.g. config before code).
+	ApplyPriority []string `json:"apply_priority,omitempty"`
+	// AgentModel is the default model hint for agent conflict resolution,
+	// as for --agent-model.
+	AgentModel string `json:"agent_model,omitempty"`
+}
+
+// RegenerateCommand returns the regeneration command configured for relPath.
+// Patterns without a slash match the file's base name; patterns are tried
+// in sorted order so the result is deterministic.
+func (m *MergeConfig) RegenerateCommand(relPath string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	return matchPathGlobs(m.Regenerate, relPath)
+}
+
+// Attribute returns the conflict strategy configured for relPath in
+// merge.attributes, using the same matching rules as RegenerateCommand.
+func (m *MergeConfig) Attribute(relPath string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	return matchPathGlobs(m.Attributes, relPath)
+}
+
+func matchPathGlobs(globs map[string]string, relPath string) (string, bool) {
+	if len(globs) == 0 {
+		return "", false
+	}
+	patterns := make([]string, 0, len(globs))
+	for pattern := range globs {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		target := relPath
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(relPath)
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return globs[pattern], true
+		}
+	}
+	return "", false
+}
+
+// ApplyPriorityGlobs returns merge.apply_priority, or nil for a nil config.
+func (m *MergeConfig) ApplyPriorityGlobs() []string {
+	if m == nil {
+		return nil
+	}
+	return m.ApplyPriority
 }
 
 // BackendType returns the configured backend type, or empty string if none.
@@ -88,6 +272,17 @@ func SaveProjectConfigAt(root string, cfg *ProjectConfig) error {
 	return store.AtomicWriteFile(path, data, 0644)
 }
 
+// SaveLastSyncAt stores rec as the project's last-sync record, leaving the
+// rest of the project config unchanged.
+func SaveLastSyncAt(root string, rec *SyncRecord) error {
+	cfg, err := LoadProjectConfigAt(root)
+	if err != nil {
+		return err
+	}
+	cfg.LastSync = rec
+	return SaveProjectConfigAt(root, cfg)
+}
+
 // isProjectRoot checks if dir contains a .fst/config.json with type "project".
 func isProjectRoot(dir string) bool {
 	data, err := os.ReadFile(filepath.Join(dir, ConfigDirName, ConfigFileName))
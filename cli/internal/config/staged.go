@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+// stagedFileName lists the workspace's staged paths, one per line.
+const stagedFileName = "STAGED"
+
+// ReadStagedPathsAt returns the staged paths of the workspace at root in
+// sorted order, or nil when nothing is staged.
+func ReadStagedPathsAt(root string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(root, ConfigDirName, stagedFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return normalizeStagedPaths(strings.Split(string(data), "\n")), nil
+}
+
+// WriteStagedPathsAt replaces the staged paths of the workspace at root.
+// An empty list clears the staging area.
+func WriteStagedPathsAt(root string, paths []string) error {
+	paths = normalizeStagedPaths(paths)
+	if len(paths) == 0 {
+		return ClearStagedPathsAt(root)
+	}
+	data := strings.Join(paths, "\n") + "\n"
+	return store.AtomicWriteFile(filepath.Join(root, ConfigDirName, stagedFileName), []byte(data), 0644)
+}
+
+// ClearStagedPathsAt empties the staging area of the workspace at root.
+func ClearStagedPathsAt(root string) error {
+	path := filepath.Join(root, ConfigDirName, stagedFileName)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func normalizeStagedPaths(paths []string) []string {
+	seen := make(map[string]struct{}, len(paths))
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+const undoFileName = "undo.json"
+
+// MaxUndoEntries is how many undo points a workspace keeps; older ones are
+// dropped as new ones are pushed.
+const MaxUndoEntries = 10
+
+// UndoEntry records the state of a workspace before a command changed it,
+// so 'fst undo' can return to it.
+type UndoEntry struct {
+	Operation   string `json:"operation"`   // e.g. "merge", "agent run"
+	Description string `json:"description"` // human-readable summary
+	SnapshotID  string `json:"snapshot_id"` // snapshot holding the prior state
+	// ResultSnapshotID is the head the command left the workspace at. Undo
+	// refuses to run without force once the head has moved past it, since
+	// that would abandon later snapshots. Empty for entries recorded
+	// before it was tracked.
+	ResultSnapshotID string    `json:"result_snapshot_id,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type undoMeta struct {
+	Entries []UndoEntry `json:"entries"`
+}
+
+// ReadUndoStackAt returns the undo points of a workspace, oldest first.
+func ReadUndoStackAt(root string) ([]UndoEntry, error) {
+	path := filepath.Join(root, ConfigDirName, undoFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var meta undoMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return meta.Entries, nil
+}
+
+// WriteUndoStackAt replaces the undo points of a workspace, keeping at most
+// the newest MaxUndoEntries.
+func WriteUndoStackAt(root string, entries []UndoEntry) error {
+	path := filepath.Join(root, ConfigDirName, undoFileName)
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if len(entries) > MaxUndoEntries {
+		entries = entries[len(entries)-MaxUndoEntries:]
+	}
+
+	data, err := json.MarshalIndent(undoMeta{Entries: entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.AtomicWriteFile(path, data, 0644)
+}
+
+// PushUndoAt adds an undo point to a workspace.
+func PushUndoAt(root string, entry UndoEntry) error {
+	entries, err := ReadUndoStackAt(root)
+	if err != nil {
+		return err
+	}
+	return WriteUndoStackAt(root, append(entries, entry))
+}
@@ -0,0 +1,115 @@
+package conflicts
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+// AnalysisCacheFileName is the name of the merge analysis cache stored in
+// a workspace's .fst/ directory.
+const AnalysisCacheFileName = "merge-analysis.json"
+
+// AnalysisCache holds the line-level analysis (MergeHunks regions) of the
+// conflicting files of one merge, so that a real merge run right after a
+// dry run does not analyse the same files again. It is keyed by the
+// manifest hashes of the merge's base, current and source snapshots, so a
+// pre-merge snapshot with the same contents as the dry run's head still
+// hits; each file entry is further keyed by its three contents, so an
+// entry is never reused for different input.
+type AnalysisCache struct {
+	BaseManifestHash    string                    `json:"base_manifest_hash"`
+	CurrentManifestHash string                    `json:"current_manifest_hash"`
+	SourceManifestHash  string                    `json:"source_manifest_hash"`
+	Files               map[string]cachedAnalysis `json:"files"`
+
+	hits int
+}
+
+type cachedAnalysis struct {
+	Key     string        `json:"key"`
+	Regions []MergeRegion `json:"regions"`
+}
+
+// AnalysisCachePath returns the path of the merge analysis cache for a
+// workspace root.
+func AnalysisCachePath(root string) string {
+	return filepath.Join(root, config.ConfigDirName, AnalysisCacheFileName)
+}
+
+// LoadAnalysisCache loads the merge analysis cache of the workspace at root
+// for the given manifest hashes. A missing or unreadable cache, or one
+// written for different manifests, yields an empty cache.
+func LoadAnalysisCache(root, baseHash, currentHash, sourceHash string) *AnalysisCache {
+	empty := &AnalysisCache{
+		BaseManifestHash:    baseHash,
+		CurrentManifestHash: currentHash,
+		SourceManifestHash:  sourceHash,
+		Files:               make(map[string]cachedAnalysis),
+	}
+	data, err := os.ReadFile(AnalysisCachePath(root))
+	if err != nil {
+		return empty
+	}
+	var c AnalysisCache
+	if err := json.Unmarshal(data, &c); err != nil {
+		return empty
+	}
+	if c.BaseManifestHash != baseHash || c.CurrentManifestHash != currentHash || c.SourceManifestHash != sourceHash || c.Files == nil {
+		return empty
+	}
+	return &c
+}
+
+// Save writes the cache to the workspace at root, replacing any previous one.
+func (c *AnalysisCache) Save(root string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return store.AtomicWriteFile(AnalysisCachePath(root), data, 0644)
+}
+
+// Regions returns MergeHunks(base, current, source) for path, from the cache
+// when it holds an entry for the same contents and computed (and cached)
+// otherwise. A nil cache always computes.
+func (c *AnalysisCache) Regions(path, base, current, source string) []MergeRegion {
+	if c == nil {
+		return MergeHunks(base, current, source)
+	}
+	key := contentKey(base, current, source)
+	if entry, ok := c.Files[path]; ok && entry.Key == key {
+		c.hits++
+		return entry.Regions
+	}
+	regions := MergeHunks(base, current, source)
+	c.Files[path] = cachedAnalysis{Key: key, Regions: regions}
+	return regions
+}
+
+// Hits returns how many Regions calls were answered from the cache.
+func (c *AnalysisCache) Hits() int {
+	if c == nil {
+		return 0
+	}
+	return c.hits
+}
+
+// contentKey hashes the three contents of a file, length-prefixed so that
+// different splits of the same bytes never collide.
+func contentKey(parts ...string) string {
+	h := sha256.New()
+	var n [8]byte
+	for _, p := range parts {
+		binary.BigEndian.PutUint64(n[:], uint64(len(p)))
+		h.Write(n[:])
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
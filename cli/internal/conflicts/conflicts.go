@@ -45,9 +45,9 @@ type BlobAccessor interface {
 	Get(hash string) (string, error)
 }
 
-// FileBlobAccessor reads blobs from the global cache
+// FileBlobAccessor reads blobs from a project blob store
 type FileBlobAccessor struct {
-	cacheDir string
+	blobs store.BlobStore
 }
 
 // NewFileBlobAccessor creates a blob accessor for the project blob store
@@ -56,15 +56,17 @@ func NewFileBlobAccessor() (*FileBlobAccessor, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &FileBlobAccessor{
-		cacheDir: blobDir,
-	}, nil
+	return NewBlobStoreAccessor(store.NewFSBlobStore(blobDir)), nil
+}
+
+// NewBlobStoreAccessor creates a blob accessor reading from blobs
+func NewBlobStoreAccessor(blobs store.BlobStore) *FileBlobAccessor {
+	return &FileBlobAccessor{blobs: blobs}
 }
 
-// Get retrieves file content by hash from the cache
+// Get retrieves file content by hash from the blob store
 func (a *FileBlobAccessor) Get(hash string) (string, error) {
-	path := filepath.Join(a.cacheDir, hash)
-	data, err := os.ReadFile(path)
+	data, err := a.blobs.Get(hash)
 	if err != nil {
 		return "", fmt.Errorf("blob not found: %s", hash)
 	}
@@ -97,10 +99,27 @@ func (a *FileSystemAccessor) Get(hash string) (string, error) {
 	return "", fmt.Errorf("file with hash %s not found", hash)
 }
 
+// Base is an explicit common ancestor for Detect, overriding the base
+// snapshot it would otherwise infer from the workspace config.
+type Base struct {
+	SnapshotID string
+	Manifest   *manifest.Manifest
+}
+
+// LoadBase loads the manifest of snapshotID as a Detect base.
+func LoadBase(root, snapshotID string) (*Base, error) {
+	m, err := loadManifestFromSnapshots(root, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base snapshot %s: %w", snapshotID, err)
+	}
+	return &Base{SnapshotID: snapshotID, Manifest: m}, nil
+}
+
 // Detect performs 3-way merge analysis to find git-style conflicts
 // between the current workspace and another workspace
-// Both workspaces must share a common base_snapshot_id for meaningful conflict detection
-func Detect(root, otherRoot string, includeDirty bool) (*Report, error) {
+// Both workspaces must share a common base_snapshot_id for meaningful conflict detection,
+// unless base is non-nil, in which case it is used as the common ancestor.
+func Detect(root, otherRoot string, includeDirty bool, base *Base) (*Report, error) {
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, fmt.Errorf("not in a project directory: %w", err)
@@ -111,23 +130,30 @@ func Detect(root, otherRoot string, includeDirty bool) (*Report, error) {
 		return nil, fmt.Errorf("cannot load other workspace config: %w", err)
 	}
 
-	// Load base snapshot manifest (common ancestor)
-	// We use current workspace's base as the reference point
-	baseSnapshotID := cfg.BaseSnapshotID
-	if baseSnapshotID == "" {
-		return nil, fmt.Errorf("no base snapshot - cannot detect conflicts")
-	}
+	var baseSnapshotID string
+	var baseManifest *manifest.Manifest
+	if base != nil {
+		baseSnapshotID = base.SnapshotID
+		baseManifest = base.Manifest
+	} else {
+		// Load base snapshot manifest (common ancestor)
+		// We use current workspace's base as the reference point
+		baseSnapshotID = cfg.BaseSnapshotID
+		if baseSnapshotID == "" {
+			return nil, fmt.Errorf("no base snapshot - cannot detect conflicts")
+		}
 
-	// Warn if bases don't match (they should for proper 3-way merge)
-	if otherCfg.BaseSnapshotID != baseSnapshotID {
-		// They might still share a common ancestor through the snapshot, but warn
-		fmt.Printf("Warning: workspaces have different base snapshots (%s vs %s)\n",
-			baseSnapshotID, otherCfg.BaseSnapshotID)
-	}
+		// Warn if bases don't match (they should for proper 3-way merge)
+		if otherCfg.BaseSnapshotID != baseSnapshotID {
+			// They might still share a common ancestor through the snapshot, but warn
+			fmt.Printf("Warning: workspaces have different base snapshots (%s vs %s)\n",
+				baseSnapshotID, otherCfg.BaseSnapshotID)
+		}
 
-	baseManifest, err := loadManifestFromSnapshots(root, baseSnapshotID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load base snapshot: %w", err)
+		baseManifest, err = loadManifestFromSnapshots(root, baseSnapshotID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load base snapshot: %w", err)
+		}
 	}
 
 	// Generate current workspace manifest
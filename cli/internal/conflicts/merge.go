@@ -0,0 +1,163 @@
+package conflicts
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// MergeRegion is one consecutive part of a three-way line merge. Regions
+// either merged cleanly (Lines) or hold overlapping changes (Conflict).
+type MergeRegion struct {
+	Lines    []string `json:"lines,omitempty"`
+	Conflict *Hunk    `json:"conflict,omitempty"` // StartLine/EndLine are 1-indexed base lines; EndLine < StartLine for a pure insertion
+}
+
+// lineChange is a run of changed lines on one side, as half-open base
+// and side line ranges.
+type lineChange struct {
+	side               int // 0 = current, 1 = source
+	baseStart, baseEnd int
+	start, end         int
+}
+
+// MergeHunks splits a three-way merge of base, current and source into
+// clean and conflicting regions. Joining every region's lines with "\n",
+// taking the resolution of each conflict, reproduces the merged file.
+func MergeHunks(base, current, source string) []MergeRegion {
+	baseLines := strings.Split(base, "\n")
+	sides := [2][]string{strings.Split(current, "\n"), strings.Split(source, "\n")}
+
+	changes := append(lineChanges(0, baseLines, sides[0]), lineChanges(1, baseLines, sides[1])...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].baseStart < changes[j].baseStart })
+
+	var regions []MergeRegion
+	var pending []string
+	flush := func() {
+		if len(pending) > 0 {
+			regions = append(regions, MergeRegion{Lines: pending})
+			pending = nil
+		}
+	}
+
+	// delta[s] is how far side s is offset from base before the next change.
+	var delta [2]int
+	basePos := 0
+	for i := 0; i < len(changes); {
+		// Group changes whose base ranges overlap or touch.
+		group := []lineChange{changes[i]}
+		groupStart, groupEnd := changes[i].baseStart, changes[i].baseEnd
+		i++
+		for i < len(changes) && changes[i].baseStart <= groupEnd {
+			group = append(group, changes[i])
+			if changes[i].baseEnd > groupEnd {
+				groupEnd = changes[i].baseEnd
+			}
+			i++
+		}
+
+		pending = append(pending, baseLines[basePos:groupStart]...)
+		basePos = groupEnd
+
+		var spans [2][2]int
+		var touched [2]bool
+		for s := 0; s < 2; s++ {
+			spans[s] = [2]int{groupStart + delta[s], groupEnd + delta[s]}
+		}
+		for _, c := range group {
+			if !touched[c.side] {
+				spans[c.side][0] = c.start - (c.baseStart - groupStart)
+				touched[c.side] = true
+			}
+			spans[c.side][1] = c.end + (groupEnd - c.baseEnd)
+		}
+		for s := 0; s < 2; s++ {
+			delta[s] = spans[s][1] - groupEnd
+		}
+
+		currentLines := sides[0][spans[0][0]:spans[0][1]]
+		sourceLines := sides[1][spans[1][0]:spans[1][1]]
+		switch {
+		case !touched[1]:
+			pending = append(pending, currentLines...)
+		case !touched[0]:
+			pending = append(pending, sourceLines...)
+		case strings.Join(currentLines, "\n") == strings.Join(sourceLines, "\n"):
+			pending = append(pending, currentLines...)
+		default:
+			flush()
+			regions = append(regions, MergeRegion{Conflict: &Hunk{
+				StartLine:    groupStart + 1,
+				EndLine:      groupEnd,
+				BaseLines:    baseLines[groupStart:groupEnd],
+				CurrentLines: currentLines,
+				SourceLines:  sourceLines,
+			}})
+		}
+	}
+	pending = append(pending, baseLines[basePos:]...)
+	flush()
+	return regions
+}
+
+// lineChanges returns the runs of lines that differ between base and
+// modified, in order.
+func lineChanges(side int, base, modified []string) []lineChange {
+	// Diff one rune per line; each distinct line gets its own rune.
+	ids := make(map[string]rune)
+	encode := func(lines []string) []rune {
+		out := make([]rune, len(lines))
+		for i, line := range lines {
+			id, ok := ids[line]
+			if !ok {
+				id = lineRune(len(ids))
+				ids[line] = id
+			}
+			out[i] = id
+		}
+		return out
+	}
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffMainRunes(encode(base), encode(modified), false)
+
+	var changes []lineChange
+	var open *lineChange
+	basePos, pos := 0, 0
+	for _, d := range diffs {
+		n := utf8.RuneCountInString(d.Text)
+		if d.Type == diffmatchpatch.DiffEqual {
+			if open != nil {
+				changes = append(changes, *open)
+				open = nil
+			}
+			basePos += n
+			pos += n
+			continue
+		}
+		if open == nil {
+			open = &lineChange{side: side, baseStart: basePos, baseEnd: basePos, start: pos, end: pos}
+		}
+		if d.Type == diffmatchpatch.DiffDelete {
+			basePos += n
+			open.baseEnd = basePos
+		} else {
+			pos += n
+			open.end = pos
+		}
+	}
+	if open != nil {
+		changes = append(changes, *open)
+	}
+	return changes
+}
+
+// lineRune maps a line number to a rune, skipping the surrogate range so
+// the diff's internal string conversions keep every rune intact.
+func lineRune(i int) rune {
+	if i >= 0xD800 {
+		i += 0x800
+	}
+	return rune(i)
+}
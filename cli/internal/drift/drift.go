@@ -17,6 +17,10 @@ type Report struct {
 	FilesDeleted   []string `json:"files_deleted"`
 	BytesChanged   int64    `json:"bytes_changed"`
 	Summary        string   `json:"summary,omitempty"`
+
+	// contents maps each added or modified path to a key for its new
+	// content, so Fingerprint tells apart edits to the same files.
+	contents map[string]string
 }
 
 // SnapshotMeta is an alias for store.SnapshotMeta.
@@ -65,7 +69,11 @@ func Compute(root string, baseManifest *manifest.Manifest) (*Report, error) {
 	}
 
 	// Compute diff
-	added, modified, deleted := manifest.Diff(baseManifest, current)
+	diff := manifest.Diff
+	if ignoreModeChangesAt(root) {
+		diff = manifest.DiffIgnoringMode
+	}
+	added, modified, deleted := diff(baseManifest, current)
 
 	// Calculate bytes changed
 	bytesChanged := calculateBytesChanged(baseManifest, current, added, modified, deleted)
@@ -75,9 +83,20 @@ func Compute(root string, baseManifest *manifest.Manifest) (*Report, error) {
 		FilesModified: modified,
 		FilesDeleted:  deleted,
 		BytesChanged:  bytesChanged,
+		contents:      contentKeys(current, added, modified),
 	}, nil
 }
 
+// ignoreModeChangesAt reports whether the project containing root ignores
+// mode-only changes (snapshot.ignore_mode_changes).
+func ignoreModeChangesAt(root string) bool {
+	_, parentCfg, err := config.FindProjectRootFrom(root)
+	if err != nil {
+		return false
+	}
+	return parentCfg.Snapshot.IgnoresModeChanges()
+}
+
 // ComputeFromCache computes drift using the cached base manifest
 // Compares current working directory against the workspace's base_snapshot_id
 func ComputeFromCache(root string) (*Report, error) {
@@ -108,6 +127,7 @@ func ComputeFromCache(root string) (*Report, error) {
 			FilesModified: nil,
 			FilesDeleted:  nil,
 			BytesChanged:  bytesChanged,
+			contents:      contentKeys(current, added, nil),
 		}, nil
 	}
 
@@ -155,6 +175,7 @@ func ComputeFromLatestSnapshot(root string) (*Report, error) {
 			FilesModified: nil,
 			FilesDeleted:  nil,
 			BytesChanged:  bytesChanged,
+			contents:      contentKeys(current, added, nil),
 		}, nil
 	}
 
@@ -192,7 +213,32 @@ func CompareManifests(base, current *manifest.Manifest) *Report {
 		FilesModified: modified,
 		FilesDeleted:  deleted,
 		BytesChanged:  bytesChanged,
+		contents:      contentKeys(current, added, modified),
+	}
+}
+
+// contentKeys returns the content key of each added and modified path in
+// current: its blob hash and mode, or its target for a symlink.
+func contentKeys(current *manifest.Manifest, added, modified []string) map[string]string {
+	entries := make(map[string]manifest.FileEntry, len(current.Files))
+	for _, f := range current.Files {
+		entries[f.Path] = f
+	}
+	keys := make(map[string]string, len(added)+len(modified))
+	for _, paths := range [][]string{added, modified} {
+		for _, p := range paths {
+			f, ok := entries[p]
+			if !ok {
+				continue
+			}
+			if f.Type == manifest.EntryTypeSymlink {
+				keys[p] = "->" + f.Target
+			} else {
+				keys[p] = fmt.Sprintf("%s:%o", f.Hash, f.Mode)
+			}
+		}
 	}
+	return keys
 }
 
 // HasChanges returns true if there are any changes
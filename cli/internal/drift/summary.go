@@ -0,0 +1,97 @@
+package drift
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/store"
+)
+
+const summaryCacheFileName = "drift-summaries.json"
+
+// maxCachedSummaries bounds the size of the summary cache file.
+const maxCachedSummaries = 64
+
+type summaryCache struct {
+	Summaries map[string]string `json:"summaries"`
+	Order     []string          `json:"order"`
+}
+
+// Fingerprint returns a stable hash identifying the set of changes in the
+// report. Two reports with the same base, the same changed paths and the
+// same new contents produce the same fingerprint regardless of the order
+// the paths were collected in; editing a changed file again changes it.
+func (r *Report) Fingerprint() string {
+	h := sha256.New()
+	h.Write([]byte(r.BaseSnapshotID))
+	h.Write([]byte{0})
+	for _, group := range []struct {
+		tag   string
+		paths []string
+	}{
+		{"A", r.FilesAdded},
+		{"M", r.FilesModified},
+		{"D", r.FilesDeleted},
+	} {
+		paths := append([]string(nil), group.paths...)
+		sort.Strings(paths)
+		for _, p := range paths {
+			h.Write([]byte(group.tag))
+			h.Write([]byte(p))
+			h.Write([]byte{0})
+			h.Write([]byte(r.contents[p]))
+			h.Write([]byte{0})
+		}
+	}
+	h.Write([]byte(strconv.FormatInt(r.BytesChanged, 10)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func summaryCachePath(root string) string {
+	return filepath.Join(root, config.ConfigDirName, summaryCacheFileName)
+}
+
+func loadSummaryCache(root string) *summaryCache {
+	cache := &summaryCache{Summaries: make(map[string]string)}
+	data, err := os.ReadFile(summaryCachePath(root))
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, cache); err != nil || cache.Summaries == nil {
+		return &summaryCache{Summaries: make(map[string]string)}
+	}
+	return cache
+}
+
+// CachedSummary returns a previously generated summary for the given drift
+// fingerprint in the workspace at root.
+func CachedSummary(root, fingerprint string) (string, bool) {
+	summary, ok := loadSummaryCache(root).Summaries[fingerprint]
+	return summary, ok && summary != ""
+}
+
+// SaveSummary records a generated summary for the given drift fingerprint.
+// The oldest entries are evicted once the cache exceeds maxCachedSummaries.
+func SaveSummary(root, fingerprint, summary string) error {
+	cache := loadSummaryCache(root)
+	if _, exists := cache.Summaries[fingerprint]; !exists {
+		cache.Order = append(cache.Order, fingerprint)
+	}
+	cache.Summaries[fingerprint] = summary
+	for len(cache.Order) > maxCachedSummaries {
+		delete(cache.Summaries, cache.Order[0])
+		cache.Order = cache.Order[1:]
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.AtomicWriteFile(summaryCachePath(root), data, 0644)
+}
@@ -0,0 +1,26 @@
+package drift
+
+import (
+	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/manifest"
+)
+
+func TestFingerprintTracksContent(t *testing.T) {
+	base := &manifest.Manifest{Version: "1", Files: []manifest.FileEntry{
+		{Type: manifest.EntryTypeFile, Path: "a.txt", Hash: "aaa", Size: 3, Mode: 0644},
+	}}
+	edit := func(hash string) *manifest.Manifest {
+		return &manifest.Manifest{Version: "1", Files: []manifest.FileEntry{
+			{Type: manifest.EntryTypeFile, Path: "a.txt", Hash: hash, Size: 3, Mode: 0644},
+		}}
+	}
+
+	first := CompareManifests(base, edit("bbb")).Fingerprint()
+	if again := CompareManifests(base, edit("bbb")).Fingerprint(); again != first {
+		t.Fatalf("expected identical changes to share a fingerprint")
+	}
+	if other := CompareManifests(base, edit("ccc")).Fingerprint(); other == first {
+		t.Fatalf("expected a same-size edit with new content to change the fingerprint")
+	}
+}
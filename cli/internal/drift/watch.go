@@ -0,0 +1,110 @@
+package drift
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ankitiscracked/fastest/cli/internal/ignore"
+)
+
+// WatchOpts configures Watch.
+type WatchOpts struct {
+	// Interval is how often the workspace is scanned for changes.
+	Interval time.Duration
+	// Debounce is how long the workspace must stay unchanged after a change
+	// before the callback fires, so bursts of writes cause a single redraw.
+	Debounce time.Duration
+}
+
+// Watch scans the workspace at root every opts.Interval and calls onChange
+// once the tree has changed and then stayed quiet for opts.Debounce. Paths
+// matched by .fstignore never count as changes. Watch blocks until ctx is
+// cancelled.
+func Watch(ctx context.Context, root string, opts WatchOpts, onChange func()) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+
+	last, err := TreeFingerprint(root)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	pending := false
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			fp, err := TreeFingerprint(root)
+			if err != nil {
+				return err
+			}
+			if fp != last {
+				last = fp
+				pending = true
+				changedAt = now
+				continue
+			}
+			if pending && now.Sub(changedAt) >= opts.Debounce {
+				pending = false
+				onChange()
+			}
+		}
+	}
+}
+
+// TreeFingerprint returns a hash of the path, size, mode and modification
+// time of every non-ignored entry under root. It changes whenever a file is
+// written, created, removed or renamed, without reading file contents.
+func TreeFingerprint(root string) (string, error) {
+	matcher, err := ignore.LoadFromDir(root)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can disappear mid-scan while an editor or agent is writing.
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if matcher.Match(relPath, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\x00%o\n", relPath, info.Size(), info.ModTime().UnixNano(), info.Mode())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
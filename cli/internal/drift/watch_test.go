@@ -0,0 +1,90 @@
+package drift
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTreeFingerprintIgnoresIgnoredPaths(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatalf("write a.txt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".fstignore"), []byte("*.swp\n"), 0644); err != nil {
+		t.Fatalf("write .fstignore: %v", err)
+	}
+
+	before, err := TreeFingerprint(root)
+	if err != nil {
+		t.Fatalf("TreeFingerprint: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, ".a.txt.swp"), []byte("tmp"), 0644); err != nil {
+		t.Fatalf("write swap file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".fst"), 0755); err != nil {
+		t.Fatalf("mkdir .fst: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".fst", "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	afterIgnored, err := TreeFingerprint(root)
+	if err != nil {
+		t.Fatalf("TreeFingerprint: %v", err)
+	}
+	if afterIgnored != before {
+		t.Fatalf("expected ignored files not to change the fingerprint")
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatalf("write b.txt: %v", err)
+	}
+	afterAdd, err := TreeFingerprint(root)
+	if err != nil {
+		t.Fatalf("TreeFingerprint: %v", err)
+	}
+	if afterAdd == before {
+		t.Fatalf("expected new file to change the fingerprint")
+	}
+}
+
+func TestWatchDebouncesBurstOfWrites(t *testing.T) {
+	root := t.TempDir()
+
+	var calls int32
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, root, WatchOpts{Interval: 10 * time.Millisecond, Debounce: 50 * time.Millisecond}, func() {
+			atomic.AddInt32(&calls, 1)
+		})
+	}()
+
+	// Give the watcher time to take its initial fingerprint.
+	time.Sleep(30 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		name := filepath.Join(root, "file.txt")
+		if err := os.WriteFile(name, []byte{byte('a' + i)}, 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		time.Sleep(15 * time.Millisecond)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&calls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected exactly one debounced change notification, got %d", got)
+	}
+}
@@ -5,9 +5,11 @@ package gitstore
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -63,6 +65,22 @@ func SaveGitMapping(configDir string, mapping *GitMapping) error {
 	return os.WriteFile(filepath.Join(exportDir, "git-map.json"), data, 0644)
 }
 
+// PruneGitMapping removes mapping entries whose snapshot is no longer in the
+// store (e.g. after gc) or whose commit no longer exists in the git
+// repository. It returns the removed snapshot IDs, sorted.
+func PruneGitMapping(g gitutil.Env, s *store.Store, mapping *GitMapping) []string {
+	var removed []string
+	for snapID, sha := range mapping.Snapshots {
+		if _, err := s.LoadSnapshotMeta(snapID); err == nil && gitutil.CommitExists(g, sha) {
+			continue
+		}
+		delete(mapping.Snapshots, snapID)
+		removed = append(removed, snapID)
+	}
+	sort.Strings(removed)
+	return removed
+}
+
 // ---- Export metadata ----
 
 const (
@@ -83,11 +101,37 @@ type ExportWorkspaceMeta struct {
 	WorkspaceID   string `json:"workspace_id"`
 	WorkspaceName string `json:"workspace_name,omitempty"`
 	Branch        string `json:"branch"`
+	// Tracked is set for a branch chosen with 'fst workspace create --track'.
+	// Export keeps using it instead of a branch named after the workspace.
+	Tracked bool `json:"tracked,omitempty"`
 }
 
 // UpdateExportMetadata adds/updates workspace info in the export metadata
-// stored in refs/fst/meta.
+// stored in refs/fst/meta. A tracked branch stays tracked while the
+// workspace keeps exporting to it.
 func UpdateExportMetadata(g gitutil.Env, cfg *config.WorkspaceConfig, branchName string) error {
+	return writeWorkspaceMetadata(g, cfg, branchName, false)
+}
+
+// TrackExportBranch records in refs/fst/meta that the workspace tracks
+// branchName, so export writes to that branch and sync imports it.
+func TrackExportBranch(g gitutil.Env, cfg *config.WorkspaceConfig, branchName string) error {
+	return writeWorkspaceMetadata(g, cfg, branchName, true)
+}
+
+// TrackedBranch returns the branch the workspace tracks according to meta,
+// or "" if it isn't tracking one.
+func TrackedBranch(meta *ExportMeta, workspaceID string) string {
+	if meta == nil {
+		return ""
+	}
+	if entry, ok := meta.Workspaces[workspaceID]; ok && entry.Tracked {
+		return entry.Branch
+	}
+	return ""
+}
+
+func writeWorkspaceMetadata(g gitutil.Env, cfg *config.WorkspaceConfig, branchName string, tracked bool) error {
 	if cfg == nil || cfg.WorkspaceID == "" {
 		return fmt.Errorf("missing workspace id for export metadata")
 	}
@@ -102,6 +146,9 @@ func UpdateExportMetadata(g gitutil.Env, cfg *config.WorkspaceConfig, branchName
 	if meta.Workspaces == nil {
 		meta.Workspaces = make(map[string]ExportWorkspaceMeta)
 	}
+	if prev, ok := meta.Workspaces[cfg.WorkspaceID]; ok && prev.Tracked && prev.Branch == branchName {
+		tracked = true
+	}
 
 	meta.ProjectID = cfg.ProjectID
 	meta.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
@@ -109,6 +156,7 @@ func UpdateExportMetadata(g gitutil.Env, cfg *config.WorkspaceConfig, branchName
 		WorkspaceID:   cfg.WorkspaceID,
 		WorkspaceName: cfg.WorkspaceName,
 		Branch:        branchName,
+		Tracked:       tracked,
 	}
 
 	data, err := json.MarshalIndent(meta, "", "  ")
@@ -209,6 +257,12 @@ func CollectExportBranches(meta *ExportMeta) []string {
 // CommitMetaFromSnapshot converts fst snapshot metadata into git commit
 // metadata (author/committer env vars).
 func CommitMetaFromSnapshot(snap *store.SnapshotMeta) *gitutil.CommitMeta {
+	return CommitMetaWithAuthors(snap, nil)
+}
+
+// CommitMetaWithAuthors is CommitMetaFromSnapshot with agent snapshots
+// attributed through authors. Agents missing from authors use AgentEmail.
+func CommitMetaWithAuthors(snap *store.SnapshotMeta, authors AuthorMap) *gitutil.CommitMeta {
 	if snap.CreatedAt == "" && snap.Agent == "" && snap.AuthorName == "" {
 		return nil
 	}
@@ -222,15 +276,89 @@ func CommitMetaFromSnapshot(snap *store.SnapshotMeta) *gitutil.CommitMeta {
 		meta.CommitterName = snap.AuthorName
 		meta.CommitterEmail = snap.AuthorEmail
 	} else if snap.Agent != "" {
-		email := AgentEmail(snap.Agent)
-		meta.AuthorName = snap.Agent
+		name, email, ok := authors.Lookup(snap.Agent)
+		if !ok {
+			name, email = snap.Agent, AgentEmail(snap.Agent)
+		}
+		meta.AuthorName = name
 		meta.AuthorEmail = email
-		meta.CommitterName = snap.Agent
+		meta.CommitterName = name
 		meta.CommitterEmail = email
 	}
 	return meta
 }
 
+// MonotonicCommitterDate returns the committer date to use for a commit of
+// a snapshot created at createdAt (RFC3339) whose parent commits have the
+// given committer dates: createdAt itself if it is later than all of them,
+// and one second after the latest parent otherwise, so dates never go
+// backwards along a branch even when snapshot clocks were skewed.
+func MonotonicCommitterDate(createdAt string, parentDates []time.Time) string {
+	var latest time.Time
+	for _, d := range parentDates {
+		if d.After(latest) {
+			latest = d
+		}
+	}
+	if latest.IsZero() {
+		return createdAt
+	}
+	if created, err := time.Parse(time.RFC3339, createdAt); err == nil && created.After(latest) {
+		return createdAt
+	}
+	return latest.Add(time.Second).UTC().Format(time.RFC3339)
+}
+
+// AuthorMap maps agent names (case-insensitively) to the git identity their
+// exported commits are attributed to.
+type AuthorMap map[string]AuthorIdent
+
+// AuthorIdent is a git author identity.
+type AuthorIdent struct {
+	Name  string
+	Email string
+}
+
+// Lookup returns the identity mapped to agent, if any.
+func (m AuthorMap) Lookup(agent string) (name, email string, ok bool) {
+	ident, ok := m[strings.ToLower(strings.TrimSpace(agent))]
+	return ident.Name, ident.Email, ok
+}
+
+// LoadAuthorMap reads an author map file. Each line maps an agent name to
+// an identity, in the format of git-svn's authors file:
+//
+//	claude = Release Bot <release-bot@example.com>
+//
+// Blank lines and lines starting with "#" are ignored.
+func LoadAuthorMap(path string) (AuthorMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read author map: %w", err)
+	}
+	authors := AuthorMap{}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		agent, ident, ok := strings.Cut(line, "=")
+		agent = strings.ToLower(strings.TrimSpace(agent))
+		ident = strings.TrimSpace(ident)
+		open := strings.LastIndex(ident, "<")
+		if !ok || agent == "" || open < 0 || !strings.HasSuffix(ident, ">") {
+			return nil, fmt.Errorf("%s:%d: expected \"agent = Name <email>\"", path, i+1)
+		}
+		name := strings.TrimSpace(ident[:open])
+		email := strings.TrimSpace(ident[open+1 : len(ident)-1])
+		if name == "" || email == "" {
+			return nil, fmt.Errorf("%s:%d: expected \"agent = Name <email>\"", path, i+1)
+		}
+		authors[agent] = AuthorIdent{Name: name, Email: email}
+	}
+	return authors, nil
+}
+
 // AgentEmail converts an agent name to an email address for git commits.
 func AgentEmail(agent string) string {
 	if agent == "" {
@@ -257,22 +385,31 @@ func AgentEmail(agent string) string {
 	return slug + "@fastest.local"
 }
 
+// RestoreOptions configures RestoreFilesFromManifest.
+type RestoreOptions struct {
+	// PruneEmptyDirs removes directories left empty after extra files are
+	// deleted, bottom-up. Directories that still hold anything (including
+	// ignored files) and directories recorded in the manifest are kept.
+	PruneEmptyDirs bool
+}
+
 // RestoreFilesFromManifest restores all files from a manifest using the
 // store's blob cache.
-func RestoreFilesFromManifest(root string, s *store.Store, m *manifest.Manifest) error {
+func RestoreFilesFromManifest(root string, s *store.Store, m *manifest.Manifest, opts RestoreOptions) error {
 	shouldExist := make(map[string]bool)
 	for _, f := range m.FileEntries() {
 		shouldExist[f.Path] = true
 	}
 
 	// Remove files that shouldn't exist (except .git and .fst)
+	var removed []string
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
 		relPath, _ := filepath.Rel(root, path)
 		relPath = filepath.ToSlash(relPath)
-		if strings.HasPrefix(relPath, ".git") || strings.HasPrefix(relPath, ".fst") || relPath == ".fst" {
+		if isMetadataPath(relPath) {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
@@ -282,11 +419,17 @@ func RestoreFilesFromManifest(root string, s *store.Store, m *manifest.Manifest)
 			return nil
 		}
 		if !info.IsDir() && !shouldExist[relPath] {
-			os.Remove(path)
+			if os.Remove(path) == nil {
+				removed = append(removed, relPath)
+			}
 		}
 		return nil
 	})
 
+	if opts.PruneEmptyDirs {
+		m.PruneEmptyDirs(root, removed)
+	}
+
 	// Restore files from blobs
 	for _, f := range m.FileEntries() {
 		content, err := s.ReadBlob(f.Hash)
@@ -300,10 +443,17 @@ func RestoreFilesFromManifest(root string, s *store.Store, m *manifest.Manifest)
 		if err := os.WriteFile(targetPath, content, os.FileMode(f.Mode)); err != nil {
 			return err
 		}
+		if err := manifest.RestoreModTime(targetPath, f); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+func isMetadataPath(relPath string) bool {
+	return strings.HasPrefix(relPath, ".git") || strings.HasPrefix(relPath, ".fst")
+}
+
 // ResolveGitParentSHAs maps snapshot parent IDs to their corresponding git
 // commit SHAs using the mapping.
 func ResolveGitParentSHAs(g gitutil.Env, mapping *GitMapping, parentIDs []string) ([]string, error) {
@@ -335,8 +485,9 @@ func ResolveGitParentSHAs(g gitutil.Env, mapping *GitMapping, parentIDs []string
 }
 
 // BuildSnapshotDAG walks all reachable parents and returns snapshots in
-// parent-before-child (topological) order.
-func BuildSnapshotDAG(s *store.Store, startID string) ([]*store.SnapshotMeta, error) {
+// parent-before-child (topological) order. The walk stops at any boundary
+// snapshot, which is excluded along with the history behind it.
+func BuildSnapshotDAG(s *store.Store, startID string, boundary ...string) ([]*store.SnapshotMeta, error) {
 	if startID == "" {
 		return nil, fmt.Errorf("empty snapshot id")
 	}
@@ -345,59 +496,26 @@ func BuildSnapshotDAG(s *store.Store, startID string) ([]*store.SnapshotMeta, er
 		return nil, fmt.Errorf("snapshot metadata not found for %s", startID)
 	}
 
-	state := make(map[string]uint8)
-	infoByID := make(map[string]*store.SnapshotMeta)
-	var ordered []*store.SnapshotMeta
-
-	var visit func(string) error
-	visit = func(id string) error {
-		if id == "" {
-			return nil
-		}
-		switch state[id] {
-		case 1:
-			return fmt.Errorf("cycle detected at snapshot %s", id)
-		case 2:
-			return nil
-		}
-		state[id] = 1
-
-		info := infoByID[id]
-		if info == nil {
-			meta, err := s.LoadSnapshotMeta(id)
-			if err != nil {
-				if os.IsNotExist(err) {
-					fmt.Printf("  warning: snapshot metadata missing for %s (skipping)\n", id)
-					state[id] = 2
-					return nil
-				}
-				return err
-			}
-			info = meta
-			infoByID[id] = info
-		}
-
-		for _, parent := range info.ParentSnapshotIDs {
-			if err := visit(parent); err != nil {
-				return err
-			}
-		}
-
-		state[id] = 2
-		ordered = append(ordered, info)
-		return nil
-	}
-
-	if err := visit(startID); err != nil {
-		return nil, err
-	}
-
-	return ordered, nil
+	return s.WalkSnapshotDAG(startID, store.WalkOpts{
+		Boundary: boundary,
+		OnMissing: func(id string) {
+			fmt.Printf("  warning: snapshot metadata missing for %s (skipping)\n", id)
+		},
+	})
 }
 
 // CreateImportedSnapshot creates a snapshot from files in sourceRoot,
 // writing blobs and metadata to the store.
 func CreateImportedSnapshot(s *store.Store, sourceRoot string, cfg *config.WorkspaceConfig, parents []string, message, createdAt, authorName, authorEmail, agentName string) (string, error) {
+	return CreateImportedSnapshotWithBlobs(s, sourceRoot, cfg, parents, message, createdAt, authorName, authorEmail, agentName, make(map[string]bool))
+}
+
+// CreateImportedSnapshotWithBlobs is CreateImportedSnapshot for one of a
+// run of imports. stored holds the blobs already known to be in the store;
+// only the snapshot's other blobs are checked, in one batch, and stored is
+// updated with them, so blobs shared by the imported commits are checked
+// once per run instead of once per commit.
+func CreateImportedSnapshotWithBlobs(s *store.Store, sourceRoot string, cfg *config.WorkspaceConfig, parents []string, message, createdAt, authorName, authorEmail, agentName string, stored map[string]bool) (string, error) {
 	if message == "" {
 		message = "Imported commit"
 	}
@@ -414,19 +532,38 @@ func CreateImportedSnapshot(s *store.Store, sourceRoot string, cfg *config.Works
 
 	if createdAt == "" {
 		createdAt = time.Now().UTC().Format(time.RFC3339)
+	} else if _, err := time.Parse(time.RFC3339, createdAt); err != nil {
+		return "", fmt.Errorf("invalid snapshot time %q (expected RFC3339)", createdAt)
 	}
 	snapshotID := store.ComputeSnapshotID(manifestHash, parents, authorName, authorEmail, createdAt)
 
+	var unknown []string
+	pathOf := make(map[string]string)
 	for _, f := range m.FileEntries() {
-		if s.BlobExists(f.Hash) {
+		if stored[f.Hash] {
 			continue
 		}
-		srcPath := filepath.Join(sourceRoot, f.Path)
-		content, err := os.ReadFile(srcPath)
+		if _, ok := pathOf[f.Hash]; !ok {
+			pathOf[f.Hash] = f.Path
+			unknown = append(unknown, f.Hash)
+		}
+	}
+	missing := make(map[string]bool)
+	for _, hash := range s.MissingBlobs(unknown) {
+		missing[hash] = true
+	}
+	for _, hash := range unknown {
+		if !missing[hash] {
+			stored[hash] = true
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(sourceRoot, pathOf[hash]))
 		if err != nil {
 			continue
 		}
-		_ = s.WriteBlob(f.Hash, content)
+		if s.WriteBlob(hash, content) == nil {
+			stored[hash] = true
+		}
 	}
 
 	if err := s.WriteSnapshotMeta(&store.SnapshotMeta{
@@ -451,7 +588,8 @@ func CreateImportedSnapshot(s *store.Store, sourceRoot string, cfg *config.Works
 
 // PushExportToRemote pushes all exported workspace branches and metadata
 // to the given remote. Returns gitutil.ErrPushRejected if a push is
-// rejected due to non-fast-forward.
+// rejected due to non-fast-forward, and gitutil.ErrPushAuth (with a hint
+// on how to fix the credentials) if the remote rejects them.
 func PushExportToRemote(projectRoot string, remoteName string) error {
 	meta, err := LoadExportMetadataFromRepo(projectRoot)
 	if err != nil {
@@ -460,16 +598,194 @@ func PushExportToRemote(projectRoot string, remoteName string) error {
 	if meta == nil {
 		return fmt.Errorf("missing export metadata in repo")
 	}
-	branches := CollectExportBranches(meta)
+	branches := append(CollectExportBranches(meta), FstMetaRef)
 
 	for _, branch := range branches {
 		if err := gitutil.Push(projectRoot, remoteName, branch); err != nil {
+			if errors.Is(err, gitutil.ErrPushAuth) {
+				remoteURL, _ := gitutil.RemoteURL(projectRoot, remoteName)
+				return fmt.Errorf("%w - %s", err, gitutil.PushAuthHint(remoteName, remoteURL))
+			}
 			return err
 		}
 	}
-	if err := gitutil.Push(projectRoot, remoteName, FstMetaRef); err != nil {
-		return err
+
+	return nil
+}
+
+// ---- Autosquash ----
+
+// AutosquashPlan describes how fixup snapshots fold into their targets
+// when exporting with --autosquash.
+type AutosquashPlan struct {
+	// folded maps a fixup snapshot ID to its parent, whose rewritten commit
+	// already carries the fixup's changes and stands in for it.
+	folded map[string]string
+	// overlays holds, per snapshot ID, the entries a fixup rewrites in that
+	// snapshot's tree. A nil entry removes the path.
+	overlays map[string]map[string]*manifest.FileEntry
+}
+
+// PlanAutosquash finds the fixup snapshots in chain (as returned by
+// BuildSnapshotDAG) that can be folded into their targets. A fixup folds
+// when its target is reached through single-parent links and none of the
+// paths it changes were touched in between, so its changes can be applied
+// to the target and every snapshot after it. exported reports snapshots
+// that already have a commit, which can't be rewritten. Fixups that can't
+// be folded are described in the returned warnings and should be exported
+// as ordinary commits.
+func PlanAutosquash(s *store.Store, chain []*store.SnapshotMeta, exported func(id string) bool) (*AutosquashPlan, []string, error) {
+	plan := &AutosquashPlan{
+		folded:   make(map[string]string),
+		overlays: make(map[string]map[string]*manifest.FileEntry),
+	}
+	byID := make(map[string]*store.SnapshotMeta, len(chain))
+	for _, snap := range chain {
+		byID[snap.ID] = snap
+	}
+
+	var warnings []string
+	for _, fixup := range chain {
+		if fixup.FixupOf == "" || exported(fixup.ID) {
+			continue
+		}
+		between, reason := fixupRange(byID, fixup, exported)
+		if reason != "" {
+			warnings = append(warnings, fmt.Sprintf("fixup %s not folded: %s", fixup.ID[:12], reason))
+			continue
+		}
+
+		parentManifest, err := plan.effectiveManifest(s, between[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		fixupManifest, err := s.LoadManifest(fixup.ManifestHash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load manifest for %s: %w", fixup.ID[:12], err)
+		}
+		added, modified, deleted := manifest.Diff(parentManifest, fixupManifest)
+		changed := append(append(added, modified...), deleted...)
+
+		// Every snapshot from the target up to the fixup's parent must hold
+		// the same version of each changed path, or the fixup's changes
+		// can't be moved back to the target.
+		clean := true
+		for _, snap := range between[1:] {
+			m, err := plan.effectiveManifest(s, snap)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, path := range changed {
+				if !sameEntry(findEntry(m, path), findEntry(parentManifest, path)) {
+					clean = false
+					break
+				}
+			}
+			if !clean {
+				break
+			}
+		}
+		if !clean {
+			warnings = append(warnings, fmt.Sprintf("fixup %s not folded: its files changed after %s", fixup.ID[:12], fixup.FixupOf[:12]))
+			continue
+		}
+
+		for _, snap := range between {
+			overlay := plan.overlays[snap.ID]
+			if overlay == nil {
+				overlay = make(map[string]*manifest.FileEntry)
+				plan.overlays[snap.ID] = overlay
+			}
+			for _, path := range changed {
+				overlay[path] = findEntry(fixupManifest, path)
+			}
+		}
+		plan.folded[fixup.ID] = between[0].ID
+	}
+	return plan, warnings, nil
+}
+
+// fixupRange returns the snapshots from the fixup's parent back to its
+// target (inclusive), or a reason the fixup can't be folded.
+func fixupRange(byID map[string]*store.SnapshotMeta, fixup *store.SnapshotMeta, exported func(id string) bool) ([]*store.SnapshotMeta, string) {
+	var between []*store.SnapshotMeta
+	current := fixup
+	for {
+		if len(current.ParentSnapshotIDs) != 1 {
+			return nil, fmt.Sprintf("merge snapshot %s lies between it and its target", current.ID[:12])
+		}
+		parent, ok := byID[current.ParentSnapshotIDs[0]]
+		if !ok {
+			return nil, fmt.Sprintf("target %s is not in its history", fixup.FixupOf[:12])
+		}
+		if exported(parent.ID) {
+			return nil, fmt.Sprintf("%s is already exported (use --rebuild)", parent.ID[:12])
+		}
+		between = append(between, parent)
+		if parent.ID == fixup.FixupOf {
+			return between, ""
+		}
+		current = parent
+	}
+}
+
+// FoldedInto returns the snapshot whose commit stands in for the fixup id,
+// if id is folded.
+func (p *AutosquashPlan) FoldedInto(id string) (string, bool) {
+	if p == nil {
+		return "", false
 	}
+	parentID, ok := p.folded[id]
+	return parentID, ok
+}
 
+// Apply returns m with the fixups folded into snapshot id. m is returned
+// unchanged when nothing folds into id.
+func (p *AutosquashPlan) Apply(id string, m *manifest.Manifest) *manifest.Manifest {
+	if p == nil || len(p.overlays[id]) == 0 {
+		return m
+	}
+	overlay := p.overlays[id]
+	out := &manifest.Manifest{Version: m.Version}
+	for _, f := range m.Files {
+		if _, ok := overlay[f.Path]; !ok {
+			out.Files = append(out.Files, f)
+		}
+	}
+	paths := make([]string, 0, len(overlay))
+	for path, entry := range overlay {
+		if entry != nil {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		out.Files = append(out.Files, *overlay[path])
+	}
+	sort.Slice(out.Files, func(i, j int) bool { return out.Files[i].Path < out.Files[j].Path })
+	return out
+}
+
+func (p *AutosquashPlan) effectiveManifest(s *store.Store, snap *store.SnapshotMeta) (*manifest.Manifest, error) {
+	m, err := s.LoadManifest(snap.ManifestHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifest for %s: %w", snap.ID[:12], err)
+	}
+	return p.Apply(snap.ID, m), nil
+}
+
+func findEntry(m *manifest.Manifest, path string) *manifest.FileEntry {
+	for i := range m.Files {
+		if m.Files[i].Path == path {
+			return &m.Files[i]
+		}
+	}
 	return nil
 }
+
+func sameEntry(a, b *manifest.FileEntry) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Type == b.Type && a.Hash == b.Hash && a.Mode == b.Mode && a.Target == b.Target
+}
@@ -144,6 +144,44 @@ func TestExportMetadataRoundTrip(t *testing.T) {
 	}
 }
 
+func TestTrackExportBranch(t *testing.T) {
+	g, _ := initGitRepo(t)
+
+	cfg := &config.WorkspaceConfig{
+		ProjectID:     "proj-1",
+		WorkspaceID:   "ws-1",
+		WorkspaceName: "mine",
+	}
+	if err := TrackExportBranch(g, cfg, "feature"); err != nil {
+		t.Fatalf("TrackExportBranch: %v", err)
+	}
+	meta, _ := LoadExportMetadata(g)
+	if got := TrackedBranch(meta, "ws-1"); got != "feature" {
+		t.Fatalf("expected ws-1 to track feature, got %q", got)
+	}
+
+	// Exporting to the tracked branch keeps it tracked.
+	if err := UpdateExportMetadata(g, cfg, "feature"); err != nil {
+		t.Fatalf("UpdateExportMetadata: %v", err)
+	}
+	meta, _ = LoadExportMetadata(g)
+	if got := TrackedBranch(meta, "ws-1"); got != "feature" {
+		t.Fatalf("expected ws-1 to still track feature, got %q", got)
+	}
+
+	// Exporting elsewhere drops the tracking.
+	if err := UpdateExportMetadata(g, cfg, "mine"); err != nil {
+		t.Fatalf("UpdateExportMetadata: %v", err)
+	}
+	meta, _ = LoadExportMetadata(g)
+	if got := TrackedBranch(meta, "ws-1"); got != "" {
+		t.Fatalf("expected ws-1 to stop tracking, got %q", got)
+	}
+	if got := TrackedBranch(nil, "ws-1"); got != "" {
+		t.Fatalf("expected no tracked branch without metadata, got %q", got)
+	}
+}
+
 func TestLoadExportMetadataFromRepo(t *testing.T) {
 	g, repoDir := initGitRepo(t)
 
@@ -253,6 +291,64 @@ func TestCommitMetaFromSnapshot(t *testing.T) {
 	}
 }
 
+func TestMonotonicCommitterDate(t *testing.T) {
+	parent := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	older := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	if got := MonotonicCommitterDate("2024-01-02T00:00:00Z", []time.Time{parent, older}); got != "2024-01-02T00:00:00Z" {
+		t.Fatalf("a later snapshot should keep its date, got %s", got)
+	}
+	if got := MonotonicCommitterDate("2024-01-01T10:00:00Z", []time.Time{older, parent}); got != "2024-01-01T12:00:01Z" {
+		t.Fatalf("a skewed snapshot should follow its latest parent, got %s", got)
+	}
+	if got := MonotonicCommitterDate("2024-01-01T12:00:00Z", []time.Time{parent}); got != "2024-01-01T12:00:01Z" {
+		t.Fatalf("an equal date should still advance, got %s", got)
+	}
+	if got := MonotonicCommitterDate("2024-01-01T10:00:00Z", nil); got != "2024-01-01T10:00:00Z" {
+		t.Fatalf("a root commit should keep its date, got %s", got)
+	}
+}
+
+func TestLoadAuthorMap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "authors.txt")
+	content := "# agent authors\n\nClaude = Release Bot <bot@example.com>\ncodex=Jane Doe <jane@example.com>\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	authors, err := LoadAuthorMap(path)
+	if err != nil {
+		t.Fatalf("LoadAuthorMap: %v", err)
+	}
+	if len(authors) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(authors))
+	}
+
+	meta := CommitMetaWithAuthors(&store.SnapshotMeta{Agent: "claude"}, authors)
+	if meta.AuthorName != "Release Bot" || meta.AuthorEmail != "bot@example.com" {
+		t.Fatalf("expected mapped author, got %s <%s>", meta.AuthorName, meta.AuthorEmail)
+	}
+	if meta.CommitterEmail != "bot@example.com" {
+		t.Fatalf("expected mapped committer, got %s", meta.CommitterEmail)
+	}
+
+	meta = CommitMetaWithAuthors(&store.SnapshotMeta{Agent: "Gemini"}, authors)
+	if meta.AuthorName != "Gemini" || meta.AuthorEmail != "gemini@fastest.local" {
+		t.Fatalf("expected default for unmapped agent, got %s <%s>", meta.AuthorName, meta.AuthorEmail)
+	}
+
+	meta = CommitMetaWithAuthors(&store.SnapshotMeta{Agent: "Claude", AuthorName: "Alice", AuthorEmail: "alice@example.com"}, authors)
+	if meta.AuthorName != "Alice" {
+		t.Fatalf("expected explicit author to win, got %s", meta.AuthorName)
+	}
+
+	if err := os.WriteFile(path, []byte("claude = no email\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := LoadAuthorMap(path); err == nil {
+		t.Fatal("expected error for malformed line")
+	}
+}
+
 func TestRestoreFilesFromManifest(t *testing.T) {
 	// Set up a store with blobs
 	projectRoot := t.TempDir()
@@ -279,7 +375,7 @@ func TestRestoreFilesFromManifest(t *testing.T) {
 	// Create an extra file that should be removed
 	os.WriteFile(filepath.Join(targetDir, "extra.txt"), []byte("should be removed"), 0644)
 
-	if err := RestoreFilesFromManifest(targetDir, s, m); err != nil {
+	if err := RestoreFilesFromManifest(targetDir, s, m, RestoreOptions{}); err != nil {
 		t.Fatalf("RestoreFilesFromManifest: %v", err)
 	}
 
@@ -320,7 +416,7 @@ func TestRestoreFilesPreservesGitAndFst(t *testing.T) {
 	os.MkdirAll(filepath.Join(targetDir, ".fst"), 0755)
 	os.WriteFile(filepath.Join(targetDir, ".fst", "config.json"), []byte("{}"), 0644)
 
-	RestoreFilesFromManifest(targetDir, s, m)
+	RestoreFilesFromManifest(targetDir, s, m, RestoreOptions{})
 
 	// .git and .fst should be preserved
 	if _, err := os.Stat(filepath.Join(targetDir, ".git", "HEAD")); err != nil {
@@ -375,6 +471,35 @@ func TestResolveGitParentSHAs(t *testing.T) {
 	}
 }
 
+func TestPruneGitMapping(t *testing.T) {
+	g, _ := initGitRepo(t)
+	os.WriteFile(filepath.Join(g.WorkTree, "f.txt"), []byte("x"), 0644)
+	g.Run("add", "-A")
+	tree, _ := gitutil.TreeSHA(g)
+	sha, _ := gitutil.CreateCommitWithParents(g, tree, "test", nil, nil)
+
+	s := store.OpenAt(t.TempDir())
+	s.EnsureDirs()
+	for _, id := range []string{"snap-kept", "snap-no-commit"} {
+		s.WriteSnapshotMeta(&store.SnapshotMeta{ID: id, ManifestHash: "h", CreatedAt: "2024-01-01T00:00:00Z"})
+	}
+
+	mapping := &GitMapping{
+		Snapshots: map[string]string{
+			"snap-kept":      sha,
+			"snap-no-commit": "0000000000000000000000000000000000000000",
+			"snap-gone":      sha, // snapshot no longer in the store
+		},
+	}
+	removed := PruneGitMapping(g, s, mapping)
+	if len(removed) != 2 || removed[0] != "snap-gone" || removed[1] != "snap-no-commit" {
+		t.Fatalf("expected [snap-gone snap-no-commit] removed, got %v", removed)
+	}
+	if len(mapping.Snapshots) != 1 || mapping.Snapshots["snap-kept"] != sha {
+		t.Fatalf("expected only snap-kept to remain, got %v", mapping.Snapshots)
+	}
+}
+
 func TestBuildSnapshotDAG(t *testing.T) {
 	projectRoot := t.TempDir()
 	s := store.OpenAt(projectRoot)
@@ -477,6 +602,54 @@ func TestCreateImportedSnapshot(t *testing.T) {
 	}
 }
 
+func TestCreateImportedSnapshotWithBlobsSharesChecks(t *testing.T) {
+	projectRoot := t.TempDir()
+	s := store.OpenAt(projectRoot)
+	s.EnsureDirs()
+
+	sourceRoot := t.TempDir()
+	os.WriteFile(filepath.Join(sourceRoot, "a.txt"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(sourceRoot, "b.txt"), []byte("b"), 0644)
+
+	wsRoot := t.TempDir()
+	config.InitAt(wsRoot, "proj-1", "ws-1", "main", "")
+	wsCfg, _ := config.LoadAt(wsRoot)
+
+	stored := make(map[string]bool)
+	if _, err := CreateImportedSnapshotWithBlobs(s, sourceRoot, wsCfg, nil, "first", "", "", "", "", stored); err != nil {
+		t.Fatalf("CreateImportedSnapshotWithBlobs: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("expected both blobs recorded as stored, got %v", stored)
+	}
+	for hash := range stored {
+		if !s.BlobExists(hash) {
+			t.Fatalf("expected blob %s to be written", hash)
+		}
+	}
+
+	// Blobs recorded as stored are not checked or written again.
+	os.WriteFile(filepath.Join(sourceRoot, "c.txt"), []byte("c"), 0644)
+	for hash := range stored {
+		os.Remove(s.BlobPath(hash))
+	}
+	if _, err := CreateImportedSnapshotWithBlobs(s, sourceRoot, wsCfg, nil, "second", "", "", "", "", stored); err != nil {
+		t.Fatalf("CreateImportedSnapshotWithBlobs: %v", err)
+	}
+	if len(stored) != 3 {
+		t.Fatalf("expected the new blob recorded as stored, got %v", stored)
+	}
+	written := 0
+	for hash := range stored {
+		if s.BlobExists(hash) {
+			written++
+		}
+	}
+	if written != 1 {
+		t.Fatalf("expected only the new blob to be written, got %d", written)
+	}
+}
+
 func TestCreateImportedSnapshotDefaultMessage(t *testing.T) {
 	projectRoot := t.TempDir()
 	s := store.OpenAt(projectRoot)
@@ -498,3 +671,42 @@ func TestCreateImportedSnapshotDefaultMessage(t *testing.T) {
 		t.Fatal("expected non-empty CreatedAt")
 	}
 }
+
+func TestRestoreFilesPrunesEmptyDirs(t *testing.T) {
+	projectRoot := t.TempDir()
+	s := store.OpenAt(projectRoot)
+	if err := s.EnsureDirs(); err != nil {
+		t.Fatalf("EnsureDirs: %v", err)
+	}
+	s.WriteBlob("hash1", []byte("keep"))
+
+	m := &manifest.Manifest{
+		Version: "1",
+		Files: []manifest.FileEntry{
+			{Type: manifest.EntryTypeFile, Path: "a.txt", Hash: "hash1", Mode: 0644, Size: 4},
+			{Type: manifest.EntryTypeDir, Path: "tracked-empty", Mode: 0755},
+		},
+	}
+
+	targetDir := t.TempDir()
+	for _, rel := range []string{"gone/deep/x.txt", "gone/y.txt", "tracked-empty/z.txt"} {
+		p := filepath.Join(targetDir, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		os.WriteFile(p, []byte(rel), 0644)
+	}
+	os.MkdirAll(filepath.Join(targetDir, ".fst"), 0755)
+
+	if err := RestoreFilesFromManifest(targetDir, s, m, RestoreOptions{PruneEmptyDirs: true}); err != nil {
+		t.Fatalf("RestoreFilesFromManifest: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(targetDir, "gone")); !os.IsNotExist(err) {
+		t.Fatalf("expected empty dir skeleton 'gone' to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, "tracked-empty")); err != nil {
+		t.Fatalf("expected dir recorded in the manifest to be kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, ".fst")); err != nil {
+		t.Fatalf("expected .fst to be kept: %v", err)
+	}
+}
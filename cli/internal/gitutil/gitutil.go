@@ -10,13 +10,24 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 )
 
 // ErrPushRejected is returned when a git push is rejected (non-fast-forward).
 // This typically means the remote has new commits that need to be fetched first.
 var ErrPushRejected = errors.New("push rejected (non-fast-forward)")
 
+// ErrPushAuth is returned when a git push fails because the remote rejected
+// the credentials (or none could be obtained).
+var ErrPushAuth = errors.New("push authentication failed")
+
+// ErrSigningFailed is returned when git could not sign a commit, usually
+// because no signing key is configured or the gpg/ssh signer is unavailable.
+var ErrSigningFailed = errors.New("commit signing failed")
+
 // Env bundles the paths needed for git plumbing commands that operate on a
 // separate work tree and index (e.g. during export/import).
 type Env struct {
@@ -140,6 +151,18 @@ func CurrentBranch(g Env) (string, error) {
 	return g.Output("rev-parse", "--abbrev-ref", "HEAD")
 }
 
+// ListBranches returns the short names of all local branches.
+func ListBranches(g Env) ([]string, error) {
+	out, err := g.Output("for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(out) == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
 // TreeSHA writes the index to a tree object and returns the tree SHA.
 func TreeSHA(g Env) (string, error) {
 	return g.Output("write-tree")
@@ -170,7 +193,8 @@ func ShowFileAtRef(g Env, ref, path string) ([]byte, error) {
 
 // ---------------- mutation helpers ----------------
 
-// CommitMeta holds author/committer env overrides for a git commit.
+// CommitMeta holds author/committer env overrides for a git commit, plus
+// whether to sign it.
 type CommitMeta struct {
 	AuthorName     string
 	AuthorEmail    string
@@ -178,6 +202,11 @@ type CommitMeta struct {
 	CommitterName  string
 	CommitterEmail string
 	CommitterDate  string
+
+	// Sign signs the commit (commit-tree -S) using SigningKey, or git's
+	// user.signingkey when SigningKey is empty.
+	Sign       bool
+	SigningKey string
 }
 
 // Env returns a map of GIT_AUTHOR_* / GIT_COMMITTER_* env vars.
@@ -212,14 +241,37 @@ func CreateCommitWithParents(g Env, treeSHA, message string, parents []string, m
 		args = append(args, "-p", p)
 	}
 	env := map[string]string{}
+	sign := false
 	if meta != nil {
 		for key, value := range meta.Env() {
 			if value != "" {
 				env[key] = value
 			}
 		}
+		if meta.Sign {
+			sign = true
+			if meta.SigningKey != "" {
+				args = append(args, "--gpg-sign="+meta.SigningKey)
+			} else {
+				args = append(args, "--gpg-sign")
+			}
+		}
+	}
+	sha, err := g.OutputWithEnv(env, args...)
+	if err != nil && sign && isSigningError(err.Error()) {
+		return "", fmt.Errorf("%w: %v (configure a key with 'git config user.signingkey' or disable signing)", ErrSigningFailed, err)
 	}
-	return g.OutputWithEnv(env, args...)
+	return sha, err
+}
+
+// isSigningError checks if git commit-tree output indicates that signing,
+// rather than the commit itself, failed.
+func isSigningError(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "gpg failed") ||
+		strings.Contains(lower, "failed to sign") ||
+		strings.Contains(lower, "cannot run gpg") ||
+		strings.Contains(lower, "ssh-keygen")
 }
 
 // UpdateBranchRef sets refs/heads/<branch> to the given SHA.
@@ -290,8 +342,69 @@ func ReadCommitInfo(g Env, sha string) (CommitInfo, error) {
 	}, nil
 }
 
+// ReadCommitInfos reads the info of every commit in shas, running up to
+// GOMAXPROCS git processes at once. Duplicate SHAs are read once. It
+// returns the first error encountered.
+func ReadCommitInfos(g Env, shas []string) (map[string]CommitInfo, error) {
+	infos := make(map[string]CommitInfo, len(shas))
+	var unique []string
+	for _, sha := range shas {
+		if _, ok := infos[sha]; !ok {
+			infos[sha] = CommitInfo{}
+			unique = append(unique, sha)
+		}
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(unique) {
+		workers = len(unique)
+	}
+	queue := make(chan string)
+	var mu sync.Mutex
+	var firstErr error
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sha := range queue {
+				info, err := ReadCommitInfo(g, sha)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				infos[sha] = info
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, sha := range unique {
+		queue <- sha
+	}
+	close(queue)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return infos, nil
+}
+
+// CommitterDate returns the committer date of a commit.
+func CommitterDate(g Env, sha string) (time.Time, error) {
+	out, err := g.Output("show", "-s", "--format=%cI", sha)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(out))
+}
+
 // CheckoutTree replaces the work tree with the tree of the given commit.
+// The index is reset to the commit first, so files tracked by a previously
+// checked-out commit but absent from this one are removed by the clean.
 func CheckoutTree(g Env, commit string) error {
+	if err := g.Run("read-tree", commit); err != nil {
+		return err
+	}
 	if err := g.Run("clean", "-fdx"); err != nil {
 		return err
 	}
@@ -321,8 +434,8 @@ func IsAncestor(g Env, ancestorSHA, descendantSHA string) bool {
 }
 
 // Push pushes a single refspec to the named remote. Returns ErrPushRejected
-// (wrapped) for non-fast-forward rejections, distinguishing them from
-// auth/network errors.
+// (wrapped) for non-fast-forward rejections and ErrPushAuth (wrapped) for
+// authentication failures, distinguishing them from network errors.
 func Push(repoDir, remoteName, refspec string) error {
 	cmd := exec.Command("git", "-C", repoDir, "push", remoteName, refspec)
 	output, err := cmd.CombinedOutput()
@@ -337,9 +450,69 @@ func Push(repoDir, remoteName, refspec string) error {
 	if trimmed == "" {
 		trimmed = err.Error()
 	}
+	if IsPushAuthFailure(msg) {
+		return fmt.Errorf("failed to push '%s': %w: %s", refspec, ErrPushAuth, trimmed)
+	}
 	return fmt.Errorf("failed to push '%s': %s", refspec, trimmed)
 }
 
+// IsPushAuthFailure checks if git push output indicates that the remote
+// rejected the credentials, or that git could not obtain any.
+func IsPushAuthFailure(output string) bool {
+	lower := strings.ToLower(output)
+	return strings.Contains(lower, "authentication failed") ||
+		strings.Contains(lower, "could not read username") ||
+		strings.Contains(lower, "could not read password") ||
+		strings.Contains(lower, "invalid username or password") ||
+		strings.Contains(lower, "terminal prompts disabled") ||
+		strings.Contains(lower, "permission denied (publickey") ||
+		(strings.Contains(lower, "permission to ") && strings.Contains(lower, " denied")) ||
+		strings.Contains(lower, "the requested url returned error: 403")
+}
+
+// RemoteURL returns the URL of the named remote in repoDir.
+func RemoteURL(repoDir, remoteName string) (string, error) {
+	out, err := exec.Command("git", "-C", repoDir, "remote", "get-url", remoteName).Output()
+	if err != nil {
+		return "", fmt.Errorf("git remote get-url %s: %w", remoteName, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// PushAuthHint suggests how to fix an authentication failure when pushing
+// to remoteName, based on the kind of URL it points to.
+func PushAuthHint(remoteName, remoteURL string) string {
+	lower := strings.ToLower(remoteURL)
+	isGitHub := strings.Contains(lower, "github.com")
+	switch {
+	case strings.HasPrefix(lower, "git@") || strings.HasPrefix(lower, "ssh://"):
+		return fmt.Sprintf("check that your SSH key is loaded ('ssh-add -l') and added to your account, or check the URL of remote '%s'", remoteName)
+	case hasURLCredentials(remoteURL):
+		if isGitHub {
+			return fmt.Sprintf("the token in remote '%s' was rejected or has expired; create a new one and update it with 'git remote set-url %s <url>', or remove it and run 'gh auth login'", remoteName, remoteName)
+		}
+		return fmt.Sprintf("the credentials in remote '%s' were rejected or have expired; update them with 'git remote set-url %s <url>'", remoteName, remoteName)
+	case isGitHub:
+		return fmt.Sprintf("run 'gh auth login' (then 'gh auth setup-git'), or check the URL and credentials of remote '%s'", remoteName)
+	default:
+		return fmt.Sprintf("check the URL and credentials of remote '%s'", remoteName)
+	}
+}
+
+// hasURLCredentials reports whether an http(s) remote URL embeds a user or
+// token (https://<token>@host/...).
+func hasURLCredentials(remoteURL string) bool {
+	rest, ok := strings.CutPrefix(strings.ToLower(remoteURL), "https://")
+	if !ok {
+		rest, ok = strings.CutPrefix(strings.ToLower(remoteURL), "http://")
+	}
+	if !ok {
+		return false
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return strings.Contains(host, "@")
+}
+
 // IsPushRejected checks if git push output indicates a non-fast-forward
 // rejection.
 func IsPushRejected(output string) bool {
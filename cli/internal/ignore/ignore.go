@@ -121,12 +121,21 @@ func (m *Matcher) addPattern(raw string) {
 
 // Match checks if a path should be ignored
 func (m *Matcher) Match(path string, isDir bool) bool {
+	_, ignored := m.MatchingPattern(path, isDir)
+	return ignored
+}
+
+// MatchingPattern reports whether a path should be ignored and, if so, the
+// pattern (as written in .fstignore) that ignores it. Later patterns win,
+// so a negated pattern can un-ignore a path matched earlier.
+func (m *Matcher) MatchingPattern(path string, isDir bool) (string, bool) {
 	// Normalize path separators
 	path = filepath.ToSlash(path)
 
 	// Get just the filename for matching
 	name := filepath.Base(path)
 
+	matchedBy := ""
 	ignored := false
 	for _, p := range m.patterns {
 		if p.dirOnly && !isDir {
@@ -151,10 +160,14 @@ func (m *Matcher) Match(path string, isDir bool) bool {
 
 		if matched {
 			ignored = !p.negated
+			matchedBy = p.raw
 		}
 	}
 
-	return ignored
+	if !ignored {
+		return "", false
+	}
+	return matchedBy, true
 }
 
 // ShouldInclude returns true if the path should be included (not ignored)
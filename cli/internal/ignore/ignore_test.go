@@ -52,6 +52,24 @@ func TestMatchNegation(t *testing.T) {
 	}
 }
 
+func TestMatchingPattern(t *testing.T) {
+	m := NewMatcher([]string{
+		"*.log",
+		"build/",
+		"!keep.log",
+	})
+
+	if pat, ok := m.MatchingPattern("error.log", false); !ok || pat != "*.log" {
+		t.Fatalf("MatchingPattern(error.log) = %q, %v; want *.log, true", pat, ok)
+	}
+	if pat, ok := m.MatchingPattern("build", true); !ok || pat != "build/" {
+		t.Fatalf("MatchingPattern(build) = %q, %v; want build/, true", pat, ok)
+	}
+	if pat, ok := m.MatchingPattern("keep.log", false); ok || pat != "" {
+		t.Fatalf("MatchingPattern(keep.log) = %q, %v; want not ignored", pat, ok)
+	}
+}
+
 func TestPathNormalization(t *testing.T) {
 	m := NewMatcher([]string{"dir"})
 	path := filepath.Join("dir", "file.txt")
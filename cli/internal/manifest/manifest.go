@@ -7,9 +7,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ankitiscracked/fastest/cli/internal/ignore"
 )
@@ -58,9 +63,87 @@ func HashFile(path string) (string, error) {
 // hashing strategies (direct hash vs stat-cache-accelerated).
 type fileHasher func(absPath, relPath string, info os.FileInfo) (string, error)
 
+// hashJob is a regular file found by the walk whose hash is still needed.
+type hashJob struct {
+	index   int // position in Manifest.Files
+	absPath string
+	relPath string
+	info    os.FileInfo
+}
+
+// hashWorkers returns how many files are hashed concurrently: the
+// FST_HASH_WORKERS environment variable if set to a positive number,
+// GOMAXPROCS otherwise.
+func hashWorkers() int {
+	if n, err := strconv.Atoi(os.Getenv("FST_HASH_WORKERS")); err == nil && n > 0 {
+		return n
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// hashFiles fills in the hash of every job's entry using a bounded pool of
+// workers, returning the first error encountered.
+func hashFiles(files []FileEntry, jobs []hashJob, hashFn fileHasher) error {
+	workers := hashWorkers()
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+	if workers <= 1 {
+		for _, j := range jobs {
+			hash, err := hashFn(j.absPath, j.relPath, j.info)
+			if err != nil {
+				return err
+			}
+			files[j.index].Hash = hash
+		}
+		return nil
+	}
+
+	queue := make(chan hashJob)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				hash, err := hashFn(j.absPath, j.relPath, j.info)
+				if err != nil {
+					errs <- err
+					return
+				}
+				files[j.index].Hash = hash
+			}
+		}()
+	}
+
+	var firstErr error
+	for _, j := range jobs {
+		if firstErr != nil {
+			break
+		}
+		select {
+		case queue <- j:
+		case firstErr = <-errs:
+		}
+	}
+	close(queue)
+	wg.Wait()
+	if firstErr == nil {
+		select {
+		case firstErr = <-errs:
+		default:
+		}
+	}
+	return firstErr
+}
+
 // generateWith creates a manifest using the provided file hashing function.
 // This is the shared walk logic used by both Generate and GenerateWithCache.
-func generateWith(root string, hashFn fileHasher) (*Manifest, error) {
+// With includeModTime, file entries record their modification time.
+// Files are hashed concurrently after the walk, so hashFn must be safe for
+// concurrent use.
+func generateWith(root string, includeModTime bool, hashFn fileHasher) (*Manifest, error) {
 	matcher, err := ignore.LoadFromDir(root)
 	if err != nil {
 		return nil, err
@@ -70,6 +153,7 @@ func generateWith(root string, hashFn fileHasher) (*Manifest, error) {
 		Version: "1",
 		Files:   []FileEntry{},
 	}
+	var jobs []hashJob
 
 	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -116,18 +200,17 @@ func generateWith(root string, hashFn fileHasher) (*Manifest, error) {
 			return nil
 		}
 
-		hash, err := hashFn(path, relPath, info)
-		if err != nil {
-			return err
-		}
-
-		m.Files = append(m.Files, FileEntry{
+		jobs = append(jobs, hashJob{index: len(m.Files), absPath: path, relPath: relPath, info: info})
+		entry := FileEntry{
 			Type: EntryTypeFile,
 			Path: relPath,
-			Hash: hash,
 			Size: info.Size(),
 			Mode: uint32(info.Mode().Perm()),
-		})
+		}
+		if includeModTime {
+			entry.ModTime = info.ModTime().Unix()
+		}
+		m.Files = append(m.Files, entry)
 		return nil
 	})
 
@@ -135,6 +218,10 @@ func generateWith(root string, hashFn fileHasher) (*Manifest, error) {
 		return nil, err
 	}
 
+	if err := hashFiles(m.Files, jobs, hashFn); err != nil {
+		return nil, err
+	}
+
 	sort.Slice(m.Files, func(i, j int) bool {
 		if m.Files[i].Path == m.Files[j].Path {
 			return m.Files[i].Type < m.Files[j].Type
@@ -146,12 +233,65 @@ func generateWith(root string, hashFn fileHasher) (*Manifest, error) {
 }
 
 // Generate creates a manifest for a directory, hashing every file from scratch.
+// With includeModTime, file modification times are recorded, which changes
+// the manifest hash but not how entries compare in Diff.
 func Generate(root string, includeModTime bool) (*Manifest, error) {
-	return generateWith(root, func(absPath, relPath string, info os.FileInfo) (string, error) {
+	return generateWith(root, includeModTime, func(absPath, relPath string, info os.FileInfo) (string, error) {
 		return HashFile(absPath)
 	})
 }
 
+// IgnoredPath is a path present in the working tree that manifest
+// generation skips because an ignore pattern matches it.
+type IgnoredPath struct {
+	Path    string `json:"path"`
+	IsDir   bool   `json:"is_dir,omitempty"`
+	Pattern string `json:"pattern"`
+}
+
+// ListIgnored walks root the same way Generate does and returns the paths
+// it would skip due to ignore rules, with the pattern that matched each.
+// Ignored directories are reported once without descending into them, and
+// fst's own .fst directory is never reported.
+func ListIgnored(root string) ([]IgnoredPath, error) {
+	matcher, err := ignore.LoadFromDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var ignored []IgnoredPath
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		pattern, ok := matcher.MatchingPattern(relPath, info.IsDir())
+		if !ok {
+			return nil
+		}
+		if relPath != ".fst" {
+			ignored = append(ignored, IgnoredPath{Path: relPath, IsDir: info.IsDir(), Pattern: pattern})
+		}
+		if info.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ignored, nil
+}
+
 // ToJSON converts the manifest to canonical JSON
 func (m *Manifest) ToJSON() ([]byte, error) {
 	return json.MarshalIndent(m, "", "  ")
@@ -212,6 +352,19 @@ func (m *Manifest) validate() error {
 
 // Diff compares two manifests and returns the differences
 func Diff(base, current *Manifest) (added, modified, deleted []string) {
+	return diff(base, current, entriesEqual)
+}
+
+// DiffIgnoringMode is like Diff but doesn't report entries whose only change
+// is their permission bits.
+func DiffIgnoringMode(base, current *Manifest) (added, modified, deleted []string) {
+	return diff(base, current, func(a, b FileEntry) bool {
+		a.Mode = b.Mode
+		return entriesEqual(a, b)
+	})
+}
+
+func diff(base, current *Manifest, equal func(a, b FileEntry) bool) (added, modified, deleted []string) {
 	baseMap := make(map[string]FileEntry)
 	for _, f := range base.Files {
 		baseMap[f.Path] = f
@@ -226,7 +379,7 @@ func Diff(base, current *Manifest) (added, modified, deleted []string) {
 	for _, f := range current.Files {
 		if baseFile, exists := baseMap[f.Path]; !exists {
 			added = append(added, f.Path)
-		} else if !entriesEqual(baseFile, f) {
+		} else if !equal(baseFile, f) {
 			modified = append(modified, f.Path)
 		}
 	}
@@ -245,6 +398,118 @@ func Diff(base, current *Manifest) (added, modified, deleted []string) {
 	return added, modified, deleted
 }
 
+// Select returns a copy of base in which the entries at paths are taken
+// from current: paths present in current are added or updated, paths
+// missing from it are removed. Directories of current that contain a
+// selected entry are carried over too, so added files keep their parents.
+func Select(base, current *Manifest, paths []string) *Manifest {
+	currentMap := make(map[string]FileEntry, len(current.Files))
+	for _, f := range current.Files {
+		currentMap[f.Path] = f
+	}
+	selected := make(map[string]FileEntry, len(base.Files))
+	for _, f := range base.Files {
+		selected[f.Path] = f
+	}
+
+	for _, p := range paths {
+		f, ok := currentMap[p]
+		if !ok {
+			delete(selected, p)
+			continue
+		}
+		selected[p] = f
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			if _, exists := selected[dir]; exists {
+				break
+			}
+			if d, ok := currentMap[dir]; ok && d.Type == EntryTypeDir {
+				selected[dir] = d
+			}
+		}
+	}
+
+	m := &Manifest{Version: base.Version, Files: make([]FileEntry, 0, len(selected))}
+	if m.Version == "" {
+		m.Version = current.Version
+	}
+	for _, f := range selected {
+		m.Files = append(m.Files, f)
+	}
+	sort.Slice(m.Files, func(i, j int) bool {
+		return m.Files[i].Path < m.Files[j].Path
+	})
+	return m
+}
+
+// SetEntry adds f to the manifest, replacing any entry at the same path,
+// and adds directory entries for its parents that are missing.
+func (m *Manifest) SetEntry(f FileEntry) {
+	have := make(map[string]bool, len(m.Files))
+	files := make([]FileEntry, 0, len(m.Files)+1)
+	for _, e := range m.Files {
+		if e.Path == f.Path {
+			continue
+		}
+		have[e.Path] = true
+		files = append(files, e)
+	}
+	files = append(files, f)
+	for dir := path.Dir(f.Path); dir != "." && !have[dir]; dir = path.Dir(dir) {
+		files = append(files, FileEntry{Type: EntryTypeDir, Path: dir, Mode: 0755})
+		have[dir] = true
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	m.Files = files
+}
+
+// KeepModesFrom resets the mode of every file and directory whose content
+// matches the same path in base to base's mode, so that mode-only changes
+// don't change the manifest hash.
+func (m *Manifest) KeepModesFrom(base *Manifest) {
+	if base == nil {
+		return
+	}
+	baseMap := make(map[string]FileEntry, len(base.Files))
+	for _, f := range base.Files {
+		baseMap[f.Path] = f
+	}
+	for i, f := range m.Files {
+		baseFile, ok := baseMap[f.Path]
+		if !ok || baseFile.Type != f.Type || baseFile.Mode == f.Mode {
+			continue
+		}
+		if f.Type == EntryTypeDir || (f.Type == EntryTypeFile && f.Hash == baseFile.Hash) {
+			m.Files[i].Mode = baseFile.Mode
+		}
+	}
+}
+
+// KeepModTimesFrom copies the recorded modification time of every file
+// whose content matches the same path in base, so that a scan without
+// modification times hashes like a --keep-mtime manifest of the same tree.
+func (m *Manifest) KeepModTimesFrom(base *Manifest) {
+	if base == nil {
+		return
+	}
+	baseMap := make(map[string]FileEntry, len(base.Files))
+	for _, f := range base.Files {
+		if f.Type == EntryTypeFile && f.ModTime != 0 {
+			baseMap[f.Path] = f
+		}
+	}
+	if len(baseMap) == 0 {
+		return
+	}
+	for i, f := range m.Files {
+		if baseFile, ok := baseMap[f.Path]; ok && f.Type == EntryTypeFile && f.Hash == baseFile.Hash {
+			m.Files[i].ModTime = baseFile.ModTime
+		}
+	}
+}
+
 func entriesEqual(a, b FileEntry) bool {
 	if a.Type != b.Type {
 		return false
@@ -261,6 +526,51 @@ func entriesEqual(a, b FileEntry) bool {
 	}
 }
 
+// RestoreModTime sets the modification time of path to the one recorded in
+// f. Entries without a recorded time are left alone.
+func RestoreModTime(path string, f FileEntry) error {
+	if f.ModTime == 0 {
+		return nil
+	}
+	t := time.Unix(f.ModTime, 0)
+	return os.Chtimes(path, t, t)
+}
+
+// PruneEmptyDirs removes the now-empty ancestors of the removed paths
+// under root, deepest first. Directories recorded in m, .git and .fst are
+// kept; os.Remove refuses non-empty directories, so anything still holding
+// files (including ignored ones) is left alone.
+func (m *Manifest) PruneEmptyDirs(root string, removed []string) {
+	keep := make(map[string]bool)
+	for _, f := range m.DirEntries() {
+		keep[f.Path] = true
+	}
+
+	dirs := make(map[string]bool)
+	for _, relPath := range removed {
+		for dir := path.Dir(relPath); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+	ordered := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		top := strings.SplitN(dir, "/", 2)[0]
+		if !keep[dir] && top != ".git" && top != ".fst" {
+			ordered = append(ordered, dir)
+		}
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		di, dj := strings.Count(ordered[i], "/"), strings.Count(ordered[j], "/")
+		if di != dj {
+			return di > dj
+		}
+		return ordered[i] < ordered[j]
+	})
+	for _, dir := range ordered {
+		_ = os.Remove(filepath.Join(root, filepath.FromSlash(dir)))
+	}
+}
+
 func (m *Manifest) FileEntries() []FileEntry {
 	files := make([]FileEntry, 0, len(m.Files))
 	for _, f := range m.Files {
@@ -312,3 +622,39 @@ func (m *Manifest) FileCount() int {
 	}
 	return count
 }
+
+// RenameSource reports whether path in current was renamed from another file
+// in base. A rename is recognised when path is absent from base and base has
+// a file with identical content that is absent from current. Returns the old
+// path when a match is found.
+func RenameSource(base, current *Manifest, path string) (string, bool) {
+	var entry *FileEntry
+	currentPaths := make(map[string]struct{}, len(current.Files))
+	for i := range current.Files {
+		currentPaths[current.Files[i].Path] = struct{}{}
+		if current.Files[i].Path == path {
+			entry = &current.Files[i]
+		}
+	}
+	if entry == nil || entry.Type != EntryTypeFile {
+		return "", false
+	}
+
+	var candidates []string
+	for _, f := range base.Files {
+		if f.Path == path {
+			return "", false
+		}
+		if f.Type != EntryTypeFile || f.Hash != entry.Hash {
+			continue
+		}
+		if _, stillPresent := currentPaths[f.Path]; !stillPresent {
+			candidates = append(candidates, f.Path)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.Strings(candidates)
+	return candidates[0], true
+}
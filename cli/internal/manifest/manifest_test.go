@@ -1,6 +1,7 @@
 package manifest
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -112,6 +113,45 @@ func TestGenerateIgnoresAndModes(t *testing.T) {
 	}
 }
 
+func TestListIgnored(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{".fst", "build", "src"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+	files := map[string]string{
+		".fstignore":     "build/\n*.log\n",
+		".fst/config":    "x",
+		"build/out.bin":  "x",
+		"src/main.go":    "x",
+		"src/debug.log":  "x",
+		"important.conf": "x",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(filepath.Join(root, path), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	ignored, err := ListIgnored(root)
+	if err != nil {
+		t.Fatalf("ListIgnored: %v", err)
+	}
+	want := []IgnoredPath{
+		{Path: "build", IsDir: true, Pattern: "build/"},
+		{Path: "src/debug.log", Pattern: "*.log"},
+	}
+	if len(ignored) != len(want) {
+		t.Fatalf("ListIgnored = %+v, want %+v", ignored, want)
+	}
+	for i := range want {
+		if ignored[i] != want[i] {
+			t.Fatalf("ListIgnored[%d] = %+v, want %+v", i, ignored[i], want[i])
+		}
+	}
+}
+
 func TestFromJSONRejectsEmptyPath(t *testing.T) {
 	data := []byte(`{"version":"1","files":[{"type":"file","path":"","hash":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","size":1,"mode":420}]}`)
 	_, err := FromJSON(data)
@@ -203,3 +243,183 @@ func TestDiff(t *testing.T) {
 		t.Fatalf("deleted mismatch: %v", deleted)
 	}
 }
+
+func TestDiffIgnoringMode(t *testing.T) {
+	base := &Manifest{
+		Version: "1",
+		Files: []FileEntry{
+			{Type: EntryTypeFile, Path: "run.sh", Hash: "h1", Mode: 0644},
+			{Type: EntryTypeFile, Path: "edit.sh", Hash: "h2", Mode: 0644},
+		},
+	}
+	current := &Manifest{
+		Version: "1",
+		Files: []FileEntry{
+			{Type: EntryTypeFile, Path: "run.sh", Hash: "h1", Mode: 0755},
+			{Type: EntryTypeFile, Path: "edit.sh", Hash: "h3", Mode: 0755},
+		},
+	}
+
+	if _, modified, _ := Diff(base, current); strings.Join(modified, ",") != "edit.sh,run.sh" {
+		t.Fatalf("expected Diff to report mode changes, got %v", modified)
+	}
+	if _, modified, _ := DiffIgnoringMode(base, current); strings.Join(modified, ",") != "edit.sh" {
+		t.Fatalf("expected only the content change, got %v", modified)
+	}
+
+	current.KeepModesFrom(base)
+	if current.Files[0].Mode != 0644 {
+		t.Fatalf("expected run.sh to keep mode 0644, got %o", current.Files[0].Mode)
+	}
+	if current.Files[1].Mode != 0755 {
+		t.Fatalf("expected edited edit.sh to keep its new mode, got %o", current.Files[1].Mode)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	base := &Manifest{
+		Version: "1",
+		Files: []FileEntry{
+			{Type: EntryTypeFile, Path: "a.txt", Hash: "a1"},
+			{Type: EntryTypeFile, Path: "b.txt", Hash: "b1"},
+			{Type: EntryTypeFile, Path: "gone.txt", Hash: "g1"},
+		},
+	}
+	current := &Manifest{
+		Version: "1",
+		Files: []FileEntry{
+			{Type: EntryTypeFile, Path: "a.txt", Hash: "a2"},
+			{Type: EntryTypeFile, Path: "b.txt", Hash: "b2"},
+			{Type: EntryTypeDir, Path: "new"},
+			{Type: EntryTypeFile, Path: "new/c.txt", Hash: "c1"},
+		},
+	}
+
+	m := Select(base, current, []string{"a.txt", "new/c.txt", "gone.txt"})
+	var got []string
+	for _, f := range m.Files {
+		got = append(got, f.Path+"="+f.Hash)
+	}
+	want := "a.txt=a2,b.txt=b1,new=,new/c.txt=c1"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("Select = %s, want %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestSetEntry(t *testing.T) {
+	m := &Manifest{
+		Version: "1",
+		Files: []FileEntry{
+			{Type: EntryTypeFile, Path: "a.txt", Hash: "a1"},
+			{Type: EntryTypeDir, Path: "logs"},
+			{Type: EntryTypeFile, Path: "z.txt", Hash: "z1"},
+		},
+	}
+
+	m.SetEntry(FileEntry{Type: EntryTypeFile, Path: "a.txt", Hash: "a2"})
+	m.SetEntry(FileEntry{Type: EntryTypeFile, Path: "logs/build.log", Hash: "l1"})
+	m.SetEntry(FileEntry{Type: EntryTypeFile, Path: "out/report/r.txt", Hash: "r1"})
+
+	var got []string
+	for _, f := range m.Files {
+		got = append(got, f.Path+"="+f.Hash)
+	}
+	want := "a.txt=a2,logs=,logs/build.log=l1,out=,out/report=,out/report/r.txt=r1,z.txt=z1"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("SetEntry = %s, want %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestRenameSource(t *testing.T) {
+	base := &Manifest{
+		Version: "1",
+		Files: []FileEntry{
+			{Type: EntryTypeFile, Path: "old.txt", Hash: "h1"},
+			{Type: EntryTypeFile, Path: "keep.txt", Hash: "h2"},
+		},
+	}
+	current := &Manifest{
+		Version: "1",
+		Files: []FileEntry{
+			{Type: EntryTypeFile, Path: "new.txt", Hash: "h1"},
+			{Type: EntryTypeFile, Path: "keep.txt", Hash: "h2"},
+			{Type: EntryTypeFile, Path: "copy.txt", Hash: "h2"},
+		},
+	}
+
+	if old, ok := RenameSource(base, current, "new.txt"); !ok || old != "old.txt" {
+		t.Fatalf("expected rename from old.txt, got %q (%v)", old, ok)
+	}
+	// keep.txt still exists in current, so copy.txt is a copy, not a rename
+	if old, ok := RenameSource(base, current, "copy.txt"); ok {
+		t.Fatalf("expected no rename for copy.txt, got %q", old)
+	}
+	if _, ok := RenameSource(base, current, "keep.txt"); ok {
+		t.Fatalf("expected no rename for unchanged path")
+	}
+}
+
+// writeSyntheticTree creates n small files spread over subdirectories.
+func writeSyntheticTree(tb testing.TB, root string, n int) {
+	tb.Helper()
+	for i := 0; i < n; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("dir%02d", i%50))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			tb.Fatalf("mkdir: %v", err)
+		}
+		content := strings.Repeat(fmt.Sprintf("line %d\n", i), 200)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file%05d.txt", i)), []byte(content), 0644); err != nil {
+			tb.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func TestGenerateParallelMatchesSequential(t *testing.T) {
+	root := t.TempDir()
+	writeSyntheticTree(t, root, 300)
+
+	t.Setenv("FST_HASH_WORKERS", "1")
+	sequential, err := Generate(root, false)
+	if err != nil {
+		t.Fatalf("Generate (1 worker): %v", err)
+	}
+	t.Setenv("FST_HASH_WORKERS", "8")
+	parallel, err := Generate(root, false)
+	if err != nil {
+		t.Fatalf("Generate (8 workers): %v", err)
+	}
+
+	h1, _ := sequential.Hash()
+	h2, _ := parallel.Hash()
+	if h1 != h2 {
+		t.Fatalf("parallel manifest differs from sequential: %s vs %s", h2, h1)
+	}
+
+	cachePath := filepath.Join(t.TempDir(), "stat-cache.json")
+	cached, err := GenerateWithCache(root, cachePath)
+	if err != nil {
+		t.Fatalf("GenerateWithCache: %v", err)
+	}
+	if h3, _ := cached.Hash(); h3 != h1 {
+		t.Fatalf("cached manifest differs: %s vs %s", h3, h1)
+	}
+	if entries := len(LoadStatCache(cachePath).Entries); entries != 300 {
+		t.Fatalf("expected 300 stat cache entries, got %d", entries)
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	root := b.TempDir()
+	writeSyntheticTree(b, root, 5000)
+
+	for _, workers := range []string{"1", "4", "8"} {
+		b.Run("workers="+workers, func(b *testing.B) {
+			b.Setenv("FST_HASH_WORKERS", workers)
+			for i := 0; i < b.N; i++ {
+				if _, err := Generate(root, false); err != nil {
+					b.Fatalf("Generate: %v", err)
+				}
+			}
+		})
+	}
+}
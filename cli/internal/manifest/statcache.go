@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -108,15 +109,22 @@ func GenerateWithCache(root string, cachePath string) (*Manifest, error) {
 
 	cache := LoadStatCache(cachePath)
 
-	m, err := generateWith(root, func(absPath, relPath string, info os.FileInfo) (string, error) {
-		if h := cache.Lookup(relPath, info); h != "" {
+	// Files are hashed concurrently; mu guards the cache's entry map.
+	var mu sync.Mutex
+	m, err := generateWith(root, false, func(absPath, relPath string, info os.FileInfo) (string, error) {
+		mu.Lock()
+		h := cache.Lookup(relPath, info)
+		mu.Unlock()
+		if h != "" {
 			return h, nil
 		}
 		h, err := HashFile(absPath)
 		if err != nil {
 			return "", err
 		}
+		mu.Lock()
 		cache.Update(relPath, info, h)
+		mu.Unlock()
 		return h, nil
 	})
 	if err != nil {
@@ -0,0 +1,145 @@
+package manifest
+
+import "sort"
+
+// ChangeKind classifies a path in a ThreeWayDiff.
+type ChangeKind string
+
+const (
+	ChangeInSync   ChangeKind = "in_sync"  // nothing to take from source
+	ChangeApply    ChangeKind = "apply"    // take the source entry, with ThreeWayChange.Mode
+	ChangeDelete   ChangeKind = "delete"   // source deleted a file current left unchanged
+	ChangeConflict ChangeKind = "conflict" // both sides changed the file differently
+)
+
+// ThreeWayChange is the classification of one path in a ThreeWayDiff.
+// Base, Current and Source are nil where the file is absent.
+type ThreeWayChange struct {
+	Path    string
+	Kind    ChangeKind
+	Base    *FileEntry
+	Current *FileEntry
+	Source  *FileEntry
+	// Mode is the mode to give the file when taking the source side: the
+	// source's mode, merged with current's (see MergeMode) when only the
+	// source changed the content or both sides agree on it.
+	Mode uint32
+}
+
+// DeleteModify reports whether c is a conflict between a deletion on one
+// side and a modification on the other.
+func (c ThreeWayChange) DeleteModify() bool {
+	return c.Kind == ChangeConflict && (c.Current == nil || c.Source == nil)
+}
+
+// ThreeWayDiff compares the files of current and source against their
+// common ancestor base and classifies every path the source has or deleted,
+// sorted by path. Paths only current touched are left out.
+//
+// Identical content on both sides is in sync even if both made the same
+// edit independently; only the mode can still need applying. A file
+// deleted on one side and modified on the other is a delete/modify
+// conflict; deleted on one side and unchanged on the other, the deletion
+// wins (ChangeDelete when the source deleted it, so callers can choose
+// whether to propagate it). Content changed on both sides is a conflict,
+// which callers may still resolve with a line-level merge.
+func ThreeWayDiff(base, current, source *Manifest) []ThreeWayChange {
+	baseFiles := fileMap(base)
+	currentFiles := fileMap(current)
+	sourceFiles := fileMap(source)
+
+	paths := make(map[string]bool, len(sourceFiles))
+	for p := range sourceFiles {
+		paths[p] = true
+	}
+	for p := range baseFiles {
+		paths[p] = true
+	}
+
+	var changes []ThreeWayChange
+	for p := range paths {
+		baseFile, inBase := baseFiles[p]
+		currentFile, inCurrent := currentFiles[p]
+		sourceFile, inSource := sourceFiles[p]
+
+		sourceDeleted := inBase && !inSource
+		if !inSource && !sourceDeleted {
+			continue
+		}
+
+		c := ThreeWayChange{Path: p}
+		if inBase {
+			c.Base = &baseFile
+		}
+		if inCurrent {
+			c.Current = &currentFile
+		}
+		if inSource {
+			c.Source = &sourceFile
+			c.Mode = sourceFile.Mode
+		}
+
+		currentChanged := !inBase && inCurrent || (inBase && inCurrent && baseFile.Hash != currentFile.Hash)
+		sourceChanged := !inBase && inSource || (inBase && inSource && baseFile.Hash != sourceFile.Hash)
+		currentDeleted := inBase && !inCurrent
+
+		switch {
+		case inCurrent && inSource && currentFile.Hash == sourceFile.Hash:
+			c.Mode = MergeMode(baseFile.Mode, currentFile.Mode, sourceFile.Mode, inBase)
+			if c.Mode == currentFile.Mode {
+				c.Kind = ChangeInSync
+			} else {
+				c.Kind = ChangeApply
+			}
+		case currentDeleted && inSource && !sourceChanged:
+			c.Kind = ChangeInSync
+		case currentDeleted && inSource:
+			c.Kind = ChangeConflict
+		case !inCurrent && inSource:
+			c.Kind = ChangeApply
+		case sourceDeleted && inCurrent && currentChanged:
+			c.Kind = ChangeConflict
+		case sourceDeleted && inCurrent:
+			c.Kind = ChangeDelete
+		case !currentChanged && sourceChanged:
+			c.Kind = ChangeApply
+			c.Mode = MergeMode(baseFile.Mode, currentFile.Mode, sourceFile.Mode, inBase)
+		case currentChanged && sourceChanged:
+			c.Kind = ChangeConflict
+		default:
+			c.Kind = ChangeInSync
+		}
+		changes = append(changes, c)
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// MergeMode merges the modes of a file whose content both sides agree
+// on. A side that kept the base mode takes the other side's mode; when both
+// sides changed it, or there is no base, permission bits set on either side
+// are kept, so an executable bit is never silently dropped. A zero mode
+// (unknown, from older manifests) defers to the other side.
+func MergeMode(base, current, source uint32, inBase bool) uint32 {
+	switch {
+	case current == source || source == 0:
+		return current
+	case current == 0:
+		return source
+	case inBase && current == base:
+		return source
+	case inBase && source == base:
+		return current
+	default:
+		return current | source
+	}
+}
+
+func fileMap(m *Manifest) map[string]FileEntry {
+	files := make(map[string]FileEntry)
+	for _, f := range m.FileEntries() {
+		files[f.Path] = f
+	}
+	return files
+}
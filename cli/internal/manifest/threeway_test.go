@@ -0,0 +1,199 @@
+package manifest
+
+import "testing"
+
+func manifestOf(entries ...FileEntry) *Manifest {
+	m := &Manifest{Version: "1"}
+	for _, e := range entries {
+		e.Type = EntryTypeFile
+		if e.Mode == 0 {
+			e.Mode = 0644
+		}
+		m.Files = append(m.Files, e)
+	}
+	return m
+}
+
+func TestThreeWayDiff(t *testing.T) {
+	tests := []struct {
+		name                  string
+		base, current, source *Manifest
+		kind                  ChangeKind
+		deleteModify          bool
+		mode                  uint32
+		omitted               bool
+	}{
+		{
+			name:    "only source changed",
+			base:    manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current: manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			source:  manifestOf(FileEntry{Path: "f", Hash: "s"}),
+			kind:    ChangeApply,
+			mode:    0644,
+		},
+		{
+			name:    "only current changed",
+			base:    manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current: manifestOf(FileEntry{Path: "f", Hash: "c"}),
+			source:  manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			kind:    ChangeInSync,
+		},
+		{
+			name:    "both changed differently",
+			base:    manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current: manifestOf(FileEntry{Path: "f", Hash: "c"}),
+			source:  manifestOf(FileEntry{Path: "f", Hash: "s"}),
+			kind:    ChangeConflict,
+		},
+		{
+			name:    "identical independent edits",
+			base:    manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current: manifestOf(FileEntry{Path: "f", Hash: "x"}),
+			source:  manifestOf(FileEntry{Path: "f", Hash: "x"}),
+			kind:    ChangeInSync,
+		},
+		{
+			name:    "identical new files",
+			base:    manifestOf(),
+			current: manifestOf(FileEntry{Path: "f", Hash: "x"}),
+			source:  manifestOf(FileEntry{Path: "f", Hash: "x"}),
+			kind:    ChangeInSync,
+		},
+		{
+			name:    "different new files",
+			base:    manifestOf(),
+			current: manifestOf(FileEntry{Path: "f", Hash: "c"}),
+			source:  manifestOf(FileEntry{Path: "f", Hash: "s"}),
+			kind:    ChangeConflict,
+		},
+		{
+			name:    "added in source",
+			base:    manifestOf(),
+			current: manifestOf(),
+			source:  manifestOf(FileEntry{Path: "f", Hash: "s", Mode: 0755}),
+			kind:    ChangeApply,
+			mode:    0755,
+		},
+		{
+			name:    "mode-only change in source",
+			base:    manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current: manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			source:  manifestOf(FileEntry{Path: "f", Hash: "b", Mode: 0755}),
+			kind:    ChangeApply,
+			mode:    0755,
+		},
+		{
+			name:    "source edit keeps current mode change",
+			base:    manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current: manifestOf(FileEntry{Path: "f", Hash: "b", Mode: 0755}),
+			source:  manifestOf(FileEntry{Path: "f", Hash: "s"}),
+			kind:    ChangeApply,
+			mode:    0755,
+		},
+		{
+			name:    "source deleted, current unchanged",
+			base:    manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current: manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			source:  manifestOf(),
+			kind:    ChangeDelete,
+		},
+		{
+			name:         "source deleted, current modified",
+			base:         manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current:      manifestOf(FileEntry{Path: "f", Hash: "c"}),
+			source:       manifestOf(),
+			kind:         ChangeConflict,
+			deleteModify: true,
+		},
+		{
+			name:    "current deleted, source unchanged",
+			base:    manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current: manifestOf(),
+			source:  manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			kind:    ChangeInSync,
+		},
+		{
+			name:         "current deleted, source modified",
+			base:         manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current:      manifestOf(),
+			source:       manifestOf(FileEntry{Path: "f", Hash: "s"}),
+			kind:         ChangeConflict,
+			deleteModify: true,
+			mode:         0644,
+		},
+		{
+			name:    "deleted on both sides",
+			base:    manifestOf(FileEntry{Path: "f", Hash: "b"}),
+			current: manifestOf(),
+			source:  manifestOf(),
+			kind:    ChangeInSync,
+		},
+		{
+			name:    "added only in current",
+			base:    manifestOf(),
+			current: manifestOf(FileEntry{Path: "f", Hash: "c"}),
+			source:  manifestOf(),
+			omitted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := ThreeWayDiff(tt.base, tt.current, tt.source)
+			if tt.omitted {
+				if len(changes) != 0 {
+					t.Fatalf("expected no changes, got %+v", changes)
+				}
+				return
+			}
+			if len(changes) != 1 {
+				t.Fatalf("expected 1 change, got %+v", changes)
+			}
+			c := changes[0]
+			if c.Path != "f" || c.Kind != tt.kind {
+				t.Fatalf("expected %s for f, got %s for %s", tt.kind, c.Kind, c.Path)
+			}
+			if c.DeleteModify() != tt.deleteModify {
+				t.Fatalf("expected DeleteModify %v, got %v", tt.deleteModify, c.DeleteModify())
+			}
+			if tt.mode != 0 && c.Mode != tt.mode {
+				t.Fatalf("expected mode %o, got %o", tt.mode, c.Mode)
+			}
+		})
+	}
+}
+
+func TestThreeWayDiffSortedAndIgnoresNonFiles(t *testing.T) {
+	base := manifestOf(FileEntry{Path: "b", Hash: "1"}, FileEntry{Path: "a", Hash: "1"})
+	source := manifestOf(FileEntry{Path: "b", Hash: "2"}, FileEntry{Path: "a", Hash: "2"}, FileEntry{Path: "c", Hash: "3"})
+	source.Files = append(source.Files, FileEntry{Type: EntryTypeDir, Path: "dir", Mode: 0755})
+
+	changes := ThreeWayDiff(base, base, source)
+	var paths []string
+	for _, c := range changes {
+		paths = append(paths, c.Path)
+	}
+	if len(paths) != 3 || paths[0] != "a" || paths[1] != "b" || paths[2] != "c" {
+		t.Fatalf("expected changes for a, b, c in order, got %v", paths)
+	}
+}
+
+func TestMergeMode(t *testing.T) {
+	tests := []struct {
+		base, current, source uint32
+		inBase                bool
+		want                  uint32
+	}{
+		{0644, 0644, 0755, true, 0755},
+		{0644, 0755, 0644, true, 0755},
+		{0644, 0700, 0655, true, 0755},
+		{0, 0644, 0755, false, 0755},
+		{0644, 0, 0755, true, 0755},
+		{0644, 0755, 0, true, 0755},
+	}
+	for _, tt := range tests {
+		if got := MergeMode(tt.base, tt.current, tt.source, tt.inBase); got != tt.want {
+			t.Errorf("MergeMode(%o, %o, %o, %v) = %o, want %o", tt.base, tt.current, tt.source, tt.inBase, got, tt.want)
+		}
+	}
+}
@@ -1,45 +1,173 @@
 package store
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 )
 
-// ReadBlob reads a blob's content by its hash.
-func (s *Store) ReadBlob(hash string) ([]byte, error) {
+// BlobStore is content-addressed storage for file contents, keyed by the
+// hex SHA-256 of the content (the hash recorded in manifests).
+type BlobStore interface {
+	// Get returns the content stored under hash.
+	Get(hash string) ([]byte, error)
+	// Put stores data under its SHA-256 and returns the hash.
+	Put(data []byte) (string, error)
+	// PutHashed stores data under a hash the caller has already computed,
+	// skipping the rehash. Existing blobs are left untouched.
+	PutHashed(hash string, data []byte) error
+	// Overwrite stores data under hash even if a blob is already stored
+	// there, atomically replacing it.
+	Overwrite(hash string, data []byte) error
+	// Has reports whether a blob is stored under hash.
+	Has(hash string) bool
+	// Size returns the size in bytes of the blob stored under hash.
+	Size(hash string) (int64, error)
+}
+
+// FSBlobStore is a BlobStore that keeps one file per blob in a directory.
+// Blobs consolidated by Repack are read from pack files in its packs
+// subdirectory; new blobs are always written loose.
+type FSBlobStore struct {
+	dir string
+
+	mu           sync.Mutex
+	packed       map[string]packedBlob // pack index, loaded on first miss
+	packsLoaded  bool
+	packsModTime time.Time // packs directory mtime when packed was loaded
+}
+
+// NewFSBlobStore returns a BlobStore backed by dir, which must exist
+// before the first write.
+func NewFSBlobStore(dir string) *FSBlobStore {
+	return &FSBlobStore{dir: dir}
+}
+
+// Get reads a blob's content by its hash.
+func (b *FSBlobStore) Get(hash string) ([]byte, error) {
 	if hash == "" {
 		return nil, fmt.Errorf("empty blob hash")
 	}
-	path := filepath.Join(s.blobsDir, hash)
-	data, err := os.ReadFile(path)
+	data, err := os.ReadFile(b.path(hash))
 	if err != nil {
+		if loc, ok := b.lookupPacked(hash); ok {
+			return readPacked(loc)
+		}
 		return nil, fmt.Errorf("blob not found: %w", err)
 	}
 	return data, nil
 }
 
-// WriteBlob writes content to the blob store under the given hash.
-// Skips writing if the blob already exists (content-addressed).
-func (s *Store) WriteBlob(hash string, content []byte) error {
+// Put writes data under its SHA-256 hash.
+func (b *FSBlobStore) Put(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	return hash, b.PutHashed(hash, data)
+}
+
+// PutHashed writes data under hash. Skips writing if the blob already
+// exists (content-addressed).
+func (b *FSBlobStore) PutHashed(hash string, data []byte) error {
 	if hash == "" {
 		return fmt.Errorf("empty blob hash")
 	}
-	path := filepath.Join(s.blobsDir, hash)
-	if _, err := os.Stat(path); err == nil {
+	if b.Has(hash) {
 		return nil // already exists
 	}
-	return AtomicWriteFile(path, content, 0644)
+	return AtomicWriteFile(b.path(hash), data, 0644)
+}
+
+// Overwrite writes data under hash unconditionally. The loose file takes
+// precedence over a packed copy of the same blob.
+func (b *FSBlobStore) Overwrite(hash string, data []byte) error {
+	if hash == "" {
+		return fmt.Errorf("empty blob hash")
+	}
+	return AtomicWriteFile(b.path(hash), data, 0644)
+}
+
+// Has checks if a blob with the given hash exists.
+func (b *FSBlobStore) Has(hash string) bool {
+	if _, err := os.Stat(b.path(hash)); err == nil {
+		return true
+	}
+	_, ok := b.lookupPacked(hash)
+	return ok
+}
+
+// Size returns the size of the blob with the given hash.
+func (b *FSBlobStore) Size(hash string) (int64, error) {
+	info, err := os.Stat(b.path(hash))
+	if err != nil {
+		if loc, ok := b.lookupPacked(hash); ok {
+			return loc.length, nil
+		}
+		return 0, fmt.Errorf("blob not found: %w", err)
+	}
+	return info.Size(), nil
+}
+
+func (b *FSBlobStore) path(hash string) string {
+	return filepath.Join(b.dir, hash)
+}
+
+// Blobs returns the store's blob storage.
+func (s *Store) Blobs() BlobStore { return s.blobs }
+
+// WithBlobStore returns a copy of s that reads and writes blobs through b,
+// keeping snapshots and manifests where they are.
+func (s *Store) WithBlobStore(b BlobStore) *Store {
+	clone := *s
+	clone.blobs = b
+	return &clone
+}
+
+// ReadBlob reads a blob's content by its hash.
+func (s *Store) ReadBlob(hash string) ([]byte, error) {
+	return s.blobs.Get(hash)
+}
+
+// WriteBlob writes content to the blob store under the given hash.
+// Skips writing if the blob already exists (content-addressed).
+func (s *Store) WriteBlob(hash string, content []byte) error {
+	return s.blobs.PutHashed(hash, content)
+}
+
+// OverwriteBlob writes content under the given hash even if a blob is
+// already stored there.
+func (s *Store) OverwriteBlob(hash string, content []byte) error {
+	return s.blobs.Overwrite(hash, content)
 }
 
 // BlobExists checks if a blob with the given hash exists.
 func (s *Store) BlobExists(hash string) bool {
-	path := filepath.Join(s.blobsDir, hash)
-	_, err := os.Stat(path)
-	return err == nil
+	return s.blobs.Has(hash)
+}
+
+// MissingBlobs returns the hashes in hashes that have no stored blob, in
+// the order they first appear. Each distinct hash is checked once, so a
+// batch collected from several manifests costs one lookup per blob.
+func (s *Store) MissingBlobs(hashes []string) []string {
+	checked := make(map[string]bool, len(hashes))
+	var missing []string
+	for _, hash := range hashes {
+		if checked[hash] {
+			continue
+		}
+		checked[hash] = true
+		if !s.blobs.Has(hash) {
+			missing = append(missing, hash)
+		}
+	}
+	return missing
 }
 
-// BlobPath returns the filesystem path for a blob by its hash.
+// BlobPath returns the filesystem path for a blob by its hash. Blobs that
+// have been packed by Repack no longer exist at this path.
 func (s *Store) BlobPath(hash string) string {
 	return filepath.Join(s.blobsDir, hash)
 }
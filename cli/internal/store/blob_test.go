@@ -1,9 +1,62 @@
 package store
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
+// memBlobStore is an in-memory BlobStore for tests.
+type memBlobStore struct {
+	blobs map[string][]byte
+}
+
+func newMemBlobStore() *memBlobStore {
+	return &memBlobStore{blobs: make(map[string][]byte)}
+}
+
+func (m *memBlobStore) Get(hash string) ([]byte, error) {
+	data, ok := m.blobs[hash]
+	if !ok {
+		return nil, fmt.Errorf("blob not found: %s", hash)
+	}
+	return data, nil
+}
+
+func (m *memBlobStore) Put(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	return hash, m.PutHashed(hash, data)
+}
+
+func (m *memBlobStore) PutHashed(hash string, data []byte) error {
+	if _, ok := m.blobs[hash]; !ok {
+		m.blobs[hash] = append([]byte(nil), data...)
+	}
+	return nil
+}
+
+func (m *memBlobStore) Overwrite(hash string, data []byte) error {
+	m.blobs[hash] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *memBlobStore) Has(hash string) bool {
+	_, ok := m.blobs[hash]
+	return ok
+}
+
+func (m *memBlobStore) Size(hash string) (int64, error) {
+	data, ok := m.blobs[hash]
+	if !ok {
+		return 0, fmt.Errorf("blob not found: %s", hash)
+	}
+	return int64(len(data)), nil
+}
+
 func TestWriteReadBlob(t *testing.T) {
 	s, _ := setupStore(t)
 
@@ -47,6 +100,16 @@ func TestBlobExists(t *testing.T) {
 	}
 }
 
+func TestMissingBlobs(t *testing.T) {
+	s, _ := setupStore(t)
+	s.WriteBlob("stored", []byte("x"))
+
+	missing := s.MissingBlobs([]string{"a", "stored", "b", "a"})
+	if len(missing) != 2 || missing[0] != "a" || missing[1] != "b" {
+		t.Fatalf("expected [a b] once each, got %v", missing)
+	}
+}
+
 func TestBlobPath(t *testing.T) {
 	s, _ := setupStore(t)
 	path := s.BlobPath("somehash")
@@ -70,3 +133,52 @@ func TestReadBlobEmptyHash(t *testing.T) {
 		t.Fatalf("expected error for empty hash")
 	}
 }
+
+func TestFSBlobStorePut(t *testing.T) {
+	s, _ := setupStore(t)
+	blobs := s.Blobs()
+
+	hash, err := blobs.Put([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	sum := sha256.Sum256([]byte("hello"))
+	if hash != hex.EncodeToString(sum[:]) {
+		t.Fatalf("expected SHA-256 hash, got %s", hash)
+	}
+	if !blobs.Has(hash) {
+		t.Fatalf("expected blob to exist after Put")
+	}
+	if size, err := blobs.Size(hash); err != nil || size != 5 {
+		t.Fatalf("Size = %d, %v; want 5", size, err)
+	}
+	if _, err := os.Stat(filepath.Join(s.BlobsDir(), hash)); err != nil {
+		t.Fatalf("expected blob file on disk: %v", err)
+	}
+	if _, err := blobs.Size("missing"); err == nil {
+		t.Fatalf("expected error for missing blob size")
+	}
+}
+
+func TestStoreWithBlobStore(t *testing.T) {
+	s, _ := setupStore(t)
+	mem := newMemBlobStore()
+	ms := s.WithBlobStore(mem)
+
+	if err := ms.WriteBlob("hash1", []byte("in memory")); err != nil {
+		t.Fatalf("WriteBlob: %v", err)
+	}
+	if !mem.Has("hash1") {
+		t.Fatalf("expected write to go to the swapped-in store")
+	}
+	if s.BlobExists("hash1") {
+		t.Fatalf("expected the original store to be unaffected")
+	}
+	data, err := ms.ReadBlob("hash1")
+	if err != nil || string(data) != "in memory" {
+		t.Fatalf("ReadBlob = %q, %v", data, err)
+	}
+	if entries, _ := os.ReadDir(s.BlobsDir()); len(entries) != 0 {
+		t.Fatalf("expected no blobs on disk, found %d", len(entries))
+	}
+}
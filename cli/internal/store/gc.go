@@ -4,12 +4,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 // GCOpts configures a garbage collection operation.
 type GCOpts struct {
 	DryRun bool
+	// Repack consolidates the remaining blobs into a single pack file
+	// after collecting (see Store.Repack).
+	Repack bool
 }
 
 // GCResult contains the outcome of garbage collection.
@@ -20,6 +25,7 @@ type GCResult struct {
 	DeletedSnapshots     int
 	DeletedManifests     int
 	DeletedBlobs         int
+	Repack               *RepackResult // set if blobs were repacked
 }
 
 // GC performs garbage collection on the store, removing unreachable snapshots,
@@ -67,28 +73,19 @@ func (s *Store) GC(opts GCOpts) (*GCResult, error) {
 		}
 	}
 
-	// Collect blob hashes referenced by reachable manifests
-	referencedBlobs := make(map[string]struct{})
-	for hash := range reachableManifests {
-		m, err := s.LoadManifest(hash)
-		if err != nil {
-			continue
-		}
-		for _, f := range m.FileEntries() {
-			referencedBlobs[f.Hash] = struct{}{}
-		}
+	referencedBlobs, err := s.blobsReferencedBy(reachableManifests)
+	if err != nil {
+		return nil, err
 	}
 
-	// Find orphaned blobs
-	if entries, err := os.ReadDir(s.blobsDir); err == nil {
-		for _, entry := range entries {
-			if entry.IsDir() {
-				continue
-			}
-			if _, ok := referencedBlobs[entry.Name()]; !ok {
-				result.OrphanedBlobs = append(result.OrphanedBlobs, entry.Name())
+	// Find orphaned blobs, loose or packed
+	if stored, err := s.fsBlobs().List(); err == nil {
+		for hash := range stored {
+			if _, ok := referencedBlobs[hash]; !ok {
+				result.OrphanedBlobs = append(result.OrphanedBlobs, hash)
 			}
 		}
+		sort.Strings(result.OrphanedBlobs)
 	}
 
 	if opts.DryRun {
@@ -112,18 +109,136 @@ func (s *Store) GC(opts GCOpts) (*GCResult, error) {
 		result.DeletedManifests++
 	}
 
-	// Delete orphaned blobs
+	// Delete orphaned blobs. Packed ones can only be dropped by rewriting
+	// their pack, so they force a repack.
+	packedOrphans := false
 	for _, hash := range result.OrphanedBlobs {
 		path := filepath.Join(s.blobsDir, hash)
-		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		if err := os.Remove(path); err != nil {
+			packedOrphans = packedOrphans || os.IsNotExist(err)
 			continue
 		}
 		result.DeletedBlobs++
 	}
 
+	if opts.Repack || packedOrphans {
+		repack, err := s.Repack(func(hash string) bool {
+			_, ok := referencedBlobs[hash]
+			return ok
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to repack blobs: %w", err)
+		}
+		result.Repack = repack
+		result.DeletedBlobs += repack.Dropped
+	}
+
 	return result, nil
 }
 
+// PruneBlobsOpts configures a blob prune operation.
+type PruneBlobsOpts struct {
+	// OlderThan is the minimum age of a blob file, by modification time,
+	// before it may be deleted. It only narrows the unreachable set; the
+	// caller must hold the exclusive project lock so that no snapshot is
+	// writing or reusing blobs concurrently.
+	OlderThan time.Duration
+	DryRun    bool
+}
+
+// PruneBlobsResult contains the outcome of a blob prune.
+type PruneBlobsResult struct {
+	Pruned        []string // hashes of deleted (or, on a dry run, deletable) blobs
+	Bytes         int64    // bytes reclaimed by the pruned blobs
+	SkippedRecent int      // unreachable blobs kept because they are too young
+}
+
+// PruneBlobs deletes loose blobs that are not referenced by any manifest
+// reachable from a workspace and whose file is older than opts.OlderThan.
+// Unlike GC it leaves snapshots, manifests and packed blobs alone. Like GC,
+// it must run under the exclusive project lock.
+func (s *Store) PruneBlobs(opts PruneBlobsOpts) (*PruneBlobsResult, error) {
+	roots, err := s.collectGCRoots()
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect workspace roots: %w", err)
+	}
+
+	allMetas, err := s.LoadAllSnapshotMetas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshots: %w", err)
+	}
+
+	result := &PruneBlobsResult{}
+	if len(allMetas) == 0 {
+		return result, nil
+	}
+
+	reachableManifests := make(map[string]struct{})
+	for id := range s.BuildReachableSet(roots) {
+		if meta, ok := allMetas[id]; ok && meta.ManifestHash != "" {
+			reachableManifests[meta.ManifestHash] = struct{}{}
+		}
+	}
+	referencedBlobs, err := s.blobsReferencedBy(reachableManifests)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(s.blobsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, fmt.Errorf("failed to read blobs directory: %w", err)
+	}
+
+	cutoff := time.Now().Add(-opts.OlderThan)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		hash := entry.Name()
+		if _, ok := referencedBlobs[hash]; ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(cutoff) {
+			result.SkippedRecent++
+			continue
+		}
+		if !opts.DryRun {
+			if err := os.Remove(filepath.Join(s.blobsDir, hash)); err != nil {
+				continue
+			}
+		}
+		result.Pruned = append(result.Pruned, hash)
+		result.Bytes += info.Size()
+	}
+	sort.Strings(result.Pruned)
+
+	return result, nil
+}
+
+// blobsReferencedBy returns the hashes of all blobs referenced by the given
+// manifests. A manifest that fails to load is an error: its blobs would
+// otherwise look unreferenced and be deleted.
+func (s *Store) blobsReferencedBy(manifests map[string]struct{}) (map[string]struct{}, error) {
+	referenced := make(map[string]struct{})
+	for hash := range manifests {
+		m, err := s.LoadManifest(hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load reachable manifest %s: %w", hash, err)
+		}
+		for _, f := range m.FileEntries() {
+			referenced[f.Hash] = struct{}{}
+		}
+	}
+	return referenced, nil
+}
+
 // LoadAllSnapshotMetas loads all snapshot metadata from the store.
 func (s *Store) LoadAllSnapshotMetas() (map[string]*SnapshotMeta, error) {
 	entries, err := os.ReadDir(s.snapshotsDir)
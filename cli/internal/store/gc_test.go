@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestGC_NothingToCollect(t *testing.T) {
@@ -136,6 +137,100 @@ func TestGC_DeletesOrphanedBlobs(t *testing.T) {
 	}
 }
 
+func TestPruneBlobs_OnlyOldUnreachable(t *testing.T) {
+	s, _ := setupStore(t)
+
+	base := seedSnapshot(t, s, "snap-base", nil, map[string]string{
+		"file.txt": "hello",
+	})
+	s.RegisterWorkspace(WorkspaceInfo{
+		WorkspaceID:       "ws-1",
+		WorkspaceName:     "main",
+		CurrentSnapshotID: base,
+	})
+
+	oldHash := "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+	newHash := "cafebabecafebabecafebabecafebabecafebabecafebabecafebabecafebabe"
+	os.WriteFile(filepath.Join(s.BlobsDir(), oldHash), []byte("old orphan"), 0644)
+	os.WriteFile(filepath.Join(s.BlobsDir(), newHash), []byte("new orphan"), 0644)
+	past := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(s.BlobsDir(), oldHash), past, past); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+	// Age the referenced blob too; reachability alone must keep it.
+	keptHash := sha256Hex([]byte("hello"))
+	if err := os.Chtimes(filepath.Join(s.BlobsDir(), keptHash), past, past); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	result, err := s.PruneBlobs(PruneBlobsOpts{OlderThan: 24 * time.Hour, DryRun: true})
+	if err != nil {
+		t.Fatalf("PruneBlobs: %v", err)
+	}
+	if len(result.Pruned) != 1 || result.Pruned[0] != oldHash {
+		t.Fatalf("expected only %s to be prunable, got %v", oldHash, result.Pruned)
+	}
+	if _, err := os.Stat(filepath.Join(s.BlobsDir(), oldHash)); err != nil {
+		t.Fatalf("dry run should not delete: %v", err)
+	}
+
+	result, err = s.PruneBlobs(PruneBlobsOpts{OlderThan: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("PruneBlobs: %v", err)
+	}
+	if len(result.Pruned) != 1 || result.Bytes != int64(len("old orphan")) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.SkippedRecent != 1 {
+		t.Fatalf("expected 1 recent blob to be kept, got %d", result.SkippedRecent)
+	}
+	if _, err := os.Stat(filepath.Join(s.BlobsDir(), oldHash)); !os.IsNotExist(err) {
+		t.Fatalf("old orphan blob should have been deleted")
+	}
+	if _, err := os.Stat(filepath.Join(s.BlobsDir(), newHash)); err != nil {
+		t.Fatalf("recent orphan blob should be kept: %v", err)
+	}
+	if !s.BlobExists(keptHash) {
+		t.Fatalf("referenced blob should be kept")
+	}
+}
+
+func TestPruneBlobs_AbortsOnUnreadableManifest(t *testing.T) {
+	s, _ := setupStore(t)
+
+	base := seedSnapshot(t, s, "snap-base", nil, map[string]string{
+		"file.txt": "hello",
+	})
+	s.RegisterWorkspace(WorkspaceInfo{
+		WorkspaceID:       "ws-1",
+		WorkspaceName:     "main",
+		CurrentSnapshotID: base,
+	})
+
+	keptHash := sha256Hex([]byte("hello"))
+	past := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(s.BlobsDir(), keptHash), past, past); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+	meta, err := s.LoadSnapshotMeta(base)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.ManifestsDir(), meta.ManifestHash+".json"), []byte("{"), 0644); err != nil {
+		t.Fatalf("corrupt manifest: %v", err)
+	}
+
+	if _, err := s.PruneBlobs(PruneBlobsOpts{OlderThan: 24 * time.Hour}); err == nil {
+		t.Fatalf("expected prune to fail on an unreadable reachable manifest")
+	}
+	if _, err := s.GC(GCOpts{Repack: true}); err == nil {
+		t.Fatalf("expected gc to fail on an unreadable reachable manifest")
+	}
+	if !s.BlobExists(keptHash) {
+		t.Fatalf("blob of the unreadable manifest should be kept")
+	}
+}
+
 func TestBuildReachableSet(t *testing.T) {
 	s, _ := setupStore(t)
 
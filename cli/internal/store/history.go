@@ -1,8 +1,12 @@
 package store
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"time"
+
+	"github.com/ankitiscracked/fastest/cli/internal/manifest"
 )
 
 // RewriteResult contains the outcome of a chain rewrite operation.
@@ -177,3 +181,266 @@ func (s *Store) EditSnapshotMessage(snapshotID, newMessage string) error {
 	meta.Message = newMessage
 	return s.WriteSnapshotMeta(meta)
 }
+
+// ReparentSnapshot writes a copy of snapshotID whose only parent is
+// newParent and returns the copy's ID. The original snapshot is left in
+// place. newParent must exist and must not be snapshotID or one of its
+// descendants, which would introduce a cycle.
+func (s *Store) ReparentSnapshot(snapshotID, newParent string) (string, error) {
+	meta, err := s.LoadSnapshotMeta(snapshotID)
+	if err != nil {
+		return "", fmt.Errorf("failed to read snapshot %s: %w", snapshotID, err)
+	}
+	if _, err := s.LoadSnapshotMeta(newParent); err != nil {
+		return "", fmt.Errorf("new parent %s not found: %w", newParent, err)
+	}
+	if s.IsAncestorOf(snapshotID, newParent) {
+		return "", fmt.Errorf("cannot reparent %s onto %s: new parent is the snapshot itself or one of its descendants", snapshotID, newParent)
+	}
+
+	newParents := []string{newParent}
+	newID := ComputeSnapshotID(meta.ManifestHash, newParents, meta.AuthorName, meta.AuthorEmail, meta.CreatedAt)
+
+	newMeta := *meta
+	newMeta.ID = newID
+	newMeta.ParentSnapshotIDs = newParents
+	if err := s.WriteSnapshotMeta(&newMeta); err != nil {
+		return "", fmt.Errorf("failed to write new snapshot %s: %w", newID, err)
+	}
+	return newID, nil
+}
+
+// WalkOpts configures a WalkSnapshotDAG traversal.
+type WalkOpts struct {
+	// Boundary lists snapshots at which the walk stops. Boundary snapshots
+	// are not included in the result and their parents are not followed.
+	Boundary []string
+	// OnMissing is called for snapshots whose metadata is missing. The walk
+	// skips them and continues. If nil, missing metadata is an error.
+	OnMissing func(id string)
+}
+
+// WalkSnapshotDAG walks all parents reachable from startID and returns the
+// visited snapshots in parent-before-child (topological) order. The walk
+// stops at any snapshot listed in opts.Boundary. Returns an error if the
+// history contains a cycle.
+func (s *Store) WalkSnapshotDAG(startID string, opts WalkOpts) ([]*SnapshotMeta, error) {
+	if startID == "" {
+		return nil, fmt.Errorf("empty snapshot id")
+	}
+
+	boundary := make(map[string]struct{}, len(opts.Boundary))
+	for _, id := range opts.Boundary {
+		if id != "" {
+			boundary[id] = struct{}{}
+		}
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]uint8)
+	var ordered []*SnapshotMeta
+
+	var visit func(string) error
+	visit = func(id string) error {
+		if id == "" {
+			return nil
+		}
+		if _, stop := boundary[id]; stop {
+			return nil
+		}
+		switch state[id] {
+		case visiting:
+			return fmt.Errorf("cycle detected at snapshot %s", id)
+		case done:
+			return nil
+		}
+		state[id] = visiting
+
+		meta, err := s.LoadSnapshotMeta(id)
+		if err != nil {
+			if opts.OnMissing != nil && errors.Is(err, fs.ErrNotExist) {
+				opts.OnMissing(id)
+				state[id] = done
+				return nil
+			}
+			return err
+		}
+
+		for _, parent := range meta.ParentSnapshotIDs {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+
+		state[id] = done
+		ordered = append(ordered, meta)
+		return nil
+	}
+
+	if err := visit(startID); err != nil {
+		return nil, err
+	}
+	return ordered, nil
+}
+
+// SnapshotsBetween returns the snapshots reachable from toID but not from
+// fromID (git's from..to), in parent-before-child order. fromID and all of
+// its ancestors are excluded. When fromID is empty the full history of toID
+// is returned.
+func (s *Store) SnapshotsBetween(fromID, toID string) ([]*SnapshotMeta, error) {
+	var boundary []string
+	if fromID != "" {
+		for id := range s.BuildReachableSet([]string{fromID}) {
+			boundary = append(boundary, id)
+		}
+	}
+	return s.WalkSnapshotDAG(toID, WalkOpts{Boundary: boundary})
+}
+
+// AheadBehind counts the snapshots on each side of localID and otherID.
+// ahead is the number of snapshots only in localID's history, behind the
+// number only in otherID's history. The two must share history.
+func (s *Store) AheadBehind(localID, otherID string) (ahead, behind int, err error) {
+	if localID == otherID {
+		return 0, 0, nil
+	}
+	if _, err := s.GetMergeBase(localID, otherID); err != nil {
+		return 0, 0, err
+	}
+	local, err := s.SnapshotsBetween(otherID, localID)
+	if err != nil {
+		return 0, 0, err
+	}
+	other, err := s.SnapshotsBetween(localID, otherID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(local), len(other), nil
+}
+
+// FileChange describes how a snapshot changed a single file.
+type FileChange struct {
+	Snapshot *SnapshotMeta
+	// Status is one of "added", "modified", "deleted" or "renamed".
+	Status string
+	Path   string
+	// OldPath is the previous path when Status is "renamed".
+	OldPath string
+}
+
+// FileHistory walks every snapshot reachable from headID, newest first in
+// topological order, and returns the snapshots that changed path. A merge
+// snapshot counts as a change only when the file differs from all of its
+// parents, so changes merged in are reported once, on the side that made
+// them. With followRenames, a file that appears in a snapshot while a file
+// with identical content disappears from its first parent is treated as a
+// rename and the walk continues under the old path.
+func (s *Store) FileHistory(headID, path string, followRenames bool) ([]FileChange, error) {
+	chain, err := s.WalkSnapshotDAG(headID, WalkOpts{})
+	if err != nil {
+		return nil, err
+	}
+	metas := make(map[string]*SnapshotMeta, len(chain))
+	for _, meta := range chain {
+		metas[meta.ID] = meta
+	}
+	manifests := make(map[string]*manifest.Manifest)
+	load := func(id string) (*manifest.Manifest, error) {
+		meta := metas[id]
+		if m, ok := manifests[meta.ManifestHash]; ok {
+			return m, nil
+		}
+		m, err := s.LoadManifest(meta.ManifestHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load manifest for %s: %w", id, err)
+		}
+		manifests[meta.ManifestHash] = m
+		return m, nil
+	}
+	empty := &manifest.Manifest{Version: "1"}
+
+	// The path to look for in each snapshot, handed down from its children.
+	paths := map[string]string{headID: path}
+	var changes []FileChange
+	for i := len(chain) - 1; i >= 0; i-- {
+		meta := chain[i]
+		path, ok := paths[meta.ID]
+		if !ok {
+			continue
+		}
+		child, err := load(meta.ID)
+		if err != nil {
+			return nil, err
+		}
+		parents := []*manifest.Manifest{empty}
+		if len(meta.ParentSnapshotIDs) > 0 {
+			parents = parents[:0]
+			for _, parentID := range meta.ParentSnapshotIDs {
+				parent, err := load(parentID)
+				if err != nil {
+					return nil, err
+				}
+				parents = append(parents, parent)
+			}
+		}
+
+		changed := true
+		for _, parent := range parents {
+			if !fileChanged(parent, child, path) {
+				changed = false
+				break
+			}
+		}
+		parentPath := path
+		if changed {
+			_, inChild := findManifestEntry(child, path)
+			_, inParent := findManifestEntry(parents[0], path)
+			switch {
+			case inChild && !inParent:
+				status := "added"
+				var oldPath string
+				if followRenames {
+					if old, ok := manifest.RenameSource(parents[0], child, path); ok {
+						status, oldPath, parentPath = "renamed", old, old
+					}
+				}
+				changes = append(changes, FileChange{Snapshot: meta, Status: status, Path: path, OldPath: oldPath})
+			case !inChild && inParent:
+				changes = append(changes, FileChange{Snapshot: meta, Status: "deleted", Path: path})
+			default:
+				changes = append(changes, FileChange{Snapshot: meta, Status: "modified", Path: path})
+			}
+		}
+
+		for _, parentID := range meta.ParentSnapshotIDs {
+			if _, ok := paths[parentID]; !ok {
+				paths[parentID] = parentPath
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// fileChanged reports whether path differs between parent and child.
+func fileChanged(parent, child *manifest.Manifest, path string) bool {
+	childEntry, inChild := findManifestEntry(child, path)
+	parentEntry, inParent := findManifestEntry(parent, path)
+	if inChild != inParent {
+		return true
+	}
+	return inChild && (childEntry.Type != parentEntry.Type || childEntry.Hash != parentEntry.Hash ||
+		childEntry.Mode != parentEntry.Mode || childEntry.Target != parentEntry.Target)
+}
+
+func findManifestEntry(m *manifest.Manifest, path string) (manifest.FileEntry, bool) {
+	for _, f := range m.Files {
+		if f.Path == path {
+			return f, true
+		}
+	}
+	return manifest.FileEntry{}, false
+}
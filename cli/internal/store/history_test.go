@@ -1,6 +1,7 @@
 package store
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -168,3 +169,188 @@ func TestEditSnapshotMessage(t *testing.T) {
 		t.Fatalf("expected 'updated message', got %q", meta.Message)
 	}
 }
+
+func TestReparentSnapshot(t *testing.T) {
+	s, _ := setupStore(t)
+
+	a := seedSnapshot(t, s, "snap-a", nil, map[string]string{"a.txt": "a"})
+	b := seedSnapshot(t, s, "snap-b", []string{"snap-missing"}, map[string]string{"b.txt": "b"})
+	c := seedSnapshot(t, s, "snap-c", []string{b}, map[string]string{"c.txt": "c"})
+
+	newB, err := s.ReparentSnapshot(b, a)
+	if err != nil {
+		t.Fatalf("ReparentSnapshot: %v", err)
+	}
+	if newB == b {
+		t.Fatalf("expected new ID for reparented snapshot")
+	}
+	meta, err := s.LoadSnapshotMeta(newB)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta(%s): %v", newB, err)
+	}
+	if len(meta.ParentSnapshotIDs) != 1 || meta.ParentSnapshotIDs[0] != a {
+		t.Fatalf("expected parent %s, got %v", a, meta.ParentSnapshotIDs)
+	}
+	orig, _ := s.LoadSnapshotMeta(b)
+	if meta.ManifestHash != orig.ManifestHash || meta.CreatedAt != orig.CreatedAt {
+		t.Fatalf("expected manifest and timestamp to be preserved")
+	}
+
+	if _, err := s.ReparentSnapshot(b, "snap-nope"); err == nil {
+		t.Fatalf("expected error for missing parent")
+	}
+	if _, err := s.ReparentSnapshot(b, c); err == nil {
+		t.Fatalf("expected error when reparenting onto a descendant")
+	}
+	if _, err := s.ReparentSnapshot(b, b); err == nil {
+		t.Fatalf("expected error when reparenting onto itself")
+	}
+}
+
+func TestSnapshotsBetween(t *testing.T) {
+	s, _ := setupStore(t)
+
+	a := seedSnapshot(t, s, "snap-a", nil, map[string]string{"a.txt": "a"})
+	b := seedSnapshot(t, s, "snap-b", []string{a}, map[string]string{"b.txt": "b"})
+	c := seedSnapshot(t, s, "snap-c", []string{b}, map[string]string{"c.txt": "c"})
+	d := seedSnapshot(t, s, "snap-d", []string{b}, map[string]string{"d.txt": "d"})
+	e := seedSnapshot(t, s, "snap-e", []string{c, d}, map[string]string{"e.txt": "e"})
+
+	between, err := s.SnapshotsBetween(b, e)
+	if err != nil {
+		t.Fatalf("SnapshotsBetween: %v", err)
+	}
+	if len(between) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", len(between))
+	}
+	if between[len(between)-1].ID != e {
+		t.Fatalf("expected head last, got %s", between[len(between)-1].ID)
+	}
+	for _, m := range between {
+		if m.ID == a || m.ID == b {
+			t.Fatalf("walk should stop at boundary, got %s", m.ID)
+		}
+	}
+
+	all, err := s.SnapshotsBetween("", e)
+	if err != nil {
+		t.Fatalf("SnapshotsBetween full: %v", err)
+	}
+	if len(all) != 5 || all[0].ID != a {
+		t.Fatalf("expected full history starting at root, got %d entries", len(all))
+	}
+}
+
+func TestWalkSnapshotDAG_Cycle(t *testing.T) {
+	s, _ := setupStore(t)
+
+	seedSnapshot(t, s, "snap-x", []string{"snap-y"}, map[string]string{"x.txt": "x"})
+	seedSnapshot(t, s, "snap-y", []string{"snap-x"}, map[string]string{"y.txt": "y"})
+
+	if _, err := s.WalkSnapshotDAG("snap-x", WalkOpts{}); err == nil {
+		t.Fatalf("expected cycle error")
+	}
+}
+
+func TestAheadBehind(t *testing.T) {
+	s, _ := setupStore(t)
+
+	a := seedSnapshot(t, s, "snap-a", nil, map[string]string{"a.txt": "a"})
+	b := seedSnapshot(t, s, "snap-b", []string{a}, map[string]string{"b.txt": "b"})
+	c := seedSnapshot(t, s, "snap-c", []string{b}, map[string]string{"c.txt": "c"})
+	d := seedSnapshot(t, s, "snap-d", []string{a}, map[string]string{"d.txt": "d"})
+
+	ahead, behind, err := s.AheadBehind(c, d)
+	if err != nil {
+		t.Fatalf("AheadBehind: %v", err)
+	}
+	if ahead != 2 || behind != 1 {
+		t.Fatalf("expected 2 ahead / 1 behind, got %d / %d", ahead, behind)
+	}
+}
+
+func TestAheadBehindAfterMerge(t *testing.T) {
+	s, _ := setupStore(t)
+
+	a := seedSnapshot(t, s, "snap-a", nil, map[string]string{"a.txt": "a"})
+	b := seedSnapshot(t, s, "snap-b", []string{a}, map[string]string{"b.txt": "b"})
+	x := seedSnapshot(t, s, "snap-x", []string{a}, map[string]string{"x.txt": "x"})
+	m := seedSnapshot(t, s, "snap-m", []string{b, x}, map[string]string{"m.txt": "m"})
+
+	between, err := s.SnapshotsBetween(x, m)
+	if err != nil {
+		t.Fatalf("SnapshotsBetween: %v", err)
+	}
+	if len(between) != 2 || between[0].ID != b || between[1].ID != m {
+		t.Fatalf("expected [b m], got %d snapshots", len(between))
+	}
+
+	ahead, behind, err := s.AheadBehind(m, x)
+	if err != nil {
+		t.Fatalf("AheadBehind: %v", err)
+	}
+	if ahead != 2 || behind != 0 {
+		t.Fatalf("expected 2 ahead / 0 behind, got %d / %d", ahead, behind)
+	}
+}
+
+func TestFileHistory_FollowRenames(t *testing.T) {
+	s, _ := setupStore(t)
+
+	s1 := seedSnapshot(t, s, "snap-1", nil, map[string]string{"old.txt": "v1"})
+	s2 := seedSnapshot(t, s, "snap-2", []string{s1}, map[string]string{"old.txt": "v2"})
+	s3 := seedSnapshot(t, s, "snap-3", []string{s2}, map[string]string{"new.txt": "v2"})
+	s4 := seedSnapshot(t, s, "snap-4", []string{s3}, map[string]string{"new.txt": "v3"})
+
+	changes, err := s.FileHistory(s4, "new.txt", false)
+	if err != nil {
+		t.Fatalf("FileHistory: %v", err)
+	}
+	if len(changes) != 2 || changes[0].Status != "modified" || changes[1].Status != "added" {
+		t.Fatalf("unexpected history without follow: %+v", changes)
+	}
+
+	changes, err = s.FileHistory(s4, "new.txt", true)
+	if err != nil {
+		t.Fatalf("FileHistory: %v", err)
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Snapshot.ID+":"+c.Status+":"+c.Path)
+	}
+	want := []string{
+		"snap-4:modified:new.txt",
+		"snap-3:renamed:new.txt",
+		"snap-2:modified:old.txt",
+		"snap-1:added:old.txt",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected history:\n got %v\nwant %v", got, want)
+	}
+	if changes[1].OldPath != "old.txt" {
+		t.Fatalf("expected rename from old.txt, got %q", changes[1].OldPath)
+	}
+}
+
+func TestFileHistory_FollowsMergedBranches(t *testing.T) {
+	s, _ := setupStore(t)
+
+	a := seedSnapshot(t, s, "snap-a", nil, map[string]string{"f.txt": "v1"})
+	b := seedSnapshot(t, s, "snap-b", []string{a}, map[string]string{"f.txt": "v1", "other.txt": "o"})
+	x := seedSnapshot(t, s, "snap-x", []string{a}, map[string]string{"f.txt": "v2"})
+	m := seedSnapshot(t, s, "snap-m", []string{b, x}, map[string]string{"f.txt": "v2", "other.txt": "o"})
+
+	changes, err := s.FileHistory(m, "f.txt", false)
+	if err != nil {
+		t.Fatalf("FileHistory: %v", err)
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Snapshot.ID+":"+c.Status)
+	}
+	// The merge takes f.txt unchanged from snap-x, which made the change.
+	want := []string{"snap-x:modified", "snap-a:added"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected history:\n got %v\nwant %v", got, want)
+	}
+}
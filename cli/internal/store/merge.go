@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"path"
+	"sort"
+	"strings"
 
 	"github.com/ankitiscracked/fastest/cli/internal/manifest"
 	"github.com/epiclabs-io/diff3"
@@ -12,7 +15,7 @@ import (
 // MergeAction represents what to do with a single file during a merge.
 type MergeAction struct {
 	Path          string
-	Type          string // "apply", "conflict", or "auto-merge"
+	Type          string // "apply", "delete", "conflict", or "auto-merge"
 	CurrentHash   string
 	SourceHash    string
 	BaseHash      string
@@ -20,12 +23,30 @@ type MergeAction struct {
 	MergedContent []byte // populated for "auto-merge" actions
 }
 
+// SourceDeleted reports whether the action is a delete/modify conflict in
+// which the source deleted a file the current workspace modified.
+func (a MergeAction) SourceDeleted() bool {
+	return a.Type == "conflict" && a.BaseHash != "" && a.SourceHash == ""
+}
+
+// CurrentDeleted reports whether the action is a delete/modify conflict in
+// which the current workspace deleted a file the source modified.
+func (a MergeAction) CurrentDeleted() bool {
+	return a.Type == "conflict" && a.BaseHash != "" && a.CurrentHash == ""
+}
+
+// IsDeleteConflict reports whether one side deleted a file the other
+// side modified.
+func (a MergeAction) IsDeleteConflict() bool {
+	return a.SourceDeleted() || a.CurrentDeleted()
+}
+
 // MergePlan is the result of planning a merge between two snapshots.
 // It contains the computed three-way diff without applying any changes.
 type MergePlan struct {
-	ToApply           []MergeAction // files to apply from source (no conflict)
+	ToApply           []MergeAction // files to apply from source (no conflict), including "delete" actions
 	AutoMerged        []MergeAction // files auto-merged at line level (non-overlapping changes)
-	Conflicts         []MergeAction // files with conflicting changes
+	Conflicts         []MergeAction // files with conflicting changes, including delete/modify conflicts
 	InSync            int           // count of files already in sync
 	MergeBaseID       string
 	CurrentSnapshotID string
@@ -37,11 +58,27 @@ type BlobReader interface {
 	ReadBlob(hash string) ([]byte, error)
 }
 
+// MergeOpts configures PlanMergeWithOpts.
+type MergeOpts struct {
+	// Force proceeds without a common ancestor (two-way merge).
+	Force bool
+	// PreserveDeletes plans a "delete" action for files the source deleted
+	// and the current workspace left unchanged since the merge base, instead
+	// of keeping them.
+	PreserveDeletes bool
+}
+
 // PlanMerge computes a three-way merge plan between two snapshots.
 // It finds the merge base via DAG traversal, loads all three manifests,
 // and classifies each file as apply, conflict, or in-sync.
 // If force is true, proceeds without a common ancestor (two-way merge).
 func (s *Store) PlanMerge(currentSnapshotID, sourceSnapshotID string, force bool) (*MergePlan, error) {
+	return s.PlanMergeWithOpts(currentSnapshotID, sourceSnapshotID, MergeOpts{Force: force})
+}
+
+// PlanMergeWithOpts is PlanMerge with additional options.
+func (s *Store) PlanMergeWithOpts(currentSnapshotID, sourceSnapshotID string, opts MergeOpts) (*MergePlan, error) {
+	force := opts.Force
 	if currentSnapshotID == "" {
 		return nil, fmt.Errorf("current snapshot ID is empty")
 	}
@@ -82,7 +119,10 @@ func (s *Store) PlanMerge(currentSnapshotID, sourceSnapshotID string, force bool
 	}
 
 	// Compute three-way diff with line-level merge for both-changed files
-	toApply, autoMerged, conflicts, inSyncCount := computeMergeActions(baseManifest, currentManifest, sourceManifest, s)
+	toApply, autoMerged, conflicts, inSyncCount := computeMergeActions(baseManifest, currentManifest, sourceManifest, s, opts.PreserveDeletes)
+	sortMergeActions(toApply)
+	sortMergeActions(autoMerged)
+	sortMergeActions(conflicts)
 
 	return &MergePlan{
 		ToApply:           toApply,
@@ -104,106 +144,97 @@ func (s *Store) loadManifestForSnapshot(snapshotID string) (*manifest.Manifest,
 	return s.LoadManifest(hash)
 }
 
-// computeMergeActions performs a three-way diff of base, current, and source manifests.
-// For each file path, it determines whether to apply from source, auto-merge (if both
-// sides changed non-overlapping lines), flag as conflict, or skip (already in sync).
-// When both sides modify the same file, it attempts a line-level three-way merge using
-// the diff3 algorithm. Non-overlapping changes are auto-merged; overlapping changes
-// remain as conflicts.
-func computeMergeActions(base, current, source *manifest.Manifest, blobs BlobReader) (toApply, autoMerged, conflicts []MergeAction, inSync int) {
-	// Build lookup maps
-	baseFiles := make(map[string]manifest.FileEntry)
-	for _, f := range base.FileEntries() {
-		baseFiles[f.Path] = f
-	}
-
-	currentFiles := make(map[string]manifest.FileEntry)
-	for _, f := range current.FileEntries() {
-		currentFiles[f.Path] = f
-	}
+// Apply orders for the non-conflicting changes of a merge plan.
+const (
+	ApplyOrderPath = "path" // sorted by path (default)
+	ApplyOrderDeps = "deps" // priority globs first, then parents before children
+)
 
-	sourceFiles := make(map[string]manifest.FileEntry)
-	for _, f := range source.FileEntries() {
-		sourceFiles[f.Path] = f
-	}
+func sortMergeActions(actions []MergeAction) {
+	sort.Slice(actions, func(i, j int) bool { return actions[i].Path < actions[j].Path })
+}
 
-	// Collect all unique paths
-	allPaths := make(map[string]bool)
-	for path := range baseFiles {
-		allPaths[path] = true
-	}
-	for path := range currentFiles {
-		allPaths[path] = true
-	}
-	for path := range sourceFiles {
-		allPaths[path] = true
+// OrderMergeActionsByDeps reorders actions so that paths matching a priority
+// glob come first, in the order of the globs, followed by the rest with
+// shallower paths before deeper ones. Within a directory, __init__ files come
+// first; ties are broken by path. Globs without a slash match the base name.
+func OrderMergeActionsByDeps(actions []MergeAction, priority []string) {
+	rank := func(p string) int {
+		for i, pattern := range priority {
+			target := p
+			if !strings.Contains(pattern, "/") {
+				target = path.Base(p)
+			}
+			if ok, _ := path.Match(pattern, target); ok {
+				return i
+			}
+		}
+		return len(priority)
 	}
-
-	for path := range allPaths {
-		baseFile, inBase := baseFiles[path]
-		currentFile, inCurrent := currentFiles[path]
-		sourceFile, inSource := sourceFiles[path]
-
-		action := MergeAction{Path: path}
-
-		if inBase {
-			action.BaseHash = baseFile.Hash
+	sort.SliceStable(actions, func(i, j int) bool {
+		a, b := actions[i].Path, actions[j].Path
+		if ra, rb := rank(a), rank(b); ra != rb {
+			return ra < rb
 		}
-		if inCurrent {
-			action.CurrentHash = currentFile.Hash
+		if da, db := strings.Count(a, "/"), strings.Count(b, "/"); da != db {
+			return da < db
 		}
-		if inSource {
-			action.SourceHash = sourceFile.Hash
-			action.SourceMode = sourceFile.Mode
+		if ia, ib := isInitFile(a), isInitFile(b); ia != ib && path.Dir(a) == path.Dir(b) {
+			return ia
 		}
+		return a < b
+	})
+}
 
-		currentChanged := !inBase && inCurrent || (inBase && inCurrent && baseFile.Hash != currentFile.Hash)
-		sourceChanged := !inBase && inSource || (inBase && inSource && baseFile.Hash != sourceFile.Hash)
-		currentDeleted := inBase && !inCurrent
-		sourceDeleted := inBase && !inSource
+// isInitFile reports whether p names a package initializer such as
+// __init__.py.
+func isInitFile(p string) bool {
+	return strings.HasPrefix(path.Base(p), "__init__")
+}
 
-		switch {
-		case !inSource && !sourceDeleted:
-			// File only in current/base — nothing from source
-			continue
+// computeMergeActions turns the three-way diff of base, current, and source
+// (see manifest.ThreeWayDiff) into merge actions: files to apply from source,
+// files auto-merged, conflicts, and a count of files already in sync.
+// When both sides modify the same file, it attempts a line-level three-way
+// merge using the diff3 algorithm. Non-overlapping changes are auto-merged;
+// overlapping changes and delete/modify conflicts remain as conflicts. A file
+// the source deleted and the current side left unchanged is deleted only if
+// preserveDeletes is set; otherwise it is kept.
+func computeMergeActions(base, current, source *manifest.Manifest, blobs BlobReader, preserveDeletes bool) (toApply, autoMerged, conflicts []MergeAction, inSync int) {
+	for _, c := range manifest.ThreeWayDiff(base, current, source) {
+		action := MergeAction{
+			Path:        c.Path,
+			BaseHash:    entryHash(c.Base),
+			CurrentHash: entryHash(c.Current),
+			SourceHash:  entryHash(c.Source),
+			SourceMode:  c.Mode,
+		}
 
-		case !inCurrent && inSource:
-			// Added in source — apply
+		switch c.Kind {
+		case manifest.ChangeApply:
 			action.Type = "apply"
 			toApply = append(toApply, action)
 
-		case currentDeleted && inSource:
-			// We deleted, source has it — conflict
-			action.Type = "conflict"
-			conflicts = append(conflicts, action)
-
-		case sourceDeleted && inCurrent:
-			// Source deleted, we have it — keep ours
-			inSync++
-
-		case inCurrent && inSource && currentFile.Hash == sourceFile.Hash:
-			// Same content
-			inSync++
-
-		case !currentChanged && sourceChanged:
-			// Only source changed — apply
-			action.Type = "apply"
+		case manifest.ChangeDelete:
+			if !preserveDeletes {
+				inSync++
+				continue
+			}
+			action.Type = "delete"
 			toApply = append(toApply, action)
 
-		case currentChanged && !sourceChanged:
-			// Only current changed — keep ours
-			inSync++
-
-		case currentChanged && sourceChanged:
+		case manifest.ChangeConflict:
 			// Both changed — attempt line-level three-way merge
-			if merged, ok := tryLinemerge(blobs, action.BaseHash, action.CurrentHash, action.SourceHash); ok {
-				action.Type = "auto-merge"
-				action.MergedContent = merged
-				autoMerged = append(autoMerged, action)
-			} else {
-				action.Type = "conflict"
-				conflicts = append(conflicts, action)
+			if !c.DeleteModify() {
+				if merged, ok := tryLinemerge(blobs, action.BaseHash, action.CurrentHash, action.SourceHash); ok {
+					action.Type = "auto-merge"
+					action.MergedContent = merged
+					autoMerged = append(autoMerged, action)
+					continue
+				}
 			}
+			action.Type = "conflict"
+			conflicts = append(conflicts, action)
 
 		default:
 			inSync++
@@ -213,6 +244,13 @@ func computeMergeActions(base, current, source *manifest.Manifest, blobs BlobRea
 	return toApply, autoMerged, conflicts, inSync
 }
 
+func entryHash(f *manifest.FileEntry) string {
+	if f == nil {
+		return ""
+	}
+	return f.Hash
+}
+
 // tryLinemerge attempts a three-way line-level merge using the diff3 algorithm.
 // Returns the merged content and true if the merge succeeds without conflicts.
 // Returns nil and false if the merge cannot be performed or has conflicts.
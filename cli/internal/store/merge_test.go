@@ -159,6 +159,37 @@ func TestPlanMerge_InSync(t *testing.T) {
 	}
 }
 
+func TestPlanMerge_IdenticalIndependentEdits(t *testing.T) {
+	s, _ := setupStore(t)
+
+	base := seedSnapshot(t, s, "snap-base", nil, map[string]string{
+		"file.txt": "line1\nline2\n",
+	})
+
+	// Both sides make the same edit and add the same new file.
+	same := map[string]string{
+		"file.txt":  "line1\nfixed\n",
+		"added.txt": "new",
+	}
+	current := seedSnapshot(t, s, "snap-current", []string{base}, same)
+	source := seedSnapshot(t, s, "snap-source", []string{base}, same)
+
+	plan, err := s.PlanMerge(current, source, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+
+	if len(plan.Conflicts) != 0 {
+		t.Fatalf("expected no conflicts for identical edits, got %v", plan.Conflicts)
+	}
+	if len(plan.ToApply) != 0 || len(plan.AutoMerged) != 0 {
+		t.Fatalf("expected nothing to apply, got %d toApply, %d autoMerged", len(plan.ToApply), len(plan.AutoMerged))
+	}
+	if plan.InSync != 2 {
+		t.Fatalf("expected 2 inSync, got %d", plan.InSync)
+	}
+}
+
 func TestPlanMerge_ForceNoBase(t *testing.T) {
 	s, _ := setupStore(t)
 
@@ -307,3 +338,172 @@ func TestPlanMerge_OnlyCurrentChanged(t *testing.T) {
 		t.Fatalf("expected at least 1 inSync, got %d", plan.InSync)
 	}
 }
+
+func TestPlanMerge_DeleteModify(t *testing.T) {
+	s, _ := setupStore(t)
+
+	base := seedSnapshot(t, s, "snap-base", nil, map[string]string{
+		"src-deleted.txt": "original",
+		"cur-deleted.txt": "original",
+		"src-clean.txt":   "original",
+		"cur-clean.txt":   "original",
+	})
+
+	// current: modifies src-deleted, deletes cur-deleted and cur-clean
+	current := seedSnapshot(t, s, "snap-current", []string{base}, map[string]string{
+		"src-deleted.txt": "current-version",
+		"src-clean.txt":   "original",
+	})
+
+	// source: deletes src-deleted and src-clean, modifies cur-deleted
+	source := seedSnapshot(t, s, "snap-source", []string{base}, map[string]string{
+		"cur-deleted.txt": "source-version",
+		"cur-clean.txt":   "original",
+	})
+
+	plan, err := s.PlanMerge(current, source, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+
+	if len(plan.ToApply) != 0 || len(plan.AutoMerged) != 0 {
+		t.Fatalf("expected nothing to apply, got %+v / %+v", plan.ToApply, plan.AutoMerged)
+	}
+	if len(plan.Conflicts) != 2 {
+		t.Fatalf("expected 2 delete/modify conflicts, got %+v", plan.Conflicts)
+	}
+	cur, src := plan.Conflicts[0], plan.Conflicts[1]
+	if cur.Path != "cur-deleted.txt" || !cur.CurrentDeleted() || cur.SourceDeleted() {
+		t.Fatalf("expected cur-deleted.txt deleted in current, got %+v", cur)
+	}
+	if src.Path != "src-deleted.txt" || !src.SourceDeleted() || src.CurrentDeleted() {
+		t.Fatalf("expected src-deleted.txt deleted in source, got %+v", src)
+	}
+	if plan.InSync != 2 {
+		t.Fatalf("expected the unmodified deletions to be in sync, got %d", plan.InSync)
+	}
+}
+
+func TestPlanMerge_PreserveDeletes(t *testing.T) {
+	s, _ := setupStore(t)
+
+	base := seedSnapshot(t, s, "snap-base", nil, map[string]string{
+		"clean.txt":    "original",
+		"modified.txt": "original",
+		"keep.txt":     "keep",
+	})
+	current := seedSnapshot(t, s, "snap-current", []string{base}, map[string]string{
+		"clean.txt":    "original",
+		"modified.txt": "current-version",
+		"keep.txt":     "keep",
+	})
+	source := seedSnapshot(t, s, "snap-source", []string{base}, map[string]string{
+		"keep.txt": "keep",
+	})
+
+	plan, err := s.PlanMerge(current, source, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+	if len(plan.ToApply) != 0 {
+		t.Fatalf("expected the clean delete to be kept by default, got %+v", plan.ToApply)
+	}
+
+	plan, err = s.PlanMergeWithOpts(current, source, MergeOpts{PreserveDeletes: true})
+	if err != nil {
+		t.Fatalf("PlanMergeWithOpts: %v", err)
+	}
+	if len(plan.ToApply) != 1 || plan.ToApply[0].Path != "clean.txt" || plan.ToApply[0].Type != "delete" {
+		t.Fatalf("expected a delete action for clean.txt, got %+v", plan.ToApply)
+	}
+	if len(plan.Conflicts) != 1 || plan.Conflicts[0].Path != "modified.txt" || !plan.Conflicts[0].SourceDeleted() {
+		t.Fatalf("expected modified.txt to stay a delete/modify conflict, got %+v", plan.Conflicts)
+	}
+	if plan.InSync != 1 {
+		t.Fatalf("expected 1 file in sync, got %d", plan.InSync)
+	}
+}
+
+func TestOrderMergeActionsByDeps(t *testing.T) {
+	var actions []MergeAction
+	for _, p := range []string{"pkg/sub/mod.py", "pkg/mod.py", "pkg/__init__.py", "README.md", "config/app.toml", "pkg/sub/__init__.py"} {
+		actions = append(actions, MergeAction{Path: p, Type: "apply"})
+	}
+
+	OrderMergeActionsByDeps(actions, []string{"*.toml"})
+
+	want := []string{"config/app.toml", "README.md", "pkg/__init__.py", "pkg/mod.py", "pkg/sub/__init__.py", "pkg/sub/mod.py"}
+	for i, a := range actions {
+		if a.Path != want[i] {
+			t.Fatalf("position %d: expected %s, got %s (order %v)", i, want[i], a.Path, actions)
+		}
+	}
+}
+
+func TestComputeMergeActions_ModeOnlyDivergence(t *testing.T) {
+	entry := func(hash string, mode uint32) manifest.FileEntry {
+		return manifest.FileEntry{Type: "file", Path: "run.sh", Hash: hash, Mode: mode}
+	}
+	manifestOf := func(f manifest.FileEntry) *manifest.Manifest {
+		return &manifest.Manifest{Version: "1", Files: []manifest.FileEntry{f}}
+	}
+
+	tests := []struct {
+		name                  string
+		base, current, source *manifest.Manifest
+		wantApply             bool
+		wantMode              uint32
+	}{
+		{
+			name:      "source made it executable",
+			base:      manifestOf(entry("h", 0644)),
+			current:   manifestOf(entry("h", 0644)),
+			source:    manifestOf(entry("h", 0755)),
+			wantApply: true,
+			wantMode:  0755,
+		},
+		{
+			name:    "current made it executable",
+			base:    manifestOf(entry("h", 0644)),
+			current: manifestOf(entry("h", 0755)),
+			source:  manifestOf(entry("h", 0644)),
+		},
+		{
+			name:      "added on both sides with different modes keeps the executable bit",
+			base:      &manifest.Manifest{Version: "1"},
+			current:   manifestOf(entry("h", 0644)),
+			source:    manifestOf(entry("h", 0755)),
+			wantApply: true,
+			wantMode:  0755,
+		},
+		{
+			name:      "source edited content, current made it executable",
+			base:      manifestOf(entry("h", 0644)),
+			current:   manifestOf(entry("h", 0755)),
+			source:    manifestOf(entry("h2", 0644)),
+			wantApply: true,
+			wantMode:  0755,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toApply, autoMerged, conflicts, inSync := computeMergeActions(tt.base, tt.current, tt.source, nil, false)
+			if len(conflicts) != 0 || len(autoMerged) != 0 {
+				t.Fatalf("expected no conflicts or auto-merges, got %v, %v", conflicts, autoMerged)
+			}
+			if !tt.wantApply {
+				if len(toApply) != 0 || inSync != 1 {
+					t.Fatalf("expected file in sync, got toApply=%v inSync=%d", toApply, inSync)
+				}
+				return
+			}
+			if len(toApply) != 1 {
+				t.Fatalf("expected 1 toApply, got %v", toApply)
+			}
+			if toApply[0].SourceMode != tt.wantMode {
+				t.Fatalf("expected mode %o, got %o", tt.wantMode, toApply[0].SourceMode)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Pack files consolidate many blobs into one file so that stores with huge
+// numbers of small blobs don't pay one filesystem entry per blob. A pack
+// "pack-<sha>.pack" is the concatenated content of its blobs; its index
+// "pack-<sha>.idx" maps each blob hash to the offset and length of its
+// content. Packs are written by Repack and never modified afterwards;
+// new blobs are written loose until the next repack.
+const (
+	packsDirName = "packs"
+	packSuffix   = ".pack"
+	indexSuffix  = ".idx"
+)
+
+// packIndex is the on-disk index of one pack file.
+type packIndex struct {
+	Version int                       `json:"version"`
+	Blobs   map[string]packIndexEntry `json:"blobs"`
+}
+
+type packIndexEntry struct {
+	Offset int64 `json:"offset"`
+	Length int64 `json:"length"`
+}
+
+// packedBlob locates a blob's content inside a pack file.
+type packedBlob struct {
+	pack   string // path of the .pack file
+	offset int64
+	length int64
+}
+
+// RepackResult reports what Repack did.
+type RepackResult struct {
+	Packed  int   // blobs in the new pack
+	Loose   int   // loose blobs moved into the pack
+	Dropped int   // packed blobs left out because keep rejected them
+	Skipped int   // loose blobs left loose because their content is corrupt
+	Bytes   int64 // size of the new pack
+	Packs   int   // pack files replaced by the new pack
+}
+
+func (b *FSBlobStore) packsDir() string {
+	return filepath.Join(b.dir, packsDirName)
+}
+
+// lookupPacked finds hash in the pack indexes. The indexes are (re)loaded
+// when the packs directory changed since they were last read, so packs
+// written by another process are picked up.
+func (b *FSBlobStore) lookupPacked(hash string) (packedBlob, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	info, err := os.Stat(b.packsDir())
+	if err != nil {
+		b.packed, b.packsLoaded = nil, false
+		return packedBlob{}, false
+	}
+	if !b.packsLoaded || !info.ModTime().Equal(b.packsModTime) {
+		packed, err := loadPackIndexes(b.packsDir())
+		if err != nil {
+			return packedBlob{}, false
+		}
+		b.packed, b.packsModTime, b.packsLoaded = packed, info.ModTime(), true
+	}
+	loc, ok := b.packed[hash]
+	return loc, ok
+}
+
+// loadPackIndexes reads every pack index in dir.
+func loadPackIndexes(dir string) (map[string]packedBlob, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	packed := make(map[string]packedBlob)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, indexSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pack index %s: %w", name, err)
+		}
+		var idx packIndex
+		if err := json.Unmarshal(data, &idx); err != nil {
+			return nil, fmt.Errorf("failed to parse pack index %s: %w", name, err)
+		}
+		packPath := filepath.Join(dir, strings.TrimSuffix(name, indexSuffix)+packSuffix)
+		for h, e := range idx.Blobs {
+			packed[h] = packedBlob{pack: packPath, offset: e.Offset, length: e.Length}
+		}
+	}
+	return packed, nil
+}
+
+// readPacked reads a blob's content from its pack file.
+func readPacked(loc packedBlob) ([]byte, error) {
+	f, err := os.Open(loc.pack)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data := make([]byte, loc.length)
+	if _, err := f.ReadAt(data, loc.offset); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(loc.pack), err)
+	}
+	return data, nil
+}
+
+// List returns the size of every stored blob, loose or packed, by hash.
+func (b *FSBlobStore) List() (map[string]int64, error) {
+	sizes := make(map[string]int64)
+	if _, err := os.Stat(b.packsDir()); err == nil {
+		packed, err := loadPackIndexes(b.packsDir())
+		if err != nil {
+			return nil, err
+		}
+		for h, loc := range packed {
+			sizes[h] = loc.length
+		}
+	}
+	entries, err := os.ReadDir(b.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		sizes[entry.Name()] = info.Size()
+	}
+	return sizes, nil
+}
+
+// Repack consolidates the loose blobs and all existing packs into a single
+// new pack, keeping only blobs for which keep returns true (all of them if
+// keep is nil). Loose blobs are removed once the pack and its index are on
+// disk; loose blobs keep rejects are left alone. Loose blobs whose content
+// doesn't match their hash are not packed: the packed copy is used instead
+// if there is one, otherwise they are left loose.
+func (b *FSBlobStore) Repack(keep func(hash string) bool) (*RepackResult, error) {
+	result := &RepackResult{}
+
+	var packed map[string]packedBlob
+	var oldPacks []string
+	if _, err := os.Stat(b.packsDir()); err == nil {
+		var err error
+		if packed, err = loadPackIndexes(b.packsDir()); err != nil {
+			return nil, err
+		}
+		if oldPacks, err = filepath.Glob(filepath.Join(b.packsDir(), "pack-*"+indexSuffix)); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := os.ReadDir(b.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read blobs: %w", err)
+	}
+	var loose []string
+	for _, entry := range entries {
+		if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			loose = append(loose, entry.Name())
+		}
+	}
+
+	type source struct {
+		hash  string
+		loose bool
+	}
+	var sources []source
+	seen := make(map[string]bool)
+	for _, h := range loose {
+		if keep == nil || keep(h) {
+			sources = append(sources, source{h, true})
+			seen[h] = true
+		}
+	}
+	for h := range packed {
+		if seen[h] {
+			continue
+		}
+		if keep == nil || keep(h) {
+			sources = append(sources, source{h, false})
+		} else {
+			result.Dropped++
+		}
+	}
+	if len(oldPacks) <= 1 && result.Dropped == 0 && len(sources) == len(packed) {
+		return result, nil // no loose blobs to pack and nothing to drop
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].hash < sources[j].hash })
+
+	if err := os.MkdirAll(b.packsDir(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create packs directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(b.packsDir(), ".fst-tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pack: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	sum := sha256.New()
+	w := io.MultiWriter(tmp, sum)
+	idx := packIndex{Version: 1, Blobs: make(map[string]packIndexEntry, len(sources))}
+	var packedLoose []string
+	var offset int64
+	for _, src := range sources {
+		var data []byte
+		if src.loose {
+			data, err = os.ReadFile(b.path(src.hash))
+			if sum := sha256.Sum256(data); err == nil && hex.EncodeToString(sum[:]) != src.hash {
+				loc, ok := packed[src.hash]
+				if !ok {
+					result.Skipped++
+					continue
+				}
+				// The old pack still has a good copy; pack that one and let
+				// the corrupt loose file be removed with the others.
+				data, err = readPacked(loc)
+			}
+		} else {
+			data, err = readPacked(packed[src.hash])
+		}
+		if err != nil {
+			tmp.Close()
+			return nil, fmt.Errorf("failed to read blob %s: %w", src.hash, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			tmp.Close()
+			return nil, fmt.Errorf("failed to write pack: %w", err)
+		}
+		idx.Blobs[src.hash] = packIndexEntry{Offset: offset, Length: int64(len(data))}
+		offset += int64(len(data))
+		if src.loose {
+			packedLoose = append(packedLoose, src.hash)
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to sync pack: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close pack: %w", err)
+	}
+
+	name := "pack-" + hex.EncodeToString(sum.Sum(nil))
+	packPath := filepath.Join(b.packsDir(), name+packSuffix)
+	indexPath := filepath.Join(b.packsDir(), name+indexSuffix)
+	if len(idx.Blobs) > 0 {
+		if err := os.Chmod(tmpPath, 0644); err != nil {
+			return nil, fmt.Errorf("failed to set pack permissions: %w", err)
+		}
+		if err := os.Rename(tmpPath, packPath); err != nil {
+			return nil, fmt.Errorf("failed to write pack: %w", err)
+		}
+		data, err := json.Marshal(idx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode pack index: %w", err)
+		}
+		// The index goes last: readers only see a pack once it is complete.
+		if err := AtomicWriteFile(indexPath, data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write pack index: %w", err)
+		}
+	}
+
+	// Everything is now reachable through the new pack.
+	for _, old := range oldPacks {
+		if old == indexPath {
+			continue
+		}
+		_ = os.Remove(old)
+		_ = os.Remove(strings.TrimSuffix(old, indexSuffix) + packSuffix)
+		result.Packs++
+	}
+	for _, h := range packedLoose {
+		_ = os.Remove(b.path(h))
+	}
+
+	b.mu.Lock()
+	b.packsLoaded = false
+	b.mu.Unlock()
+
+	result.Packed = len(idx.Blobs)
+	result.Loose = len(packedLoose)
+	result.Bytes = offset
+	return result, nil
+}
+
+// fsBlobs returns the store's blobs as an FSBlobStore, or one over the
+// store's blobs directory if blobs are served from elsewhere.
+func (s *Store) fsBlobs() *FSBlobStore {
+	if fs, ok := s.blobs.(*FSBlobStore); ok {
+		return fs
+	}
+	return NewFSBlobStore(s.blobsDir)
+}
+
+// Repack consolidates the store's loose blobs and packs into one pack file.
+// See FSBlobStore.Repack.
+func (s *Store) Repack(keep func(hash string) bool) (*RepackResult, error) {
+	return s.fsBlobs().Repack(keep)
+}
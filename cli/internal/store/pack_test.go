@@ -0,0 +1,160 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepack(t *testing.T) {
+	s, _ := setupStore(t)
+
+	contents := []string{"alpha", "beta", "gamma"}
+	for _, c := range contents {
+		if err := s.WriteBlob(sha256Hex([]byte(c)), []byte(c)); err != nil {
+			t.Fatalf("WriteBlob: %v", err)
+		}
+	}
+
+	result, err := s.Repack(nil)
+	if err != nil {
+		t.Fatalf("Repack: %v", err)
+	}
+	if result.Packed != 3 || result.Loose != 3 || result.Packs != 0 {
+		t.Fatalf("unexpected repack result: %+v", result)
+	}
+	for _, c := range contents {
+		hash := sha256Hex([]byte(c))
+		if _, err := os.Stat(s.BlobPath(hash)); !os.IsNotExist(err) {
+			t.Fatalf("loose blob %s should have been removed", c)
+		}
+		data, err := s.ReadBlob(hash)
+		if err != nil || string(data) != c {
+			t.Fatalf("ReadBlob %s = %q, %v", c, data, err)
+		}
+		if !s.BlobExists(hash) {
+			t.Fatalf("BlobExists %s = false", c)
+		}
+		if size, err := s.Blobs().Size(hash); err != nil || size != int64(len(c)) {
+			t.Fatalf("Size %s = %d, %v", c, size, err)
+		}
+	}
+
+	// New blobs stay loose; a second repack folds them into a single pack.
+	if err := s.WriteBlob(sha256Hex([]byte("delta")), []byte("delta")); err != nil {
+		t.Fatalf("WriteBlob: %v", err)
+	}
+	if _, err := os.Stat(s.BlobPath(sha256Hex([]byte("delta")))); err != nil {
+		t.Fatalf("new blob should be loose: %v", err)
+	}
+	result, err = s.Repack(nil)
+	if err != nil {
+		t.Fatalf("Repack: %v", err)
+	}
+	if result.Packed != 4 || result.Loose != 1 || result.Packs != 1 {
+		t.Fatalf("unexpected second repack result: %+v", result)
+	}
+	packs, _ := filepath.Glob(filepath.Join(s.BlobsDir(), packsDirName, "pack-*"+packSuffix))
+	if len(packs) != 1 {
+		t.Fatalf("expected 1 pack file, got %v", packs)
+	}
+
+	listed, err := s.fsBlobs().List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(listed) != 4 || listed[sha256Hex([]byte("delta"))] != 5 {
+		t.Fatalf("unexpected blob list: %v", listed)
+	}
+
+	// Nothing loose and a single pack: repacking again is a no-op.
+	result, err = s.Repack(nil)
+	if err != nil {
+		t.Fatalf("Repack: %v", err)
+	}
+	if result.Loose != 0 || result.Packs != 0 || result.Dropped != 0 {
+		t.Fatalf("expected no-op repack, got %+v", result)
+	}
+}
+
+func TestRepackKeepsPackedCopyOfCorruptLooseBlob(t *testing.T) {
+	s, _ := setupStore(t)
+
+	hash := sha256Hex([]byte("alpha"))
+	other := sha256Hex([]byte("beta"))
+	for _, c := range []string{"alpha", "beta"} {
+		if err := s.WriteBlob(sha256Hex([]byte(c)), []byte(c)); err != nil {
+			t.Fatalf("WriteBlob: %v", err)
+		}
+	}
+	if _, err := s.Repack(nil); err != nil {
+		t.Fatalf("Repack: %v", err)
+	}
+
+	// A corrupt loose copy next to the good packed one, plus a new loose
+	// blob so the next repack rewrites the pack.
+	if err := os.WriteFile(s.BlobPath(hash), []byte("tampered"), 0644); err != nil {
+		t.Fatalf("write corrupt blob: %v", err)
+	}
+	if err := s.WriteBlob(sha256Hex([]byte("gamma")), []byte("gamma")); err != nil {
+		t.Fatalf("WriteBlob: %v", err)
+	}
+
+	result, err := s.Repack(nil)
+	if err != nil {
+		t.Fatalf("Repack: %v", err)
+	}
+	if result.Packed != 3 || result.Skipped != 0 {
+		t.Fatalf("unexpected repack result: %+v", result)
+	}
+	if _, err := os.Stat(s.BlobPath(hash)); !os.IsNotExist(err) {
+		t.Fatalf("corrupt loose blob should have been removed")
+	}
+	for c, h := range map[string]string{"alpha": hash, "beta": other} {
+		if data, err := s.ReadBlob(h); err != nil || string(data) != c {
+			t.Fatalf("ReadBlob %s = %q, %v", c, data, err)
+		}
+	}
+}
+
+func TestGC_RepackDropsPackedOrphans(t *testing.T) {
+	s, _ := setupStore(t)
+
+	base := seedSnapshot(t, s, "snap-base", nil, map[string]string{
+		"file.txt": "hello",
+	})
+	s.RegisterWorkspace(WorkspaceInfo{
+		WorkspaceID:       "ws-1",
+		WorkspaceName:     "main",
+		CurrentSnapshotID: base,
+	})
+	orphan := sha256Hex([]byte("orphan"))
+	if err := s.WriteBlob(orphan, []byte("orphan")); err != nil {
+		t.Fatalf("WriteBlob: %v", err)
+	}
+	if _, err := s.Repack(nil); err != nil {
+		t.Fatalf("Repack: %v", err)
+	}
+
+	result, err := s.GC(GCOpts{})
+	if err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	if result.DeletedBlobs != 1 || result.Repack == nil || result.Repack.Dropped != 1 {
+		t.Fatalf("expected packed orphan to be dropped, got %+v", result)
+	}
+	if s.BlobExists(orphan) {
+		t.Fatalf("orphan blob should be gone")
+	}
+	if !s.BlobExists(sha256Hex([]byte("hello"))) {
+		t.Fatalf("referenced blob should survive")
+	}
+
+	verify, err := s.VerifyBlobs(VerifyBlobsOpts{})
+	if err != nil {
+		t.Fatalf("VerifyBlobs: %v", err)
+	}
+	if verify.Checked != 1 || len(verify.Problems) != 0 {
+		t.Fatalf("expected 1 clean blob after repack, got %+v", verify)
+	}
+}
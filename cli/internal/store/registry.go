@@ -88,6 +88,15 @@ func (s *Store) UpdateWorkspaceHead(workspaceID, snapshotID string) error {
 	return s.saveWorkspaceInfo(existing)
 }
 
+// UnregisterWorkspace removes a workspace entry. Removing an entry that
+// does not exist is not an error.
+func (s *Store) UnregisterWorkspace(workspaceID string) error {
+	if err := os.Remove(s.workspacePath(workspaceID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 // FindWorkspaceByName returns the workspace with the given name, or error if not found.
 func (s *Store) FindWorkspaceByName(name string) (*WorkspaceInfo, error) {
 	entries, err := os.ReadDir(s.workspacesDir())
@@ -24,6 +24,9 @@ type SnapshotMeta struct {
 	CreatedAt         string   `json:"created_at"`
 	Files             int      `json:"files,omitempty"`
 	Size              int64    `json:"size,omitempty"`
+	// FixupOf is the ID of an earlier snapshot this one amends. Exporting
+	// with --autosquash folds the fixup into that snapshot's commit.
+	FixupOf string `json:"fixup_of,omitempty"`
 }
 
 // LoadSnapshotMeta reads snapshot metadata by ID from the store.
@@ -22,16 +22,19 @@ type Store struct {
 	snapshotsDir string
 	manifestsDir string
 	blobsDir     string
+	blobs        BlobStore
 }
 
 // OpenAt creates a Store rooted at the given project root directory.
 func OpenAt(projectRoot string) *Store {
 	base := filepath.Join(projectRoot, configDirName)
+	blobsDir := filepath.Join(base, blobsDirName)
 	return &Store{
 		root:         projectRoot,
 		snapshotsDir: filepath.Join(base, snapshotsDirName),
 		manifestsDir: filepath.Join(base, manifestsDirName),
-		blobsDir:     filepath.Join(base, blobsDirName),
+		blobsDir:     blobsDir,
+		blobs:        NewFSBlobStore(blobsDir),
 	}
 }
 
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// UsageStats summarizes the number and on-disk size of a set of store objects.
+type UsageStats struct {
+	Count int
+	Bytes int64
+}
+
+// DiskUsage reports how much disk space the store occupies.
+type DiskUsage struct {
+	Blobs     UsageStats
+	Manifests UsageStats
+	Snapshots UsageStats
+}
+
+// TotalBytes returns the combined size of blobs, manifests and snapshots.
+func (u *DiskUsage) TotalBytes() int64 {
+	return u.Blobs.Bytes + u.Manifests.Bytes + u.Snapshots.Bytes
+}
+
+// WorkspaceUsage attributes blob bytes to a single workspace.
+type WorkspaceUsage struct {
+	WorkspaceID   string
+	WorkspaceName string
+	Blobs         UsageStats
+}
+
+// UsageByWorkspace attributes reachable blob bytes to workspaces. A blob
+// reachable from exactly one workspace is counted under that workspace;
+// a blob reachable from several is counted once under Shared. Blobs not
+// reachable from any workspace are counted under Unreferenced.
+type UsageByWorkspace struct {
+	Workspaces   []WorkspaceUsage
+	Shared       UsageStats
+	Unreferenced UsageStats
+}
+
+// DiskUsage walks the store directories and reports object counts and sizes.
+func (s *Store) DiskUsage() (*DiskUsage, error) {
+	stored, err := s.fsBlobs().List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blobs: %w", err)
+	}
+	var blobs UsageStats
+	for _, size := range stored {
+		blobs.Count++
+		blobs.Bytes += size
+	}
+	manifests, err := dirUsage(s.manifestsDir, ".json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+	snapshots, err := dirUsage(s.snapshotsDir, ".meta.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+	return &DiskUsage{Blobs: blobs, Manifests: manifests, Snapshots: snapshots}, nil
+}
+
+// UsageByWorkspace computes per-workspace blob usage using the same
+// reachability rules as GC: a workspace owns everything reachable from its
+// current and base snapshots.
+func (s *Store) UsageByWorkspace() (*UsageByWorkspace, error) {
+	workspaces, err := s.ListWorkspaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	// blob hash -> indexes of workspaces that reach it
+	owners := make(map[string][]int)
+	manifestBlobs := make(map[string][]string)
+
+	for i, ws := range workspaces {
+		reachable := s.BuildReachableSet([]string{ws.CurrentSnapshotID, ws.BaseSnapshotID})
+		seen := make(map[string]struct{})
+		for id := range reachable {
+			meta, err := s.LoadSnapshotMeta(id)
+			if err != nil || meta.ManifestHash == "" {
+				continue
+			}
+			hashes, ok := manifestBlobs[meta.ManifestHash]
+			if !ok {
+				m, err := s.LoadManifest(meta.ManifestHash)
+				if err != nil {
+					continue
+				}
+				for _, f := range m.FileEntries() {
+					hashes = append(hashes, f.Hash)
+				}
+				manifestBlobs[meta.ManifestHash] = hashes
+			}
+			for _, h := range hashes {
+				if _, dup := seen[h]; dup {
+					continue
+				}
+				seen[h] = struct{}{}
+				owners[h] = append(owners[h], i)
+			}
+		}
+	}
+
+	result := &UsageByWorkspace{Workspaces: make([]WorkspaceUsage, len(workspaces))}
+	for i, ws := range workspaces {
+		result.Workspaces[i] = WorkspaceUsage{
+			WorkspaceID:   ws.WorkspaceID,
+			WorkspaceName: ws.WorkspaceName,
+		}
+	}
+
+	stored, err := s.fsBlobs().List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blobs: %w", err)
+	}
+	for hash, size := range stored {
+		switch ws := owners[hash]; len(ws) {
+		case 0:
+			result.Unreferenced.Count++
+			result.Unreferenced.Bytes += size
+		case 1:
+			result.Workspaces[ws[0]].Blobs.Count++
+			result.Workspaces[ws[0]].Blobs.Bytes += size
+		default:
+			result.Shared.Count++
+			result.Shared.Bytes += size
+		}
+	}
+
+	sort.Slice(result.Workspaces, func(i, j int) bool {
+		return result.Workspaces[i].WorkspaceName < result.Workspaces[j].WorkspaceName
+	})
+
+	return result, nil
+}
+
+// dirUsage counts regular files in dir whose names end with suffix and
+// sums their sizes. A missing directory reports zero usage.
+func dirUsage(dir, suffix string) (UsageStats, error) {
+	var stats UsageStats
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return stats, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), suffix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		stats.Count++
+		stats.Bytes += info.Size()
+	}
+	return stats, nil
+}
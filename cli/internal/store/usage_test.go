@@ -0,0 +1,82 @@
+package store
+
+import "testing"
+
+func TestDiskUsage(t *testing.T) {
+	s, _ := setupStore(t)
+
+	base := seedSnapshot(t, s, "snap-base", nil, map[string]string{
+		"a.txt": "hello",
+	})
+	seedSnapshot(t, s, "snap-next", []string{base}, map[string]string{
+		"a.txt": "hello",
+		"b.txt": "world!",
+	})
+
+	usage, err := s.DiskUsage()
+	if err != nil {
+		t.Fatalf("DiskUsage: %v", err)
+	}
+	if usage.Blobs.Count != 2 {
+		t.Fatalf("expected 2 blobs, got %d", usage.Blobs.Count)
+	}
+	if usage.Blobs.Bytes != int64(len("hello")+len("world!")) {
+		t.Fatalf("unexpected blob bytes: %d", usage.Blobs.Bytes)
+	}
+	if usage.Manifests.Count != 2 {
+		t.Fatalf("expected 2 manifests, got %d", usage.Manifests.Count)
+	}
+	if usage.Snapshots.Count != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", usage.Snapshots.Count)
+	}
+	if usage.TotalBytes() <= usage.Blobs.Bytes {
+		t.Fatalf("expected total to include manifests and snapshots")
+	}
+}
+
+func TestUsageByWorkspace_SharedCountedOnce(t *testing.T) {
+	s, _ := setupStore(t)
+
+	base := seedSnapshot(t, s, "snap-base", nil, map[string]string{
+		"shared.txt": "shared",
+	})
+	a := seedSnapshot(t, s, "snap-a", []string{base}, map[string]string{
+		"shared.txt": "shared",
+		"a.txt":      "only-a",
+	})
+	b := seedSnapshot(t, s, "snap-b", []string{base}, map[string]string{
+		"shared.txt": "shared",
+		"b.txt":      "only-b!!",
+	})
+	seedSnapshot(t, s, "snap-orphan", nil, map[string]string{
+		"orphan.txt": "orphan",
+	})
+
+	s.RegisterWorkspace(WorkspaceInfo{WorkspaceID: "ws-a", WorkspaceName: "a", CurrentSnapshotID: a, BaseSnapshotID: base})
+	s.RegisterWorkspace(WorkspaceInfo{WorkspaceID: "ws-b", WorkspaceName: "b", CurrentSnapshotID: b, BaseSnapshotID: base})
+
+	usage, err := s.UsageByWorkspace()
+	if err != nil {
+		t.Fatalf("UsageByWorkspace: %v", err)
+	}
+	if len(usage.Workspaces) != 2 {
+		t.Fatalf("expected 2 workspaces, got %d", len(usage.Workspaces))
+	}
+
+	byName := map[string]UsageStats{}
+	for _, ws := range usage.Workspaces {
+		byName[ws.WorkspaceName] = ws.Blobs
+	}
+	if got := byName["a"]; got.Count != 1 || got.Bytes != int64(len("only-a")) {
+		t.Fatalf("unexpected usage for a: %+v", got)
+	}
+	if got := byName["b"]; got.Count != 1 || got.Bytes != int64(len("only-b!!")) {
+		t.Fatalf("unexpected usage for b: %+v", got)
+	}
+	if usage.Shared.Count != 1 || usage.Shared.Bytes != int64(len("shared")) {
+		t.Fatalf("unexpected shared usage: %+v", usage.Shared)
+	}
+	if usage.Unreferenced.Count != 1 {
+		t.Fatalf("expected 1 unreferenced blob, got %d", usage.Unreferenced.Count)
+	}
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Blob problem kinds reported by VerifyBlobs.
+const (
+	BlobCorrupt = "corrupt"
+	BlobMissing = "missing"
+)
+
+// BlobProblem describes one blob that failed verification.
+type BlobProblem struct {
+	Hash   string
+	Kind   string // BlobCorrupt or BlobMissing
+	Detail string
+}
+
+// VerifyBlobsOpts configures a blob store verification.
+type VerifyBlobsOpts struct {
+	// FailFast stops at the first problem instead of scanning everything.
+	FailFast bool
+}
+
+// VerifyBlobsResult is the outcome of VerifyBlobs.
+type VerifyBlobsResult struct {
+	Checked  int // blobs whose content was hashed
+	Problems []BlobProblem
+}
+
+// VerifyBlobs checks that every stored blob's content hashes to its name
+// and that every blob referenced by a snapshot's manifest is present.
+// Corrupt blobs are reported before missing ones, each group in hash order.
+// A snapshot manifest that cannot be loaded is returned as an error.
+func (s *Store) VerifyBlobs(opts VerifyBlobsOpts) (*VerifyBlobsResult, error) {
+	result := &VerifyBlobsResult{}
+
+	stored, err := s.fsBlobs().List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blobs: %w", err)
+	}
+	hashes := make([]string, 0, len(stored))
+	for hash := range stored {
+		// Temp files of in-flight writes are not blobs yet.
+		if strings.HasPrefix(hash, ".fst-tmp-") {
+			delete(stored, hash)
+			continue
+		}
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	for _, hash := range hashes {
+		data, err := s.blobs.Get(hash)
+		result.Checked++
+		if err != nil {
+			result.Problems = append(result.Problems, BlobProblem{Hash: hash, Kind: BlobCorrupt, Detail: err.Error()})
+		} else {
+			sum := sha256.Sum256(data)
+			if got := hex.EncodeToString(sum[:]); got != hash {
+				result.Problems = append(result.Problems, BlobProblem{Hash: hash, Kind: BlobCorrupt, Detail: "content hashes to " + got})
+			}
+		}
+		if opts.FailFast && len(result.Problems) > 0 {
+			return result, nil
+		}
+	}
+
+	metas, err := s.LoadAllSnapshotMetas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshots: %w", err)
+	}
+	ids := make([]string, 0, len(metas))
+	for id := range metas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	seenManifests := make(map[string]struct{})
+	reported := make(map[string]struct{})
+	var missing []BlobProblem
+	for _, id := range ids {
+		hash := metas[id].ManifestHash
+		if _, ok := seenManifests[hash]; ok || hash == "" {
+			continue
+		}
+		seenManifests[hash] = struct{}{}
+		m, err := s.LoadManifest(hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load manifest %s of snapshot %s: %w", hash, id, err)
+		}
+		for _, f := range m.FileEntries() {
+			if _, ok := stored[f.Hash]; ok {
+				continue
+			}
+			if _, ok := reported[f.Hash]; ok {
+				continue
+			}
+			reported[f.Hash] = struct{}{}
+			missing = append(missing, BlobProblem{
+				Hash:   f.Hash,
+				Kind:   BlobMissing,
+				Detail: fmt.Sprintf("%s in snapshot %s", f.Path, id),
+			})
+			if opts.FailFast {
+				result.Problems = append(result.Problems, missing...)
+				return result, nil
+			}
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Hash < missing[j].Hash })
+	result.Problems = append(result.Problems, missing...)
+
+	return result, nil
+}
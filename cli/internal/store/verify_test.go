@@ -0,0 +1,83 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyBlobs(t *testing.T) {
+	s, _ := setupStore(t)
+
+	seedSnapshot(t, s, "snap-one", nil, map[string]string{
+		"a.txt": "hello",
+		"b.txt": "world!",
+		"c.txt": "intact",
+	})
+	hashOf := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(sum[:])
+	}
+
+	result, err := s.VerifyBlobs(VerifyBlobsOpts{})
+	if err != nil {
+		t.Fatalf("VerifyBlobs: %v", err)
+	}
+	if result.Checked != 3 || len(result.Problems) != 0 {
+		t.Fatalf("expected 3 clean blobs, got %+v", result)
+	}
+
+	if err := os.WriteFile(s.BlobPath(hashOf("hello")), []byte("tampered"), 0644); err != nil {
+		t.Fatalf("corrupt blob: %v", err)
+	}
+	if err := os.Remove(s.BlobPath(hashOf("world!"))); err != nil {
+		t.Fatalf("remove blob: %v", err)
+	}
+
+	result, err = s.VerifyBlobs(VerifyBlobsOpts{})
+	if err != nil {
+		t.Fatalf("VerifyBlobs: %v", err)
+	}
+	if len(result.Problems) != 2 {
+		t.Fatalf("expected 2 problems, got %+v", result.Problems)
+	}
+	if p := result.Problems[0]; p.Kind != BlobCorrupt || p.Hash != hashOf("hello") {
+		t.Fatalf("expected corrupt hello blob first, got %+v", p)
+	}
+	if p := result.Problems[1]; p.Kind != BlobMissing || p.Hash != hashOf("world!") {
+		t.Fatalf("expected missing world! blob second, got %+v", p)
+	}
+
+	result, err = s.VerifyBlobs(VerifyBlobsOpts{FailFast: true})
+	if err != nil {
+		t.Fatalf("VerifyBlobs: %v", err)
+	}
+	if len(result.Problems) != 1 {
+		t.Fatalf("expected fail-fast to stop at 1 problem, got %+v", result.Problems)
+	}
+
+	if err := os.WriteFile(filepath.Join(s.blobsDir, ".fst-tmp-123"), []byte("partial"), 0644); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+	result, err = s.VerifyBlobs(VerifyBlobsOpts{})
+	if err != nil {
+		t.Fatalf("VerifyBlobs: %v", err)
+	}
+	if len(result.Problems) != 2 {
+		t.Fatalf("expected temp files to be skipped, got %+v", result.Problems)
+	}
+
+	meta, err := s.LoadSnapshotMeta("snap-one")
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.manifestsDir, meta.ManifestHash+".json"), []byte("{"), 0644); err != nil {
+		t.Fatalf("corrupt manifest: %v", err)
+	}
+	if _, err := s.VerifyBlobs(VerifyBlobsOpts{}); err == nil || !strings.Contains(err.Error(), "snap-one") {
+		t.Fatalf("expected an error for the unreadable manifest, got %v", err)
+	}
+}
@@ -2,13 +2,23 @@
 //
 // All functions return styled strings using lipgloss, which automatically
 // respects NO_COLOR env, non-TTY output, and terminal color capabilities.
-// Call Disable() to force plain text output (e.g. for --no-color flags).
+// Call Disable() to force plain text output (e.g. for --no-color flags),
+// or SetColorMode() to apply a --color setting.
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
 
 var disabled bool
 
+// detected is the color profile lipgloss detected before SetColorMode
+// forced one, restored by the "auto" mode.
+var detected *termenv.Profile
+
 var (
 	bold     = lipgloss.NewStyle().Bold(true)
 	green    = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
@@ -38,5 +48,31 @@ func BoldCyan(s string) string { return render(boldCyan, s) }
 // Call before producing output when the user passes --no-color.
 func Disable() { disabled = true }
 
+// SetColorMode applies a --color setting: "auto" colors only when stdout
+// is a terminal and NO_COLOR is unset, "always" colors even when output is
+// piped, and "never" disables color.
+func SetColorMode(mode string) error {
+	switch mode {
+	case "auto", "":
+		disabled = false
+		if detected != nil {
+			lipgloss.SetColorProfile(*detected)
+			detected = nil
+		}
+	case "always":
+		disabled = false
+		if detected == nil {
+			p := lipgloss.ColorProfile()
+			detected = &p
+		}
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	case "never":
+		disabled = true
+	default:
+		return fmt.Errorf("invalid color mode %q (expected auto, always or never)", mode)
+	}
+	return nil
+}
+
 // Reset re-enables styling. Useful in tests to avoid state leaking.
 func Reset() { disabled = false }
@@ -12,6 +12,7 @@ const (
 	workspaceLockFile = "lock"
 	gcLockFile        = "gc.lock"
 	backendLockFile   = "backend.lock"
+	spawnLockFile     = "backend-spawn.lock"
 )
 
 // LockFile represents a held file lock (flock-based).
@@ -48,6 +49,28 @@ func (l *LockFile) Release() error {
 	return l.file.Close()
 }
 
+// File returns the open lock file, e.g. to hand the lock to a child process
+// through exec.Cmd.ExtraFiles.
+func (l *LockFile) File() *os.File {
+	return l.file
+}
+
+// Close closes this process's handle on the lock without unlocking it. A
+// lock handed to a child process stays held until the child releases it or
+// exits.
+func (l *LockFile) Close() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// InheritedLock adopts a lock handed down by the parent process as an open
+// file descriptor.
+func InheritedLock(fd uintptr) *LockFile {
+	return &LockFile{file: os.NewFile(fd, spawnLockFile)}
+}
+
 // AcquireWorkspaceLock acquires an exclusive lock on a workspace directory.
 // This prevents concurrent fst operations on the same workspace from
 // interleaving and producing corrupted state.
@@ -108,3 +131,17 @@ func TryAcquireBackendLock(projectRoot string) (*LockFile, error) {
 	}
 	return lock, nil
 }
+
+// TryAcquireBackendSpawnLock attempts to acquire the lock that marks a
+// background sync as spawned but not yet running. The spawner hands it to
+// the child, which releases it once it holds the backend lock, so at most one
+// background sync is ever waiting. Returns nil, nil if a spawned sync is
+// still pending.
+func TryAcquireBackendSpawnLock(projectRoot string) (*LockFile, error) {
+	path := filepath.Join(projectRoot, lockDirName, spawnLockFile)
+	lock, err := acquireFlock(path, syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		return nil, nil
+	}
+	return lock, nil
+}
@@ -100,6 +100,29 @@ time.sleep(30)
 	}
 }
 
+func TestTryAcquireBackendSpawnLock(t *testing.T) {
+	root := t.TempDir()
+
+	pending, err := TryAcquireBackendSpawnLock(root)
+	if err != nil || pending == nil {
+		t.Fatalf("expected to acquire spawn lock when free, got %v, %v", pending, err)
+	}
+
+	if second, _ := TryAcquireBackendSpawnLock(root); second != nil {
+		second.Release()
+		pending.Release()
+		t.Fatalf("expected spawn lock to be held while a spawn is pending")
+	}
+
+	pending.Release()
+
+	again, _ := TryAcquireBackendSpawnLock(root)
+	if again == nil {
+		t.Fatalf("expected spawn lock to be free after release")
+	}
+	again.Release()
+}
+
 func TestReleaseNilLock(t *testing.T) {
 	// Releasing a nil lock should not panic
 	var lock *LockFile
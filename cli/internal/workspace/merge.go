@@ -16,8 +16,9 @@ type ConflictMode int
 
 const (
 	ConflictModeManual ConflictMode = iota // Write <<<<<<< markers
-	ConflictModeTheirs                     // Take source version
+	ConflictModeTheirs                     // Take source version (deleting the file if the source deleted it)
 	ConflictModeOurs                       // Keep current version
+	ConflictModeSkip                       // Leave the file untouched and unresolved
 )
 
 // ConflictResolver is called for each conflicting file during merge.
@@ -26,21 +27,57 @@ const (
 // falls back to the conflict mode specified in ApplyMergeOpts.
 type ConflictResolver func(path string, current, source, base []byte) ([]byte, error)
 
+// ConflictInput is one conflicting file passed to a BatchConflictResolver.
+type ConflictInput struct {
+	Path                  string
+	Current, Source, Base []byte
+}
+
+// BatchConflictResolver resolves several conflicting files in one call and
+// returns the merged content by path. Files it errors on or leaves out are
+// resolved one at a time with the ConflictResolver.
+type BatchConflictResolver func(conflicts []ConflictInput) (map[string][]byte, error)
+
 // ApplyMergeOpts configures how a merge plan is applied to the workspace.
 type ApplyMergeOpts struct {
 	Plan     *store.MergePlan
 	Mode     ConflictMode
 	Resolver ConflictResolver // optional; called before falling back to Mode
+	// BatchResolver, if set, is tried first on groups of up to ChunkSize
+	// conflicts (those not covered by PathModes) whose base, current and
+	// source contents total at most ChunkBytes (0 = no limit). A file over
+	// ChunkBytes on its own is sent alone.
+	BatchResolver BatchConflictResolver
+	ChunkSize     int
+	ChunkBytes    int
+	// PathModes overrides Mode (and skips Resolver) for specific conflicting paths.
+	PathModes map[string]ConflictMode
+	// Progress, if set, is called after each non-conflicting change is
+	// written, with the number written so far and the total.
+	Progress func(done, total int)
 }
 
 // MergeResult contains the outcome of applying a merge.
 type MergeResult struct {
 	Applied    []string // files successfully merged
 	AutoMerged []string // files auto-merged at line level (non-overlapping changes)
-	Conflicts  []string // files left with conflict markers
+	Deleted    []string // files deleted because the source deleted them
+	Conflicts  []string // files left unresolved, with conflict markers unless skipped
 	Failed     []string // files that failed
+	// Resolutions records how each conflicting file was handled, by path,
+	// as one of the Resolved* values. Failed files are not recorded.
+	Resolutions map[string]string
 }
 
+// Values of MergeResult.Resolutions.
+const (
+	ResolvedTheirs   = "theirs"   // source version taken
+	ResolvedOurs     = "ours"     // current version kept
+	ResolvedResolver = "resolver" // merged by the ConflictResolver or BatchConflictResolver
+	ResolvedManual   = "manual"   // conflict markers written
+	ResolvedSkipped  = "skipped"  // left untouched for the user to resolve
+)
+
 // ApplyMerge writes a merge plan to the workspace's working tree.
 // It applies non-conflicting changes, resolves conflicts per the
 // configured mode, and records merge parents for the next snapshot.
@@ -63,15 +100,24 @@ func (ws *Workspace) ApplyMerge(opts ApplyMergeOpts) (*MergeResult, error) {
 		return nil, fmt.Errorf("failed to record merge parents: %w", err)
 	}
 
-	result := &MergeResult{}
+	result := &MergeResult{Resolutions: make(map[string]string)}
 
 	// Apply non-conflicting changes
-	for _, action := range plan.ToApply {
-		if err := ws.applyAction(action); err != nil {
+	for i, action := range plan.ToApply {
+		if action.Type == "delete" {
+			if err := ws.deleteAction(action); err != nil {
+				result.Failed = append(result.Failed, action.Path)
+			} else {
+				result.Deleted = append(result.Deleted, action.Path)
+			}
+		} else if err := ws.applyAction(action); err != nil {
 			result.Failed = append(result.Failed, action.Path)
 		} else {
 			result.Applied = append(result.Applied, action.Path)
 		}
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(plan.ToApply))
+		}
 	}
 
 	// Apply auto-merged files (line-level merge succeeded in planner)
@@ -81,8 +127,7 @@ func (ws *Workspace) ApplyMerge(opts ApplyMergeOpts) (*MergeResult, error) {
 			result.Failed = append(result.Failed, action.Path)
 			continue
 		}
-		mode := fileModeOrDefault(action.SourceMode, 0644)
-		if err := os.WriteFile(targetPath, action.MergedContent, mode); err != nil {
+		if err := WriteFileMode(targetPath, action.MergedContent, ConflictFileMode(targetPath, action.SourceMode)); err != nil {
 			result.Failed = append(result.Failed, action.Path)
 		} else {
 			result.AutoMerged = append(result.AutoMerged, action.Path)
@@ -90,48 +135,96 @@ func (ws *Workspace) ApplyMerge(opts ApplyMergeOpts) (*MergeResult, error) {
 	}
 
 	// Handle conflicts
+	batchResolved := ws.resolveInChunks(plan.Conflicts, opts)
 	for _, action := range plan.Conflicts {
-		resolved := false
+		if mode, ok := opts.PathModes[action.Path]; ok {
+			ws.applyConflictMode(action, mode, result)
+			continue
+		}
+		if batchResolved[action.Path] {
+			result.Applied = append(result.Applied, action.Path)
+			result.Resolutions[action.Path] = ResolvedResolver
+			continue
+		}
 
-		// Try resolver first
-		if opts.Resolver != nil {
+		// Try resolver first. A delete/modify conflict has no content to
+		// merge, so it goes straight to the conflict mode.
+		if opts.Resolver != nil && !action.IsDeleteConflict() {
 			if err := ws.resolveWithCallback(action, opts.Resolver); err == nil {
 				result.Applied = append(result.Applied, action.Path)
-				resolved = true
+				result.Resolutions[action.Path] = ResolvedResolver
+				continue
 			}
 		}
 
-		if !resolved {
-			switch opts.Mode {
-			case ConflictModeTheirs:
-				if err := ws.applyAction(action); err != nil {
-					result.Failed = append(result.Failed, action.Path)
-				} else {
-					result.Applied = append(result.Applied, action.Path)
-				}
-
-			case ConflictModeOurs:
-				// Keep current version — no-op
-				result.Applied = append(result.Applied, action.Path)
-
-			case ConflictModeManual:
-				if err := ws.writeConflictMarkers(action); err != nil {
-					result.Failed = append(result.Failed, action.Path)
-				} else {
-					result.Conflicts = append(result.Conflicts, action.Path)
-				}
-			}
-		}
+		ws.applyConflictMode(action, opts.Mode, result)
 	}
 
 	// If everything failed, clear the merge parents
-	if len(result.Failed) > 0 && len(result.Applied) == 0 && len(result.Conflicts) == 0 {
+	if len(result.Failed) > 0 && len(result.Applied) == 0 && len(result.Deleted) == 0 && len(result.Conflicts) == 0 {
 		_ = config.ClearPendingMergeParentsAt(ws.root)
 	}
 
 	return result, nil
 }
 
+// applyConflictMode resolves a single conflict according to mode and records
+// the outcome in result.
+func (ws *Workspace) applyConflictMode(action store.MergeAction, mode ConflictMode, result *MergeResult) {
+	switch mode {
+	case ConflictModeTheirs:
+		if action.SourceDeleted() {
+			if err := ws.deleteAction(action); err != nil {
+				result.Failed = append(result.Failed, action.Path)
+			} else {
+				result.Deleted = append(result.Deleted, action.Path)
+				result.Resolutions[action.Path] = ResolvedTheirs
+			}
+			return
+		}
+		if err := ws.applyAction(action); err != nil {
+			result.Failed = append(result.Failed, action.Path)
+		} else {
+			result.Applied = append(result.Applied, action.Path)
+			result.Resolutions[action.Path] = ResolvedTheirs
+		}
+
+	case ConflictModeOurs:
+		// Keep current version — no-op
+		result.Applied = append(result.Applied, action.Path)
+		result.Resolutions[action.Path] = ResolvedOurs
+
+	case ConflictModeManual:
+		if err := ws.writeConflictMarkers(action); err != nil {
+			result.Failed = append(result.Failed, action.Path)
+		} else {
+			result.Conflicts = append(result.Conflicts, action.Path)
+			result.Resolutions[action.Path] = ResolvedManual
+		}
+
+	case ConflictModeSkip:
+		result.Conflicts = append(result.Conflicts, action.Path)
+		result.Resolutions[action.Path] = ResolvedSkipped
+	}
+}
+
+// RecordMerge records sourceSnapshotID as merged into the workspace without
+// applying any changes. It snapshots the working tree as-is with the current
+// and source snapshots as parents, so later merges use the source snapshot
+// as their merge base.
+func (ws *Workspace) RecordMerge(sourceSnapshotID, message string) (*SnapshotResult, error) {
+	if ws.cfg.CurrentSnapshotID == "" {
+		return nil, fmt.Errorf("current workspace has no snapshots")
+	}
+	if sourceSnapshotID == "" {
+		return nil, fmt.Errorf("source snapshot is empty")
+	}
+	return ws.Snapshot(SnapshotOpts{
+		Message:   message,
+		ParentIDs: []string{ws.cfg.CurrentSnapshotID, sourceSnapshotID},
+	})
+}
+
 // MergeAbort clears pending merge state.
 func (ws *Workspace) MergeAbort() error {
 	return config.ClearPendingMergeParentsAt(ws.root)
@@ -216,7 +309,81 @@ func (ws *Workspace) applyAction(action store.MergeAction) error {
 	}
 
 	mode := fileModeOrDefault(action.SourceMode, 0644)
-	return os.WriteFile(targetPath, content, mode)
+	return WriteFileMode(targetPath, content, mode)
+}
+
+// deleteAction removes a file the source deleted from the working tree.
+func (ws *Workspace) deleteAction(action store.MergeAction) error {
+	err := os.Remove(filepath.Join(ws.root, action.Path))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// resolveInChunks runs opts.BatchResolver over the conflicts not covered by
+// opts.PathModes, in chunks bounded by opts.ChunkSize files and
+// opts.ChunkBytes input bytes, and writes the merged files. It returns the
+// paths that were resolved; a chunk whose call fails is left for per-file
+// resolution.
+func (ws *Workspace) resolveInChunks(conflicts []store.MergeAction, opts ApplyMergeOpts) map[string]bool {
+	resolved := make(map[string]bool)
+	if opts.BatchResolver == nil || opts.ChunkSize < 2 {
+		return resolved
+	}
+
+	var pending []store.MergeAction
+	for _, action := range conflicts {
+		if _, ok := opts.PathModes[action.Path]; !ok && !action.IsDeleteConflict() {
+			pending = append(pending, action)
+		}
+	}
+
+	var chunk []store.MergeAction
+	var inputs []ConflictInput
+	size := 0
+	flush := func() {
+		merged, err := opts.BatchResolver(inputs)
+		if err == nil {
+			for _, action := range chunk {
+				content, ok := merged[action.Path]
+				if !ok {
+					continue
+				}
+				targetPath := filepath.Join(ws.root, action.Path)
+				if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+					continue
+				}
+				if err := WriteFileMode(targetPath, content, ConflictFileMode(targetPath, action.SourceMode)); err != nil {
+					continue
+				}
+				resolved[action.Path] = true
+			}
+		}
+		chunk, inputs, size = nil, nil, 0
+	}
+	for _, action := range pending {
+		input := ConflictInput{
+			Path:    action.Path,
+			Current: readBlobOrEmpty(ws.store, action.CurrentHash),
+			Source:  readBlobOrEmpty(ws.store, action.SourceHash),
+			Base:    readBlobOrEmpty(ws.store, action.BaseHash),
+		}
+		n := len(input.Base) + len(input.Current) + len(input.Source)
+		if opts.ChunkBytes > 0 && len(inputs) > 0 && size+n > opts.ChunkBytes {
+			flush()
+		}
+		chunk = append(chunk, action)
+		inputs = append(inputs, input)
+		size += n
+		if len(inputs) == opts.ChunkSize {
+			flush()
+		}
+	}
+	if len(inputs) > 0 {
+		flush()
+	}
+	return resolved
 }
 
 // resolveWithCallback calls the conflict resolver and writes the result.
@@ -235,8 +402,7 @@ func (ws *Workspace) resolveWithCallback(action store.MergeAction, resolver Conf
 		return err
 	}
 
-	mode := fileModeOrDefault(action.SourceMode, 0644)
-	return os.WriteFile(targetPath, merged, mode)
+	return WriteFileMode(targetPath, merged, ConflictFileMode(targetPath, action.SourceMode))
 }
 
 // writeConflictMarkers writes a file with <<<<<<< / ======= / >>>>>>> markers.
@@ -269,7 +435,7 @@ func (ws *Workspace) writeConflictMarkers(action store.MergeAction) error {
 	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
 		return err
 	}
-	return os.WriteFile(targetPath, []byte(b.String()), 0644)
+	return WriteFileMode(targetPath, []byte(b.String()), ConflictFileMode(targetPath, action.SourceMode))
 }
 
 func readBlobOrEmpty(s *store.Store, hash string) []byte {
@@ -289,3 +455,22 @@ func fileModeOrDefault(mode uint32, fallback os.FileMode) os.FileMode {
 	}
 	return os.FileMode(mode)
 }
+
+// ConflictFileMode returns the mode for a file whose content combines both
+// sides of a merge: the working tree file's mode if it exists, otherwise
+// the source's.
+func ConflictFileMode(targetPath string, sourceMode uint32) os.FileMode {
+	if info, err := os.Stat(targetPath); err == nil {
+		return info.Mode().Perm()
+	}
+	return fileModeOrDefault(sourceMode, 0644)
+}
+
+// WriteFileMode writes data to path and sets its mode. Unlike os.WriteFile,
+// the mode is also applied when the file already exists.
+func WriteFileMode(path string, data []byte, mode os.FileMode) error {
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return err
+	}
+	return os.Chmod(path, mode)
+}
@@ -59,6 +59,13 @@ func setupMergeTest(t *testing.T, baseFiles, currentChanges, sourceChanges map[s
 
 func seedSourceSnapshot(t *testing.T, s *store.Store, parentIDs []string, files map[string]string) string {
 	t.Helper()
+	return seedSourceSnapshotModes(t, s, parentIDs, files, nil)
+}
+
+// seedSourceSnapshotModes is like seedSourceSnapshot but records the given
+// file modes (0644 for paths not in modes).
+func seedSourceSnapshotModes(t *testing.T, s *store.Store, parentIDs []string, files map[string]string, modes map[string]uint32) string {
+	t.Helper()
 
 	// Write blobs
 	type entry struct {
@@ -80,7 +87,11 @@ func seedSourceSnapshot(t *testing.T, s *store.Store, parentIDs []string, files
 		if i > 0 {
 			b.WriteString(",")
 		}
-		fmt.Fprintf(&b, `{"type":"file","path":%q,"hash":"%s","size":%d,"mode":420}`, e.path, e.hash, e.size)
+		mode, ok := modes[e.path]
+		if !ok {
+			mode = 0644
+		}
+		fmt.Fprintf(&b, `{"type":"file","path":%q,"hash":"%s","size":%d,"mode":%d}`, e.path, e.hash, e.size, mode)
 	}
 	b.WriteString(`],"symlinks":[]}`)
 	manifestJSON := []byte(b.String())
@@ -146,6 +157,37 @@ func TestApplyMerge_Apply(t *testing.T) {
 	}
 }
 
+func TestApplyMerge_Progress(t *testing.T) {
+	ws, sourceID := setupMergeTest(t,
+		map[string]string{"base.txt": "base"},
+		map[string]string{},
+		map[string]string{"a.txt": "a", "b.txt": "b", "c.txt": "c"},
+	)
+
+	plan, err := ws.store.PlanMerge(ws.CurrentSnapshotID(), sourceID, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+
+	var calls [][2]int
+	if _, err := ws.ApplyMerge(ApplyMergeOpts{
+		Plan:     plan,
+		Mode:     ConflictModeManual,
+		Progress: func(done, total int) { calls = append(calls, [2]int{done, total}) },
+	}); err != nil {
+		t.Fatalf("ApplyMerge: %v", err)
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 progress calls, got %v", calls)
+	}
+	for i, c := range calls {
+		if c[0] != i+1 || c[1] != 3 {
+			t.Fatalf("unexpected progress sequence: %v", calls)
+		}
+	}
+}
+
 func TestApplyMerge_ConflictTheirs(t *testing.T) {
 	ws, sourceID := setupMergeTest(t,
 		map[string]string{"shared.txt": "original"},
@@ -251,6 +293,208 @@ func TestApplyMerge_ConflictManual(t *testing.T) {
 	}
 }
 
+func TestApplyMerge_ConflictSkip(t *testing.T) {
+	ws, sourceID := setupMergeTest(t,
+		map[string]string{"shared.txt": "original"},
+		map[string]string{"shared.txt": "current-version"},
+		map[string]string{"shared.txt": "source-version"},
+	)
+
+	plan, err := ws.store.PlanMerge(ws.CurrentSnapshotID(), sourceID, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+
+	result, err := ws.ApplyMerge(ApplyMergeOpts{
+		Plan:      plan,
+		Mode:      ConflictModeTheirs,
+		PathModes: map[string]ConflictMode{"shared.txt": ConflictModeSkip},
+	})
+	if err != nil {
+		t.Fatalf("ApplyMerge: %v", err)
+	}
+
+	if len(result.Conflicts) != 1 || result.Resolutions["shared.txt"] != ResolvedSkipped {
+		t.Fatalf("expected shared.txt to be left unresolved, got %+v", result)
+	}
+	content, err := os.ReadFile(filepath.Join(ws.Root(), "shared.txt"))
+	if err != nil {
+		t.Fatalf("read shared.txt: %v", err)
+	}
+	if string(content) != "current-version" {
+		t.Fatalf("expected the skipped file to be untouched, got %q", string(content))
+	}
+}
+
+func TestApplyMerge_ConflictKeepsExecutableBit(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{"run.sh": "#!/bin/sh\necho base\n"})
+	script := filepath.Join(root, "run.sh")
+	if err := os.Chmod(script, 0755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	author := &config.Author{Name: "Test", Email: "t@t"}
+	base, err := ws.Snapshot(SnapshotOpts{Message: "base", Author: author})
+	if err != nil {
+		t.Fatalf("base snapshot: %v", err)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho current version\n"), 0755); err != nil {
+		t.Fatalf("write run.sh: %v", err)
+	}
+	if _, err := ws.Snapshot(SnapshotOpts{Message: "current", Author: author}); err != nil {
+		t.Fatalf("current snapshot: %v", err)
+	}
+	// The source side doesn't carry the executable bit; the current
+	// file's mode must win for content merged from both sides.
+	sourceID := seedSourceSnapshot(t, ws.store, []string{base.SnapshotID},
+		map[string]string{"run.sh": "#!/bin/sh\necho source\n"})
+
+	for _, tc := range []struct {
+		name     string
+		mode     ConflictMode
+		resolver ConflictResolver
+	}{
+		{name: "manual", mode: ConflictModeManual},
+		{name: "resolver", resolver: func(path string, current, source, base []byte) ([]byte, error) {
+			return []byte("#!/bin/sh\necho resolved\n"), nil
+		}},
+	} {
+		plan, err := ws.store.PlanMerge(ws.CurrentSnapshotID(), sourceID, false)
+		if err != nil {
+			t.Fatalf("%s: PlanMerge: %v", tc.name, err)
+		}
+		if len(plan.Conflicts) != 1 {
+			t.Fatalf("%s: expected 1 conflict, got %d", tc.name, len(plan.Conflicts))
+		}
+		if _, err := ws.ApplyMerge(ApplyMergeOpts{Plan: plan, Mode: tc.mode, Resolver: tc.resolver}); err != nil {
+			t.Fatalf("%s: ApplyMerge: %v", tc.name, err)
+		}
+		info, err := os.Stat(script)
+		if err != nil {
+			t.Fatalf("%s: stat run.sh: %v", tc.name, err)
+		}
+		if info.Mode().Perm() != 0755 {
+			t.Fatalf("%s: expected run.sh to stay 0755, got %o", tc.name, info.Mode().Perm())
+		}
+		// Reset the working tree for the next case.
+		if err := os.WriteFile(script, []byte("#!/bin/sh\necho current version\n"), 0755); err != nil {
+			t.Fatalf("reset run.sh: %v", err)
+		}
+		_ = config.ClearPendingMergeParentsAt(root)
+	}
+}
+
+func TestApplyMerge_ConflictMarkersUseSourceModeWhenDeleted(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{"run.sh": "#!/bin/sh\necho base\n", "keep.txt": "keep"})
+	author := &config.Author{Name: "Test", Email: "t@t"}
+	base, err := ws.Snapshot(SnapshotOpts{Message: "base", Author: author})
+	if err != nil {
+		t.Fatalf("base snapshot: %v", err)
+	}
+	if err := os.Remove(filepath.Join(root, "run.sh")); err != nil {
+		t.Fatalf("remove run.sh: %v", err)
+	}
+	if _, err := ws.Snapshot(SnapshotOpts{Message: "delete", Author: author}); err != nil {
+		t.Fatalf("current snapshot: %v", err)
+	}
+	sourceID := seedSourceSnapshotModes(t, ws.store, []string{base.SnapshotID},
+		map[string]string{"run.sh": "#!/bin/sh\necho source\n", "keep.txt": "keep"},
+		map[string]uint32{"run.sh": 0755})
+
+	plan, err := ws.store.PlanMerge(ws.CurrentSnapshotID(), sourceID, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+	if _, err := ws.ApplyMerge(ApplyMergeOpts{Plan: plan, Mode: ConflictModeManual}); err != nil {
+		t.Fatalf("ApplyMerge: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(root, "run.sh"))
+	if err != nil {
+		t.Fatalf("stat run.sh: %v", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Fatalf("expected conflicted run.sh to take the source mode 0755, got %o", info.Mode().Perm())
+	}
+}
+
+func TestApplyMerge_DeleteModifyConflicts(t *testing.T) {
+	for _, tc := range []struct {
+		mode       ConflictMode
+		wantExists bool
+	}{
+		{ConflictModeTheirs, false},
+		{ConflictModeOurs, true},
+	} {
+		root, ws := setupTestWorkspace(t, map[string]string{"gone.txt": "original", "keep.txt": "keep"})
+		author := &config.Author{Name: "Test", Email: "t@t"}
+		base, err := ws.Snapshot(SnapshotOpts{Message: "base", Author: author})
+		if err != nil {
+			t.Fatalf("base snapshot: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(root, "gone.txt"), []byte("modified here"), 0644); err != nil {
+			t.Fatalf("write gone.txt: %v", err)
+		}
+		if _, err := ws.Snapshot(SnapshotOpts{Message: "modify", Author: author}); err != nil {
+			t.Fatalf("current snapshot: %v", err)
+		}
+		sourceID := seedSourceSnapshot(t, ws.store, []string{base.SnapshotID}, map[string]string{"keep.txt": "keep"})
+
+		plan, err := ws.store.PlanMerge(ws.CurrentSnapshotID(), sourceID, false)
+		if err != nil {
+			t.Fatalf("PlanMerge: %v", err)
+		}
+		resolver := func(path string, current, source, base []byte) ([]byte, error) {
+			t.Fatalf("resolver called for delete/modify conflict %s", path)
+			return nil, nil
+		}
+		result, err := ws.ApplyMerge(ApplyMergeOpts{Plan: plan, Mode: tc.mode, Resolver: resolver})
+		if err != nil {
+			t.Fatalf("ApplyMerge: %v", err)
+		}
+		_, statErr := os.Stat(filepath.Join(root, "gone.txt"))
+		if exists := statErr == nil; exists != tc.wantExists {
+			t.Fatalf("mode %d: expected gone.txt exists=%v, got %v", tc.mode, tc.wantExists, exists)
+		}
+		if !tc.wantExists && (len(result.Deleted) != 1 || result.Deleted[0] != "gone.txt") {
+			t.Fatalf("expected gone.txt reported deleted, got %+v", result)
+		}
+	}
+}
+
+func TestApplyMerge_PreserveDeletes(t *testing.T) {
+	ws, sourceID := setupMergeTest(t,
+		map[string]string{"clean.txt": "original", "modified.txt": "original", "keep.txt": "keep"},
+		map[string]string{"modified.txt": "current-version"},
+		nil,
+	)
+	// The source deletes both files
+	base, err := ws.store.LoadSnapshotMeta(sourceID)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	sourceID = seedSourceSnapshot(t, ws.store, base.ParentSnapshotIDs, map[string]string{"keep.txt": "keep"})
+
+	plan, err := ws.store.PlanMergeWithOpts(ws.CurrentSnapshotID(), sourceID, store.MergeOpts{PreserveDeletes: true})
+	if err != nil {
+		t.Fatalf("PlanMergeWithOpts: %v", err)
+	}
+	result, err := ws.ApplyMerge(ApplyMergeOpts{Plan: plan, Mode: ConflictModeOurs})
+	if err != nil {
+		t.Fatalf("ApplyMerge: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(ws.root, "clean.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected clean.txt to be deleted, stat err: %v", err)
+	}
+	deleted := strings.Join(result.Deleted, ",")
+	if !strings.Contains(deleted, "clean.txt") || strings.Contains(deleted, "modified.txt") {
+		t.Fatalf("expected only unmodified files reported deleted, got %+v", result)
+	}
+	data, err := os.ReadFile(filepath.Join(ws.root, "modified.txt"))
+	if err != nil || string(data) != "current-version" {
+		t.Fatalf("expected modified.txt to keep the current version, got %q (%v)", data, err)
+	}
+}
+
 func TestApplyMerge_Resolver(t *testing.T) {
 	ws, sourceID := setupMergeTest(t,
 		map[string]string{"shared.txt": "original"},
@@ -322,6 +566,112 @@ func TestApplyMerge_ResolverFallback(t *testing.T) {
 	}
 }
 
+func TestApplyMerge_BatchResolver(t *testing.T) {
+	ws, sourceID := setupMergeTest(t,
+		map[string]string{"a.txt": "original", "b.txt": "original", "c.txt": "original"},
+		map[string]string{"a.txt": "current", "b.txt": "current", "c.txt": "current"},
+		map[string]string{"a.txt": "source", "b.txt": "source", "c.txt": "source"},
+	)
+
+	plan, err := ws.store.PlanMerge(ws.CurrentSnapshotID(), sourceID, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+
+	// The batch resolver handles the first chunk and fails on the second,
+	// which falls back to the per-file resolver.
+	var chunks [][]string
+	batch := func(conflicts []ConflictInput) (map[string][]byte, error) {
+		var paths []string
+		merged := make(map[string][]byte)
+		for _, c := range conflicts {
+			paths = append(paths, c.Path)
+			merged[c.Path] = []byte("batch:" + string(c.Current) + "+" + string(c.Source))
+		}
+		chunks = append(chunks, paths)
+		if len(chunks) > 1 {
+			return nil, fmt.Errorf("chunk failed")
+		}
+		return merged, nil
+	}
+	resolver := func(path string, current, source, base []byte) ([]byte, error) {
+		return []byte("single:" + string(current) + "+" + string(source)), nil
+	}
+
+	result, err := ws.ApplyMerge(ApplyMergeOpts{
+		Plan:          plan,
+		Mode:          ConflictModeManual,
+		Resolver:      resolver,
+		BatchResolver: batch,
+		ChunkSize:     2,
+	})
+	if err != nil {
+		t.Fatalf("ApplyMerge: %v", err)
+	}
+	if len(result.Applied) != 3 || len(result.Conflicts) != 0 {
+		t.Fatalf("expected 3 applied, got %v (conflicts: %v)", result.Applied, result.Conflicts)
+	}
+	if len(chunks) != 2 || len(chunks[0]) != 2 || len(chunks[1]) != 1 {
+		t.Fatalf("expected chunks of 2 and 1, got %v", chunks)
+	}
+
+	want := map[string]string{
+		"a.txt": "batch:current+source",
+		"b.txt": "batch:current+source",
+		"c.txt": "single:current+source",
+	}
+	for name, expected := range want {
+		content, err := os.ReadFile(filepath.Join(ws.Root(), name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if string(content) != expected {
+			t.Fatalf("%s: expected %q, got %q", name, expected, string(content))
+		}
+	}
+}
+
+func TestApplyMerge_BatchResolverByteBudget(t *testing.T) {
+	large := strings.Repeat("x", 100)
+	ws, sourceID := setupMergeTest(t,
+		map[string]string{"a.txt": large, "b.txt": "original", "c.txt": "original"},
+		map[string]string{"a.txt": large + "current", "b.txt": "current", "c.txt": "current"},
+		map[string]string{"a.txt": large + "source", "b.txt": "source", "c.txt": "source"},
+	)
+
+	plan, err := ws.store.PlanMerge(ws.CurrentSnapshotID(), sourceID, false)
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+
+	// a.txt alone exceeds the budget and is sent by itself; b.txt and
+	// c.txt fit in one chunk together.
+	var chunks [][]string
+	batch := func(conflicts []ConflictInput) (map[string][]byte, error) {
+		var paths []string
+		merged := make(map[string][]byte)
+		for _, c := range conflicts {
+			paths = append(paths, c.Path)
+			merged[c.Path] = c.Current
+		}
+		chunks = append(chunks, paths)
+		return merged, nil
+	}
+
+	if _, err := ws.ApplyMerge(ApplyMergeOpts{
+		Plan:          plan,
+		Mode:          ConflictModeManual,
+		BatchResolver: batch,
+		ChunkSize:     3,
+		ChunkBytes:    50,
+	}); err != nil {
+		t.Fatalf("ApplyMerge: %v", err)
+	}
+	if len(chunks) != 2 || len(chunks[0]) != 1 || chunks[0][0] != "a.txt" || len(chunks[1]) != 2 {
+		t.Fatalf("expected chunks [a.txt] and [b.txt c.txt], got %v", chunks)
+	}
+}
+
 func TestApplyMerge_DirtyOverlapAborts(t *testing.T) {
 	ws, sourceID := setupMergeTest(t,
 		map[string]string{"file.txt": "original"},
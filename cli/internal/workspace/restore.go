@@ -3,6 +3,7 @@ package workspace
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -16,6 +17,14 @@ type RestoreOpts struct {
 	ToBase     bool     // use base snapshot
 	Files      []string // specific files/dirs; empty = all
 	DryRun     bool
+	// DeleteMissing deletes the working copy of requested Files that do not
+	// exist in the target snapshot. Without it they are only reported in
+	// RestoreResult.NotInSnapshot.
+	DeleteMissing bool
+	// PruneEmptyDirs removes directories left empty by the deletions,
+	// deepest first. Directories recorded in the target snapshot are kept.
+	// It is implied by DeleteMissing.
+	PruneEmptyDirs bool
 }
 
 // RestoreAction describes a single file-level action.
@@ -33,6 +42,7 @@ type RestoreResult struct {
 	Deleted          int
 	Skipped          int
 	MissingBlobs     []string
+	NotInSnapshot    []string // requested paths absent from the target but present on disk
 }
 
 // Restore restores files from a target snapshot.
@@ -60,6 +70,7 @@ func (ws *Workspace) Restore(opts RestoreOpts) (*RestoreResult, error) {
 	all := len(opts.Files) == 0
 	var toRestore []manifest.FileEntry
 	var toDelete []string
+	var notInSnapshot []string
 
 	if all {
 		toRestore = targetManifest.Files
@@ -75,13 +86,41 @@ func (ws *Workspace) Restore(opts RestoreOpts) (*RestoreResult, error) {
 			}
 		}
 	} else {
-		for _, pattern := range opts.Files {
-			pattern = filepath.ToSlash(pattern)
-			pattern = strings.TrimSuffix(pattern, "/")
+		// Deletion candidates come from the ignore-aware scan, so ignored
+		// files under a requested directory are never deleted.
+		var working []manifest.FileEntry
+		for _, raw := range opts.Files {
+			pattern, err := cleanRestorePattern(raw)
+			if err != nil {
+				return nil, err
+			}
 
+			matched := false
 			for _, f := range targetManifest.Files {
-				if f.Path == pattern || strings.HasPrefix(f.Path, pattern+"/") {
+				if underRestorePattern(f.Path, pattern) {
 					toRestore = append(toRestore, f)
+					matched = true
+				}
+			}
+			if matched {
+				continue
+			}
+			if _, err := os.Lstat(filepath.Join(ws.root, filepath.FromSlash(pattern))); err != nil {
+				continue
+			}
+			notInSnapshot = append(notInSnapshot, pattern)
+			if opts.DeleteMissing {
+				if working == nil {
+					currentManifest, err := manifest.GenerateWithCache(ws.root, ws.StatCachePath())
+					if err != nil {
+						return nil, fmt.Errorf("failed to scan current files: %w", err)
+					}
+					working = append(currentManifest.FileEntries(), currentManifest.SymlinkEntries()...)
+				}
+				for _, f := range working {
+					if underRestorePattern(f.Path, pattern) {
+						toDelete = append(toDelete, f.Path)
+					}
 				}
 			}
 		}
@@ -144,6 +183,7 @@ func (ws *Workspace) Restore(opts RestoreOpts) (*RestoreResult, error) {
 	result := &RestoreResult{
 		TargetSnapshotID: targetID,
 		Actions:          actions,
+		NotInSnapshot:    notInSnapshot,
 	}
 
 	if opts.DryRun {
@@ -188,30 +228,48 @@ func (ws *Workspace) Restore(opts RestoreOpts) (*RestoreResult, error) {
 				result.Skipped++
 				continue
 			}
+			_ = manifest.RestoreModTime(targetPath, f)
 			result.Restored++
 		}
 	}
 
+	var deleted []string
 	for _, f := range toDelete {
-		targetPath := filepath.Join(ws.root, f)
-		if err := os.Remove(targetPath); err != nil {
+		if err := os.Remove(filepath.Join(ws.root, f)); err != nil {
 			continue
 		}
+		deleted = append(deleted, f)
 		result.Deleted++
-
-		// Try to remove empty parent directories
-		dir := filepath.Dir(targetPath)
-		for dir != ws.root {
-			if err := os.Remove(dir); err != nil {
-				break
-			}
-			dir = filepath.Dir(dir)
-		}
+	}
+	// Deleting requested paths missing from the snapshot deletes their
+	// directories too.
+	if opts.PruneEmptyDirs || opts.DeleteMissing {
+		targetManifest.PruneEmptyDirs(ws.root, deleted)
 	}
 
 	return result, nil
 }
 
+// cleanRestorePattern normalizes a requested restore path to a clean,
+// slash-separated path relative to the workspace root. Paths outside the
+// root or inside .fst are rejected.
+func cleanRestorePattern(pattern string) (string, error) {
+	p := path.Clean(filepath.ToSlash(pattern))
+	if path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("cannot restore %s: path is outside the workspace", pattern)
+	}
+	if p == ".fst" || strings.HasPrefix(p, ".fst/") {
+		return "", fmt.Errorf("cannot restore %s: path is inside .fst", pattern)
+	}
+	return p, nil
+}
+
+// underRestorePattern reports whether p is pattern or lies below it. The
+// pattern "." covers the whole workspace.
+func underRestorePattern(p, pattern string) bool {
+	return pattern == "." || p == pattern || strings.HasPrefix(p, pattern+"/")
+}
+
 func (ws *Workspace) resolveRestoreTarget(opts RestoreOpts) (string, error) {
 	if opts.SnapshotID != "" {
 		return opts.SnapshotID, nil
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ankitiscracked/fastest/cli/internal/config"
 )
@@ -42,6 +43,58 @@ func TestRestoreFiles(t *testing.T) {
 	}
 }
 
+func TestRestoreKeepsModTime(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{
+		"kept.txt":  "original",
+		"fresh.txt": "original",
+	})
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, name := range []string{"kept.txt", "fresh.txt"} {
+		if err := os.Chtimes(filepath.Join(root, name), old, old); err != nil {
+			t.Fatalf("Chtimes: %v", err)
+		}
+	}
+	author := &config.Author{Name: "T", Email: "t@t"}
+
+	plain, err := ws.Snapshot(SnapshotOpts{Message: "plain", Author: author})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	kept, err := ws.Snapshot(SnapshotOpts{Message: "kept", Author: author, KeepModTime: true})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if kept.ManifestHash == plain.ManifestHash {
+		t.Fatalf("recording mtimes should change the manifest hash")
+	}
+
+	for _, name := range []string{"kept.txt", "fresh.txt"} {
+		os.WriteFile(filepath.Join(root, name), []byte("modified"), 0644)
+	}
+
+	if _, err := ws.Restore(RestoreOpts{SnapshotID: kept.SnapshotID, Files: []string{"kept.txt"}}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if _, err := ws.Restore(RestoreOpts{SnapshotID: plain.SnapshotID, Files: []string{"fresh.txt"}}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(root, "kept.txt"))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if !info.ModTime().Equal(old) {
+		t.Fatalf("expected kept.txt mtime %v, got %v", old, info.ModTime())
+	}
+	info, err = os.Stat(filepath.Join(root, "fresh.txt"))
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.ModTime().Equal(old) {
+		t.Fatalf("fresh.txt should get a new mtime without --keep-mtime")
+	}
+}
+
 func TestRestoreDryRun(t *testing.T) {
 	root, ws := setupTestWorkspace(t, map[string]string{
 		"file.txt": "original",
@@ -108,6 +161,48 @@ func TestRestoreDeletesExtraFiles(t *testing.T) {
 	}
 }
 
+func TestRestorePruneEmptyDirs(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{
+		"file.txt": "original",
+	})
+	os.MkdirAll(filepath.Join(root, "tracked-empty"), 0755)
+
+	r, err := ws.Snapshot(SnapshotOpts{
+		Message: "v1",
+		Author:  &config.Author{Name: "T", Email: "t@t"},
+	})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	addExtra := func() {
+		for _, rel := range []string{"gen/deep/x.txt", "gen/y.txt", "tracked-empty/z.txt"} {
+			p := filepath.Join(root, filepath.FromSlash(rel))
+			os.MkdirAll(filepath.Dir(p), 0755)
+			os.WriteFile(p, []byte(rel), 0644)
+		}
+	}
+
+	addExtra()
+	if _, err := ws.Restore(RestoreOpts{SnapshotID: r.SnapshotID}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "gen", "deep")); err != nil {
+		t.Fatalf("expected empty directories to be kept by default: %v", err)
+	}
+
+	addExtra()
+	if _, err := ws.Restore(RestoreOpts{SnapshotID: r.SnapshotID, PruneEmptyDirs: true}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "gen")); !os.IsNotExist(err) {
+		t.Fatalf("expected empty dir skeleton 'gen' to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(root, "tracked-empty")); err != nil {
+		t.Fatalf("expected dir recorded in the snapshot to be kept: %v", err)
+	}
+}
+
 func TestRestoreSpecificFiles(t *testing.T) {
 	root, ws := setupTestWorkspace(t, map[string]string{
 		"a.txt": "a-content",
@@ -191,3 +286,145 @@ func TestRestoreToBase(t *testing.T) {
 		t.Fatalf("expected 'base-content', got %q", string(content))
 	}
 }
+
+func TestRestoreFilesNotInSnapshot(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{
+		"a.txt": "a-content",
+	})
+
+	r, err := ws.Snapshot(SnapshotOpts{
+		Message: "v1",
+		Author:  &config.Author{Name: "T", Email: "t@t"},
+	})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	os.MkdirAll(filepath.Join(root, "gen", "sub"), 0755)
+	os.WriteFile(filepath.Join(root, "gen", "sub", "x.txt"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(root, "new.txt"), []byte("new"), 0644)
+
+	result, err := ws.Restore(RestoreOpts{
+		SnapshotID: r.SnapshotID,
+		Files:      []string{"a.txt", "gen", "new.txt", "absent.txt"},
+	})
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(result.NotInSnapshot) != 2 || result.NotInSnapshot[0] != "gen" || result.NotInSnapshot[1] != "new.txt" {
+		t.Fatalf("expected [gen new.txt] not in snapshot, got %v", result.NotInSnapshot)
+	}
+	if _, err := os.Stat(filepath.Join(root, "new.txt")); err != nil {
+		t.Fatalf("expected new.txt to be kept without DeleteMissing")
+	}
+
+	result, err = ws.Restore(RestoreOpts{
+		SnapshotID:    r.SnapshotID,
+		Files:         result.NotInSnapshot,
+		DeleteMissing: true,
+	})
+	if err != nil {
+		t.Fatalf("Restore with DeleteMissing: %v", err)
+	}
+	if result.Deleted != 2 {
+		t.Fatalf("expected 2 deleted, got %d", result.Deleted)
+	}
+	for _, p := range []string{"new.txt", "gen"} {
+		if _, err := os.Stat(filepath.Join(root, p)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed", p)
+		}
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "a.txt")); string(content) != "a-content" {
+		t.Fatalf("a.txt should be untouched")
+	}
+}
+
+func TestRestoreRejectsPathsOutsideWorkspace(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{
+		"a.txt": "a-content",
+	})
+
+	r, err := ws.Snapshot(SnapshotOpts{
+		Message: "v1",
+		Author:  &config.Author{Name: "T", Email: "t@t"},
+	})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	outside := filepath.Join(filepath.Dir(root), "outside.txt")
+	if err := os.WriteFile(outside, []byte("keep"), 0644); err != nil {
+		t.Fatalf("write outside: %v", err)
+	}
+
+	for _, p := range []string{"../outside.txt", ".fst", ".fst/config.json", "sub/../../outside.txt"} {
+		if _, err := ws.Restore(RestoreOpts{
+			SnapshotID:    r.SnapshotID,
+			Files:         []string{p},
+			DeleteMissing: true,
+		}); err == nil {
+			t.Fatalf("expected restore of %s to be rejected", p)
+		}
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Fatalf("expected file outside the workspace to be kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".fst", "config.json")); err != nil {
+		t.Fatalf("expected .fst to be kept: %v", err)
+	}
+}
+
+func TestRestoreDotKeepsMetadataAndIgnoredFiles(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{
+		"a.txt":      "a-content",
+		".fstignore": "*.log\n",
+	})
+
+	r, err := ws.Snapshot(SnapshotOpts{
+		Message: "v1",
+		Author:  &config.Author{Name: "T", Email: "t@t"},
+	})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(root, "a.txt"), []byte("changed"), 0644)
+	os.WriteFile(filepath.Join(root, "build.log"), []byte("log"), 0644)
+	os.MkdirAll(filepath.Join(root, "gen"), 0755)
+	os.WriteFile(filepath.Join(root, "gen", "out.txt"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(root, "gen", "trace.log"), []byte("log"), 0644)
+
+	if _, err := ws.Restore(RestoreOpts{
+		SnapshotID:    r.SnapshotID,
+		Files:         []string{"."},
+		DeleteMissing: true,
+	}); err != nil {
+		t.Fatalf("Restore .: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "a.txt")); string(content) != "a-content" {
+		t.Fatalf("expected a.txt restored, got %q", string(content))
+	}
+	if _, err := os.Stat(filepath.Join(root, ".fst", "config.json")); err != nil {
+		t.Fatalf("expected .fst to be kept: %v", err)
+	}
+
+	result, err := ws.Restore(RestoreOpts{
+		SnapshotID:    r.SnapshotID,
+		Files:         []string{"gen/"},
+		DeleteMissing: true,
+	})
+	if err != nil {
+		t.Fatalf("Restore gen: %v", err)
+	}
+	if result.Deleted != 1 {
+		t.Fatalf("expected 1 deleted, got %d", result.Deleted)
+	}
+	if _, err := os.Stat(filepath.Join(root, "gen", "out.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected gen/out.txt to be removed")
+	}
+	for _, p := range []string{"build.log", "gen/trace.log"} {
+		if _, err := os.Stat(filepath.Join(root, p)); err != nil {
+			t.Fatalf("expected ignored %s to be kept: %v", p, err)
+		}
+	}
+}
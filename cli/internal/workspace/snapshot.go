@@ -1,9 +1,13 @@
 package workspace
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/ankitiscracked/fastest/cli/internal/config"
@@ -17,7 +21,20 @@ type SnapshotResult struct {
 	ManifestHash string
 	Files        int
 	Size         int64
-	BlobsCached  int
+	BlobsCached  int                  // blobs newly written to the store
+	BlobsReused  int                  // files whose blob was already stored
+	LargeFiles   []manifest.FileEntry // files above SnapshotOpts.LargeFileThreshold
+}
+
+// LargeFilesError is returned by Snapshot when RefuseLargeFiles is set and
+// files exceed the threshold. Nothing is written to the store.
+type LargeFilesError struct {
+	Threshold int64
+	Files     []manifest.FileEntry
+}
+
+func (e *LargeFilesError) Error() string {
+	return fmt.Sprintf("%d file(s) larger than %d bytes", len(e.Files), e.Threshold)
 }
 
 // SnapshotOpts configures a Snapshot operation.
@@ -25,7 +42,37 @@ type SnapshotOpts struct {
 	Message   string
 	Agent     string // agent name, if message was generated by an agent
 	Author    *config.Author
-	ParentIDs []string // explicit parent IDs; nil = auto-resolve from config + merge parents
+	ParentIDs []string  // explicit parent IDs; nil = auto-resolve from config + merge parents
+	CreatedAt time.Time // explicit creation time (e.g. backdated imports); zero = now
+	FixupOf   string    // ID of an earlier snapshot this one amends, if any
+	// LargeFileThreshold flags files larger than this many bytes in
+	// SnapshotResult.LargeFiles; zero disables the check.
+	LargeFileThreshold int64
+	// RefuseLargeFiles fails the snapshot with a *LargeFilesError instead.
+	RefuseLargeFiles bool
+	// IgnoreModeChanges keeps the current snapshot's mode for entries whose
+	// content is unchanged, so permission-only changes aren't recorded.
+	IgnoreModeChanges bool
+	// Paths, if non-nil, limits the snapshot to changes at these paths:
+	// every other entry is kept as in the current snapshot.
+	Paths []string
+	// SkipDedupCheck writes every blob without first checking whether the
+	// store already has it, e.g. for a first import into an empty store.
+	// Existing blobs are overwritten, so every write is a real one.
+	SkipDedupCheck bool
+	// KeepModTime records each file's modification time in the manifest so
+	// restores can reapply it.
+	KeepModTime bool
+	// Attachments are files from outside the working tree recorded in the
+	// snapshot at the given paths, replacing any scanned entry there.
+	Attachments []Attachment
+}
+
+// Attachment is a file read from outside the workspace and recorded in a
+// snapshot as if it lived at Path.
+type Attachment struct {
+	Source string // path of the file to read
+	Path   string // slash-separated path inside the snapshot
 }
 
 // Snapshot captures the current workspace state as an immutable snapshot.
@@ -43,35 +90,67 @@ func (ws *Workspace) Snapshot(opts SnapshotOpts) (*SnapshotResult, error) {
 	}
 
 	// Generate manifest
-	m, err := manifest.Generate(ws.root, false)
+	m, err := manifest.Generate(ws.root, opts.KeepModTime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan files: %w", err)
 	}
 
-	// Populate stat cache
+	if opts.IgnoreModeChanges {
+		ws.keepCurrentModes(m)
+	}
+
+	// The stat cache describes the working tree, so it is built from the
+	// full scan even when only some paths are snapshotted.
 	manifest.BuildStatCacheFromManifest(ws.root, m, ws.StatCachePath())
 
+	if opts.Paths != nil {
+		m, err = ws.selectPaths(m, opts.Paths)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	attached, err := attachFiles(m, opts.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	largeFiles := filesLargerThan(m, opts.LargeFileThreshold)
+	if opts.RefuseLargeFiles && len(largeFiles) > 0 {
+		return nil, &LargeFilesError{Threshold: opts.LargeFileThreshold, Files: largeFiles}
+	}
+
 	manifestHash, err := m.Hash()
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute manifest hash: %w", err)
 	}
 
 	// Cache blobs
-	blobsCached := 0
+	blobsCached, blobsReused := 0, 0
 	if err := ws.store.EnsureDirs(); err != nil {
 		return nil, fmt.Errorf("failed to ensure store directories: %w", err)
 	}
+	written := make(map[string]bool)
 	for _, f := range m.FileEntries() {
-		if ws.store.BlobExists(f.Hash) {
+		if written[f.Hash] || (!opts.SkipDedupCheck && ws.store.BlobExists(f.Hash)) {
+			blobsReused++
 			continue
 		}
-		content, err := os.ReadFile(filepath.Join(ws.root, f.Path))
-		if err != nil {
-			return nil, fmt.Errorf("failed to read file for blob cache %s: %w", f.Path, err)
+		content, ok := attached[f.Hash]
+		if !ok {
+			content, err = os.ReadFile(filepath.Join(ws.root, f.Path))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read file for blob cache %s: %w", f.Path, err)
+			}
 		}
-		if err := ws.store.WriteBlob(f.Hash, content); err != nil {
+		write := ws.store.WriteBlob
+		if opts.SkipDedupCheck {
+			write = ws.store.OverwriteBlob
+		}
+		if err := write(f.Hash, content); err != nil {
 			return nil, fmt.Errorf("failed to cache blob for %s: %w", f.Path, err)
 		}
+		written[f.Hash] = true
 		blobsCached++
 	}
 
@@ -89,7 +168,11 @@ func (ws *Workspace) Snapshot(opts SnapshotOpts) (*SnapshotResult, error) {
 	}
 
 	// Compute content-addressed snapshot ID
-	createdAt := time.Now().UTC().Format(time.RFC3339)
+	createdAtTime := opts.CreatedAt
+	if createdAtTime.IsZero() {
+		createdAtTime = time.Now()
+	}
+	createdAt := createdAtTime.UTC().Format(time.RFC3339)
 	snapshotID := store.ComputeSnapshotID(manifestHash, parents, author.Name, author.Email, createdAt)
 
 	// Write snapshot metadata
@@ -106,6 +189,7 @@ func (ws *Workspace) Snapshot(opts SnapshotOpts) (*SnapshotResult, error) {
 		CreatedAt:         createdAt,
 		Files:             m.FileCount(),
 		Size:              m.TotalSize(),
+		FixupOf:           opts.FixupOf,
 	}
 
 	if err := ws.store.WriteSnapshotMeta(meta); err != nil {
@@ -116,12 +200,22 @@ func (ws *Workspace) Snapshot(opts SnapshotOpts) (*SnapshotResult, error) {
 	// after writing the snapshot but before saving config, the snapshot
 	// is orphaned but harmless (GC will clean it up). Once config is
 	// saved, the workspace points to the new snapshot.
+	previousHead := ws.cfg.CurrentSnapshotID
 	ws.cfg.CurrentSnapshotID = snapshotID
 	if err := ws.SaveConfig(); err != nil {
 		return nil, fmt.Errorf("failed to update workspace config: %w", err)
 	}
 
-	// Clear pending merge parents (post-commit cleanup, non-fatal)
+	// Clear pending merge parents (post-commit cleanup, non-fatal). The
+	// snapshot completing a merge (or sync merge) is part of it, so undoing
+	// it stays possible without --force.
+	if parents, err := config.ReadPendingMergeParentsAt(ws.root); err == nil && len(parents) > 0 {
+		if entries, err := config.ReadUndoStackAt(ws.root); err == nil && len(entries) > 0 {
+			if last := entries[len(entries)-1]; (last.Operation == "merge" || last.Operation == "sync") && last.ResultSnapshotID == previousHead {
+				_ = ws.RecordUndoResult(snapshotID)
+			}
+		}
+	}
 	_ = config.ClearPendingMergeParentsAt(ws.root)
 
 	// Update project-level workspace registry (non-fatal)
@@ -133,9 +227,75 @@ func (ws *Workspace) Snapshot(opts SnapshotOpts) (*SnapshotResult, error) {
 		Files:        m.FileCount(),
 		Size:         m.TotalSize(),
 		BlobsCached:  blobsCached,
+		BlobsReused:  blobsReused,
+		LargeFiles:   largeFiles,
 	}, nil
 }
 
+// attachFiles layers attachments over m and returns their contents by hash,
+// for the blob cache.
+func attachFiles(m *manifest.Manifest, attachments []Attachment) (map[string][]byte, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+	entries := make(map[string]manifest.FileEntry, len(m.Files))
+	for _, f := range m.Files {
+		entries[f.Path] = f
+	}
+	contents := make(map[string][]byte, len(attachments))
+	for _, a := range attachments {
+		p := path.Clean(filepath.ToSlash(a.Path))
+		if p == "." || path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") || p == ".fst" || strings.HasPrefix(p, ".fst/") {
+			return nil, fmt.Errorf("invalid attachment path %q: must be a relative path inside the workspace", a.Path)
+		}
+		if e, ok := entries[p]; ok && e.Type == manifest.EntryTypeDir {
+			return nil, fmt.Errorf("cannot attach %s: %s is a directory", a.Source, p)
+		}
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			if e, ok := entries[dir]; ok && e.Type != manifest.EntryTypeDir {
+				return nil, fmt.Errorf("cannot attach %s at %s: %s is not a directory", a.Source, p, dir)
+			}
+		}
+
+		info, err := os.Stat(a.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attachment: %w", err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("cannot attach %s: not a regular file", a.Source)
+		}
+		content, err := os.ReadFile(a.Source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attachment: %w", err)
+		}
+		sum := sha256.Sum256(content)
+		f := manifest.FileEntry{
+			Type: manifest.EntryTypeFile,
+			Path: p,
+			Hash: hex.EncodeToString(sum[:]),
+			Size: int64(len(content)),
+			Mode: uint32(info.Mode().Perm()),
+		}
+		m.SetEntry(f)
+		entries[p] = f
+		contents[f.Hash] = content
+	}
+	return contents, nil
+}
+
+func filesLargerThan(m *manifest.Manifest, threshold int64) []manifest.FileEntry {
+	if threshold <= 0 {
+		return nil
+	}
+	var large []manifest.FileEntry
+	for _, f := range m.FileEntries() {
+		if f.Size > threshold {
+			large = append(large, f)
+		}
+	}
+	return large
+}
+
 // AutoSnapshot creates a snapshot silently if there are changes since the
 // current snapshot. Returns the snapshot ID, or empty string if no changes.
 // Used before destructive operations (merge, restore, pull).
@@ -148,15 +308,16 @@ func (ws *Workspace) AutoSnapshot(message string) (string, error) {
 
 	manifest.BuildStatCacheFromManifest(ws.root, m, ws.StatCachePath())
 
-	manifestHash, err := m.Hash()
-	if err != nil {
-		return "", fmt.Errorf("failed to compute manifest hash: %w", err)
-	}
-
-	// Skip if no changes since current snapshot
+	// Skip if no changes since current snapshot. A --keep-mtime head records
+	// modification times the scan lacks; those alone are not changes.
 	if ws.cfg.CurrentSnapshotID != "" {
-		if currentHash, err := ws.store.ManifestHashFromSnapshotID(ws.cfg.CurrentSnapshotID); err == nil && currentHash == manifestHash {
-			return "", nil
+		if currentHash, err := ws.store.ManifestHashFromSnapshotID(ws.cfg.CurrentSnapshotID); err == nil {
+			if current, err := ws.store.LoadManifest(currentHash); err == nil {
+				m.KeepModTimesFrom(current)
+			}
+			if manifestHash, err := m.Hash(); err == nil && manifestHash == currentHash {
+				return "", nil
+			}
 		}
 	}
 
@@ -176,6 +337,66 @@ func (ws *Workspace) AutoSnapshot(message string) (string, error) {
 	return result.SnapshotID, nil
 }
 
+// selectPaths applies manifest.Select to the current snapshot's manifest,
+// taking only paths from the scanned manifest m.
+func (ws *Workspace) selectPaths(m *manifest.Manifest, paths []string) (*manifest.Manifest, error) {
+	base := &manifest.Manifest{Version: m.Version, Files: []manifest.FileEntry{}}
+	if ws.cfg.CurrentSnapshotID != "" {
+		hash, err := ws.store.ManifestHashFromSnapshotID(ws.cfg.CurrentSnapshotID)
+		if err != nil {
+			return nil, err
+		}
+		base, err = ws.store.LoadManifest(hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load current manifest: %w", err)
+		}
+	}
+	return manifest.Select(base, m, paths), nil
+}
+
+// keepCurrentModes applies manifest.KeepModesFrom with the current
+// snapshot's manifest. It is a no-op when there is no current snapshot.
+func (ws *Workspace) keepCurrentModes(m *manifest.Manifest) {
+	if ws.cfg.CurrentSnapshotID == "" {
+		return
+	}
+	hash, err := ws.store.ManifestHashFromSnapshotID(ws.cfg.CurrentSnapshotID)
+	if err != nil {
+		return
+	}
+	if current, err := ws.store.LoadManifest(hash); err == nil {
+		m.KeepModesFrom(current)
+	}
+}
+
+// UnchangedFrom reports whether the working tree has the same manifest hash
+// as snapshotID, i.e. snapshotting now would record no file changes. With
+// ignoreMode, permission-only changes don't count. Modification times
+// recorded by --keep-mtime never count.
+func (ws *Workspace) UnchangedFrom(snapshotID string, ignoreMode bool) (bool, error) {
+	refHash, err := ws.store.ManifestHashFromSnapshotID(snapshotID)
+	if err != nil {
+		return false, err
+	}
+	m, err := manifest.GenerateWithCache(ws.root, ws.StatCachePath())
+	if err != nil {
+		return false, fmt.Errorf("failed to scan files: %w", err)
+	}
+	ref, err := ws.store.LoadManifest(refHash)
+	if err != nil {
+		return false, err
+	}
+	if ignoreMode {
+		m.KeepModesFrom(ref)
+	}
+	m.KeepModTimesFrom(ref)
+	hash, err := m.Hash()
+	if err != nil {
+		return false, fmt.Errorf("failed to compute manifest hash: %w", err)
+	}
+	return hash == refHash, nil
+}
+
 // resolveSnapshotParents determines parent snapshot IDs from pending merge
 // parents or the current snapshot.
 func (ws *Workspace) resolveSnapshotParents() []string {
@@ -4,8 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ankitiscracked/fastest/cli/internal/config"
+	"github.com/ankitiscracked/fastest/cli/internal/manifest"
 	"github.com/ankitiscracked/fastest/cli/internal/store"
 )
 
@@ -113,6 +115,156 @@ func TestSnapshotParentChain(t *testing.T) {
 	}
 }
 
+func TestSnapshotBlobDedupCounts(t *testing.T) {
+	_, ws := setupTestWorkspace(t, map[string]string{
+		"a.txt": "same",
+		"b.txt": "same",
+		"c.txt": "other",
+	})
+	author := &config.Author{Name: "T", Email: "t@t"}
+
+	// .fstignore, "same" and "other" are new; b.txt reuses a.txt's blob.
+	r1, err := ws.Snapshot(SnapshotOpts{Message: "v1", Author: author})
+	if err != nil {
+		t.Fatalf("Snapshot v1: %v", err)
+	}
+	if r1.BlobsCached != 3 || r1.BlobsReused != 1 {
+		t.Fatalf("v1: expected 3 new, 1 reused; got %d new, %d reused", r1.BlobsCached, r1.BlobsReused)
+	}
+
+	os.WriteFile(filepath.Join(ws.Root(), "c.txt"), []byte("changed"), 0644)
+	r2, err := ws.Snapshot(SnapshotOpts{Message: "v2", Author: author})
+	if err != nil {
+		t.Fatalf("Snapshot v2: %v", err)
+	}
+	if r2.BlobsCached != 1 || r2.BlobsReused != 3 {
+		t.Fatalf("v2: expected 1 new, 3 reused; got %d new, %d reused", r2.BlobsCached, r2.BlobsReused)
+	}
+
+	// Without the existence check every distinct blob is written again,
+	// replacing whatever the store held under that hash.
+	sameHash := sha256Hex([]byte("same"))
+	if err := os.WriteFile(ws.Store().BlobPath(sameHash), []byte("stale"), 0644); err != nil {
+		t.Fatalf("overwrite blob: %v", err)
+	}
+	os.WriteFile(filepath.Join(ws.Root(), "c.txt"), []byte("changed again"), 0644)
+	r3, err := ws.Snapshot(SnapshotOpts{Message: "v3", Author: author, SkipDedupCheck: true})
+	if err != nil {
+		t.Fatalf("Snapshot v3: %v", err)
+	}
+	if r3.BlobsCached != 3 || r3.BlobsReused != 1 {
+		t.Fatalf("v3: expected 3 new, 1 reused; got %d new, %d reused", r3.BlobsCached, r3.BlobsReused)
+	}
+	if data, err := ws.Store().ReadBlob(sameHash); err != nil || string(data) != "same" {
+		t.Fatalf("expected the blob to be rewritten, got %q (%v)", data, err)
+	}
+}
+
+func TestSnapshotPaths(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{
+		"keep.txt": "v1",
+		"take.txt": "v1",
+	})
+	r1, err := ws.Snapshot(SnapshotOpts{
+		Message: "v1",
+		Author:  &config.Author{Name: "T", Email: "t@t"},
+	})
+	if err != nil {
+		t.Fatalf("Snapshot v1: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(root, "keep.txt"), []byte("v2 keep"), 0644)
+	os.WriteFile(filepath.Join(root, "take.txt"), []byte("v2 take"), 0644)
+
+	if _, err := ws.Snapshot(SnapshotOpts{
+		Message: "partial",
+		Author:  &config.Author{Name: "T", Email: "t@t"},
+		Paths:   []string{"take.txt"},
+	}); err != nil {
+		t.Fatalf("partial Snapshot: %v", err)
+	}
+
+	oldHash, _ := ws.Store().ManifestHashFromSnapshotID(r1.SnapshotID)
+	oldManifest, _ := ws.Store().LoadManifest(oldHash)
+	newHash, _ := ws.Store().ManifestHashFromSnapshotID(ws.CurrentSnapshotID())
+	newManifest, err := ws.Store().LoadManifest(newHash)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	_, modified, _ := manifest.Diff(oldManifest, newManifest)
+	if len(modified) != 1 || modified[0] != "take.txt" {
+		t.Fatalf("expected only take.txt in the snapshot, got %v", modified)
+	}
+
+	unchanged, err := ws.UnchangedFrom(ws.CurrentSnapshotID(), false)
+	if err != nil {
+		t.Fatalf("UnchangedFrom: %v", err)
+	}
+	if unchanged {
+		t.Fatal("expected keep.txt to remain an uncommitted change")
+	}
+}
+
+func TestSnapshotAttachments(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{
+		"main.go": "package main",
+	})
+	external := filepath.Join(t.TempDir(), "build.log")
+	if err := os.WriteFile(external, []byte("build ok\n"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	author := &config.Author{Name: "T", Email: "t@t"}
+
+	result, err := ws.Snapshot(SnapshotOpts{
+		Message:     "with log",
+		Author:      author,
+		Attachments: []Attachment{{Source: external, Path: "artifacts/build.log"}},
+	})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "artifacts")); !os.IsNotExist(err) {
+		t.Fatalf("attaching should not write into the working tree")
+	}
+
+	m, err := ws.Store().LoadManifest(result.ManifestHash)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	var found bool
+	for _, f := range m.Files {
+		if f.Path == "artifacts/build.log" {
+			found = true
+			data, err := ws.Store().ReadBlob(f.Hash)
+			if err != nil || string(data) != "build ok\n" {
+				t.Fatalf("expected attached blob content, got %q (%v)", data, err)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected artifacts/build.log in manifest, got %+v", m.Files)
+	}
+
+	if _, err := ws.Restore(RestoreOpts{SnapshotID: result.SnapshotID}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "artifacts", "build.log"))
+	if err != nil || string(data) != "build ok\n" {
+		t.Fatalf("expected restore to materialize the attachment, got %q (%v)", data, err)
+	}
+
+	for _, bad := range []string{"../outside.log", "main.go/x", ".fst/config.json"} {
+		_, err := ws.Snapshot(SnapshotOpts{
+			Message:     "bad",
+			Author:      author,
+			Attachments: []Attachment{{Source: external, Path: bad}},
+		})
+		if err == nil {
+			t.Fatalf("expected attaching at %q to fail", bad)
+		}
+	}
+}
+
 func TestAutoSnapshotNoChanges(t *testing.T) {
 	_, ws := setupTestWorkspace(t, map[string]string{
 		"file.txt": "content",
@@ -137,6 +289,41 @@ func TestAutoSnapshotNoChanges(t *testing.T) {
 	}
 }
 
+func TestAutoSnapshotNoChangesSinceKeepModTime(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{
+		"file.txt": "content",
+	})
+
+	head, err := ws.Snapshot(SnapshotOpts{
+		Message:     "initial",
+		Author:      &config.Author{Name: "T", Email: "t@t"},
+		KeepModTime: true,
+	})
+	if err != nil {
+		t.Fatalf("initial snapshot: %v", err)
+	}
+
+	unchanged, err := ws.UnchangedFrom(head.SnapshotID, false)
+	if err != nil {
+		t.Fatalf("UnchangedFrom: %v", err)
+	}
+	if !unchanged {
+		t.Fatalf("expected the tree to match its --keep-mtime snapshot")
+	}
+	id, err := ws.AutoSnapshot("no changes")
+	if err != nil {
+		t.Fatalf("AutoSnapshot: %v", err)
+	}
+	if id != "" {
+		t.Fatalf("expected empty ID for no changes, got %s", id)
+	}
+
+	os.WriteFile(filepath.Join(root, "file.txt"), []byte("changed"), 0644)
+	if unchanged, _ := ws.UnchangedFrom(head.SnapshotID, false); unchanged {
+		t.Fatalf("expected a content change to count")
+	}
+}
+
 func TestAutoSnapshotWithChanges(t *testing.T) {
 	_, ws := setupTestWorkspace(t, map[string]string{
 		"file.txt": "v1",
@@ -204,3 +391,32 @@ func TestSnapshotIntegrity(t *testing.T) {
 		t.Fatalf("snapshot failed verification")
 	}
 }
+
+func TestSnapshotExplicitCreatedAt(t *testing.T) {
+	_, ws := setupTestWorkspace(t, map[string]string{
+		"file.txt": "content",
+	})
+
+	createdAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("X", 2*60*60))
+	result, err := ws.Snapshot(SnapshotOpts{
+		Message:   "backdated",
+		Author:    &config.Author{Name: "T", Email: "t@t"},
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	meta, err := ws.Store().LoadSnapshotMeta(result.SnapshotID)
+	if err != nil {
+		t.Fatalf("LoadSnapshotMeta: %v", err)
+	}
+	if meta.CreatedAt != "2021-03-04T03:06:07Z" {
+		t.Fatalf("expected UTC created_at, got %s", meta.CreatedAt)
+	}
+
+	expected := store.ComputeSnapshotID(result.ManifestHash, nil, "T", "t@t", meta.CreatedAt)
+	if result.SnapshotID != expected {
+		t.Fatalf("snapshot ID %s does not match computed ID %s", result.SnapshotID, expected)
+	}
+}
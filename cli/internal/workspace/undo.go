@@ -0,0 +1,106 @@
+package workspace
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+)
+
+// UndoResult contains the outcome of an Undo.
+type UndoResult struct {
+	Entry   config.UndoEntry // the undo point returned to
+	Restore *RestoreResult
+}
+
+// UndoOpts configures an Undo.
+type UndoOpts struct {
+	DryRun bool
+	// Force undoes even if the head has moved since the command being
+	// undone, abandoning the snapshots made in between.
+	Force bool
+	// PruneEmptyDirs removes directories left empty by the restore (see
+	// RestoreOpts.PruneEmptyDirs).
+	PruneEmptyDirs bool
+}
+
+// RecordUndoPoint remembers snapshotID as the state to return to if the
+// operation about to run is undone. Commands that rewrite the working tree
+// call it after their pre-operation snapshot. Until RecordUndoResult says
+// otherwise, the operation is assumed to leave the head at snapshotID.
+func (ws *Workspace) RecordUndoPoint(operation, description, snapshotID string) error {
+	if snapshotID == "" {
+		return nil
+	}
+	return config.PushUndoAt(ws.root, config.UndoEntry{
+		Operation:        operation,
+		Description:      description,
+		SnapshotID:       snapshotID,
+		ResultSnapshotID: snapshotID,
+		CreatedAt:        time.Now().UTC(),
+	})
+}
+
+// RecordUndoResult records snapshotID as the head the most recent undoable
+// operation left the workspace at, e.g. its post-run snapshot.
+func (ws *Workspace) RecordUndoResult(snapshotID string) error {
+	entries, err := config.ReadUndoStackAt(ws.root)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+	entries[len(entries)-1].ResultSnapshotID = snapshotID
+	return config.WriteUndoStackAt(ws.root, entries)
+}
+
+// UndoStack returns the workspace's undo points, newest last.
+func (ws *Workspace) UndoStack() ([]config.UndoEntry, error) {
+	return config.ReadUndoStackAt(ws.root)
+}
+
+// Undo returns the workspace to its most recent undo point: the working
+// tree is restored to the recorded snapshot, which becomes the head again,
+// and any pending merge is abandoned. The undo point is then dropped, so
+// repeated calls step further back. Uncommitted changes are discarded.
+// If the head has moved since the operation finished, Undo refuses unless
+// opts.Force is set. With opts.DryRun, only the restore plan is computed.
+func (ws *Workspace) Undo(opts UndoOpts) (*UndoResult, error) {
+	dryRun := opts.DryRun
+	entries, err := config.ReadUndoStackAt(ws.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read undo history: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+	entry := entries[len(entries)-1]
+
+	if head := ws.cfg.CurrentSnapshotID; !opts.Force && entry.ResultSnapshotID != "" && head != entry.ResultSnapshotID {
+		return nil, fmt.Errorf("the workspace head moved to %s after %s (which left it at %s); undoing would abandon the snapshots since - use --force to undo anyway", head, entry.Description, entry.ResultSnapshotID)
+	}
+
+	if !ws.store.SnapshotExists(entry.SnapshotID) {
+		if !dryRun {
+			_ = config.WriteUndoStackAt(ws.root, entries[:len(entries)-1])
+		}
+		return nil, fmt.Errorf("snapshot %s from before %s no longer exists (removed by gc?)", entry.SnapshotID, entry.Description)
+	}
+
+	restore, err := ws.Restore(RestoreOpts{SnapshotID: entry.SnapshotID, DryRun: dryRun, PruneEmptyDirs: opts.PruneEmptyDirs})
+	if err != nil {
+		return &UndoResult{Entry: entry, Restore: restore}, err
+	}
+	if dryRun {
+		return &UndoResult{Entry: entry, Restore: restore}, nil
+	}
+
+	if err := ws.SetCurrentSnapshotID(entry.SnapshotID); err != nil {
+		return nil, fmt.Errorf("failed to update workspace config: %w", err)
+	}
+	_ = config.ClearPendingMergeParentsAt(ws.root)
+	_ = ws.store.UpdateWorkspaceHead(ws.cfg.WorkspaceID, entry.SnapshotID)
+
+	if err := config.WriteUndoStackAt(ws.root, entries[:len(entries)-1]); err != nil {
+		return nil, fmt.Errorf("failed to update undo history: %w", err)
+	}
+	return &UndoResult{Entry: entry, Restore: restore}, nil
+}
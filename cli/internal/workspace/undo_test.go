@@ -0,0 +1,139 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ankitiscracked/fastest/cli/internal/config"
+)
+
+func TestUndo(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{
+		"file.txt": "v1",
+	})
+	author := &config.Author{Name: "T", Email: "t@t"}
+
+	first, err := ws.Snapshot(SnapshotOpts{Message: "v1", Author: author})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if err := ws.RecordUndoPoint("merge", "merge from a", first.SnapshotID); err != nil {
+		t.Fatalf("RecordUndoPoint: %v", err)
+	}
+	os.WriteFile(filepath.Join(root, "file.txt"), []byte("v2"), 0644)
+	os.WriteFile(filepath.Join(root, "added.txt"), []byte("new"), 0644)
+	second, err := ws.Snapshot(SnapshotOpts{Message: "v2", Author: author})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if err := ws.RecordUndoResult(second.SnapshotID); err != nil {
+		t.Fatalf("RecordUndoResult: %v", err)
+	}
+	if err := ws.RecordUndoPoint("merge", "merge from b", second.SnapshotID); err != nil {
+		t.Fatalf("RecordUndoPoint: %v", err)
+	}
+	os.WriteFile(filepath.Join(root, "file.txt"), []byte("v3"), 0644)
+	third, err := ws.Snapshot(SnapshotOpts{Message: "v3", Author: author})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if err := ws.RecordUndoResult(third.SnapshotID); err != nil {
+		t.Fatalf("RecordUndoResult: %v", err)
+	}
+
+	result, err := ws.Undo(UndoOpts{DryRun: true})
+	if err != nil {
+		t.Fatalf("Undo dry run: %v", err)
+	}
+	if result.Entry.SnapshotID != second.SnapshotID {
+		t.Fatalf("expected dry run to target %s, got %s", second.SnapshotID, result.Entry.SnapshotID)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "file.txt")); string(content) != "v3" {
+		t.Fatalf("dry run should not change files, got %q", content)
+	}
+
+	if _, err := ws.Undo(UndoOpts{}); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "file.txt")); string(content) != "v2" {
+		t.Fatalf("expected v2 after first undo, got %q", content)
+	}
+	if ws.CurrentSnapshotID() != second.SnapshotID {
+		t.Fatalf("expected head %s, got %s", second.SnapshotID, ws.CurrentSnapshotID())
+	}
+
+	// A second undo steps further back.
+	if _, err := ws.Undo(UndoOpts{}); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "file.txt")); string(content) != "v1" {
+		t.Fatalf("expected v1 after second undo, got %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(root, "added.txt")); !os.IsNotExist(err) {
+		t.Fatalf("added.txt should be removed by undo")
+	}
+	if ws.CurrentSnapshotID() != first.SnapshotID {
+		t.Fatalf("expected head %s, got %s", first.SnapshotID, ws.CurrentSnapshotID())
+	}
+
+	if _, err := ws.Undo(UndoOpts{}); err == nil {
+		t.Fatalf("expected nothing to undo")
+	}
+}
+
+func TestUndoStackIsBounded(t *testing.T) {
+	_, ws := setupTestWorkspace(t, nil)
+
+	for i := 0; i < config.MaxUndoEntries+3; i++ {
+		if err := ws.RecordUndoPoint("merge", "merge", "snap"); err != nil {
+			t.Fatalf("RecordUndoPoint: %v", err)
+		}
+	}
+	entries, err := ws.UndoStack()
+	if err != nil {
+		t.Fatalf("UndoStack: %v", err)
+	}
+	if len(entries) != config.MaxUndoEntries {
+		t.Fatalf("expected %d undo points, got %d", config.MaxUndoEntries, len(entries))
+	}
+}
+
+func TestUndoRefusesWhenHeadMoved(t *testing.T) {
+	root, ws := setupTestWorkspace(t, map[string]string{
+		"file.txt": "v1",
+	})
+	author := &config.Author{Name: "T", Email: "t@t"}
+
+	first, err := ws.Snapshot(SnapshotOpts{Message: "v1", Author: author})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+	if err := ws.RecordUndoPoint("agent run", "mock run", first.SnapshotID); err != nil {
+		t.Fatalf("RecordUndoPoint: %v", err)
+	}
+
+	// A snapshot taken after the operation finished is not part of it.
+	os.WriteFile(filepath.Join(root, "file.txt"), []byte("later"), 0644)
+	later, err := ws.Snapshot(SnapshotOpts{Message: "later", Author: author})
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	if _, err := ws.Undo(UndoOpts{}); err == nil {
+		t.Fatalf("expected undo to refuse once the head moved")
+	}
+	if ws.CurrentSnapshotID() != later.SnapshotID {
+		t.Fatalf("expected head to stay at %s, got %s", later.SnapshotID, ws.CurrentSnapshotID())
+	}
+
+	if _, err := ws.Undo(UndoOpts{Force: true}); err != nil {
+		t.Fatalf("Undo --force: %v", err)
+	}
+	if ws.CurrentSnapshotID() != first.SnapshotID {
+		t.Fatalf("expected head %s, got %s", first.SnapshotID, ws.CurrentSnapshotID())
+	}
+	if content, _ := os.ReadFile(filepath.Join(root, "file.txt")); string(content) != "v1" {
+		t.Fatalf("expected v1 after forced undo, got %q", content)
+	}
+}